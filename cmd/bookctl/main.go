@@ -0,0 +1,60 @@
+// Package main es el punto de entrada de bookctl, la línea de comandos de administración
+//
+// 🎯 Como cmd/server/main.go, este archivo es un Composition Root: arma el modo
+// directo (almacenamiento → casos de uso) y le pasa el control a internal/delivery/cli
+//
+//	go run ./cmd/bookctl books list --author martin
+//	go run ./cmd/bookctl --remote http://localhost:8080 --token <access_token> users list -o json
+//
+// 💡 El modo directo usa las MISMAS variables de entorno que el servidor
+// (STORAGE_DRIVER, DATABASE_URL, BCRYPT_COST, ...): ver internal/config
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/delivery/cli"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/infrastructure/storage"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+func main() {
+	// Ctrl+C cancela el context: la consulta en curso se aborta y la conexión se cierra bien
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	code := cli.Run(ctx, os.Args[1:], cli.Env{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Getenv: os.Getenv,
+		Direct: connectDirect,
+	})
+
+	stop()
+	os.Exit(code)
+}
+
+// connectDirect abre el almacenamiento configurado y arma los casos de uso
+func connectDirect(ctx context.Context) (cli.Client, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("configuración inválida: %w", err)
+	}
+	if cfg.Storage.Driver == config.StorageMemory {
+		fmt.Fprintln(os.Stderr, "⚠️ STORAGE_DRIVER=memory: los datos viven solo durante este comando; usa STORAGE_DRIVER=postgres o --remote")
+	}
+
+	repos, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no se pudo abrir el almacenamiento: %w", err)
+	}
+
+	books := usecase.NewBookUseCase(repos.Books, repos.Authors, repos.Copies, repos.Loans, repos.Holds, repos.Fines)
+	users := usecase.NewUserUseCase(repos.Users, repos.Loans, repos.Holds, repos.Fines, security.NewBcryptHasher(cfg.Auth.BcryptCost))
+	return cli.NewDirectClient(books, users), func() { repos.Close() }, nil
+}
@@ -19,10 +19,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
+	"time"
 
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/delivery/graphql"
+	"go-book-clean-architecture-api/internal/delivery/grpc"
 	"go-book-clean-architecture-api/internal/delivery/http"
-	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/delivery/opds"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/infrastructure/storage"
 	"go-book-clean-architecture-api/internal/routes"
 	"go-book-clean-architecture-api/internal/usecase"
 
@@ -32,17 +40,25 @@ import (
 )
 
 func main() {
+	// 🎯 PASO 0: Cargar la configuración desde variables de entorno
+	// Si algo está mal configurado, preferimos fallar AHORA y no en la primera petición
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("💥 Configuración inválida: ", err)
+	}
+
 	// 🎯 PASO 1: Crear la aplicación Fiber
 	// Fiber es nuestro framework web, pero está completamente aislado en la capa de delivery
 	// Si quisiéramos cambiar a Gin, Echo, etc., solo cambiaríamos esta línea y los handlers
 	app := fiber.New(fiber.Config{
 		// Configurar manejo global de errores
-		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
-			log.Printf("Error no manejado: %v", err)
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Error interno del servidor",
-			})
-		},
+		// Todas las respuestas de error usan application/problem+json (RFC 7807),
+		// incluidas las de rutas inexistentes (404) y métodos no permitidos (405)
+		ErrorHandler: http.ErrorHandler,
+		// 🌊 Los bodies grandes se leen como stream: POST /api/books/import procesa el
+		// archivo a medida que llega (el límite de los bodies JSON lo pone http.BodyLimit)
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		// Prefork para mejor performance en producción (opcional)
 		Prefork: false,
 		// Configuración de JSON más legible
@@ -58,9 +74,17 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*", // En producción, especificar dominios exactos
 		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-API-Key,If-Match,If-None-Match",
+		// El frontend necesita leer el ETag para enviarlo en If-Match (ver http/etag.go)
+		ExposeHeaders: "ETag",
 	})) // Habilitar CORS para peticiones desde el frontend
 
+	// Deadline por petición: si vence, el contexto se cancela y la DB aborta la consulta
+	app.Use(http.RequestTimeout(cfg.RequestTimeout))
+
+	// Bodies de hasta 4 MB (salvo la importación, que lee el archivo en streaming)
+	app.Use(http.BodyLimit(fiber.DefaultBodyLimit, routes.StreamedRoutes...))
+
 	// 🎯 PASO 3: DEPENDENCY INJECTION - ¡La parte MÁS IMPORTANTE!
 	// Esta es la implementación práctica de Clean Architecture
 	//
@@ -76,42 +100,147 @@ func main() {
 
 	// 3.1: CAPA DE INFRAESTRUCTURA (más externa)
 	// Aquí creamos las implementaciones concretas de persistencia
-	log.Println("📁 Creando repositorios de infraestructura...")
-	bookRepo := memory.NewInMemoryBookRepository() // Implementación en memoria
-	userRepo := memory.NewInMemoryUserRepository() // Implementación en memoria
+	log.Printf("📁 Creando repositorios de infraestructura (driver: %s)...", cfg.Storage.Driver)
+	repos, err := storage.New(context.Background(), cfg.Storage)
+	if err != nil {
+		log.Fatal("💥 Error al inicializar el almacenamiento: ", err)
+	}
+	defer repos.Close()
 
-	// 💡 FLEXIBILIDAD: Para cambiar a PostgreSQL, solo cambiarías estas líneas:
-	// bookRepo := postgresql.NewPostgresBookRepository(db)
-	// userRepo := postgresql.NewPostgresUserRepository(db)
+	bookRepo := repos.Books // memoria o PostgreSQL, según STORAGE_DRIVER
+	userRepo := repos.Users
+	authorRepo := repos.Authors
+	loanRepo := repos.Loans
+	copyRepo := repos.Copies
+	holdRepo := repos.Holds
+	fineRepo := repos.Fines
+	sessionRepo := repos.Sessions
+	apiKeyRepo := repos.APIKeys
+
+	// 💡 FLEXIBILIDAD: Para cambiar a PostgreSQL NO hace falta tocar código:
+	// STORAGE_DRIVER=postgres DATABASE_URL=postgres://... go run cmd/server/main.go
 
 	log.Println("✅ Repositorios creados exitosamente")
 
+	// 🔐 Criptografía: hash de contraseñas y firma de tokens (también son infraestructura)
+	clock := usecase.SystemClock{} // Hora real; los tests inyectan un reloj fijo
+	hasher := security.NewBcryptHasher(cfg.Auth.BcryptCost)
+	tokenManager, err := security.NewJWTManager(cfg.Auth, clock.Now)
+	if err != nil {
+		log.Fatal("💥 Error al inicializar la firma de tokens: ", err)
+	}
+	if len(cfg.Auth.Keys) == 0 {
+		log.Println("⚠️ JWT_SIGNING_KEYS no está configurado: se usa una clave aleatoria y los tokens dejan de valer al reiniciar")
+	}
+
 	// 3.2: CAPA DE APLICACIÓN/CASOS DE USO (capa media)
 	// Inyectamos los repositorios en los casos de uso
 	log.Println("🧠 Creando casos de uso de aplicación...")
-	bookUseCase := usecase.NewBookUseCase(bookRepo) // Inyectar repositorio de libros
-	userUseCase := usecase.NewUserUseCase(userRepo) // Inyectar repositorio de usuarios
+	bookUseCase := usecase.NewBookUseCase(bookRepo, authorRepo, copyRepo, loanRepo, holdRepo, fineRepo)                 // Libros (autores vinculados y disponibilidad)
+	userUseCase := usecase.NewUserUseCase(userRepo, loanRepo, holdRepo, fineRepo, hasher)                               // Usuarios (la contraseña se guarda hasheada)
+	authorUseCase := usecase.NewAuthorUseCase(authorRepo, bookRepo)                                                     // Autores (y sus libros)
+	loanUseCase := usecase.NewLoanUseCase(loanRepo, bookRepo, userRepo, copyRepo, holdRepo, fineRepo, cfg.Loans, clock) // Préstamos (conecta usuarios y ejemplares)
+	copyUseCase := usecase.NewCopyUseCase(copyRepo, bookRepo, loanRepo, holdRepo, cfg.Loans, clock)                     // Inventario de ejemplares
+	holdUseCase := usecase.NewHoldUseCase(holdRepo, bookRepo, userRepo, copyRepo, cfg.Loans, clock)                     // Cola de reservas
+	fineUseCase := usecase.NewFineUseCase(fineRepo, loanRepo, userRepo, cfg.Loans, clock)                               // Multas por atraso
+	authUseCase := usecase.NewAuthUseCase(userRepo, sessionRepo, hasher, tokenManager, cfg.Auth.Tokens, clock)          // Login y tokens
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, userRepo, clock)                                              // API keys de clientes máquina
 
 	log.Println("✅ Casos de uso creados exitosamente")
 
+	// 🛡️ Admin inicial: sin él nadie podría asignar roles (todo registro nuevo es socio)
+	if cfg.Auth.AdminEmail != "" {
+		admin, err := userUseCase.BootstrapAdmin(context.Background(), cfg.Auth.AdminEmail, cfg.Auth.AdminPassword)
+		if err != nil {
+			log.Fatalf("❌ Error creando el admin inicial: %v", err)
+		}
+		log.Printf("🛡️ Admin inicial: %s", admin.Email)
+	}
+
 	// 3.3: CAPA DE DELIVERY/INTERFAZ (más interna de las externas)
 	// Inyectamos los casos de uso en los handlers
 	log.Println("🌐 Creando handlers de delivery...")
-	bookHandler := http.NewBookHandler(bookUseCase) // Inyectar caso de uso de libros
-	userHandler := http.NewUserHandler(userUseCase) // Inyectar caso de uso de usuarios
+	bookHandler := http.NewBookHandler(bookUseCase)                                     // Inyectar caso de uso de libros
+	importHandler := http.NewBookImportHandler(bookUseCase, cfg.ImportTimeout)          // Importación masiva de libros
+	exportHandler := http.NewExportHandler(bookUseCase, userUseCase, cfg.ExportTimeout) // Exportación a CSV, NDJSON y xlsx
+	citationHandler := http.NewCitationHandler(bookUseCase, cfg.ExportTimeout)          // Citas: BibTeX, RIS y CSL-JSON
+	userHandler := http.NewUserHandler(userUseCase)                                     // Inyectar caso de uso de usuarios
+	authorHandler := http.NewAuthorHandler(authorUseCase)                               // Inyectar caso de uso de autores
+	loanHandler := http.NewLoanHandler(loanUseCase)                                     // Inyectar caso de uso de préstamos
+	copyHandler := http.NewCopyHandler(copyUseCase)                                     // Inyectar caso de uso de ejemplares
+	holdHandler := http.NewHoldHandler(holdUseCase)                                     // Inyectar caso de uso de reservas
+	fineHandler := http.NewFineHandler(fineUseCase)                                     // Inyectar caso de uso de multas
+	authHandler := http.NewAuthHandler(authUseCase)                                     // Inyectar caso de uso de autenticación
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyUseCase)                               // Inyectar caso de uso de API keys
+	graphqlHandler := graphql.NewHandler(graphql.Deps{                                  // GraphQL: libros, usuarios y préstamos
+		Books:      bookUseCase,
+		Users:      userUseCase,
+		Loans:      loanUseCase,
+		Playground: cfg.Dev(),
+	})
+	opdsHandler := opds.NewHandler(bookUseCase, authorUseCase) // OPDS: el catálogo para apps de lectura
 
 	log.Println("✅ Handlers creados exitosamente")
 
 	// 🎯 PASO 4: Configurar las rutas
 	// Las rutas conectan URLs con handlers específicos
 	log.Println("🛣️ Configurando rutas de la aplicación...")
-	routes.SetupRoutes(app, bookHandler, userHandler)
+	routes.SetupRoutes(app, routes.Handlers{
+		Books:     bookHandler,
+		Imports:   importHandler,
+		Exports:   exportHandler,
+		Citations: citationHandler,
+		Users:     userHandler,
+		Authors:   authorHandler,
+		Loans:     loanHandler,
+		Copies:    copyHandler,
+		Holds:     holdHandler,
+		Fines:     fineHandler,
+		Auth:      authHandler,
+		APIKeys:   apiKeyHandler,
+		GraphQL:   graphqlHandler,
+		OPDS:      opdsHandler,
+	})
 	log.Println("✅ Rutas configuradas exitosamente")
 
+	// 📡 PASO 4b: El servidor gRPC, en su propio puerto
+	// 💡 Recibe las MISMAS instancias de los casos de uso que los handlers HTTP:
+	// otra puerta de entrada, las mismas reglas de negocio
+	grpcServer := grpc.NewServer(grpc.Deps{
+		Books:   bookUseCase,
+		Users:   userUseCase,
+		Auth:    authUseCase,
+		APIKeys: apiKeyUseCase,
+		Timeout: cfg.RequestTimeout,
+	})
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		repos.Close()
+		log.Fatal("💥 Error al abrir el puerto gRPC:", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Println("⚠️ El servidor gRPC se detuvo: ", err)
+		}
+	}()
+
+	// ⏰ Barrido periódico de reservas: las que no se retiraron a tiempo vencen
+	// y su ejemplar pasa a la siguiente de la cola
+	go sweepExpiredHolds(holdUseCase, cfg.HoldSweep)
+	// 💰 Y de multas: los listados generales muestran el atraso al día
+	go sweepOverdueFines(fineUseCase, cfg.FineSweep)
+
 	// 🎯 PASO 5: Mostrar información útil y iniciar el servidor
 	log.Println("")
 	log.Println("🚀 ===== SERVIDOR INICIADO EXITOSAMENTE =====")
-	log.Println("🌐 URL: http://localhost:8080")
+	log.Printf("🌐 URL: http://localhost:%s", cfg.Port)
+	log.Printf("📡 gRPC: localhost:%s (BookService y UserService, ver internal/delivery/grpc/proto)", cfg.GRPCPort)
+	log.Printf("🕸️ GraphQL: POST http://localhost:%s/graphql (entorno: %s)", cfg.Port, cfg.Env)
+	if cfg.Dev() {
+		log.Printf("🧪 GraphiQL: http://localhost:%s/graphql", cfg.Port)
+	}
+	log.Printf("📱 OPDS: http://localhost:%s/opds (apps de lectura: la API key como contraseña)", cfg.Port)
+	log.Printf("💾 Almacenamiento: %s", cfg.Storage.Driver)
 	log.Println("� Documentación: README.md")
 	log.Println("🧪 Ejemplos de peticiones: api_examples.http")
 	log.Println("")
@@ -119,19 +248,85 @@ func main() {
 	log.Println("🔍 Health Check:")
 	log.Println("  GET    /health              - Verificar estado de la API")
 	log.Println("")
+	log.Println("🔐 Autenticación (el resto de /api exige Authorization: Bearer <access_token>):")
+	log.Println("  POST   /api/users           - Registrarse (público)")
+	log.Println("  POST   /api/auth/login      - Iniciar sesión: email y contraseña → tokens")
+	log.Println("  POST   /api/auth/refresh    - Rotar el refresh token")
+	log.Println("  POST   /api/auth/revoke     - Cerrar sesión")
+	log.Println("  GET    /api/auth/me         - Usuario autenticado")
+	log.Println("")
 	log.Println("📖 Gestión de Libros:")
 	log.Println("  POST   /api/books           - Crear un nuevo libro")
-	log.Println("  GET    /api/books           - Obtener todos los libros")
+	log.Println("  POST   /api/books/import    - Importar un catálogo (CSV, NDJSON o MARC21, multipart)")
+	log.Println("  GET    /api/books           - Obtener todos los libros (?author, title, author_id, language)")
+	log.Println("  GET    /api/books/export    - Exportar el catálogo (?format=csv|ndjson|xlsx|marc|marcxml, mismos filtros)")
+	log.Println("  GET    /api/books/citation  - Citar los libros (?format=bibtex|ris|csl-json, mismos filtros)")
 	log.Println("  GET    /api/books/:id       - Obtener libro por ID")
+	log.Println("  GET    /api/books/:id/citation - Citar un libro (?format=bibtex|ris|csl-json)")
 	log.Println("  PUT    /api/books/:id       - Actualizar libro existente")
 	log.Println("  DELETE /api/books/:id       - Eliminar libro")
 	log.Println("")
 	log.Println("👤 Gestión de Usuarios:")
 	log.Println("  POST   /api/users           - Crear un nuevo usuario")
 	log.Println("  GET    /api/users           - Obtener todos los usuarios")
+	log.Println("  GET    /api/users/export    - Exportar los usuarios (?format=csv|ndjson|xlsx)")
 	log.Println("  GET    /api/users/:id       - Obtener usuario por ID")
 	log.Println("  PUT    /api/users/:id       - Actualizar usuario existente")
 	log.Println("  DELETE /api/users/:id       - Eliminar usuario")
+	log.Println("  PUT    /api/users/:id/role  - Cambiar el rol (solo admin)")
+	log.Println("")
+	log.Println("🔑 API keys (solo admin; los clientes la envían en X-API-Key):")
+	log.Println("  POST   /api/api-keys        - Crear una API key (la clave se muestra una sola vez)")
+	log.Println("  GET    /api/api-keys        - Listar API keys")
+	log.Println("  GET    /api/api-keys/:id    - Obtener API key por ID")
+	log.Println("  DELETE /api/api-keys/:id    - Revocar una API key")
+	log.Println("")
+	log.Println("✍️ Gestión de Autores:")
+	log.Println("  POST   /api/authors           - Crear un nuevo autor")
+	log.Println("  GET    /api/authors           - Obtener todos los autores")
+	log.Println("  GET    /api/authors/:id       - Obtener autor por ID")
+	log.Println("  GET    /api/authors/:id/books - Libros del autor")
+	log.Println("  PUT    /api/authors/:id       - Actualizar autor existente")
+	log.Println("  DELETE /api/authors/:id       - Eliminar autor (sin libros)")
+	log.Println("")
+	log.Println("🔁 Préstamos:")
+	log.Println("  POST   /api/loans             - Prestar un libro a un usuario")
+	log.Println("  GET    /api/loans             - Listar préstamos (?status=overdue)")
+	log.Println("  GET    /api/loans/:id         - Obtener préstamo por ID")
+	log.Println("  POST   /api/loans/:id/return  - Devolver")
+	log.Println("  POST   /api/loans/:id/renew   - Renovar")
+	log.Println("  GET    /api/users/:id/loans   - Préstamos de un usuario")
+	log.Println("")
+	log.Println("📦 Inventario de ejemplares:")
+	log.Println("  POST   /api/books/:id/copies       - Alta de un ejemplar")
+	log.Println("  GET    /api/books/:id/copies       - Ejemplares de un libro")
+	log.Println("  GET    /api/books/:id/availability - Disponibilidad por estado")
+	log.Println("  GET    /api/copies/:id             - Obtener ejemplar por ID")
+	log.Println("  PUT    /api/copies/:id             - Actualizar ejemplar")
+	log.Println("  PUT    /api/copies/:id/status      - Cambiar estado (available, lost, in_repair)")
+	log.Println("  DELETE /api/copies/:id             - Dar de baja un ejemplar")
+	log.Println("")
+	log.Println("⏳ Reservas:")
+	log.Println("  POST   /api/books/:id/holds  - Anotarse en la cola de un libro")
+	log.Println("  GET    /api/books/:id/holds  - Cola de reservas del libro")
+	log.Println("  GET    /api/users/:id/holds  - Reservas de un usuario")
+	log.Println("  GET    /api/holds/:id        - Obtener reserva por ID")
+	log.Println("  POST   /api/holds/:id/cancel - Cancelar reserva")
+	log.Println("")
+	log.Println("💰 Multas:")
+	log.Println("  GET    /api/fines              - Listar multas (?status=open)")
+	log.Println("  GET    /api/fines/:id          - Obtener multa por ID")
+	log.Println("  POST   /api/fines/:id/pay      - Registrar un pago")
+	log.Println("  POST   /api/fines/:id/waive    - Perdonar una multa")
+	log.Println("  GET    /api/users/:id/fines    - Multas de un usuario")
+	log.Println("  GET    /api/users/:id/balance  - Deuda del usuario")
+	log.Println("")
+	log.Println("📱 Catálogo OPDS (Basic: la API key como contraseña):")
+	log.Println("  GET    /opds                 - Menú principal")
+	log.Println("  GET    /opds/books           - Libros (?author_id, language, sort) con facetas")
+	log.Println("  GET    /opds/search?q=       - Buscar (ver /opds/opensearch.xml)")
+	log.Println("  GET    /opds/authors         - Autores")
+	log.Println("  GET    /opds/languages       - Idiomas")
 	log.Println("")
 	log.Println("🎯 ===== EMPEZAR A PROBAR =====")
 	log.Println("1. Abre api_examples.http en VS Code")
@@ -140,13 +335,44 @@ func main() {
 	log.Println("4. ¡Experimenta y aprende!")
 	log.Println("")
 
-	// Iniciar el servidor en el puerto 8080
+	// Iniciar el servidor en el puerto configurado (8080 por defecto)
 	// Esta línea bloquea el programa hasta que el servidor se detenga
-	if err := app.Listen(":8080"); err != nil {
+	if err := app.Listen(":" + cfg.Port); err != nil {
+		repos.Close() // log.Fatal no ejecuta los defer, cerramos el pool a mano
 		log.Fatal("💥 Error al iniciar el servidor:", err)
 	}
 }
 
+// sweepExpiredHolds vence cada interval las reservas listas que no se retiraron a tiempo
+//
+// 💡 Un error no detiene el barrido: se registra y se reintenta en el próximo tick
+func sweepExpiredHolds(holdUseCase *usecase.HoldUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := holdUseCase.ExpireHolds(context.Background())
+		if err != nil {
+			log.Println("⚠️ Error al vencer reservas: ", err)
+		}
+		if expired > 0 {
+			log.Printf("⏰ %d reserva(s) vencida(s); ejemplares ofrecidos a la cola", expired)
+		}
+	}
+}
+
+// sweepOverdueFines recalcula cada interval las multas de los préstamos vencidos
+func sweepOverdueFines(fineUseCase *usecase.FineUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := fineUseCase.AssessOverdue(context.Background()); err != nil {
+			log.Println("⚠️ Error al recalcular multas: ", err)
+		}
+	}
+}
+
 /*
 🎓 EXPLICACIÓN DETALLADA DEL FLUJO DE CLEAN ARCHITECTURE:
 
@@ -0,0 +1,79 @@
+package citation
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// writeBibTeX escribe una entrada @book
+//
+//	@book{martin2008clean,
+//	  author     = {Martin, Robert C.},
+//	  title      = {Clean Code: A Handbook of Agile Software Craftsmanship},
+//	  publisher  = {Prentice Hall},
+//	  year       = {2008},
+//	  isbn       = {9780132350884},
+//	}
+//
+// 👥 Varias personas se unen con " and " (la regla de BibTeX); editor y translator
+// son campos de biblatex que BibTeX clásico ignora sin error
+func writeBibTeX(w *bufio.Writer, key string, b *domain.Book, first bool) error {
+	if !first {
+		w.WriteString("\n")
+	}
+	fmt.Fprintf(w, "@book{%s,\n", key)
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-10s = {%s},\n", name, value)
+		}
+	}
+	people := creators(b)
+	field("author", bibtexNames(withRole(people, domain.RoleAuthor)))
+	field("editor", bibtexNames(withRole(people, domain.RoleEditor)))
+	field("translator", bibtexNames(withRole(people, domain.RoleTranslator)))
+	field("title", bibtexEscape(b.Title))
+	field("publisher", bibtexEscape(b.Publisher))
+	if b.PublicationYear > 0 {
+		field("year", strconv.Itoa(b.PublicationYear))
+	}
+	field("isbn", bibtexEscape(b.ISBN))
+
+	_, err := w.WriteString("}\n")
+	return err
+}
+
+// bibtexNames une los nombres con " and "
+//
+// 🛡️ Un nombre que contiene " and " (una institución, "Simon and Schuster") va
+// entre llaves: si no, BibTeX lo leería como dos personas
+func bibtexNames(people []creator) string {
+	names := make([]string, len(people))
+	for i, p := range people {
+		name := bibtexEscape(p.name)
+		if strings.Contains(strings.ToLower(name), " and ") {
+			name = "{" + name + "}"
+		}
+		names[i] = name
+	}
+	return strings.Join(names, " and ")
+}
+
+// bibtexEscape escapa los caracteres especiales de LaTeX
+//
+// 💡 Las letras acentuadas quedan tal cual: biblatex, biber y BibTeX con inputenc
+// leen UTF-8. Lo que sí rompe un .bib es una llave sin cerrar o un % (comentario)
+func bibtexEscape(s string) string {
+	return bibtexReplacer.Replace(singleLine(s))
+}
+
+var bibtexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`, "}", `\}`,
+	"&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`,
+	"~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+)
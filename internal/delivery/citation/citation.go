@@ -0,0 +1,252 @@
+// Package citation escribe libros como referencias bibliográficas: BibTeX, RIS y CSL-JSON
+//
+// 🎓 Son los formatos que leen los gestores de referencias (Zotero, Mendeley, EndNote,
+// JabRef) y LaTeX o pandoc: quien cita un libro del catálogo lo pega o lo importa tal cual
+//
+// 📋 De cada libro se toman los autores (con su rol), el título, la editorial, el año
+// y el ISBN. Con autores vinculados se usan sus nombres y roles (author, editor,
+// translator); sin vínculos, los créditos (Book.Author) separados por ";"
+//
+// 🔑 Cada referencia lleva una clave estable (ver Key): el mismo libro tiene siempre la
+// misma clave, así que un documento que ya lo cita no se rompe al volver a exportar
+//
+// 💡 Es un detalle de entrega, como tabular y marc: GET /api/books/:id/citation y
+// GET /api/books/citation lo usan con format=bibtex|ris|csl-json
+package citation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Format es un formato de referencias
+type Format string
+
+// Formatos soportados
+const (
+	FormatBibTeX  Format = "bibtex"   // Entradas @book{...} (LaTeX, JabRef, Zotero)
+	FormatRIS     Format = "ris"      // Research Information Systems (EndNote, Mendeley, Zotero)
+	FormatCSLJSON Format = "csl-json" // Citation Style Language en JSON (pandoc, citeproc)
+)
+
+// ParseFormat valida el nombre de un formato ("bibtex", "bib", "ris", "csl-json" o "csl")
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bibtex", "bib":
+		return FormatBibTeX, nil
+	case "ris":
+		return FormatRIS, nil
+	case "csl-json", "csl", "csljson":
+		return FormatCSLJSON, nil
+	default:
+		return "", domain.NewFieldsError(domain.FieldError{
+			Field:   "format",
+			Code:    domain.CodeInvalidFormat,
+			Message: fmt.Sprintf("formato de cita desconocido %q: usa bibtex, ris o csl-json", name),
+		})
+	}
+}
+
+// ContentType es el tipo MIME del formato (para el header Content-Type)
+func (f Format) ContentType() string {
+	switch f {
+	case FormatRIS:
+		return "application/x-research-info-systems; charset=utf-8"
+	case FormatCSLJSON:
+		return "application/vnd.citationstyles.csl+json"
+	default:
+		return "application/x-bibtex; charset=utf-8"
+	}
+}
+
+// Extension es la extensión habitual de los archivos del formato
+func (f Format) Extension() string {
+	switch f {
+	case FormatRIS:
+		return "ris"
+	case FormatCSLJSON:
+		return "json"
+	default:
+		return "bib"
+	}
+}
+
+// Writer escribe libros como referencias, una por vez
+//
+// 🔑 Dentro de un mismo archivo las claves no se repiten: si dos libros comparten
+// autor, año y primera palabra del título, el segundo lleva una "b", el tercero una "c"...
+// ⚠️ Close es obligatorio: vacía el buffer y, en CSL-JSON, cierra el array
+type Writer struct {
+	buf    *bufio.Writer
+	format Format
+	keys   map[string]int // Clave base → cuántas veces se usó
+	n      int            // Referencias escritas
+}
+
+// NewWriter crea un Writer (un formato desconocido es un error)
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	switch format {
+	case FormatBibTeX, FormatRIS, FormatCSLJSON:
+		return &Writer{buf: bufio.NewWriter(w), format: format, keys: map[string]int{}}, nil
+	default:
+		return nil, fmt.Errorf("citation: formato no soportado %q", format)
+	}
+}
+
+// Write escribe la referencia de un libro
+func (w *Writer) Write(b *domain.Book) error {
+	key := w.uniqueKey(Key(b))
+	var err error
+	switch w.format {
+	case FormatRIS:
+		err = writeRIS(w.buf, key, b, w.n == 0)
+	case FormatCSLJSON:
+		err = writeCSL(w.buf, key, b, w.n == 0)
+	default:
+		err = writeBibTeX(w.buf, key, b, w.n == 0)
+	}
+	w.n++
+	return err
+}
+
+// Close termina el archivo y vacía el buffer
+func (w *Writer) Close() error {
+	if w.format == FormatCSLJSON {
+		closing := "\n]\n"
+		if w.n == 0 {
+			closing = "[]\n"
+		}
+		if _, err := w.buf.WriteString(closing); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+// uniqueKey agrega una letra a las claves repetidas: martin2008clean, martin2008cleanb, ...
+func (w *Writer) uniqueKey(key string) string {
+	n := w.keys[key]
+	w.keys[key] = n + 1
+	switch {
+	case n == 0:
+		return key
+	case n < 26:
+		return key + string(rune('a'+n))
+	default:
+		return key + "-" + strconv.Itoa(n+1)
+	}
+}
+
+// Key es la clave de cita de un libro: apellido del primer autor + año + primera
+// palabra significativa del título (ni artículos ni números), sin acentos ni signos
+//
+// 📋 Ejemplos: "martin2008clean", "garciamarquez1967cien", "anon2001manual" (sin autor)
+// o "martin-sd-clean" (sin año)
+// 💡 Solo depende de esos tres datos: editar la descripción o las materias no la cambia
+func Key(b *domain.Book) string {
+	surname := "anon"
+	if people := creators(b); len(people) > 0 {
+		if s := keyPart(people[0].family()); s != "" {
+			surname = s
+		}
+	}
+	year := "-sd-" // Sin fecha
+	if b.PublicationYear > 0 {
+		year = strconv.Itoa(b.PublicationYear)
+	}
+	word := ""
+	for _, term := range domain.SearchTerms(b.Title) {
+		if !stopWords[term] && strings.IndexFunc(term, unicode.IsLetter) >= 0 {
+			word = keyPart(term)
+			break
+		}
+	}
+	return surname + year + word
+}
+
+// keyPart deja solo letras ASCII y dígitos ("García Márquez" → "garciamarquez")
+func keyPart(s string) string {
+	var out strings.Builder
+	for _, r := range domain.FoldAccents(strings.ToLower(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// stopWords son los artículos y preposiciones que no sirven como palabra de la clave
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "on": true, "and": true,
+	"el": true, "la": true, "los": true, "las": true, "un": true, "una": true, "unos": true, "unas": true,
+	"lo": true, "de": true, "del": true, "y": true, "en": true,
+	"o": true, "os": true, "as": true, "um": true, "uma": true, "do": true, "da": true,
+	"le": true, "les": true, "des": true, "du": true, "der": true, "die": true, "das": true,
+}
+
+// creator es una persona de la referencia con su rol
+type creator struct {
+	name string
+	role domain.AuthorRole
+}
+
+// creators son las personas del libro, en orden de créditos
+func creators(b *domain.Book) []creator {
+	var people []creator
+	for _, a := range b.Authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			role := a.Role
+			if role == "" {
+				role = domain.RoleAuthor
+			}
+			people = append(people, creator{name: name, role: role})
+		}
+	}
+	if len(people) > 0 {
+		return people
+	}
+	for _, name := range strings.Split(b.Author, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			people = append(people, creator{name: name, role: domain.RoleAuthor})
+		}
+	}
+	return people
+}
+
+// withRole filtra las personas con un rol
+func withRole(people []creator, role domain.AuthorRole) []creator {
+	var out []creator
+	for _, p := range people {
+		if p.role == role {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// split separa "Apellido, Nombre"; sin coma, el nombre no se puede separar con seguridad
+// ("Gabriel García Márquez" tiene dos apellidos) y ok es false
+func (c creator) split() (family, given string, ok bool) {
+	family, given, ok = strings.Cut(c.name, ",")
+	return strings.TrimSpace(family), strings.TrimSpace(given), ok
+}
+
+// family es el apellido para la clave: lo que va antes de la coma o, si no hay, la última palabra
+func (c creator) family() string {
+	if family, _, ok := c.split(); ok {
+		return family
+	}
+	words := strings.Fields(c.name)
+	return words[len(words)-1]
+}
+
+// singleLine junta un texto en una línea (RIS y BibTeX no admiten saltos dentro de un valor)
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,81 @@
+package citation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// cslItem es una referencia CSL-JSON (https://citeproc-js.readthedocs.io/en/latest/csl-json/markup.html)
+type cslItem struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Title      string    `json:"title,omitempty"`
+	Author     []cslName `json:"author,omitempty"`
+	Editor     []cslName `json:"editor,omitempty"`
+	Translator []cslName `json:"translator,omitempty"`
+	Publisher  string    `json:"publisher,omitempty"`
+	Issued     *cslDate  `json:"issued,omitempty"`
+	ISBN       string    `json:"ISBN,omitempty"`
+}
+
+// cslName es una persona: apellido y nombre, o el nombre tal cual (literal)
+//
+// 💡 "Gabriel García Márquez" va como literal: adivinar dónde empieza el apellido
+// haría que un estilo APA lo cite como "Márquez, G. G."
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// cslDate es una fecha CSL: {"date-parts": [[2008]]}
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// writeCSL escribe una referencia como elemento de un array JSON
+//
+// 📋 El archivo es siempre un array (aunque tenga un solo libro): es lo que esperan
+// pandoc (--bibliography) y citeproc. Close escribe el "]" final
+func writeCSL(w *bufio.Writer, key string, b *domain.Book, first bool) error {
+	item := cslItem{ID: key, Type: "book", Title: b.Title, Publisher: b.Publisher, ISBN: b.ISBN}
+	people := creators(b)
+	item.Author = cslNames(withRole(people, domain.RoleAuthor))
+	item.Editor = cslNames(withRole(people, domain.RoleEditor))
+	item.Translator = cslNames(withRole(people, domain.RoleTranslator))
+	if b.PublicationYear > 0 {
+		item.Issued = &cslDate{DateParts: [][]int{{b.PublicationYear}}}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // "Simon & Schuster", no "Simon & Schuster"
+	enc.SetIndent("  ", "  ")
+	if err := enc.Encode(item); err != nil {
+		return err
+	}
+
+	separator := ",\n  "
+	if first {
+		separator = "[\n  "
+	}
+	w.WriteString(separator)
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// cslNames traduce las personas a nombres CSL
+func cslNames(people []creator) []cslName {
+	var names []cslName
+	for _, p := range people {
+		if family, given, ok := p.split(); ok {
+			names = append(names, cslName{Family: family, Given: given})
+		} else {
+			names = append(names, cslName{Literal: p.name})
+		}
+	}
+	return names
+}
@@ -0,0 +1,56 @@
+package citation
+
+import (
+	"bufio"
+	"strconv"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// risTags es la etiqueta RIS de cada rol (como las exportan EndNote y Zotero)
+var risTags = map[domain.AuthorRole]string{
+	domain.RoleAuthor:     "AU",
+	domain.RoleEditor:     "ED",
+	domain.RoleTranslator: "A4", // Subsidiary author: el traductor en los libros
+}
+
+// writeRIS escribe un registro RIS
+//
+//	TY  - BOOK
+//	ID  - martin2008clean
+//	AU  - Martin, Robert C.
+//	TI  - Clean Code
+//	PB  - Prentice Hall
+//	PY  - 2008
+//	SN  - 9780132350884
+//	ER  -
+//
+// 📋 Cada línea es "TAG  - valor" y termina en CRLF, como pide la especificación:
+// algunos lectores (EndNote) no aceptan solo LF. Un valor nunca tiene saltos de línea
+func writeRIS(w *bufio.Writer, key string, b *domain.Book, first bool) error {
+	if !first {
+		w.WriteString("\r\n")
+	}
+	line := func(tag, value string) {
+		if value = singleLine(value); value != "" {
+			w.WriteString(tag + "  - " + value + "\r\n")
+		}
+	}
+	line("TY", "BOOK")
+	line("ID", key)
+	for _, p := range creators(b) {
+		tag, ok := risTags[p.role]
+		if !ok {
+			tag = "AU"
+		}
+		line(tag, p.name)
+	}
+	line("TI", b.Title)
+	line("PB", b.Publisher)
+	if b.PublicationYear > 0 {
+		line("PY", strconv.Itoa(b.PublicationYear))
+	}
+	line("SN", b.ISBN)
+	_, err := w.WriteString("ER  - \r\n") // Fin del registro (con el espacio final)
+	return err
+}
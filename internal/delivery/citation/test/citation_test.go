@@ -0,0 +1,185 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-book-clean-architecture-api/internal/delivery/citation"
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// cleanCode es un libro con autores vinculados de los tres roles
+func cleanCode() *domain.Book {
+	return &domain.Book{
+		Title:  "Clean Code: A Handbook of Agile Software Craftsmanship",
+		Author: "Robert C. Martin",
+		Authors: []domain.BookAuthor{
+			{AuthorID: "a1", Name: "Martin, Robert C.", Role: domain.RoleAuthor},
+			{AuthorID: "a2", Name: "Feathers, Michael", Role: domain.RoleAuthor},
+			{AuthorID: "a3", Name: "Simon and Schuster", Role: domain.RoleEditor},
+			{AuthorID: "a4", Name: "Luis Joyanes Aguilar", Role: domain.RoleTranslator},
+		},
+		Publisher:       "Prentice Hall",
+		PublicationYear: 2008,
+		ISBN:            "9780132350884",
+	}
+}
+
+// write escribe los libros en el formato y retorna el archivo
+func write(t *testing.T, format citation.Format, books ...*domain.Book) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := citation.NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("No se esperaba error al crear el writer, pero se obtuvo: %v", err)
+	}
+	for _, b := range books {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("No se esperaba error al cerrar, pero se obtuvo: %v", err)
+	}
+	return buf.String()
+}
+
+// TestKey verifica que las claves sean estables y solo tengan letras ASCII y dígitos
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		book *domain.Book
+		want string
+	}{
+		{"autor vinculado", cleanCode(), "martin2008clean"},
+		{"acentos y artículo", &domain.Book{Title: "El otoño del patriarca", Author: "García Márquez, Gabriel", PublicationYear: 1975}, "garciamarquez1975otono"},
+		{"orden directo", &domain.Book{Title: "Rayuela", Author: "Julio Cortázar; Otro", PublicationYear: 1963}, "cortazar1963rayuela"},
+		{"sin autor ni año", &domain.Book{Title: "The Art of Computer Programming"}, "anon-sd-art"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := citation.Key(tt.book)
+
+			// Assert
+			if got != tt.want {
+				t.Errorf("Se esperaba la clave %q, pero se obtuvo: %q", tt.want, got)
+			}
+			b := *tt.book
+			b.Description, b.Subjects = "otra descripción", []string{"Otra"}
+			if again := citation.Key(&b); again != got {
+				t.Errorf("Se esperaba la misma clave al editar la descripción, pero se obtuvo: %q", again)
+			}
+		})
+	}
+}
+
+// TestBibTeX verifica la entrada completa, el escape de LaTeX y las claves repetidas
+func TestBibTeX(t *testing.T) {
+	// Arrange
+	tricky := &domain.Book{Title: "100% {fiable} & $barato$ #1_a~b^c\\d", Author: "Pérez, Ana", PublicationYear: 2020}
+	clone := &domain.Book{Title: "Clean code (otra edición)", Author: "Martin, Bob", PublicationYear: 2008}
+
+	// Act
+	got := write(t, citation.FormatBibTeX, cleanCode(), tricky, clone)
+
+	// Assert
+	want := `@book{martin2008clean,
+  author     = {Martin, Robert C. and Feathers, Michael},
+  editor     = {{Simon and Schuster}},
+  translator = {Luis Joyanes Aguilar},
+  title      = {Clean Code: A Handbook of Agile Software Craftsmanship},
+  publisher  = {Prentice Hall},
+  year       = {2008},
+  isbn       = {9780132350884},
+}
+
+@book{perez2020fiable,
+  author     = {Pérez, Ana},
+  title      = {100\% \{fiable\} \& \$barato\$ \#1\_a\textasciitilde{}b\textasciicircum{}c\textbackslash{}d},
+  year       = {2020},
+}
+
+@book{martin2008cleanb,
+  author     = {Martin, Bob},
+  title      = {Clean code (otra edición)},
+  year       = {2008},
+}
+`
+	if got != want {
+		t.Errorf("Se esperaba:\n%s\npero se obtuvo:\n%s", want, got)
+	}
+}
+
+// TestRIS verifica las etiquetas de cada rol, el CRLF y los saltos de línea dentro de un valor
+func TestRIS(t *testing.T) {
+	// Arrange
+	multiline := &domain.Book{Title: "Una línea\ny otra", Author: "Ana"}
+
+	// Act
+	got := write(t, citation.FormatRIS, cleanCode(), multiline)
+
+	// Assert
+	want := "TY  - BOOK\r\nID  - martin2008clean\r\nAU  - Martin, Robert C.\r\nAU  - Feathers, Michael\r\n" +
+		"ED  - Simon and Schuster\r\nA4  - Luis Joyanes Aguilar\r\n" +
+		"TI  - Clean Code: A Handbook of Agile Software Craftsmanship\r\nPB  - Prentice Hall\r\n" +
+		"PY  - 2008\r\nSN  - 9780132350884\r\nER  - \r\n" +
+		"\r\nTY  - BOOK\r\nID  - ana-sd-linea\r\nAU  - Ana\r\nTI  - Una línea y otra\r\nER  - \r\n"
+	if got != want {
+		t.Errorf("Se esperaba:\n%q\npero se obtuvo:\n%q", want, got)
+	}
+}
+
+// TestCSLJSON verifica los nombres (separados o literales), la fecha y el array vacío
+func TestCSLJSON(t *testing.T) {
+	// Act
+	got := write(t, citation.FormatCSLJSON, cleanCode(), &domain.Book{Title: `"Comillas" & <html>`, Author: "Ana"})
+
+	// Assert
+	var items []struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Title  string `json:"title"`
+		Author []struct {
+			Family, Given, Literal string
+		} `json:"author"`
+		Translator []struct{ Literal string } `json:"translator"`
+		Issued     struct {
+			DateParts [][]int `json:"date-parts"`
+		} `json:"issued"`
+		ISBN string `json:"ISBN"`
+	}
+	if err := json.Unmarshal([]byte(got), &items); err != nil || len(items) != 2 {
+		t.Fatalf("Se esperaba un array JSON con 2 elementos, pero se obtuvo: %v\n%s", err, got)
+	}
+	first := items[0]
+	if first.ID != "martin2008clean" || first.Type != "book" || first.ISBN != "9780132350884" ||
+		len(first.Author) != 2 || first.Author[0].Family != "Martin" || first.Author[0].Given != "Robert C." ||
+		len(first.Translator) != 1 || first.Translator[0].Literal != "Luis Joyanes Aguilar" ||
+		len(first.Issued.DateParts) != 1 || first.Issued.DateParts[0][0] != 2008 {
+		t.Errorf("Se esperaba la referencia completa, pero se obtuvo: %+v", first)
+	}
+	if items[1].Title != `"Comillas" & <html>` || !strings.Contains(got, `& <html>`) {
+		t.Errorf("Se esperaba el título sin escapes HTML, pero se obtuvo:\n%s", got)
+	}
+
+	if empty := write(t, citation.FormatCSLJSON); empty != "[]\n" {
+		t.Errorf("Se esperaba un array vacío, pero se obtuvo: %q", empty)
+	}
+}
+
+// TestParseFormat verifica los alias y el error de campo
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]citation.Format{"BibTeX": citation.FormatBibTeX, "bib": citation.FormatBibTeX, "ris": citation.FormatRIS, "csl": citation.FormatCSLJSON} {
+		if got, err := citation.ParseFormat(name); err != nil || got != want {
+			t.Errorf("Se esperaba %q para %q, pero se obtuvo: %q (%v)", want, name, got, err)
+		}
+	}
+	var domainErr *domain.Error
+	if _, err := citation.ParseFormat("apa"); !errors.As(err, &domainErr) || len(domainErr.Fields) != 1 {
+		t.Errorf("Se esperaba un error de campo, pero se obtuvo: %v", err)
+	}
+}
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// bookCommands son los subcomandos de "bookctl books"
+var bookCommands = map[string]command{
+	"list":   {"books list [--title T] [--author A] [--sort campo] [--desc] [--all]", "Listar libros (una página, o todos con --all)", booksList},
+	"get":    {"books get <id>", "Ver un libro", booksGet},
+	"create": {"books create --title T --author A [--isbn I] [--year N] ...", "Crear un libro", booksCreate},
+	"update": {"books update <id> [--title T] [--isbn I] [--year N] ...", "Cambiar SOLO los campos indicados", booksUpdate},
+	"delete": {"books delete <id>", "Eliminar un libro", booksDelete},
+	"import": {"books import <archivo> [--map col=campo,...] [--dry-run] [--upsert] [--report rechazos.csv]", "Importar libros desde un CSV, NDJSON o MARC21", booksImport},
+}
+
+// bookColumns son las columnas de un libro (los encabezados son los nombres del JSON)
+var bookColumns = []column[*domain.Book]{
+	{"id", func(b *domain.Book) string { return b.ID }, true},
+	{"title", func(b *domain.Book) string { return b.Title }, true},
+	{"author", func(b *domain.Book) string { return b.Author }, true},
+	{"isbn", func(b *domain.Book) string { return b.ISBN }, true},
+	{"publisher", func(b *domain.Book) string { return b.Publisher }, false},
+	{"publication_year", func(b *domain.Book) string { return formatInt(b.PublicationYear) }, true},
+	{"language", func(b *domain.Book) string { return b.Language }, false},
+	{"page_count", func(b *domain.Book) string { return formatInt(b.PageCount) }, false},
+	{"edition", func(b *domain.Book) string { return b.Edition }, false},
+	{"subjects", func(b *domain.Book) string { return strings.Join(b.Subjects, ", ") }, false},
+	{"description", func(b *domain.Book) string { return b.Description }, false},
+	{"available_copies", func(b *domain.Book) string {
+		if b.AvailableCopies == nil {
+			return ""
+		}
+		return strconv.Itoa(*b.AvailableCopies)
+	}, false},
+	{"created_at", func(b *domain.Book) string { return b.CreatedAt.Format(time.RFC3339) }, false},
+}
+
+// booksList: bookctl books list
+func booksList(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books list")
+	var page pageFlags
+	page.register(fs, "title, author, created_at")
+	var filter domain.BookFilter
+	fs.StringVar(&filter.Title, "title", "", "parte del título")
+	fs.StringVar(&filter.Author, "author", "", "parte del autor")
+	fs.StringVar(&filter.AuthorID, "author-id", "", "ID de un autor vinculado")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return listPages(r, page, bookColumns, func(p domain.PageRequest) (*domain.Page[*domain.Book], error) {
+		return client.ListBooks(ctx, domain.BookQuery{PageRequest: p, Filter: filter})
+	})
+}
+
+// booksGet: bookctl books get <id>
+func booksGet(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books get")
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	book, err := client.GetBook(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, book, bookColumns)
+}
+
+// booksCreate: bookctl books create --title T --author A ...
+func booksCreate(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books create")
+	fields := newBookFlags(fs)
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var in usecase.BookInput
+	fields.apply(&in)
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	book, err := client.CreateBook(ctx, in)
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, book, bookColumns)
+}
+
+// booksUpdate: bookctl books update <id> --campo valor ...
+//
+// ✏️ La API reemplaza el libro entero (PUT); este comando lee el libro actual
+// y cambia solo los flags indicados: "--year 2018" no borra el resto de los datos
+// 🔢 Guarda con la versión leída: si alguien lo editó en el medio, falla en vez de pisarlo
+func booksUpdate(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books update")
+	fields := newBookFlags(fs)
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+	if !fields.changed() {
+		return usageErrorf("books update: indica al menos un campo a cambiar (ej: --year 2018)")
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := client.GetBook(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	in := bookInputFrom(current)
+	fields.apply(&in)
+
+	book, err := client.UpdateBook(ctx, ids[0], in, current.Version)
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, book, bookColumns)
+}
+
+// booksDelete: bookctl books delete <id>
+func booksDelete(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books delete")
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteBook(ctx, ids[0]); err != nil {
+		return err
+	}
+	r.notify("🗑️ Libro %s eliminado", ids[0])
+	return nil
+}
+
+// rejectionColumns son las columnas de las filas rechazadas por "books import"
+var rejectionColumns = []column[domain.ImportRejection]{
+	{"line", func(r domain.ImportRejection) string { return strconv.Itoa(r.Line) }, true},
+	{"reason", func(r domain.ImportRejection) string { return r.Reason }, true},
+	{"errors", func(r domain.ImportRejection) string {
+		fields := make([]string, len(r.Errors))
+		for i, f := range r.Errors {
+			fields[i] = f.Field + ": " + f.Message
+		}
+		return strings.Join(fields, "; ")
+	}, true},
+	{"record", func(r domain.ImportRejection) string { return r.Record }, false},
+}
+
+// booksImport: bookctl books import <archivo>
+//
+// 📥 Cada fila pasa por las mismas validaciones que "books create"; las que fallan
+// se listan (stdout) y el resumen va a stderr. Con -o json, el reporte completo
+//
+// 💡 Termina con código 1 si se rechazó alguna fila: un script (o un dry run en CI)
+// puede cortar ahí sin leer el reporte
+func booksImport(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("books import")
+	formatName := fs.String("format", "", "formato del archivo: csv, ndjson, marc o marcxml (por defecto, según la extensión)")
+	mapping := fs.String("map", "", "columnas con otro nombre: titulo=title,autor=author")
+	dryRun := fs.Bool("dry-run", false, "validar todo sin guardar nada")
+	upsert := fs.Bool("upsert", false, "si el ISBN ya existe, actualizar ese libro (las celdas vacías no cambian)")
+	reportPath := fs.String("report", "", "guardar las filas rechazadas en este CSV")
+	paths, err := parseFlags(fs, args, "archivo")
+	if err != nil {
+		return err
+	}
+	// Flags inválidos antes de abrir nada: es un error de uso, no del archivo
+	if _, err := tabular.ResolveFormat(*formatName, paths[0]); err != nil {
+		return usageErrorf("books import: %v", err)
+	}
+	if _, err := tabular.ParseMapping(*mapping); err != nil {
+		return usageErrorf("books import: --map: %v", err)
+	}
+
+	file, err := os.Open(paths[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := client.ImportBooks(ctx, ImportRequest{
+		File: file, Filename: paths[0], Format: *formatName, Mapping: *mapping, DryRun: *dryRun, Upsert: *upsert,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *reportPath != "" {
+		if err := writeRejections(*reportPath, report.Rejections); err != nil {
+			return err
+		}
+	}
+	if r.format == formatJSON {
+		err = printOne(r.env.Stdout, r.format, report, nil)
+	} else if report.Rejected > 0 || r.format == formatCSV {
+		out := newListWriter(r.env.Stdout, r.format, rejectionColumns)
+		if err = out.write(report.Rejections); err == nil {
+			err = out.close()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("📥 %d filas: %d creadas, %d actualizadas, %d rechazadas", report.Total, report.Created, report.Updated, report.Rejected)
+	if report.DryRun {
+		summary += " (dry run: no se guardó nada)"
+	}
+	r.notify("%s", summary)
+	if report.RejectionsTruncated {
+		r.notify("⚠️ El reporte detalla solo las primeras %d filas rechazadas", len(report.Rejections))
+	}
+	if report.Rejected > 0 {
+		return fmt.Errorf("se rechazaron %d filas", report.Rejected)
+	}
+	return nil
+}
+
+// writeRejections guarda el reporte de filas rechazadas en un CSV
+func writeRejections(path string, rejections []domain.ImportRejection) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tabular.WriteRejections(file, rejections); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// bookFlags son los campos de un libro como flags (create y update)
+type bookFlags struct {
+	fs                                                                       *flag.FlagSet
+	title, author, isbn, publisher, language, description, edition, subjects string
+	year, pages                                                              int
+}
+
+// newBookFlags registra los campos del libro en el FlagSet
+func newBookFlags(fs *flag.FlagSet) *bookFlags {
+	b := &bookFlags{fs: fs}
+	fs.StringVar(&b.title, "title", "", "título")
+	fs.StringVar(&b.author, "author", "", "autor (créditos)")
+	fs.StringVar(&b.isbn, "isbn", "", "ISBN-10 o ISBN-13, con o sin guiones")
+	fs.StringVar(&b.publisher, "publisher", "", "editorial")
+	fs.IntVar(&b.year, "year", 0, "año de publicación")
+	fs.StringVar(&b.language, "language", "", "código de idioma ISO 639 (es, en, pt-BR)")
+	fs.IntVar(&b.pages, "pages", 0, "cantidad de páginas")
+	fs.StringVar(&b.description, "description", "", "sinopsis")
+	fs.StringVar(&b.subjects, "subjects", "", "materias separadas por coma")
+	fs.StringVar(&b.edition, "edition", "", "edición")
+	return b
+}
+
+// changed informa si se pasó algún campo del libro
+func (b *bookFlags) changed() bool {
+	changed := false
+	b.fs.Visit(func(f *flag.Flag) {
+		if f.Name != "o" && f.Name != "output" {
+			changed = true
+		}
+	})
+	return changed
+}
+
+// apply copia en in los campos que se pasaron como flag
+// 💡 Un flag vacío (--isbn "") borra el campo: es la forma de quitar un dato
+func (b *bookFlags) apply(in *usecase.BookInput) {
+	b.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "title":
+			in.Title = b.title
+		case "author":
+			in.Author = b.author
+		case "isbn":
+			in.ISBN = b.isbn
+		case "publisher":
+			in.Publisher = b.publisher
+		case "year":
+			in.PublicationYear = b.year
+		case "language":
+			in.Language = b.language
+		case "pages":
+			in.PageCount = b.pages
+		case "description":
+			in.Description = b.description
+		case "subjects":
+			in.Subjects = splitList(b.subjects)
+		case "edition":
+			in.Edition = b.edition
+		}
+	})
+}
+
+// bookInputFrom arma la entrada del caso de uso con los datos actuales del libro
+func bookInputFrom(book *domain.Book) usecase.BookInput {
+	return usecase.BookInput{
+		Title:           book.Title,
+		Author:          book.Author,
+		ISBN:            book.ISBN,
+		Publisher:       book.Publisher,
+		PublicationYear: book.PublicationYear,
+		Language:        book.Language,
+		PageCount:       book.PageCount,
+		Description:     book.Description,
+		Subjects:        book.Subjects,
+		Edition:         book.Edition,
+		Authors:         book.Authors,
+	}
+}
+
+// splitList separa "a, b,c" en ["a", "b", "c"] (sin elementos vacíos)
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatInt muestra un entero opcional (0 = sin dato, celda vacía)
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
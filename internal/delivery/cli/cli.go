@@ -0,0 +1,345 @@
+// Package cli es la línea de comandos de administración (bookctl)
+//
+// 🎯 Otra capa de delivery sobre los MISMOS casos de uso, pensada para operaciones:
+// correcciones masivas, scripts y tareas puntuales sin armar peticiones con curl
+//
+//	bookctl books list --author martin -o csv > libros.csv
+//	bookctl --remote https://biblioteca.example.com --api-key bk_... books update <id> --year 2018
+//
+// 🔌 Dos modos de conexión (ver Client):
+//   - Directo (por defecto): abre el almacenamiento con la misma configuración que el
+//     servidor (STORAGE_DRIVER, DATABASE_URL, ...) y llama a los casos de uso
+//   - Remoto (--remote URL): habla con la API HTTP, con un access token o una API key
+//
+// 📁 Estructura:
+//   - cli.go      Flags globales, despacho de subcomandos y errores
+//   - client.go   La interfaz Client y el modo directo
+//   - remote.go   El modo remoto (cliente de la API REST)
+//   - books.go, users.go   Los subcomandos
+//   - output.go   Formatos de salida: table, json y csv
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// Códigos de salida del proceso
+//
+// 💡 Un script distingue "lo pedí mal" (2) de "el sistema dijo que no" (1)
+const (
+	ExitOK    = 0
+	ExitError = 1 // La operación falló (validación, no encontrado, conflicto, sin conexión...)
+	ExitUsage = 2 // Comando, flags o argumentos inválidos
+)
+
+// Variables de entorno equivalentes a los flags globales
+// 🔐 Así el token o la API key no quedan en el historial de la shell
+const (
+	EnvRemote = "BOOKCTL_REMOTE"
+	EnvToken  = "BOOKCTL_TOKEN"
+	EnvAPIKey = "BOOKCTL_API_KEY"
+)
+
+// Env es lo que el comando toma del proceso
+//
+// 🧪 Todo inyectable: los tests ejecutan bookctl sin tocar os.Stdout ni la red
+type Env struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	Getenv func(string) string
+
+	// Direct abre el modo directo; lo arma cmd/bookctl, que es el composition root.
+	// Retorna también la función que libera el almacenamiento
+	Direct func(ctx context.Context) (Client, func(), error)
+
+	// HTTPClient es el cliente del modo remoto (nil = uno con timeout por petición)
+	HTTPClient *http.Client
+}
+
+// command es un subcomando: "books list", "users role", ...
+type command struct {
+	usage   string // Sintaxis, para la ayuda
+	summary string // Qué hace, en una línea
+	run     func(ctx context.Context, r *runner, args []string) error
+}
+
+// commands son los subcomandos agrupados por recurso
+var commands = map[string]map[string]command{
+	"books": bookCommands,
+	"users": userCommands,
+}
+
+// runner es el estado de una ejecución: salida elegida y conexión (que se abre al usarla)
+type runner struct {
+	env     Env
+	format  format
+	connect func(ctx context.Context) (Client, func(), error)
+	client  Client
+	close   func()
+}
+
+// Run ejecuta bookctl con los argumentos indicados (sin el nombre del programa)
+// y retorna el código de salida
+func Run(ctx context.Context, args []string, env Env) int {
+	r := &runner{env: env, format: formatTable}
+
+	global := flag.NewFlagSet("bookctl", flag.ContinueOnError)
+	global.SetOutput(env.Stderr)
+	global.Usage = func() { fmt.Fprint(env.Stderr, usage()) }
+	remote := global.String("remote", env.Getenv(EnvRemote), "URL de la API (modo remoto); sin ella, modo directo")
+	token := global.String("token", env.Getenv(EnvToken), "access token para el modo remoto")
+	apiKey := global.String("api-key", env.Getenv(EnvAPIKey), "API key para el modo remoto")
+	r.format.register(global)
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage // El paquete flag ya mostró el error y la ayuda
+	}
+	rest := global.Args()
+	if len(rest) == 0 {
+		global.Usage()
+		return ExitUsage
+	}
+	group, ok := commands[rest[0]]
+	if !ok || len(rest) < 2 {
+		return r.report(usageErrorf("comando desconocido: %s\n\n%s", strings.Join(rest, " "), usage()))
+	}
+	cmd, ok := group[rest[1]]
+	if !ok {
+		return r.report(usageErrorf("comando desconocido: %s %s\n\n%s", rest[0], rest[1], usage()))
+	}
+
+	if *remote != "" {
+		client := NewRemoteClient(*remote, Credentials{Token: *token, APIKey: *apiKey}, env.HTTPClient)
+		r.connect = func(context.Context) (Client, func(), error) { return client, func() {}, nil }
+	} else {
+		r.connect = env.Direct
+	}
+	defer r.disconnect()
+
+	return r.report(cmd.run(ctx, r, rest[2:]))
+}
+
+// conn abre la conexión la primera vez que un comando la pide
+//
+// 💡 Perezosa a propósito: un flag mal escrito se informa sin abrir la base de datos
+func (r *runner) conn(ctx context.Context) (Client, error) {
+	if r.client != nil {
+		return r.client, nil
+	}
+	if r.connect == nil {
+		return nil, errors.New("el modo directo no está disponible: usa --remote")
+	}
+	client, closeFn, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.client, r.close = client, closeFn
+	return client, nil
+}
+
+// disconnect libera la conexión si se abrió
+func (r *runner) disconnect() {
+	if r.close != nil {
+		r.close()
+	}
+}
+
+// notify escribe un mensaje para la persona (stderr: stdout queda limpio para los datos)
+func (r *runner) notify(format string, args ...interface{}) {
+	fmt.Fprintf(r.env.Stderr, format+"\n", args...)
+}
+
+// flagSet crea los flags de un subcomando; -o también se acepta después del subcomando
+func (r *runner) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.env.Stderr)
+	r.format.register(fs)
+	return fs
+}
+
+// errUsageShown indica que el paquete flag ya explicó el error de uso
+var errUsageShown = errors.New("uso incorrecto")
+
+// usageError es un comando mal invocado (código de salida 2)
+type usageError struct{ message string }
+
+func (e *usageError) Error() string { return e.message }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// parseFlags lee los flags de un subcomando y exige exactamente los argumentos posicionales indicados
+//
+// 🔀 Los flags pueden ir antes o después de los argumentos:
+// "books update <id> --year 2018" y "books update --year 2018 <id>" son lo mismo
+func parseFlags(fs *flag.FlagSet, args []string, positional ...string) ([]string, error) {
+	var values []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsageShown
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		values = append(values, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(values) != len(positional) {
+		want := "ningún argumento"
+		if len(positional) > 0 {
+			want = "<" + strings.Join(positional, "> <") + ">"
+		}
+		return nil, usageErrorf("%s espera %s, pero recibió %d", fs.Name(), want, len(values))
+	}
+	return values, nil
+}
+
+// report escribe el error (si lo hay) y retorna el código de salida
+//
+// 📋 Los errores de validación muestran el detalle por campo, como la API:
+//
+//	❌ los datos enviados no son válidos
+//	   - isbn: el ISBN no es válido
+func (r *runner) report(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if errors.Is(err, errUsageShown) {
+		return ExitUsage
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		r.notify("❌ %s", usageErr.message)
+		return ExitUsage
+	}
+
+	r.notify("❌ %v", err)
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		for _, field := range domainErr.Fields {
+			r.notify("   - %s: %s", field.Field, field.Message)
+		}
+	}
+	return ExitError
+}
+
+// usage arma la ayuda general a partir de la tabla de subcomandos
+func usage() string {
+	var b strings.Builder
+	b.WriteString(`bookctl: administración de la biblioteca desde la línea de comandos
+
+Uso:
+  bookctl [flags globales] <recurso> <acción> [argumentos] [flags]
+
+Flags globales:
+  --remote URL     Usar la API HTTP en lugar del almacenamiento (o ` + EnvRemote + `)
+  --token T        Access token para --remote (o ` + EnvToken + `)
+  --api-key K      API key para --remote (o ` + EnvAPIKey + `)
+  -o, --output F   Formato de salida: table (por defecto), json o csv
+
+Sin --remote, bookctl abre el almacenamiento con las mismas variables que el
+servidor (STORAGE_DRIVER, DATABASE_URL, ...) y actúa con permisos de admin.
+
+Comandos:
+`)
+	groups := make([]string, 0, len(commands))
+	for name := range commands {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	table := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	for _, name := range groups {
+		actions := make([]string, 0, len(commands[name]))
+		for action := range commands[name] {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			cmd := commands[name][action]
+			fmt.Fprintf(table, "  %s\t%s\n", cmd.usage, cmd.summary)
+		}
+	}
+	table.Flush()
+	b.WriteString("\nCada comando acepta -h para ver sus flags.\n")
+	return b.String()
+}
+
+// pageFlags son los flags de paginación comunes a los listados
+type pageFlags struct {
+	limit  int
+	offset int
+	cursor string
+	sort   string
+	desc   bool
+	all    bool
+}
+
+// register agrega los flags de paginación al FlagSet
+func (p *pageFlags) register(fs *flag.FlagSet, sortFields string) {
+	fs.IntVar(&p.limit, "limit", 0, fmt.Sprintf("elementos por página (por defecto %d, máximo %d)", usecase.DefaultPageLimit, usecase.MaxPageLimit))
+	fs.IntVar(&p.offset, "offset", 0, "elementos a saltar")
+	fs.StringVar(&p.cursor, "cursor", "", "cursor de la página siguiente (lo informa el listado anterior)")
+	fs.StringVar(&p.sort, "sort", "", "campo de orden: "+sortFields)
+	fs.BoolVar(&p.desc, "desc", false, "orden descendente")
+	fs.BoolVar(&p.all, "all", false, "recorrer TODAS las páginas")
+}
+
+// request traduce los flags a domain.PageRequest (los valida el caso de uso)
+func (p *pageFlags) request() domain.PageRequest {
+	req := domain.PageRequest{Limit: p.limit, Offset: p.offset, Cursor: p.cursor, Sort: p.sort, Desc: p.desc}
+	if p.all && req.Limit == 0 {
+		req.Limit = usecase.MaxPageLimit // Menos viajes: páginas lo más grandes posible
+	}
+	return req
+}
+
+// listPages escribe una página del listado o, con --all, todas siguiendo el cursor
+//
+// 💡 El cursor lleva el límite, el orden y los filtros: las páginas siguientes
+// se piden solo con él, igual que un cliente de la API
+func listPages[T any](r *runner, page pageFlags, columns []column[T], fetch func(domain.PageRequest) (*domain.Page[T], error)) error {
+	out := newListWriter(r.env.Stdout, r.format, columns)
+	req := page.request()
+	for {
+		result, err := fetch(req)
+		if err != nil {
+			return err
+		}
+		if err := out.write(result.Items); err != nil {
+			return err
+		}
+
+		if page.all && result.NextCursor != "" {
+			req = domain.PageRequest{Cursor: result.NextCursor}
+			continue
+		}
+		if err := out.close(); err != nil {
+			return err
+		}
+		if !page.all && result.NextCursor != "" {
+			r.notify("📄 %d-%d de %d · página siguiente: --cursor %s (o --all para todas)",
+				result.Offset+1, result.Offset+len(result.Items), result.Total, result.NextCursor)
+		}
+		return nil
+	}
+}
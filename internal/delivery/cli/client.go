@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"io"
+
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// Client es lo que bookctl necesita del sistema, venga de donde venga
+//
+// 🔌 Dos implementaciones:
+//   - DirectClient: llama a los casos de uso (misma base de datos que el servidor)
+//   - RemoteClient: llama a la API HTTP
+//
+// 💡 Los comandos solo conocen esta interfaz: el mismo "books update" funciona
+// igual en los dos modos, con las mismas validaciones y los mismos errores
+// 🔢 version en los Update es la que se leyó (0 = sin verificar, ver domain.Book)
+type Client interface {
+	ListBooks(ctx context.Context, q domain.BookQuery) (*domain.Page[*domain.Book], error)
+	GetBook(ctx context.Context, id string) (*domain.Book, error)
+	CreateBook(ctx context.Context, in usecase.BookInput) (*domain.Book, error)
+	UpdateBook(ctx context.Context, id string, in usecase.BookInput, version int) (*domain.Book, error)
+	DeleteBook(ctx context.Context, id string) error
+	ImportBooks(ctx context.Context, req ImportRequest) (*domain.ImportReport, error)
+
+	ListUsers(ctx context.Context, q domain.UserQuery) (*domain.Page[*domain.User], error)
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	CreateUser(ctx context.Context, name, email, password string) (*domain.User, error)
+	UpdateUser(ctx context.Context, id, name, email string, version int) (*domain.User, error)
+	ChangeUserRole(ctx context.Context, id string, role domain.Role) (*domain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+}
+
+// ImportRequest es un archivo a importar y sus opciones (ver "books import")
+type ImportRequest struct {
+	File     io.Reader // Se lee de a una fila (directo) o se sube en streaming (remoto)
+	Filename string    // Para deducir el formato por la extensión
+	Format   string    // csv | ndjson | marc | marcxml ("" = según la extensión)
+	Mapping  string    // Columnas con otro nombre: "titulo=title,autor=author"
+	DryRun   bool
+	Upsert   bool
+}
+
+// Operator es el usuario con el que actúa el modo directo
+//
+// 🔐 Quien tiene las credenciales de la base de datos ya puede hacer cualquier cosa:
+// el modo directo actúa como admin, pero pasando por los casos de uso
+// (validaciones, normalización del ISBN, conflictos) en lugar de escribir SQL a mano
+var Operator = &domain.User{ID: "bookctl", Name: "bookctl", Role: domain.RoleAdmin}
+
+// DirectClient implementa Client llamando a los casos de uso
+type DirectClient struct {
+	books *usecase.BookUseCase
+	users *usecase.UserUseCase
+}
+
+// NewDirectClient constructor para DirectClient
+func NewDirectClient(books *usecase.BookUseCase, users *usecase.UserUseCase) *DirectClient {
+	return &DirectClient{books: books, users: users}
+}
+
+// as agrega el operador al context, como hace RequireAuth con el usuario del token
+func (c *DirectClient) as(ctx context.Context) context.Context {
+	return usecase.ContextWithUser(ctx, Operator)
+}
+
+func (c *DirectClient) ListBooks(ctx context.Context, q domain.BookQuery) (*domain.Page[*domain.Book], error) {
+	return c.books.ListBooks(c.as(ctx), q)
+}
+
+func (c *DirectClient) GetBook(ctx context.Context, id string) (*domain.Book, error) {
+	return c.books.GetBookByID(c.as(ctx), id)
+}
+
+func (c *DirectClient) CreateBook(ctx context.Context, in usecase.BookInput) (*domain.Book, error) {
+	return c.books.CreateBook(c.as(ctx), in)
+}
+
+func (c *DirectClient) UpdateBook(ctx context.Context, id string, in usecase.BookInput, version int) (*domain.Book, error) {
+	return c.books.UpdateBook(c.as(ctx), id, in, version)
+}
+
+func (c *DirectClient) DeleteBook(ctx context.Context, id string) error {
+	return c.books.DeleteBook(c.as(ctx), id, 0)
+}
+
+// ImportBooks lee el archivo acá mismo, con el mismo lector que usa la API
+func (c *DirectClient) ImportBooks(ctx context.Context, req ImportRequest) (*domain.ImportReport, error) {
+	format, err := tabular.ResolveFormat(req.Format, req.Filename)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := tabular.ParseMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tabular.NewBookReader(req.File, format, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return c.books.ImportBooks(c.as(ctx), rows, usecase.BookImportOptions{DryRun: req.DryRun, Upsert: req.Upsert})
+}
+
+func (c *DirectClient) ListUsers(ctx context.Context, q domain.UserQuery) (*domain.Page[*domain.User], error) {
+	return c.users.ListUsers(c.as(ctx), q)
+}
+
+func (c *DirectClient) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	return c.users.GetUserByID(c.as(ctx), id)
+}
+
+func (c *DirectClient) CreateUser(ctx context.Context, name, email, password string) (*domain.User, error) {
+	return c.users.CreateUser(c.as(ctx), name, email, password)
+}
+
+func (c *DirectClient) UpdateUser(ctx context.Context, id, name, email string, version int) (*domain.User, error) {
+	return c.users.UpdateUser(c.as(ctx), id, name, email, version)
+}
+
+func (c *DirectClient) ChangeUserRole(ctx context.Context, id string, role domain.Role) (*domain.User, error) {
+	return c.users.ChangeUserRole(c.as(ctx), id, role)
+}
+
+func (c *DirectClient) DeleteUser(ctx context.Context, id string) error {
+	return c.users.DeleteUser(c.as(ctx), id, 0)
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// format es el formato de salida elegido con -o
+//
+// 📋 Formatos:
+//   - table: columnas alineadas, para leer en la terminal
+//   - json:  un objeto (get, create, update) o un array (list), para jq y scripts
+//   - csv:   con encabezado y TODAS las columnas, para planillas
+type format string
+
+const (
+	formatTable format = "table"
+	formatJSON  format = "json"
+	formatCSV   format = "csv"
+)
+
+func (f *format) String() string { return string(*f) }
+
+// Set valida el valor de -o (flag.Value)
+func (f *format) Set(value string) error {
+	switch v := format(strings.ToLower(value)); v {
+	case formatTable, formatJSON, formatCSV:
+		*f = v
+		return nil
+	}
+	return fmt.Errorf("formato desconocido %q (usa table, json o csv)", value)
+}
+
+// register agrega -o y --output al FlagSet
+func (f *format) register(fs *flag.FlagSet) {
+	fs.Var(f, "o", "formato de salida: table, json o csv")
+	fs.Var(f, "output", "formato de salida: table, json o csv")
+}
+
+// column es una columna de la salida: encabezado y cómo obtener el valor
+type column[T any] struct {
+	header string
+	value  func(T) string
+	table  bool // También en la tabla de los listados (csv y get muestran todas)
+}
+
+// listWriter escribe un listado a medida que llegan las páginas
+//
+// 🌊 Con --all se pueden listar miles de elementos: cada página se escribe
+// al llegar, sin juntar todo en memoria (salvo la tabla, que necesita
+// ver todas las filas para alinear las columnas)
+type listWriter[T any] struct {
+	w       io.Writer
+	format  format
+	columns []column[T]
+	csv     *csv.Writer
+	table   *tabwriter.Writer
+	started bool // Ya se escribió el encabezado
+	count   int
+}
+
+// newListWriter crea el escritor de un listado en el formato elegido
+func newListWriter[T any](w io.Writer, f format, columns []column[T]) *listWriter[T] {
+	l := &listWriter[T]{w: w, format: f, columns: columns}
+	switch f {
+	case formatCSV:
+		l.csv = csv.NewWriter(w)
+	case formatTable:
+		l.columns = nil
+		for _, col := range columns {
+			if col.table {
+				l.columns = append(l.columns, col)
+			}
+		}
+		l.table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	}
+	return l
+}
+
+// write agrega elementos al listado (la primera llamada escribe el encabezado)
+func (l *listWriter[T]) write(items []T) error {
+	if err := l.header(); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		var err error
+		switch l.format {
+		case formatJSON:
+			err = l.writeJSON(item)
+		case formatCSV:
+			err = l.csv.Write(l.row(item))
+		default:
+			_, err = fmt.Fprintln(l.table, strings.Join(l.row(item), "\t"))
+		}
+		if err != nil {
+			return err
+		}
+		l.count++
+	}
+
+	if l.csv != nil {
+		l.csv.Flush()
+		return l.csv.Error()
+	}
+	return nil
+}
+
+// close termina el listado (cierra el array JSON, alinea la tabla)
+func (l *listWriter[T]) close() error {
+	if err := l.header(); err != nil {
+		return err
+	}
+
+	switch l.format {
+	case formatJSON:
+		closing := "\n]\n"
+		if l.count == 0 {
+			closing = "]\n"
+		}
+		_, err := io.WriteString(l.w, closing)
+		return err
+	case formatCSV:
+		l.csv.Flush()
+		return l.csv.Error()
+	default:
+		return l.table.Flush()
+	}
+}
+
+// header escribe lo que va antes del primer elemento (una sola vez)
+func (l *listWriter[T]) header() error {
+	if l.started {
+		return nil
+	}
+	l.started = true
+
+	headers := make([]string, len(l.columns))
+	for i, col := range l.columns {
+		headers[i] = col.header
+	}
+
+	switch l.format {
+	case formatJSON:
+		_, err := io.WriteString(l.w, "[")
+		return err
+	case formatCSV:
+		return l.csv.Write(headers)
+	default:
+		_, err := fmt.Fprintln(l.table, strings.ToUpper(strings.Join(headers, "\t")))
+		return err
+	}
+}
+
+// writeJSON escribe un elemento del array, indentado como el resto de la salida JSON
+func (l *listWriter[T]) writeJSON(item T) error {
+	data, err := json.MarshalIndent(item, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	separator := ",\n  "
+	if l.count == 0 {
+		separator = "\n  "
+	}
+	_, err = fmt.Fprintf(l.w, "%s%s", separator, data)
+	return err
+}
+
+// row son los valores de un elemento, en el orden de las columnas
+func (l *listWriter[T]) row(item T) []string {
+	values := make([]string, len(l.columns))
+	for i, col := range l.columns {
+		values[i] = col.value(item)
+	}
+	return values
+}
+
+// printOne escribe un solo elemento (get, create, update)
+//
+// 📋 En tabla se muestra en vertical, un campo por línea:
+//
+//	id        0b5c…
+//	title     Clean Architecture
+func printOne[T any](w io.Writer, f format, item T, columns []column[T]) error {
+	switch f {
+	case formatJSON:
+		data, err := json.MarshalIndent(item, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case formatCSV:
+		list := newListWriter(w, f, columns)
+		if err := list.write([]T{item}); err != nil {
+			return err
+		}
+		return list.close()
+	default:
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, col := range columns {
+			if value := col.value(item); value != "" {
+				fmt.Fprintf(table, "%s\t%s\n", col.header, value)
+			}
+		}
+		return table.Flush()
+	}
+}
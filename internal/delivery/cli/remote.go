@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// remoteTimeout es el límite de cada petición del modo remoto
+const remoteTimeout = 30 * time.Second
+
+// Credentials son las credenciales del modo remoto (una de las dos)
+type Credentials struct {
+	Token  string // Access token: Authorization: Bearer <token>
+	APIKey string // API key: X-API-Key (tiene prioridad, como en el servidor)
+}
+
+// RemoteClient implementa Client sobre la API REST
+//
+// 💡 Usa los MISMOS tipos de la capa HTTP (CreateBookRequest, PageResponse, Problem):
+// si el contrato cambia, este cliente deja de compilar en lugar de fallar en producción
+type RemoteClient struct {
+	baseURL string
+	creds   Credentials
+	http    *http.Client
+	uploads *http.Client // Sin remoteTimeout: subir un catálogo grande tarda lo que tarde
+}
+
+// NewRemoteClient constructor para RemoteClient (httpClient nil = uno con remoteTimeout)
+func NewRemoteClient(baseURL string, creds Credentials, httpClient *http.Client) *RemoteClient {
+	uploads := httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: remoteTimeout}
+		uploads = &http.Client{} // Ctrl+C cancela el context y corta la subida
+	}
+	return &RemoteClient{baseURL: strings.TrimRight(baseURL, "/"), creds: creds, http: httpClient, uploads: uploads}
+}
+
+func (c *RemoteClient) ListBooks(ctx context.Context, q domain.BookQuery) (*domain.Page[*domain.Book], error) {
+	params := pageParams(q.PageRequest)
+	setParam(params, "title", q.Filter.Title)
+	setParam(params, "author", q.Filter.Author)
+	setParam(params, "author_id", q.Filter.AuthorID)
+
+	var resp api.PageResponse[*domain.Book]
+	if err := c.do(ctx, http.MethodGet, "/api/books?"+params.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return fromPageResponse(resp), nil
+}
+
+func (c *RemoteClient) GetBook(ctx context.Context, id string) (*domain.Book, error) {
+	return request[domain.Book](ctx, c, http.MethodGet, "/api/books/"+url.PathEscape(id), nil)
+}
+
+func (c *RemoteClient) CreateBook(ctx context.Context, in usecase.BookInput) (*domain.Book, error) {
+	return request[domain.Book](ctx, c, http.MethodPost, "/api/books", bookRequest(in))
+}
+
+func (c *RemoteClient) UpdateBook(ctx context.Context, id string, in usecase.BookInput, version int) (*domain.Book, error) {
+	return requestIfMatch[domain.Book](ctx, c, http.MethodPut, "/api/books/"+url.PathEscape(id), version, api.UpdateBookRequest(bookRequest(in)))
+}
+
+func (c *RemoteClient) DeleteBook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/books/"+url.PathEscape(id), nil, nil)
+}
+
+// ImportBooks sube el archivo como multipart a POST /api/books/import
+//
+// 🌊 El cuerpo se arma mientras se envía (io.Pipe): el archivo nunca está entero en memoria
+func (c *RemoteClient) ImportBooks(ctx context.Context, in ImportRequest) (*domain.ImportReport, error) {
+	params := url.Values{}
+	setParam(params, "format", in.Format)
+	setParam(params, "mapping", in.Mapping)
+	if in.DryRun {
+		params.Set("dry_run", "true")
+	}
+	if in.Upsert {
+		params.Set("upsert", "true")
+	}
+
+	body, pipe := io.Pipe()
+	form := multipart.NewWriter(pipe)
+	go func() {
+		part, err := form.CreateFormFile("file", filepath.Base(in.Filename))
+		if err == nil {
+			_, err = io.Copy(part, in.File)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pipe.CloseWithError(err) // Si la petición falla antes, Close del body corta la copia
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/books/import?"+params.Encode(), body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("URL remota inválida: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var report domain.ImportReport
+	if err := c.send(c.uploads, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *RemoteClient) ListUsers(ctx context.Context, q domain.UserQuery) (*domain.Page[*domain.User], error) {
+	params := pageParams(q.PageRequest)
+	setParam(params, "name", q.Filter.Name)
+	setParam(params, "email", q.Filter.Email)
+
+	var resp api.PageResponse[*domain.User]
+	if err := c.do(ctx, http.MethodGet, "/api/users?"+params.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return fromPageResponse(resp), nil
+}
+
+func (c *RemoteClient) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	return request[domain.User](ctx, c, http.MethodGet, "/api/users/"+url.PathEscape(id), nil)
+}
+
+func (c *RemoteClient) CreateUser(ctx context.Context, name, email, password string) (*domain.User, error) {
+	req := api.CreateUserRequest{Name: name, Email: email, Password: password}
+	return request[domain.User](ctx, c, http.MethodPost, "/api/users", req)
+}
+
+func (c *RemoteClient) UpdateUser(ctx context.Context, id, name, email string, version int) (*domain.User, error) {
+	req := api.UpdateUserRequest{Name: name, Email: email}
+	return requestIfMatch[domain.User](ctx, c, http.MethodPut, "/api/users/"+url.PathEscape(id), version, req)
+}
+
+func (c *RemoteClient) ChangeUserRole(ctx context.Context, id string, role domain.Role) (*domain.User, error) {
+	req := api.ChangeUserRoleRequest{Role: role}
+	return request[domain.User](ctx, c, http.MethodPut, "/api/users/"+url.PathEscape(id)+"/role", req)
+}
+
+func (c *RemoteClient) DeleteUser(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/users/"+url.PathEscape(id), nil, nil)
+}
+
+// request envía una petición y decodifica la respuesta como un T
+func request[T any](ctx context.Context, c *RemoteClient, method, path string, body interface{}) (*T, error) {
+	var out T
+	if err := c.do(ctx, method, path, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// requestIfMatch es request con el header If-Match (version 0 = sin verificar)
+//
+// 🔢 Si otro cambió el recurso desde que se leyó, la API responde 412 y vuelve
+// domain.ErrPreconditionFailed, igual que en el modo directo
+func requestIfMatch[T any](ctx context.Context, c *RemoteClient, method, path string, version int, body interface{}) (*T, error) {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 {
+		req.Header.Set("If-Match", `"`+strconv.Itoa(version)+`"`)
+	}
+	var out T
+	if err := c.send(c.http, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// do envía una petición JSON y decodifica la respuesta en out (nil = sin cuerpo)
+//
+// 🚨 Las respuestas de error (problem+json) vuelven como *domain.Error:
+// los comandos las tratan igual que los errores del modo directo
+func (c *RemoteClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return c.send(c.http, req, out)
+}
+
+// newRequest arma una petición con body JSON (nil = sin cuerpo)
+func (c *RemoteClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("URL remota inválida: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// send agrega las credenciales, envía la petición y decodifica la respuesta en out
+func (c *RemoteClient) send(client *http.Client, req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.creds.APIKey != "":
+		req.Header.Set("X-API-Key", c.creds.APIKey)
+	case c.creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.creds.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("no se pudo conectar con %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return problemError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("respuesta inválida de %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// problemKinds traduce el "code" estable de la API a la categoría del dominio
+// (el camino inverso de classifyError en la capa HTTP)
+var problemKinds = map[string]error{
+	"validation_error": domain.ErrValidation,
+	"unauthorized":     domain.ErrUnauthorized,
+	"forbidden":        domain.ErrForbidden,
+	"not_found":        domain.ErrNotFound,
+	"conflict":         domain.ErrConflict,
+
+	"precondition_failed": domain.ErrPreconditionFailed,
+}
+
+// problemError convierte una respuesta problem+json en un *domain.Error
+func problemError(resp *http.Response) error {
+	var problem api.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil || problem.Code == "" {
+		return fmt.Errorf("el servidor respondió %s", resp.Status)
+	}
+
+	kind, ok := problemKinds[problem.Code]
+	if !ok {
+		kind = domain.ErrInternal
+	}
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+	return &domain.Error{Kind: kind, Message: message, Fields: problem.Errors}
+}
+
+// bookRequest arma el cuerpo JSON de un libro (el camino inverso de toInput en la capa HTTP)
+func bookRequest(in usecase.BookInput) api.CreateBookRequest {
+	var authors []api.BookAuthorRequest
+	for _, a := range in.Authors {
+		authors = append(authors, api.BookAuthorRequest{AuthorID: a.AuthorID, Role: string(a.Role)})
+	}
+	return api.CreateBookRequest{
+		Title:           in.Title,
+		Author:          in.Author,
+		ISBN:            in.ISBN,
+		Publisher:       in.Publisher,
+		PublicationYear: in.PublicationYear,
+		Language:        in.Language,
+		PageCount:       in.PageCount,
+		Description:     in.Description,
+		Subjects:        in.Subjects,
+		Edition:         in.Edition,
+		Authors:         authors,
+	}
+}
+
+// pageParams traduce la paginación a ?limit, ?offset, ?cursor, ?sort y ?order
+func pageParams(p domain.PageRequest) url.Values {
+	params := url.Values{}
+	if p.Limit > 0 {
+		params.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		params.Set("offset", strconv.Itoa(p.Offset))
+	}
+	setParam(params, "cursor", p.Cursor)
+	setParam(params, "sort", p.Sort)
+	if p.Desc {
+		params.Set("order", "desc")
+	}
+	return params
+}
+
+// setParam agrega el parámetro solo si tiene valor
+func setParam(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// fromPageResponse convierte el sobre data/pagination de la API en una domain.Page
+func fromPageResponse[T any](resp api.PageResponse[T]) *domain.Page[T] {
+	return &domain.Page[T]{
+		Items:      resp.Data,
+		Total:      resp.Pagination.Total,
+		Limit:      resp.Pagination.Limit,
+		Offset:     resp.Pagination.Offset,
+		NextCursor: resp.Pagination.NextCursor,
+		PrevCursor: resp.Pagination.PrevCursor,
+	}
+}
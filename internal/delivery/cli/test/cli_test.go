@@ -0,0 +1,264 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/delivery/cli"
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fiberTransport entrega las peticiones del modo remoto a una app de Fiber en memoria
+// 🧪 El cliente HTTP es el real; solo se evita abrir un puerto
+type fiberTransport struct{ app *fiber.App }
+
+func (t fiberTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// app.Test no sabe enviar un cuerpo de largo desconocido (la subida de import): se arma entero
+	if req.Body != nil && req.ContentLength == 0 {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body, req.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+	}
+	return t.app.Test(req, -1)
+}
+
+// newUseCases arma los casos de uso con repositorios en memoria
+func newUseCases() (*usecase.BookUseCase, *usecase.UserUseCase) {
+	copies := memory.NewInMemoryCopyRepository()
+	loans := memory.NewInMemoryLoanRepository(copies)
+	holds := memory.NewInMemoryHoldRepository()
+	fines := memory.NewInMemoryFineRepository()
+	books := usecase.NewBookUseCase(memory.NewInMemoryBookRepository(), memory.NewInMemoryAuthorRepository(), copies, loans, holds, fines)
+	users := usecase.NewUserUseCase(memory.NewInMemoryUserRepository(), loans, holds, fines, security.NewBcryptHasher(config.MinBcryptCost))
+	return books, users
+}
+
+// directEnv es bookctl en modo directo
+func directEnv() cli.Env {
+	books, users := newUseCases()
+	client := cli.NewDirectClient(books, users)
+	return cli.Env{
+		Getenv: func(string) string { return "" },
+		Direct: func(context.Context) (cli.Client, func(), error) { return client, func() {}, nil },
+	}
+}
+
+// remoteEnv es bookctl en modo remoto contra la API HTTP (como un admin autenticado)
+func remoteEnv() cli.Env {
+	books, users := newUseCases()
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler, StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(usecase.ContextWithUser(c.UserContext(), &domain.User{ID: "admin", Role: domain.RoleAdmin}))
+		return c.Next()
+	})
+	userHandler := api.NewUserHandler(users)
+	app.Post("/api/users", userHandler.CreateUser)
+	routes.SetupBookRoutes(app, api.NewBookHandler(books))
+	routes.SetupBookImportRoutes(app, api.NewBookImportHandler(books, time.Minute))
+	routes.SetupUserRoutes(app, userHandler)
+
+	env := map[string]string{cli.EnvRemote: "http://biblioteca.test", cli.EnvToken: "token"}
+	return cli.Env{
+		Getenv:     func(key string) string { return env[key] },
+		HTTPClient: &http.Client{Transport: fiberTransport{app: app}},
+	}
+}
+
+// bookctl ejecuta un comando y retorna el código de salida, stdout y stderr
+func bookctl(env cli.Env, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	env.Stdout, env.Stderr = &stdout, &stderr
+	code := cli.Run(context.Background(), args, env)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestBookctl_BooksCRUD recorre el ciclo de vida de un libro en los dos modos
+func TestBookctl_BooksCRUD(t *testing.T) {
+	for name, newEnv := range map[string]func() cli.Env{"directo": directEnv, "remoto": remoteEnv} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			env := newEnv()
+
+			// Act: crear
+			code, out, errOut := bookctl(env, "-o", "json", "books", "create",
+				"--title", "Clean Architecture", "--author", "Robert C. Martin", "--isbn", "978-0-13-449416-6", "--subjects", "Arquitectura, Software")
+			if code != cli.ExitOK {
+				t.Fatalf("Se esperaba crear el libro, pero se obtuvo: %d %s", code, errOut)
+			}
+			var book domain.Book
+			if err := json.Unmarshal([]byte(out), &book); err != nil || book.ISBN != "9780134494166" || len(book.Subjects) != 2 {
+				t.Fatalf("Se esperaba el libro con el ISBN normalizado, pero se obtuvo: %s (err: %v)", out, err)
+			}
+
+			// Act: actualizar SOLO el año (el flag puede ir después del ID)
+			code, out, errOut = bookctl(env, "books", "update", book.ID, "--year", "2017", "-o", "json")
+			if code != cli.ExitOK {
+				t.Fatalf("Se esperaba actualizar el libro, pero se obtuvo: %d %s", code, errOut)
+			}
+			var updated domain.Book
+			json.Unmarshal([]byte(out), &updated)
+			if updated.PublicationYear != 2017 || updated.ISBN != book.ISBN || updated.Title != book.Title {
+				t.Errorf("Se esperaba cambiar solo el año, pero se obtuvo: %+v", updated)
+			}
+
+			// Act: listar en CSV (encabezado + una fila)
+			code, out, _ = bookctl(env, "books", "list", "--author", "martin", "-o", "csv")
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if code != cli.ExitOK || len(lines) != 2 || !strings.HasPrefix(lines[0], "id,title,author,isbn,") ||
+				!strings.Contains(lines[1], `"Arquitectura, Software"`) {
+				t.Errorf("Se esperaba un CSV con una fila, pero se obtuvo: %d %q", code, out)
+			}
+
+			// Act: eliminar y volver a pedirlo → not found (código 1)
+			if code, _, errOut = bookctl(env, "books", "delete", book.ID); code != cli.ExitOK {
+				t.Fatalf("Se esperaba eliminar el libro, pero se obtuvo: %d %s", code, errOut)
+			}
+			code, _, errOut = bookctl(env, "books", "get", book.ID)
+			if code != cli.ExitError || !strings.Contains(errOut, domain.ErrBookNotFound.Message) {
+				t.Errorf("Se esperaba 'libro no encontrado', pero se obtuvo: %d %s", code, errOut)
+			}
+		})
+	}
+}
+
+// TestBookctl_Errors verifica los errores de validación y los de uso
+func TestBookctl_Errors(t *testing.T) {
+	for name, newEnv := range map[string]func() cli.Env{"directo": directEnv, "remoto": remoteEnv} {
+		t.Run(name, func(t *testing.T) {
+			env := newEnv()
+
+			// Validación: código 1 y el detalle por campo, igual en los dos modos
+			code, out, errOut := bookctl(env, "books", "create", "--title", "Sin autor", "--isbn", "123")
+			if code != cli.ExitError || out != "" ||
+				!strings.Contains(errOut, "- author:") || !strings.Contains(errOut, "- isbn:") {
+				t.Errorf("Se esperaba el detalle de author e isbn, pero se obtuvo: %d %q", code, errOut)
+			}
+
+			// Uso incorrecto: código 2
+			for _, args := range [][]string{
+				{"books"},
+				{"books", "borrar", "x"},
+				{"books", "get"},
+				{"books", "update", "x"}, // Sin campos que cambiar
+				{"-o", "xml", "books", "list"},
+				{"users", "role", "x"},
+			} {
+				if code, _, _ := bookctl(env, args...); code != cli.ExitUsage {
+					t.Errorf("Se esperaba el código %d para %v, pero se obtuvo: %d", cli.ExitUsage, args, code)
+				}
+			}
+		})
+	}
+}
+
+// TestBookctl_ListAll verifica que --all recorre todas las páginas siguiendo el cursor
+func TestBookctl_ListAll(t *testing.T) {
+	for name, newEnv := range map[string]func() cli.Env{"directo": directEnv, "remoto": remoteEnv} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange: más usuarios que una página
+			env := newEnv()
+			total := usecase.MaxPageLimit + 5
+			for i := 0; i < total; i++ {
+				email := fmt.Sprintf("socio%03d@example.com", i)
+				if code, _, errOut := bookctl(env, "users", "create", "--name", "Socio", "--email", email, "--password", "contraseña-segura"); code != cli.ExitOK {
+					t.Fatalf("No se pudo crear el usuario: %s", errOut)
+				}
+			}
+
+			// Act: una página informa el cursor; --all trae todos
+			code, _, errOut := bookctl(env, "users", "list", "--limit", "10")
+			if code != cli.ExitOK || !strings.Contains(errOut, "--cursor") {
+				t.Errorf("Se esperaba el aviso de la página siguiente, pero se obtuvo: %q", errOut)
+			}
+			code, out, _ := bookctl(env, "users", "list", "--all", "-o", "json")
+			var users []domain.User
+			if err := json.Unmarshal([]byte(out), &users); err != nil || code != cli.ExitOK || len(users) != total {
+				t.Errorf("Se esperaban %d usuarios, pero se obtuvieron %d (err: %v)", total, len(users), err)
+			}
+		})
+	}
+}
+
+// TestBookctl_Import importa un CSV con mapping, dry run, upsert y reporte de rechazos
+func TestBookctl_Import(t *testing.T) {
+	for name, newEnv := range map[string]func() cli.Env{"directo": directEnv, "remoto": remoteEnv} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange: columnas en castellano, una fila sin autor y un año que no es número
+			env := newEnv()
+			dir := t.TempDir()
+			file := filepath.Join(dir, "catalogo.csv")
+			csv := "\ufefftitulo,autor,isbn,año\n" +
+				"Clean Code,Robert C. Martin,0132350882,2008\n" +
+				"Sin autor,,,2001\n" +
+				"Refactoring,Martin Fowler,,dos mil\n"
+			if err := os.WriteFile(file, []byte(csv), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			mapping := "titulo=title,autor=author,año=publication_year"
+
+			// Act: dry run → valida pero no guarda
+			code, _, errOut := bookctl(env, "books", "import", file, "--map", mapping, "--dry-run")
+			if code != cli.ExitError || !strings.Contains(errOut, "3 filas: 1 creadas, 0 actualizadas, 2 rechazadas") {
+				t.Fatalf("Se esperaba el resumen del dry run, pero se obtuvo: %d %s", code, errOut)
+			}
+			if _, out, _ := bookctl(env, "books", "list", "-o", "json"); strings.Contains(out, "Clean Code") {
+				t.Fatalf("El dry run no debe guardar nada, pero se obtuvo: %s", out)
+			}
+
+			// Act: importar de verdad, con el reporte de rechazos en un archivo
+			report := filepath.Join(dir, "rechazos.csv")
+			code, out, errOut := bookctl(env, "books", "import", file, "--map", mapping, "--report", report, "-o", "json")
+			var result domain.ImportReport
+			if err := json.Unmarshal([]byte(out), &result); err != nil || code != cli.ExitError || result.Created != 1 || result.Rejected != 2 {
+				t.Fatalf("Se esperaba 1 creado y 2 rechazados, pero se obtuvo: %d %s %s", code, out, errOut)
+			}
+			rejections, _ := os.ReadFile(report)
+			lines := strings.Split(strings.TrimSpace(string(rejections)), "\n")
+			if len(lines) != 3 || lines[0] != "line,reason,errors,record" ||
+				!strings.HasPrefix(lines[1], "3,") || !strings.Contains(lines[2], "publication_year:") {
+				t.Errorf("Se esperaba el CSV con las 2 filas rechazadas, pero se obtuvo: %q", rejections)
+			}
+
+			// Act: reimportar con upsert → el libro existente se actualiza
+			update := filepath.Join(dir, "editoriales.ndjson")
+			os.WriteFile(update, []byte(`{"isbn":"978-0-13-235088-4","publisher":"Prentice Hall"}`+"\n"), 0o600)
+			if code, out, _ = bookctl(env, "books", "import", update); code != cli.ExitError || !strings.Contains(out, domain.ErrISBNAlreadyInUse.Message) {
+				t.Errorf("Sin --upsert se esperaba el ISBN en uso, pero se obtuvo: %d %s", code, out)
+			}
+			code, _, errOut = bookctl(env, "books", "import", update, "--upsert")
+			if code != cli.ExitOK || !strings.Contains(errOut, "1 actualizadas") {
+				t.Errorf("Se esperaba 1 libro actualizado, pero se obtuvo: %d %s", code, errOut)
+			}
+
+			// Uso incorrecto: código 2
+			for _, args := range [][]string{
+				{"books", "import"},
+				{"books", "import", file, "--map", "titulo=titel"},
+				{"books", "import", file, "--format", "pdf"},
+			} {
+				if code, _, _ := bookctl(env, args...); code != cli.ExitUsage {
+					t.Errorf("Se esperaba el código %d para %v, pero se obtuvo: %d", cli.ExitUsage, args, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// userCommands son los subcomandos de "bookctl users"
+var userCommands = map[string]command{
+	"list":   {"users list [--name N] [--email E] [--sort campo] [--desc] [--all]", "Listar usuarios (una página, o todos con --all)", usersList},
+	"get":    {"users get <id>", "Ver un usuario", usersGet},
+	"create": {"users create --name N --email E --password P", "Crear un usuario (socio)", usersCreate},
+	"update": {"users update <id> [--name N] [--email E]", "Cambiar nombre o email", usersUpdate},
+	"role":   {"users role <id> <admin|librarian|member>", "Cambiar el rol", usersRole},
+	"delete": {"users delete <id>", "Eliminar un usuario", usersDelete},
+}
+
+// userColumns son las columnas de un usuario (nunca incluye el hash de la contraseña)
+var userColumns = []column[*domain.User]{
+	{"id", func(u *domain.User) string { return u.ID }, true},
+	{"name", func(u *domain.User) string { return u.Name }, true},
+	{"email", func(u *domain.User) string { return u.Email }, true},
+	{"role", func(u *domain.User) string { return string(u.Role) }, true},
+	{"created_at", func(u *domain.User) string { return u.CreatedAt.Format(time.RFC3339) }, true},
+}
+
+// usersList: bookctl users list
+func usersList(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users list")
+	var page pageFlags
+	page.register(fs, "name, email, created_at")
+	var filter domain.UserFilter
+	fs.StringVar(&filter.Name, "name", "", "parte del nombre")
+	fs.StringVar(&filter.Email, "email", "", "parte del email")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return listPages(r, page, userColumns, func(p domain.PageRequest) (*domain.Page[*domain.User], error) {
+		return client.ListUsers(ctx, domain.UserQuery{PageRequest: p, Filter: filter})
+	})
+}
+
+// usersGet: bookctl users get <id>
+func usersGet(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users get")
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := client.GetUser(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, user, userColumns)
+}
+
+// usersCreate: bookctl users create --name N --email E --password P
+func usersCreate(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users create")
+	name := fs.String("name", "", "nombre")
+	email := fs.String("email", "", "email")
+	password := fs.String("password", "", "contraseña (al menos 8 caracteres)")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := client.CreateUser(ctx, *name, *email, *password)
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, user, userColumns)
+}
+
+// usersUpdate: bookctl users update <id> [--name N] [--email E]
+// ✏️ Como "books update": lo que no se indica queda como estaba
+func usersUpdate(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users update")
+	name := fs.String("name", "", "nombre nuevo")
+	email := fs.String("email", "", "email nuevo")
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["name"] && !set["email"] {
+		return usageErrorf("users update: indica --name, --email o ambos")
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := client.GetUser(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	if !set["name"] {
+		*name = current.Name
+	}
+	if !set["email"] {
+		*email = current.Email
+	}
+
+	user, err := client.UpdateUser(ctx, ids[0], *name, *email, current.Version)
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, user, userColumns)
+}
+
+// usersRole: bookctl users role <id> <rol>
+func usersRole(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users role")
+	values, err := parseFlags(fs, args, "id", "rol")
+	if err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := client.ChangeUserRole(ctx, values[0], domain.Role(values[1]))
+	if err != nil {
+		return err
+	}
+	return printOne(r.env.Stdout, r.format, user, userColumns)
+}
+
+// usersDelete: bookctl users delete <id>
+func usersDelete(ctx context.Context, r *runner, args []string) error {
+	fs := r.flagSet("users delete")
+	ids, err := parseFlags(fs, args, "id")
+	if err != nil {
+		return err
+	}
+
+	client, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteUser(ctx, ids[0]); err != nil {
+		return err
+	}
+	r.notify("🗑️ Usuario %s eliminado", ids[0])
+	return nil
+}
@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// bookResolver resuelve los campos del tipo Book
+type bookResolver struct {
+	root *resolver
+	book *domain.Book
+}
+
+func (b *bookResolver) ID() gql.ID              { return gql.ID(b.book.ID) }
+func (b *bookResolver) Title() string           { return b.book.Title }
+func (b *bookResolver) Author() string          { return b.book.Author }
+func (b *bookResolver) ISBN() *string           { return optional(b.book.ISBN) }
+func (b *bookResolver) Publisher() *string      { return optional(b.book.Publisher) }
+func (b *bookResolver) PublicationYear() *int32 { return optionalInt(b.book.PublicationYear) }
+func (b *bookResolver) Language() *string       { return optional(b.book.Language) }
+func (b *bookResolver) PageCount() *int32       { return optionalInt(b.book.PageCount) }
+func (b *bookResolver) Description() *string    { return optional(b.book.Description) }
+func (b *bookResolver) Edition() *string        { return optional(b.book.Edition) }
+func (b *bookResolver) CreatedAt() gql.Time     { return gql.Time{Time: b.book.CreatedAt} }
+func (b *bookResolver) Version() int32          { return int32(b.book.Version) }
+
+// Subjects retorna las materias (lista vacía, no null, si no tiene)
+func (b *bookResolver) Subjects() []string {
+	if b.book.Subjects == nil {
+		return []string{}
+	}
+	return b.book.Subjects
+}
+
+// Authors retorna los autores vinculados con su rol
+func (b *bookResolver) Authors() []*bookAuthorResolver {
+	authors := make([]*bookAuthorResolver, len(b.book.Authors))
+	for i := range b.book.Authors {
+		authors[i] = &bookAuthorResolver{link: b.book.Authors[i]}
+	}
+	return authors
+}
+
+// AvailableCopies solo viene en la consulta book(id): en los listados es null
+func (b *bookResolver) AvailableCopies() *int32 {
+	if b.book.AvailableCopies == nil {
+		return nil
+	}
+	n := int32(*b.book.AvailableCopies)
+	return &n
+}
+
+// Loans retorna los préstamos del libro, los más recientes primero
+// 🔐 Listar préstamos de todos los usuarios requiere circulation:manage
+func (b *bookResolver) Loans(ctx context.Context, args loanArgs) ([]*loanResolver, error) {
+	page, err := b.root.loans.ListLoans(ctx, args.query(domain.LoanFilter{BookID: b.book.ID}))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return b.root.loanResolvers(page.Items), nil
+}
+
+// bookAuthorResolver resuelve los campos del tipo BookAuthor
+type bookAuthorResolver struct {
+	link domain.BookAuthor
+}
+
+func (a *bookAuthorResolver) AuthorID() gql.ID { return gql.ID(a.link.AuthorID) }
+func (a *bookAuthorResolver) Name() string     { return a.link.Name }
+func (a *bookAuthorResolver) Role() string     { return string(a.link.Role) }
+
+// bookPageResolver resuelve los campos del tipo BookPage
+type bookPageResolver struct {
+	root *resolver
+	page *domain.Page[*domain.Book]
+}
+
+func (p *bookPageResolver) Total() int32        { return int32(p.page.Total) }
+func (p *bookPageResolver) NextCursor() *string { return optional(p.page.NextCursor) }
+func (p *bookPageResolver) PrevCursor() *string { return optional(p.page.PrevCursor) }
+
+// Items retorna los libros de la página
+func (p *bookPageResolver) Items() []*bookResolver {
+	items := make([]*bookResolver, len(p.page.Items))
+	for i, book := range p.page.Items {
+		items[i] = &bookResolver{root: p.root, book: book}
+	}
+	return items
+}
+
+// bookInput son los datos de BookInput (los opcionales llegan como punteros)
+type bookInput struct {
+	Title           string
+	Author          *string
+	ISBN            *string
+	Publisher       *string
+	PublicationYear *int32
+	Language        *string
+	PageCount       *int32
+	Description     *string
+	Subjects        *[]string
+	Edition         *string
+	Authors         *[]struct {
+		AuthorID gql.ID
+		Role     *string
+	}
+}
+
+// toUseCase traduce el input a la entrada del caso de uso
+func (in bookInput) toUseCase() usecase.BookInput {
+	var authors []domain.BookAuthor
+	for _, a := range deref(in.Authors) {
+		authors = append(authors, domain.BookAuthor{AuthorID: string(a.AuthorID), Role: domain.AuthorRole(deref(a.Role))})
+	}
+
+	return usecase.BookInput{
+		Title:           in.Title,
+		Author:          deref(in.Author),
+		ISBN:            deref(in.ISBN),
+		Publisher:       deref(in.Publisher),
+		PublicationYear: int(deref(in.PublicationYear)),
+		Language:        deref(in.Language),
+		PageCount:       int(deref(in.PageCount)),
+		Description:     deref(in.Description),
+		Subjects:        deref(in.Subjects),
+		Edition:         deref(in.Edition),
+		Authors:         authors,
+	}
+}
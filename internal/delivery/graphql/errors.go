@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Códigos de error que viajan en errors[].extensions.code
+//
+// 💡 Son los mismos códigos estables del problem+json de la API REST
+// (validation_error, not_found, ...): un cliente puede tratar igual ambos errores
+const (
+	codeValidation   = "validation_error"
+	codeUnauthorized = "unauthorized"
+	codeForbidden    = "forbidden"
+	codeNotFound     = "not_found"
+	codeConflict     = "conflict"
+	codeTimeout      = "timeout"
+	codeInternal     = "internal_error"
+
+	codePreconditionFailed = "precondition_failed"
+)
+
+// resolverError es el error que devuelven los resolvers
+//
+// 📋 En la respuesta queda así:
+//
+//	{
+//	  "errors": [{
+//	    "message": "los datos enviados no son válidos",
+//	    "path": ["createBook"],
+//	    "extensions": {
+//	      "code": "validation_error",
+//	      "fields": [{"field": "title", "code": "required", "message": "el título del libro es obligatorio"}]
+//	    }
+//	  }],
+//	  "data": null
+//	}
+type resolverError struct {
+	message string
+	code    string
+	fields  []domain.FieldError
+}
+
+// Error implementa la interfaz error
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions lo usa graphql-go para completar errors[].extensions
+func (e *resolverError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.code}
+	if len(e.fields) > 0 {
+		ext["fields"] = e.fields
+	}
+	return ext
+}
+
+// errorCode traduce un error del dominio a su código
+//
+// 🗺️ Misma tabla que classifyError en la capa HTTP; en GraphQL el status HTTP
+// es siempre 200 y el tipo de error viaja en extensions.code
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return codeValidation
+	case errors.Is(err, domain.ErrUnauthorized):
+		return codeUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return codeForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return codeNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return codeConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return codePreconditionFailed
+	case errors.Is(err, context.DeadlineExceeded):
+		return codeTimeout
+	default:
+		return codeInternal
+	}
+}
+
+// toGraphQLError convierte un error del caso de uso en el error que ve el cliente
+//
+// 🚨 Para errores internos NO mostramos el mensaje original (podría contener
+// detalles de la base de datos); lo registramos en el log
+func toGraphQLError(err error) error {
+	code := errorCode(err)
+
+	switch code {
+	case codeInternal:
+		log.Printf("Error interno en GraphQL: %v", err)
+		return &resolverError{message: "error interno del servidor", code: code}
+	case codeTimeout:
+		return &resolverError{message: "la petición tardó demasiado", code: code}
+	}
+
+	result := &resolverError{message: err.Error(), code: code}
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		result.fields = domainErr.Fields
+	}
+	return result
+}
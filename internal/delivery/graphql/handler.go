@@ -0,0 +1,142 @@
+// Package graphql expone libros, usuarios y préstamos en un endpoint GraphQL (/graphql)
+//
+// 🎯 ¿Por qué GraphQL además de REST?
+// - El cliente pide EXACTAMENTE los campos que necesita, y las relaciones en una sola consulta:
+//
+//	{ me { name books { title } loans(status: "overdue") { dueAt book { title } } } }
+//
+// - Es otra capa de delivery sobre los MISMOS casos de uso (como HTTP y gRPC)
+//
+// 📁 Estructura:
+//   - schema.graphql   El esquema (la fuente de verdad de la API GraphQL)
+//   - handler.go       El handler de Fiber: POST /graphql y la página de GraphiQL
+//   - resolver.go      Query y Mutation
+//   - book.go, user.go, loan.go   Los resolvers de cada tipo
+//   - loader.go        Dataloaders: agrupan las búsquedas por ID (evitan el problema N+1)
+//   - errors.go        Traducción de errores del dominio a errors[].extensions.code
+//
+// 🔐 La autenticación es la misma de la API REST (Authorization: Bearer o X-API-Key),
+// pero opcional: createUser (el registro) funciona sin credenciales y el resto
+// de las operaciones responde "unauthorized" si faltan
+package graphql
+
+import (
+	_ "embed"
+
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth limita el anidamiento de las consultas
+// 🛡️ Sin límite, { me { loans { user { loans { user { ... } } } } } } podría crecer sin fin
+const maxDepth = 8
+
+// Deps agrupa lo que necesita el handler de GraphQL
+//
+// 💡 Los casos de uso son las MISMAS instancias que usan los handlers REST
+type Deps struct {
+	Books      *usecase.BookUseCase
+	Users      *usecase.UserUseCase
+	Loans      *usecase.LoanUseCase
+	Playground bool // Servir GraphiQL en GET /graphql (solo en desarrollo)
+}
+
+// Handler maneja las peticiones HTTP de GraphQL
+type Handler struct {
+	schema     *gql.Schema
+	books      *usecase.BookUseCase
+	users      *usecase.UserUseCase
+	playground bool
+}
+
+// NewHandler constructor para Handler
+//
+// ⚠️ Si el esquema no coincide con los resolvers, falla al arrancar (panic):
+// mejor enterarse en el arranque que en la primera consulta
+func NewHandler(deps Deps) *Handler {
+	root := &resolver{books: deps.Books, users: deps.Users, loans: deps.Loans}
+	return &Handler{
+		schema: gql.MustParseSchema(schema, root,
+			gql.UseStringDescriptions(),
+			gql.MaxDepth(maxDepth),
+			// Los elementos de una lista se resuelven en paralelo: con una página
+			// entera en vuelo, el loader junta todos sus IDs en una sola consulta
+			gql.MaxParallelism(usecase.MaxPageLimit),
+		),
+		books:      deps.Books,
+		users:      deps.Users,
+		playground: deps.Playground,
+	}
+}
+
+// Request es el cuerpo de una petición GraphQL
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query maneja las peticiones POST /graphql
+//
+// 📊 Códigos de estado HTTP utilizados:
+// - 200 OK: siempre que la consulta se pudo leer; los errores de los resolvers
+// viajan en "errors" junto a los datos parciales (así funciona GraphQL)
+// - 400 Bad Request: el cuerpo no es JSON o no trae "query"
+func (h *Handler) Query(c *fiber.Ctx) error {
+	var req Request
+	if err := c.BodyParser(&req); err != nil || req.Query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"errors": []fiber.Map{{"message": "se esperaba un JSON con el campo query"}},
+		})
+	}
+
+	// Loaders nuevos en cada petición: su caché nunca sirve datos de otra
+	ctx := newLoaders(c.UserContext(), h.books, h.users)
+
+	return c.JSON(h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// Playground maneja las peticiones GET /graphql: la página de GraphiQL
+// 🧪 Solo en desarrollo (APP_ENV=development); en producción responde 404
+func (h *Handler) Playground(c *fiber.Ctx) error {
+	if !h.playground {
+		return fiber.ErrNotFound
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(playgroundHTML)
+}
+
+// playgroundHTML es GraphiQL servido desde un CDN
+// 💡 Las credenciales se cargan en la pestaña "Headers": {"Authorization": "Bearer <token>"}
+const playgroundHTML = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL - Biblioteca</title>
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+</head>
+<body>
+  <div id="graphiql">Cargando…</div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, {
+        fetcher,
+        defaultEditorToolsVisibility: true,
+        defaultQuery: '{\n  me {\n    name\n    books { title author }\n    loans(status: "active") { dueAt book { title } }\n  }\n}\n',
+      }),
+    );
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,169 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// Parámetros de los dataloaders
+const (
+	loaderWait     = time.Millisecond     // Cuánto se espera a que lleguen más IDs antes de consultar
+	loaderMaxBatch = usecase.MaxPageLimit // IDs por consulta como máximo
+)
+
+// loader agrupa en UNA consulta las búsquedas por ID que llegan casi juntas
+//
+// 🐌 El problema N+1:
+//
+//	{ me { loans { book { title } } } }
+//
+// Resolver "book" en cada uno de 20 préstamos haría 20 GetByID al repositorio.
+// El ejecutor de GraphQL resuelve los elementos de una lista en paralelo, así que
+// las 20 llamadas a Load llegan casi al mismo tiempo: el loader las junta durante
+// loaderWait y hace UNA sola consulta GetByIDs con los 20 IDs.
+//
+// 📦 Además recuerda los resultados: pedir dos veces el mismo ID en la misma
+// petición no vuelve a consultar. Por eso vive lo que dura UNA petición
+// (ver newLoaders): nunca sirve datos viejos ni de otro usuario.
+type loader[T any] struct {
+	ctx      context.Context                                      // Context de la petición (lleva el usuario)
+	fetch    func(ctx context.Context, ids []string) ([]T, error) // Consulta batch (caso de uso)
+	key      func(T) string                                       // ID de cada resultado
+	notFound error                                                // Error para los IDs que no aparecen
+
+	mutex   sync.Mutex
+	pending *batch[T]            // Lote que todavía junta IDs (nil = ninguno)
+	batches map[string]*batch[T] // Lote que resolvió (o resolverá) cada ID
+}
+
+// batch es un grupo de IDs que se consultan juntos
+type batch[T any] struct {
+	ids   []string
+	once  sync.Once
+	done  chan struct{} // Se cierra cuando llegó el resultado
+	items map[string]T
+	err   error
+}
+
+// newLoader crea un loader para el context de una petición
+func newLoader[T any](ctx context.Context, fetch func(context.Context, []string) ([]T, error), key func(T) string, notFound error) *loader[T] {
+	return &loader[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		key:      key,
+		notFound: notFound,
+		batches:  make(map[string]*batch[T]),
+	}
+}
+
+// Load retorna el elemento con ese ID, esperando a que se consulte su lote
+func (l *loader[T]) Load(id string) (T, error) {
+	return l.result(l.enqueue(id), id)
+}
+
+// LoadMany retorna los elementos de varios IDs (en el mismo orden) con la menor cantidad de consultas
+func (l *loader[T]) LoadMany(ids []string) ([]T, error) {
+	batches := make([]*batch[T], len(ids))
+	for i, id := range ids {
+		batches[i] = l.enqueue(id)
+	}
+
+	items := make([]T, len(ids))
+	for i, id := range ids {
+		item, err := l.result(batches[i], id)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// enqueue agrega el ID al lote pendiente (o retorna el lote que ya lo tiene)
+func (l *loader[T]) enqueue(id string) *batch[T] {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if b, ok := l.batches[id]; ok {
+		return b
+	}
+
+	b := l.pending
+	if b == nil {
+		b = &batch[T]{done: make(chan struct{})}
+		l.pending = b
+		time.AfterFunc(loaderWait, func() { l.dispatch(b) })
+	}
+	b.ids = append(b.ids, id)
+	l.batches[id] = b
+
+	if len(b.ids) >= loaderMaxBatch {
+		l.pending = nil
+		go l.dispatch(b)
+	}
+	return b
+}
+
+// dispatch consulta el lote (una sola vez, lo dispare el timer o el tamaño máximo)
+func (l *loader[T]) dispatch(b *batch[T]) {
+	l.mutex.Lock()
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mutex.Unlock()
+
+	b.once.Do(func() {
+		defer close(b.done)
+
+		items, err := l.fetch(l.ctx, b.ids)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.items = make(map[string]T, len(items))
+		for _, item := range items {
+			b.items[l.key(item)] = item
+		}
+	})
+}
+
+// result espera el lote y retorna el elemento del ID
+func (l *loader[T]) result(b *batch[T], id string) (T, error) {
+	<-b.done
+
+	item, ok := b.items[id]
+	switch {
+	case b.err != nil:
+		return item, b.err
+	case !ok:
+		return item, l.notFound
+	default:
+		return item, nil
+	}
+}
+
+// loaders son los dataloaders de UNA petición
+type loaders struct {
+	books *loader[*domain.Book]
+	users *loader[*domain.User]
+}
+
+// loadersKey es la clave privada de los loaders en el context
+type loadersKey struct{}
+
+// newLoaders crea los loaders de una petición y los guarda en su context
+func newLoaders(ctx context.Context, books *usecase.BookUseCase, users *usecase.UserUseCase) context.Context {
+	l := &loaders{}
+	l.books = newLoader(ctx, books.GetBooksByIDs, func(b *domain.Book) string { return b.ID }, domain.ErrBookNotFound)
+	l.users = newLoader(ctx, users.GetUsersByIDs, func(u *domain.User) string { return u.ID }, domain.ErrUserNotFound)
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom retorna los loaders de la petición
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// maxNestedLoans es el tope de préstamos que trae un campo anidado (loans, books)
+const maxNestedLoans = usecase.MaxPageLimit
+
+// loanArgs son los argumentos de los campos loans(status, limit)
+type loanArgs struct {
+	Status *string
+	Limit  *int32
+}
+
+// query arma la consulta de préstamos (por defecto 20, los más recientes primero)
+func (a loanArgs) query(filter domain.LoanFilter) domain.LoanQuery {
+	filter.Status = domain.LoanStatus(deref(a.Status))
+	return domain.LoanQuery{
+		PageRequest: domain.PageRequest{Limit: int(deref(a.Limit))},
+		Filter:      filter,
+	}
+}
+
+// loanResolver resuelve los campos del tipo Loan
+type loanResolver struct {
+	root *resolver
+	loan *domain.Loan
+}
+
+// loanResolvers envuelve una lista de préstamos
+func (r *resolver) loanResolvers(loans []*domain.Loan) []*loanResolver {
+	result := make([]*loanResolver, len(loans))
+	for i, loan := range loans {
+		result[i] = &loanResolver{root: r, loan: loan}
+	}
+	return result
+}
+
+func (l *loanResolver) ID() gql.ID             { return gql.ID(l.loan.ID) }
+func (l *loanResolver) Status() string         { return string(l.loan.Status) }
+func (l *loanResolver) CheckedOutAt() gql.Time { return gql.Time{Time: l.loan.CheckedOutAt} }
+func (l *loanResolver) DueAt() gql.Time        { return gql.Time{Time: l.loan.DueAt} }
+func (l *loanResolver) Renewals() int32        { return int32(l.loan.Renewals) }
+
+// ReturnedAt es null mientras el libro siga prestado
+func (l *loanResolver) ReturnedAt() *gql.Time {
+	if l.loan.ReturnedAt == nil {
+		return nil
+	}
+	return &gql.Time{Time: *l.loan.ReturnedAt}
+}
+
+// Book resuelve el libro del préstamo a través del loader (sin N+1)
+func (l *loanResolver) Book(ctx context.Context) (*bookResolver, error) {
+	book, err := loadersFrom(ctx).books.Load(l.loan.BookID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &bookResolver{root: l.root, book: book}, nil
+}
+
+// User resuelve el usuario del préstamo a través del loader (sin N+1)
+func (l *loanResolver) User(ctx context.Context) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(l.loan.UserID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{root: l.root, user: user}, nil
+}
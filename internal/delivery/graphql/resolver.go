@@ -0,0 +1,207 @@
+package graphql
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// resolver es la raíz del esquema: sus métodos resuelven los campos de Query y Mutation
+//
+// 🎯 Igual que un handler HTTP: traduce argumentos a tipos del dominio, llama
+// al caso de uso y traduce la respuesta. Ninguna regla de negocio vive aquí.
+type resolver struct {
+	books *usecase.BookUseCase
+	users *usecase.UserUseCase
+	loans *usecase.LoanUseCase
+}
+
+// pageArgs son los argumentos de paginación comunes a los listados
+type pageArgs struct {
+	Limit  *int32
+	Offset *int32
+	Cursor *string
+	Sort   *string
+	Desc   *bool
+}
+
+// pageRequest traduce los argumentos a domain.PageRequest (los valida el caso de uso)
+func (a pageArgs) pageRequest() domain.PageRequest {
+	return domain.PageRequest{
+		Limit:  int(deref(a.Limit)),
+		Offset: int(deref(a.Offset)),
+		Cursor: deref(a.Cursor),
+		Sort:   deref(a.Sort),
+		Desc:   deref(a.Desc),
+	}
+}
+
+// ==================== Query ====================
+
+// Me resuelve query { me }
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	user, ok := usecase.UserFromContext(ctx)
+	if !ok {
+		return nil, toGraphQLError(domain.ErrAuthRequired)
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// Book resuelve query { book(id) }
+func (r *resolver) Book(ctx context.Context, args struct{ ID gql.ID }) (*bookResolver, error) {
+	book, err := r.books.GetBookByID(ctx, string(args.ID))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &bookResolver{root: r, book: book}, nil
+}
+
+// Books resuelve query { books(...) }
+func (r *resolver) Books(ctx context.Context, args struct {
+	pageArgs
+	Title    *string
+	Author   *string
+	AuthorID *gql.ID
+}) (*bookPageResolver, error) {
+	page, err := r.books.ListBooks(ctx, domain.BookQuery{
+		PageRequest: args.pageRequest(),
+		Filter:      domain.BookFilter{Title: deref(args.Title), Author: deref(args.Author), AuthorID: string(deref(args.AuthorID))},
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &bookPageResolver{root: r, page: page}, nil
+}
+
+// User resuelve query { user(id) }
+func (r *resolver) User(ctx context.Context, args struct{ ID gql.ID }) (*userResolver, error) {
+	user, err := r.users.GetUserByID(ctx, string(args.ID))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// Users resuelve query { users(...) }
+func (r *resolver) Users(ctx context.Context, args struct {
+	pageArgs
+	Name  *string
+	Email *string
+}) (*userPageResolver, error) {
+	page, err := r.users.ListUsers(ctx, domain.UserQuery{
+		PageRequest: args.pageRequest(),
+		Filter:      domain.UserFilter{Name: deref(args.Name), Email: deref(args.Email)},
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userPageResolver{root: r, page: page}, nil
+}
+
+// ==================== Mutation ====================
+
+// CreateBook resuelve mutation { createBook(input) }
+func (r *resolver) CreateBook(ctx context.Context, args struct{ Input bookInput }) (*bookResolver, error) {
+	book, err := r.books.CreateBook(ctx, args.Input.toUseCase())
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &bookResolver{root: r, book: book}, nil
+}
+
+// UpdateBook resuelve mutation { updateBook(id, input, version) }
+func (r *resolver) UpdateBook(ctx context.Context, args struct {
+	ID      gql.ID
+	Input   bookInput
+	Version *int32
+}) (*bookResolver, error) {
+	book, err := r.books.UpdateBook(ctx, string(args.ID), args.Input.toUseCase(), int(deref(args.Version)))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &bookResolver{root: r, book: book}, nil
+}
+
+// DeleteBook resuelve mutation { deleteBook(id, version) }
+func (r *resolver) DeleteBook(ctx context.Context, args struct {
+	ID      gql.ID
+	Version *int32
+}) (bool, error) {
+	if err := r.books.DeleteBook(ctx, string(args.ID), int(deref(args.Version))); err != nil {
+		return false, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+// CreateUser resuelve mutation { createUser(input) } (el registro, público)
+func (r *resolver) CreateUser(ctx context.Context, args struct {
+	Input struct {
+		Name     string
+		Email    string
+		Password string
+	}
+}) (*userResolver, error) {
+	user, err := r.users.CreateUser(ctx, args.Input.Name, args.Input.Email, args.Input.Password)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// UpdateUser resuelve mutation { updateUser(id, input, version) }
+func (r *resolver) UpdateUser(ctx context.Context, args struct {
+	ID    gql.ID
+	Input struct {
+		Name  string
+		Email string
+	}
+	Version *int32
+}) (*userResolver, error) {
+	user, err := r.users.UpdateUser(ctx, string(args.ID), args.Input.Name, args.Input.Email, int(deref(args.Version)))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// DeleteUser resuelve mutation { deleteUser(id, version) }
+func (r *resolver) DeleteUser(ctx context.Context, args struct {
+	ID      gql.ID
+	Version *int32
+}) (bool, error) {
+	if err := r.users.DeleteUser(ctx, string(args.ID), int(deref(args.Version))); err != nil {
+		return false, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+// ==================== Helpers ====================
+
+// deref retorna el valor apuntado, o el valor cero si el argumento no vino
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// optional retorna nil para los textos vacíos (null en GraphQL)
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalInt retorna nil para los números en cero (null en GraphQL)
+func optionalInt(n int) *int32 {
+	if n == 0 {
+		return nil
+	}
+	v := int32(n)
+	return &v
+}
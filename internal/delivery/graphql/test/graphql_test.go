@@ -0,0 +1,303 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/delivery/graphql"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/repository"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// countingBookRepository cuenta las búsquedas por ID que llegan al repositorio
+// 🔍 Así el test ve si un campo anidado hizo N consultas o una sola
+type countingBookRepository struct {
+	repository.BookRepository
+	getByID, getByIDs atomic.Int32
+}
+
+func (r *countingBookRepository) GetByID(ctx context.Context, id string) (*domain.Book, error) {
+	r.getByID.Add(1)
+	return r.BookRepository.GetByID(ctx, id)
+}
+
+func (r *countingBookRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Book, error) {
+	r.getByIDs.Add(1)
+	return r.BookRepository.GetByIDs(ctx, ids)
+}
+
+// countingUserRepository es lo mismo para los usuarios
+type countingUserRepository struct {
+	repository.UserRepository
+	getByID, getByIDs atomic.Int32
+}
+
+func (r *countingUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.getByID.Add(1)
+	return r.UserRepository.GetByID(ctx, id)
+}
+
+func (r *countingUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	r.getByIDs.Add(1)
+	return r.UserRepository.GetByIDs(ctx, ids)
+}
+
+// graphqlFixture arma el handler de GraphQL sobre repositorios en memoria
+type graphqlFixture struct {
+	app      *fiber.App
+	bookRepo *countingBookRepository
+	userRepo *countingUserRepository
+	books    *usecase.BookUseCase
+	users    *usecase.UserUseCase
+	copies   *usecase.CopyUseCase
+	loans    *usecase.LoanUseCase
+}
+
+// staffCtx es el contexto de un admin para preparar los datos
+var staffCtx = usecase.ContextWithUser(context.Background(), &domain.User{ID: "staff", Role: domain.RoleAdmin})
+
+func newGraphQLFixture() graphqlFixture {
+	bookRepo := &countingBookRepository{BookRepository: memory.NewInMemoryBookRepository()}
+	userRepo := &countingUserRepository{UserRepository: memory.NewInMemoryUserRepository()}
+	copyRepo := memory.NewInMemoryCopyRepository()
+	holdRepo := memory.NewInMemoryHoldRepository()
+	loanRepo := memory.NewInMemoryLoanRepository(copyRepo)
+	fineRepo := memory.NewInMemoryFineRepository()
+	policy := domain.DefaultLoanPolicy()
+
+	f := graphqlFixture{
+		bookRepo: bookRepo,
+		userRepo: userRepo,
+		books:    usecase.NewBookUseCase(bookRepo, memory.NewInMemoryAuthorRepository(), copyRepo, loanRepo, holdRepo, fineRepo),
+		users:    usecase.NewUserUseCase(userRepo, loanRepo, holdRepo, fineRepo, security.NewBcryptHasher(config.MinBcryptCost)),
+		copies:   usecase.NewCopyUseCase(copyRepo, bookRepo, loanRepo, holdRepo, policy, nil),
+		loans: usecase.NewLoanUseCase(loanRepo, bookRepo, userRepo,
+			copyRepo, holdRepo, fineRepo, policy, nil),
+	}
+
+	handler := graphql.NewHandler(graphql.Deps{Books: f.books, Users: f.users, Loans: f.loans, Playground: true})
+	f.app = fiber.New()
+	// En lugar del JWT, el test elige el usuario con el header X-Test-User
+	routes.SetupGraphQLRoutes(f.app, handler, func(c *fiber.Ctx) error {
+		if id := c.Get("X-Test-User"); id != "" {
+			user, err := userRepo.UserRepository.GetByID(c.UserContext(), id)
+			if err != nil {
+				return err
+			}
+			c.SetUserContext(usecase.ContextWithUser(c.UserContext(), user))
+		}
+		return c.Next()
+	})
+	return f
+}
+
+// gqlResponse es la respuesta de GraphQL con los errores ya decodificados
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code   string              `json:"code"`
+			Fields []domain.FieldError `json:"fields"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// exec ejecuta una consulta como el usuario indicado ("" = sin credenciales)
+func (f graphqlFixture) exec(t *testing.T, userID, query string, variables map[string]interface{}) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(graphql.Request{Query: query, Variables: variables})
+	req := httptest.NewRequest(fiber.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+
+	resp, err := f.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("No se pudo ejecutar la petición: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Se esperaba 200 OK, pero se obtuvo: %d", resp.StatusCode)
+	}
+
+	var out gqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("No se pudo leer la respuesta: %v", err)
+	}
+	return out
+}
+
+// newUser registra un usuario y lo promueve al rol indicado
+func (f graphqlFixture) newUser(t *testing.T, email string, role domain.Role) string {
+	t.Helper()
+	user, err := f.users.CreateUser(context.Background(), "Usuario "+email, email, "contraseña-segura")
+	if err != nil {
+		t.Fatalf("No se pudo crear el usuario: %v", err)
+	}
+	if role != domain.RoleMember {
+		if _, err := f.users.ChangeUserRole(staffCtx, user.ID, role); err != nil {
+			t.Fatalf("No se pudo cambiar el rol: %v", err)
+		}
+	}
+	return user.ID
+}
+
+// TestGraphQL_NestedFieldsAreBatched verifica que los campos anidados no hacen N+1
+func TestGraphQL_NestedFieldsAreBatched(t *testing.T) {
+	// Arrange: el admin y 3 socios con 3 libros prestados cada uno
+	f := newGraphQLFixture()
+	admin := f.newUser(t, "admin@example.com", domain.RoleAdmin)
+	readers := []string{admin}
+	for u := 0; u < 3; u++ {
+		readers = append(readers, f.newUser(t, fmt.Sprintf("socio%d@example.com", u), domain.RoleMember))
+	}
+	loansCount := 0
+	for u, reader := range readers {
+		for b := 0; b < 3; b++ {
+			book, err := f.books.CreateBook(staffCtx, usecase.BookInput{Title: fmt.Sprintf("Libro %d-%d", u, b), Author: "Autor"})
+			if err != nil {
+				t.Fatalf("No se pudo crear el libro: %v", err)
+			}
+			if _, err := f.copies.AddCopy(staffCtx, book.ID, usecase.CopyInput{Barcode: "BC-" + book.ID[:8]}); err != nil {
+				t.Fatalf("No se pudo crear el ejemplar: %v", err)
+			}
+			if _, err := f.loans.Checkout(staffCtx, usecase.CheckoutInput{BookID: book.ID, UserID: reader}); err != nil {
+				t.Fatalf("No se pudo prestar el libro: %v", err)
+			}
+			loansCount++
+		}
+	}
+	f.bookRepo.getByID.Store(0)
+	f.userRepo.getByID.Store(0)
+
+	// Act
+	resp := f.exec(t, admin, `{
+		users(limit: 10) {
+			items {
+				name
+				books { title }
+				loans { status book { title } user { email } }
+			}
+		}
+	}`, nil)
+
+	// Assert: los datos están completos
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Se esperaba una respuesta sin errores, pero se obtuvo: %+v", resp.Errors)
+	}
+	var data struct {
+		Users struct {
+			Items []struct {
+				Books []struct{ Title string }
+				Loans []struct {
+					Book struct{ Title string }
+					User struct{ Email string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatalf("No se pudo leer data: %v", err)
+	}
+	nested := 0
+	for _, user := range data.Users.Items {
+		for _, loan := range user.Loans {
+			if loan.Book.Title == "" || loan.User.Email == "" {
+				t.Errorf("Se esperaba el libro y el usuario del préstamo, pero se obtuvo: %+v", loan)
+			}
+			nested++
+		}
+	}
+	if nested != loansCount {
+		t.Errorf("Se esperaban %d préstamos anidados, pero se obtuvieron %d", loansCount, nested)
+	}
+
+	// Ninguna búsqueda individual: todo pasa por GetByIDs, en menos consultas que préstamos
+	if n := f.bookRepo.getByID.Load(); n != 0 {
+		t.Errorf("Se esperaban 0 llamadas a BookRepository.GetByID, pero se obtuvieron %d", n)
+	}
+	if n := f.userRepo.getByID.Load(); n != 0 {
+		t.Errorf("Se esperaban 0 llamadas a UserRepository.GetByID, pero se obtuvieron %d", n)
+	}
+	if n := f.bookRepo.getByIDs.Load(); n == 0 || int(n) >= loansCount {
+		t.Errorf("Se esperaban entre 1 y %d llamadas a BookRepository.GetByIDs, pero se obtuvieron %d", loansCount-1, n)
+	}
+}
+
+// TestGraphQL_Mutations verifica que las mutaciones usan los casos de uso y sus errores
+func TestGraphQL_Mutations(t *testing.T) {
+	// Arrange
+	f := newGraphQLFixture()
+	librarian := f.newUser(t, "bibliotecaria@example.com", domain.RoleLibrarian)
+	member := f.newUser(t, "socia@example.com", domain.RoleMember)
+	const create = `mutation($input: BookInput!) { createBook(input: $input) { id title } }`
+
+	// Act + Assert: la bibliotecaria crea un libro
+	resp := f.exec(t, librarian, create, map[string]interface{}{
+		"input": map[string]interface{}{"title": "Rayuela", "author": "Cortázar"},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Se esperaba crear el libro, pero se obtuvo: %+v", resp.Errors)
+	}
+
+	// Sin título → validation_error con el detalle por campo
+	resp = f.exec(t, librarian, create, map[string]interface{}{
+		"input": map[string]interface{}{"title": "", "author": "Cortázar"},
+	})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "validation_error" ||
+		len(resp.Errors[0].Extensions.Fields) == 0 || resp.Errors[0].Extensions.Fields[0].Field != "title" {
+		t.Errorf("Se esperaba validation_error en title, pero se obtuvo: %+v", resp.Errors)
+	}
+
+	// Una socia no puede crear libros
+	resp = f.exec(t, member, create, map[string]interface{}{
+		"input": map[string]interface{}{"title": "Ficciones", "author": "Borges"},
+	})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "forbidden" {
+		t.Errorf("Se esperaba forbidden, pero se obtuvo: %+v", resp.Errors)
+	}
+
+	// Sin credenciales: me → unauthorized, pero el registro es público
+	resp = f.exec(t, "", `{ me { id } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions.Code != "unauthorized" {
+		t.Errorf("Se esperaba unauthorized, pero se obtuvo: %+v", resp.Errors)
+	}
+	resp = f.exec(t, "", `mutation { createUser(input: {name: "Ana", email: "ana@example.com", password: "contraseña-segura"}) { role } }`, nil)
+	if len(resp.Errors) > 0 || string(resp.Data) != `{"createUser":{"role":"member"}}` {
+		t.Errorf("Se esperaba registrar a Ana como socia, pero se obtuvo: %s %+v", resp.Data, resp.Errors)
+	}
+}
+
+// TestGraphQL_Playground verifica que GraphiQL solo se sirve en desarrollo
+func TestGraphQL_Playground(t *testing.T) {
+	for _, playground := range []bool{true, false} {
+		app := fiber.New()
+		routes.SetupGraphQLRoutes(app, graphql.NewHandler(graphql.Deps{Playground: playground}))
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/graphql", nil), -1)
+		if err != nil {
+			t.Fatalf("No se pudo ejecutar la petición: %v", err)
+		}
+		want := fiber.StatusNotFound
+		if playground {
+			want = fiber.StatusOK
+		}
+		if resp.StatusCode != want {
+			t.Errorf("Con Playground=%v se esperaba %d, pero se obtuvo: %d", playground, want, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// userResolver resuelve los campos del tipo User
+type userResolver struct {
+	root *resolver
+	user *domain.User
+}
+
+func (u *userResolver) ID() gql.ID          { return gql.ID(u.user.ID) }
+func (u *userResolver) Name() string        { return u.user.Name }
+func (u *userResolver) Email() string       { return u.user.Email }
+func (u *userResolver) Role() string        { return string(u.user.Role) }
+func (u *userResolver) CreatedAt() gql.Time { return gql.Time{Time: u.user.CreatedAt} }
+func (u *userResolver) Version() int32      { return int32(u.user.Version) }
+
+// Loans retorna los préstamos del usuario, los más recientes primero
+// 🔐 Un socio ve los suyos; los de otro usuario requieren circulation:manage
+func (u *userResolver) Loans(ctx context.Context, args loanArgs) ([]*loanResolver, error) {
+	page, err := u.root.loans.ListUserLoans(ctx, u.user.ID, args.query(domain.LoanFilter{}))
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return u.root.loanResolvers(page.Items), nil
+}
+
+// Books retorna los libros que el usuario tiene prestados ahora
+//
+// 📦 Los préstamos salen de UNA consulta y sus libros de otra (el loader):
+// nunca un GetByID por libro
+func (u *userResolver) Books(ctx context.Context) ([]*bookResolver, error) {
+	page, err := u.root.loans.ListUserLoans(ctx, u.user.ID, domain.LoanQuery{
+		PageRequest: domain.PageRequest{Limit: maxNestedLoans},
+		Filter:      domain.LoanFilter{Status: domain.LoanActive},
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	ids := make([]string, 0, len(page.Items))
+	seen := make(map[string]bool, len(page.Items))
+	for _, loan := range page.Items {
+		if !seen[loan.BookID] {
+			seen[loan.BookID] = true
+			ids = append(ids, loan.BookID)
+		}
+	}
+
+	books, err := loadersFrom(ctx).books.LoadMany(ids)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	result := make([]*bookResolver, len(books))
+	for i, book := range books {
+		result[i] = &bookResolver{root: u.root, book: book}
+	}
+	return result, nil
+}
+
+// userPageResolver resuelve los campos del tipo UserPage
+type userPageResolver struct {
+	root *resolver
+	page *domain.Page[*domain.User]
+}
+
+func (p *userPageResolver) Total() int32        { return int32(p.page.Total) }
+func (p *userPageResolver) NextCursor() *string { return optional(p.page.NextCursor) }
+func (p *userPageResolver) PrevCursor() *string { return optional(p.page.PrevCursor) }
+
+// Items retorna los usuarios de la página
+func (p *userPageResolver) Items() []*userResolver {
+	items := make([]*userResolver, len(p.page.Items))
+	for i, user := range p.page.Items {
+		items[i] = &userResolver{root: p.root, user: user}
+	}
+	return items
+}
@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/grpc/pb"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Claves de metadata con las que se autentica un cliente gRPC
+// 💡 La metadata de gRPC es el equivalente a los headers HTTP (y va en minúsculas)
+const (
+	MetadataAuthorization = "authorization" // "Bearer <access_token>"
+	MetadataAPIKey        = "x-api-key"     // "bk_<prefijo>_<secreto>"
+)
+
+// publicMethods son las llamadas que no exigen autenticación
+// 🔓 Como POST /api/users en HTTP: el registro es público
+var publicMethods = map[string]bool{
+	pb.UserService_CreateUser_FullMethodName: true,
+}
+
+// reflectionPrefix identifica los métodos del servicio de reflexión (grpcurl list/describe)
+const reflectionPrefix = "/grpc.reflection."
+
+// authenticator agrupa los interceptores de autenticación
+//
+// 🔄 Mismo flujo que los middlewares HTTP (APIKeyHandler.Authenticate + AuthHandler.RequireAuth):
+// 1. Con x-api-key: el caso de uso de API keys la verifica y sus scopes acotan los permisos
+// 2. Si no, exige "authorization: Bearer <token>"
+// 3. Guarda el usuario en el context: los casos de uso deciden los permisos
+type authenticator struct {
+	auth    *usecase.AuthUseCase
+	apiKeys *usecase.APIKeyUseCase
+}
+
+// unary es el interceptor de las llamadas unarias
+func (a *authenticator) unary(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+	authCtx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return handler(authCtx, req)
+}
+
+// stream es el interceptor de las llamadas con streaming
+func (a *authenticator) stream(srv any, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+	authCtx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return toStatus(ss.Context(), err)
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: authCtx})
+}
+
+// authenticate retorna el context con el usuario que hace la llamada
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] || strings.HasPrefix(method, reflectionPrefix) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if raw := firstValue(md, MetadataAPIKey); raw != "" {
+		user, key, err := a.apiKeys.Authenticate(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		return usecase.ContextWithAPIKey(ctx, user, key), nil
+	}
+
+	token, ok := bearerToken(firstValue(md, MetadataAuthorization))
+	if !ok {
+		return nil, domain.ErrAuthRequired
+	}
+	user, err := a.auth.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.ContextWithUser(ctx, user), nil
+}
+
+// authenticatedStream reemplaza el context del stream por el que lleva el usuario
+type authenticatedStream struct {
+	gogrpc.ServerStream
+	ctx context.Context
+}
+
+// Context retorna el context con el usuario autenticado
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// timeoutInterceptor le pone un deadline a cada llamada unaria
+//
+// ⏱️ Si el cliente ya mandó un deadline más corto, manda el del cliente
+// (context.WithTimeout nunca extiende el deadline del context padre)
+func timeoutInterceptor(timeout time.Duration) gogrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// firstValue retorna el primer valor de una clave de metadata ("" si no está)
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// bearerToken extrae el token de "Bearer <token>" (el esquema no distingue mayúsculas)
+func bearerToken(value string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
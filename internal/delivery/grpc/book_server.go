@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/delivery/grpc/pb"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BookServer implementa pb.BookServiceServer sobre BookUseCase
+//
+// 🔄 Cada método hace lo mismo que su handler HTTP:
+// 1. Traduce el mensaje protobuf a los tipos del dominio
+// 2. Llama al caso de uso con el context (que lleva el usuario y el deadline)
+// 3. Traduce la respuesta, o el error a un código gRPC
+type BookServer struct {
+	pb.UnimplementedBookServiceServer
+	bookUseCase *usecase.BookUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewBookServer constructor para BookServer
+func NewBookServer(bookUseCase *usecase.BookUseCase) *BookServer {
+	return &BookServer{
+		bookUseCase: bookUseCase,
+	}
+}
+
+// CreateBook crea un libro (equivale a POST /api/books)
+func (s *BookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.Book, error) {
+	book, err := s.bookUseCase.CreateBook(ctx, toBookInput(req.GetBook()))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBBook(book), nil
+}
+
+// GetBook obtiene un libro con sus ejemplares disponibles (equivale a GET /api/books/:id)
+func (s *BookServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.Book, error) {
+	book, err := s.bookUseCase.GetBookByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBBook(book), nil
+}
+
+// UpdateBook reemplaza los datos de un libro (equivale a PUT /api/books/:id sin If-Match)
+func (s *BookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.Book, error) {
+	book, err := s.bookUseCase.UpdateBook(ctx, req.GetId(), toBookInput(req.GetBook()), 0)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBBook(book), nil
+}
+
+// DeleteBook elimina un libro (equivale a DELETE /api/books/:id sin If-Match)
+func (s *BookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*emptypb.Empty, error) {
+	if err := s.bookUseCase.DeleteBook(ctx, req.GetId(), 0); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListBooks envía todos los libros que cumplen los filtros, uno por mensaje
+//
+// 🌊 ¿Por qué streaming?
+//   - En HTTP el cliente pide página por página con el cursor
+//   - Aquí el servidor recorre las páginas (de MaxPageLimit libros) y va enviando:
+//     el cliente recibe el catálogo completo sin manejar cursores, y ninguno de
+//     los dos tiene que tenerlo entero en memoria
+//
+// 💡 Cada página se pide con el cursor de la anterior: mismo orden y mismos filtros.
+// Si el cliente corta la llamada, el context se cancela y el repositorio aborta
+func (s *BookServer) ListBooks(req *pb.ListBooksRequest, stream pb.BookService_ListBooksServer) error {
+	ctx := stream.Context()
+	query := domain.BookQuery{
+		PageRequest: domain.PageRequest{Limit: usecase.MaxPageLimit, Sort: req.GetSort(), Desc: req.GetDesc()},
+		Filter:      domain.BookFilter{Title: req.GetTitle(), Author: req.GetAuthor(), AuthorID: req.GetAuthorId()},
+	}
+
+	for {
+		page, err := s.bookUseCase.ListBooks(ctx, query)
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+
+		for _, book := range page.Items {
+			if err := stream.Send(toPBBook(book)); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.PageRequest = domain.PageRequest{Cursor: page.NextCursor}
+	}
+}
+
+// toBookInput traduce el mensaje protobuf a la entrada del caso de uso
+func toBookInput(in *pb.BookInput) usecase.BookInput {
+	authors := make([]domain.BookAuthor, len(in.GetAuthors()))
+	for i, a := range in.GetAuthors() {
+		authors[i] = domain.BookAuthor{AuthorID: a.GetAuthorId(), Role: domain.AuthorRole(a.GetRole())}
+	}
+
+	return usecase.BookInput{
+		Title:           in.GetTitle(),
+		Author:          in.GetAuthor(),
+		ISBN:            in.GetIsbn(),
+		Publisher:       in.GetPublisher(),
+		PublicationYear: int(in.GetPublicationYear()),
+		Language:        in.GetLanguage(),
+		PageCount:       int(in.GetPageCount()),
+		Description:     in.GetDescription(),
+		Subjects:        in.GetSubjects(),
+		Edition:         in.GetEdition(),
+		Authors:         authors,
+	}
+}
+
+// toPBBook traduce un libro del dominio al mensaje protobuf
+func toPBBook(b *domain.Book) *pb.Book {
+	authors := make([]*pb.BookAuthor, len(b.Authors))
+	for i, a := range b.Authors {
+		authors[i] = &pb.BookAuthor{AuthorId: a.AuthorID, Name: a.Name, Role: string(a.Role)}
+	}
+
+	book := &pb.Book{
+		Id:              b.ID,
+		Title:           b.Title,
+		Author:          b.Author,
+		Authors:         authors,
+		Isbn:            b.ISBN,
+		Publisher:       b.Publisher,
+		PublicationYear: int32(b.PublicationYear),
+		Language:        b.Language,
+		PageCount:       int32(b.PageCount),
+		Description:     b.Description,
+		Subjects:        b.Subjects,
+		Edition:         b.Edition,
+		CreatedAt:       timestamppb.New(b.CreatedAt),
+	}
+	if b.AvailableCopies != nil {
+		available := int32(*b.AvailableCopies)
+		book.AvailableCopies = &available
+	}
+	return book
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorCode traduce un error del dominio a su código gRPC
+//
+// 🗺️ Tabla de traducción (la misma idea que classifyError en la capa HTTP):
+// - domain.ErrValidation     → InvalidArgument   (HTTP 400)
+// - domain.ErrUnauthorized   → Unauthenticated   (HTTP 401)
+// - domain.ErrForbidden      → PermissionDenied  (HTTP 403)
+// - domain.ErrNotFound       → NotFound          (HTTP 404)
+// - domain.ErrConflict       → FailedPrecondition (HTTP 409)
+// - domain.ErrPreconditionFailed → Aborted       (HTTP 412: otra versión, releer y reintentar)
+// - context.DeadlineExceeded → DeadlineExceeded  (HTTP 504)
+// - context.Canceled         → Canceled          (el cliente cortó la llamada)
+// - cualquier otro           → Internal          (HTTP 500)
+//
+// 💡 ¿Por qué FailedPrecondition y no AlreadyExists para los conflictos?
+// ErrConflict cubre también "el libro está prestado" o "la reserva ya venció":
+// el estado actual impide la operación, que es justo lo que significa FailedPrecondition
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(err, domain.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, domain.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrConflict):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return codes.Aborted
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus convierte un error del caso de uso en el error gRPC que recibe el cliente
+//
+// 📋 Los errores de validación llevan el detalle por campo como
+// google.rpc.BadRequest (el equivalente a "errors" del problem+json)
+//
+// 🚨 Para errores internos NO mostramos el mensaje original (podría contener
+// detalles de la base de datos); lo registramos en el log
+func toStatus(ctx context.Context, err error) error {
+	code := errorCode(err)
+
+	switch code {
+	case codes.Internal:
+		method, _ := gogrpc.Method(ctx)
+		log.Printf("Error interno en gRPC %s: %v", method, err)
+		return status.Error(code, "error interno del servidor")
+	case codes.DeadlineExceeded:
+		return status.Error(code, "la llamada tardó demasiado")
+	case codes.Canceled:
+		return status.Error(code, "la llamada fue cancelada")
+	}
+
+	st := status.New(code, err.Error())
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) && len(domainErr.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(domainErr.Fields))
+		for i, f := range domainErr.Fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message}
+		}
+		if detailed, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailErr == nil {
+			st = detailed
+		}
+	}
+
+	return st.Err()
+}
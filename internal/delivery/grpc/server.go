@@ -0,0 +1,67 @@
+// Package grpc expone los casos de uso de libros y usuarios por gRPC
+//
+// 🎯 ¿Por qué una segunda capa de delivery?
+// - Clean Architecture en acción: HTTP y gRPC son dos "puertas" a los MISMOS casos de uso
+// - Ninguna regla de negocio vive aquí: solo se traducen mensajes protobuf ↔ dominio
+// - cmd/server levanta ambos servidores con las mismas instancias de los casos de uso
+//
+// 📁 Estructura:
+//   - proto/library.proto   El contrato (la fuente de verdad de la API gRPC)
+//   - pb/                   Código generado por protoc (NO editar a mano)
+//   - server.go             NewServer: registra los servicios y los interceptores
+//   - auth.go               Interceptores de autenticación (Bearer o API key en la metadata)
+//   - errors.go             Traducción de errores del dominio a códigos gRPC
+//   - book_server.go        BookService (incluye el listado en streaming)
+//   - user_server.go        UserService
+//
+// 🔧 Regenerar pb/ después de cambiar el .proto (necesita protoc, protoc-gen-go y protoc-gen-go-grpc):
+//
+//	go generate ./internal/delivery/grpc
+package grpc
+
+//go:generate protoc -I proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative proto/library.proto
+
+import (
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/grpc/pb"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Deps agrupa los casos de uso que necesita el servidor gRPC
+//
+// 💡 Son las MISMAS instancias que usan los handlers HTTP (se crean una vez en main.go)
+type Deps struct {
+	Books   *usecase.BookUseCase
+	Users   *usecase.UserUseCase
+	Auth    *usecase.AuthUseCase
+	APIKeys *usecase.APIKeyUseCase
+	Timeout time.Duration // Deadline de cada llamada unaria (0 = sin límite), como REQUEST_TIMEOUT en HTTP
+}
+
+// NewServer crea el servidor gRPC con los servicios de libros y usuarios registrados
+//
+// 🔄 Cada llamada pasa por:
+// 1. El interceptor de timeout (solo llamadas unarias: un stream dura lo que tarde en enviarse)
+// 2. El interceptor de autenticación (salvo CreateUser y la reflexión)
+// 3. El método del servicio, que llama al caso de uso
+//
+// 🔍 Registra también la reflexión del servidor: grpcurl puede descubrir los
+// servicios sin tener el .proto a mano
+func NewServer(deps Deps) *gogrpc.Server {
+	auth := &authenticator{auth: deps.Auth, apiKeys: deps.APIKeys}
+
+	server := gogrpc.NewServer(
+		gogrpc.ChainUnaryInterceptor(timeoutInterceptor(deps.Timeout), auth.unary),
+		gogrpc.ChainStreamInterceptor(auth.stream),
+	)
+
+	pb.RegisterBookServiceServer(server, NewBookServer(deps.Books))
+	pb.RegisterUserServiceServer(server, NewUserServer(deps.Users))
+	reflection.Register(server)
+
+	return server
+}
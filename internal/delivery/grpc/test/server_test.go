@@ -0,0 +1,288 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/delivery/grpc"
+	"go-book-clean-architecture-api/internal/delivery/grpc/pb"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testPassword = "contraseña-segura"
+
+// grpcFixture levanta el servidor gRPC en memoria (bufconn, sin puertos reales)
+type grpcFixture struct {
+	books pb.BookServiceClient
+	users pb.UserServiceClient
+	auth  *usecase.AuthUseCase
+	uc    *usecase.UserUseCase
+}
+
+// newGRPCFixture arma los casos de uso con repositorios en memoria y conecta un cliente
+func newGRPCFixture(t *testing.T) grpcFixture {
+	t.Helper()
+
+	bookRepo := memory.NewInMemoryBookRepository()
+	userRepo := memory.NewInMemoryUserRepository()
+	copyRepo := memory.NewInMemoryCopyRepository()
+	loanRepo := memory.NewInMemoryLoanRepository(copyRepo)
+	holdRepo := memory.NewInMemoryHoldRepository()
+	fineRepo := memory.NewInMemoryFineRepository()
+	hasher := security.NewBcryptHasher(config.MinBcryptCost)
+	tokens, err := security.NewJWTManager(config.AuthConfig{
+		Keys:      []config.SigningKey{{ID: "test", Secret: []byte("clave-de-prueba-de-32-bytes-o-mas")}},
+		ActiveKey: "test",
+		Issuer:    "test",
+	}, time.Now)
+	if err != nil {
+		t.Fatalf("No se pudo crear el firmador de tokens: %v", err)
+	}
+
+	users := usecase.NewUserUseCase(userRepo, loanRepo, holdRepo, fineRepo, hasher)
+	auth := usecase.NewAuthUseCase(userRepo, memory.NewInMemorySessionRepository(), hasher, tokens, domain.DefaultTokenPolicy(), nil)
+	server := grpc.NewServer(grpc.Deps{
+		Books:   usecase.NewBookUseCase(bookRepo, memory.NewInMemoryAuthorRepository(), copyRepo, loanRepo, holdRepo, fineRepo),
+		Users:   users,
+		Auth:    auth,
+		APIKeys: usecase.NewAPIKeyUseCase(memory.NewInMemoryAPIKeyRepository(), userRepo, nil),
+		Timeout: 5 * time.Second,
+	})
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener) // Termina con server.Stop()
+	t.Cleanup(server.Stop)
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("No se pudo conectar al servidor gRPC: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return grpcFixture{books: pb.NewBookServiceClient(conn), users: pb.NewUserServiceClient(conn), auth: auth, uc: users}
+}
+
+// login retorna un context con el access token del usuario en la metadata
+func (f grpcFixture) login(t *testing.T, email string) context.Context {
+	t.Helper()
+	pair, err := f.auth.Login(context.Background(), email, testPassword)
+	if err != nil {
+		t.Fatalf("No se pudo iniciar sesión: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), grpc.MetadataAuthorization, "Bearer "+pair.AccessToken)
+}
+
+// adminCtx crea el admin inicial y retorna su context autenticado
+func (f grpcFixture) adminCtx(t *testing.T) context.Context {
+	t.Helper()
+	if _, err := f.uc.BootstrapAdmin(context.Background(), "admin@example.com", testPassword); err != nil {
+		t.Fatalf("No se pudo crear el admin: %v", err)
+	}
+	return f.login(t, "admin@example.com")
+}
+
+// assertCode verifica el código gRPC de un error
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("Se esperaba el código %s, pero se obtuvo: %s (%v)", want, got, err)
+	}
+}
+
+// TestGRPC_Authentication verifica que solo el registro es público
+func TestGRPC_Authentication(t *testing.T) {
+	// Arrange
+	f := newGRPCFixture(t)
+
+	// Act + Assert: sin credenciales → Unauthenticated
+	_, err := f.books.GetBook(context.Background(), &pb.GetBookRequest{Id: "x"})
+	assertCode(t, err, codes.Unauthenticated)
+
+	// Con un token inválido, también
+	bad := metadata.AppendToOutgoingContext(context.Background(), grpc.MetadataAuthorization, "Bearer basura")
+	_, err = f.users.ListUsers(bad, &pb.ListUsersRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+
+	// El registro es público y el rol inicial es member
+	user, err := f.users.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Ana", Email: "ana@example.com", Password: testPassword})
+	if err != nil || user.GetRole() != string(domain.RoleMember) {
+		t.Fatalf("Se esperaba registrar a Ana como member, pero se obtuvo: %v (err: %v)", user, err)
+	}
+
+	// Una socia autenticada lee su propio usuario pero no crea libros
+	ctx := f.login(t, "ana@example.com")
+	if _, err := f.users.GetUser(ctx, &pb.GetUserRequest{Id: user.GetId()}); err != nil {
+		t.Errorf("Se esperaba poder leer el propio usuario, pero se obtuvo: %v", err)
+	}
+	_, err = f.books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.BookInput{Title: "Rayuela", Author: "Cortázar"}})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+// TestGRPC_BookCRUD prueba el ciclo de vida de un libro y la traducción de errores
+func TestGRPC_BookCRUD(t *testing.T) {
+	// Arrange
+	f := newGRPCFixture(t)
+	ctx := f.adminCtx(t)
+
+	// Act: crear, leer y actualizar
+	created, err := f.books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.BookInput{
+		Title: "Clean Architecture", Author: "Robert C. Martin", PublicationYear: 2017, Subjects: []string{"Arquitectura"},
+	}})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	got, err := f.books.GetBook(ctx, &pb.GetBookRequest{Id: created.GetId()})
+	if err != nil || got.GetTitle() != "Clean Architecture" || got.AvailableCopies == nil || got.GetCreatedAt() == nil {
+		t.Errorf("Se esperaba el libro con disponibilidad y fecha de alta, pero se obtuvo: %v (err: %v)", got, err)
+	}
+	updated, err := f.books.UpdateBook(ctx, &pb.UpdateBookRequest{Id: created.GetId(), Book: &pb.BookInput{Title: "Clean Code", Author: "Robert C. Martin"}})
+	if err != nil || updated.GetTitle() != "Clean Code" {
+		t.Errorf("Se esperaba el título actualizado, pero se obtuvo: %v (err: %v)", updated, err)
+	}
+
+	// Validación → InvalidArgument con el detalle por campo
+	_, err = f.books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.BookInput{}})
+	assertCode(t, err, codes.InvalidArgument)
+	fields := map[string]bool{}
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields[v.GetField()] = true
+			}
+		}
+	}
+	if !fields["title"] || !fields["author"] {
+		t.Errorf("Se esperaban violaciones en title y author, pero se obtuvo: %v", fields)
+	}
+
+	// Borrar → NotFound al volver a pedirlo
+	if _, err := f.books.DeleteBook(ctx, &pb.DeleteBookRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	_, err = f.books.GetBook(ctx, &pb.GetBookRequest{Id: created.GetId()})
+	assertCode(t, err, codes.NotFound)
+}
+
+// TestGRPC_ListBooksStream verifica que el stream recorre todas las páginas
+func TestGRPC_ListBooksStream(t *testing.T) {
+	// Arrange: más libros que una página (MaxPageLimit)
+	f := newGRPCFixture(t)
+	ctx := f.adminCtx(t)
+	total := usecase.MaxPageLimit + 30
+	for i := 0; i < total; i++ {
+		title := fmt.Sprintf("Libro %03d", i)
+		if i%10 == 0 {
+			title = fmt.Sprintf("Go %03d", i)
+		}
+		if _, err := f.books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.BookInput{Title: title, Author: "Autor"}}); err != nil {
+			t.Fatalf("No se pudo crear el libro: %v", err)
+		}
+	}
+
+	// Act
+	all := receiveAll(t, ctx, f.books, &pb.ListBooksRequest{Sort: domain.SortByTitle})
+	filtered := receiveAll(t, ctx, f.books, &pb.ListBooksRequest{Title: "go"})
+
+	// Assert: todos, sin repetidos y en orden
+	seen := map[string]bool{}
+	for i, book := range all {
+		if seen[book.GetId()] {
+			t.Fatalf("Libro repetido en el stream: %s", book.GetTitle())
+		}
+		seen[book.GetId()] = true
+		if i > 0 && all[i-1].GetTitle() > book.GetTitle() {
+			t.Fatalf("Se esperaba orden por título, pero %q vino antes que %q", all[i-1].GetTitle(), book.GetTitle())
+		}
+	}
+	if len(all) != total {
+		t.Errorf("Se esperaban %d libros, pero se obtuvieron %d", total, len(all))
+	}
+	if len(filtered) != total/10 {
+		t.Errorf("Se esperaban %d libros con \"go\", pero se obtuvieron %d", total/10, len(filtered))
+	}
+
+	// Un orden inválido llega como error del stream
+	stream, err := f.books.ListBooks(ctx, &pb.ListBooksRequest{Sort: "isbn"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+// receiveAll lee el stream de ListBooks hasta el final
+func receiveAll(t *testing.T, ctx context.Context, client pb.BookServiceClient, req *pb.ListBooksRequest) []*pb.Book {
+	t.Helper()
+	stream, err := client.ListBooks(ctx, req)
+	if err != nil {
+		t.Fatalf("No se pudo abrir el stream: %v", err)
+	}
+
+	var books []*pb.Book
+	for {
+		book, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return books
+		}
+		if err != nil {
+			t.Fatalf("Error leyendo el stream: %v", err)
+		}
+		books = append(books, book)
+	}
+}
+
+// TestGRPC_Users prueba el listado paginado y la edición de usuarios
+func TestGRPC_Users(t *testing.T) {
+	// Arrange
+	f := newGRPCFixture(t)
+	ctx := f.adminCtx(t)
+	for _, name := range []string{"Ana", "Beto", "Carla"} {
+		if _, err := f.users.CreateUser(ctx, &pb.CreateUserRequest{Name: name, Email: name + "@example.com", Password: testPassword}); err != nil {
+			t.Fatalf("No se pudo crear el usuario: %v", err)
+		}
+	}
+
+	// Act: primera página de 2 y la siguiente con el cursor
+	first, err := f.users.ListUsers(ctx, &pb.ListUsersRequest{Limit: 2, Sort: domain.SortByName})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	second, err := f.users.ListUsers(ctx, &pb.ListUsersRequest{Cursor: first.GetNextCursor()})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+
+	// Assert: 4 usuarios (con el admin), 2 + 2
+	if first.GetTotal() != 4 || len(first.GetUsers()) != 2 || len(second.GetUsers()) != 2 || second.GetNextCursor() != "" {
+		t.Errorf("Se esperaban dos páginas de 2 usuarios, pero se obtuvo: %v / %v", first, second)
+	}
+
+	// Editar y borrar; el email repetido es un conflicto → FailedPrecondition
+	ana := first.GetUsers()[1]
+	_, err = f.users.UpdateUser(ctx, &pb.UpdateUserRequest{Id: ana.GetId(), Name: "Ana", Email: "Beto@example.com"})
+	assertCode(t, err, codes.FailedPrecondition)
+	if _, err := f.users.DeleteUser(ctx, &pb.DeleteUserRequest{Id: ana.GetId()}); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	_, err = f.users.GetUser(ctx, &pb.GetUserRequest{Id: ana.GetId()})
+	assertCode(t, err, codes.NotFound)
+}
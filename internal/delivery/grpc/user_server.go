@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/delivery/grpc/pb"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserServer implementa pb.UserServiceServer sobre UserUseCase
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+	userUseCase *usecase.UserUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewUserServer constructor para UserServer
+func NewUserServer(userUseCase *usecase.UserUseCase) *UserServer {
+	return &UserServer{
+		userUseCase: userUseCase,
+	}
+}
+
+// CreateUser registra un usuario (equivale a POST /api/users, público)
+func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	user, err := s.userUseCase.CreateUser(ctx, req.GetName(), req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBUser(user), nil
+}
+
+// GetUser obtiene un usuario (equivale a GET /api/users/:id)
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	user, err := s.userUseCase.GetUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBUser(user), nil
+}
+
+// UpdateUser actualiza nombre y email (equivale a PUT /api/users/:id sin If-Match)
+func (s *UserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	user, err := s.userUseCase.UpdateUser(ctx, req.GetId(), req.GetName(), req.GetEmail(), 0)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toPBUser(user), nil
+}
+
+// DeleteUser elimina un usuario (equivale a DELETE /api/users/:id sin If-Match)
+func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.userUseCase.DeleteUser(ctx, req.GetId(), 0); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListUsers obtiene una página de usuarios (equivale a GET /api/users)
+// 📄 Mismos límites, orden, filtros y cursores que la API HTTP
+func (s *UserServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	page, err := s.userUseCase.ListUsers(ctx, domain.UserQuery{
+		PageRequest: domain.PageRequest{
+			Limit:  int(req.GetLimit()),
+			Offset: int(req.GetOffset()),
+			Cursor: req.GetCursor(),
+			Sort:   req.GetSort(),
+			Desc:   req.GetDesc(),
+		},
+		Filter: domain.UserFilter{Name: req.GetName(), Email: req.GetEmail()},
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	users := make([]*pb.User, len(page.Items))
+	for i, user := range page.Items {
+		users[i] = toPBUser(user)
+	}
+	return &pb.ListUsersResponse{
+		Users:      users,
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}, nil
+}
+
+// toPBUser traduce un usuario del dominio al mensaje protobuf (sin el hash de la contraseña)
+func toPBUser(u *domain.User) *pb.User {
+	return &pb.User{
+		Id:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		Role:      string(u.Role),
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+}
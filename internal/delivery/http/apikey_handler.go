@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey es el header con el que los clientes máquina envían su API key
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyHandler maneja las peticiones HTTP de API keys
+//
+// 🔑 Dos papeles:
+// - Endpoints de administración (crear, listar, revocar), solo para admins
+// - Authenticate: el middleware que acepta X-API-Key como alternativa al JWT
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewAPIKeyHandler constructor para APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// CreateAPIKeyRequest representa los datos para crear una API key
+type CreateAPIKeyRequest struct {
+	Name      string              `json:"name"`       // Para qué es
+	UserID    string              `json:"user_id"`    // En nombre de quién actúa ("" = quien la crea)
+	Scopes    []domain.Permission `json:"scopes"`     // books:read | books:write | users:admin
+	ExpiresAt *time.Time          `json:"expires_at"` // Vencimiento RFC 3339 (opcional)
+}
+
+// CreateAPIKey maneja las peticiones POST /api/api-keys
+//
+// 📊 Códigos de estado HTTP utilizados:
+// - 201 Created: la respuesta trae "key", la clave completa (no se vuelve a mostrar)
+// - 400 Bad Request: sin nombre, sin scopes, scope desconocido o que el rol no tiene
+// - 403 Forbidden: quien la pide no es admin
+// - 404 Not Found: el usuario no existe
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	key, err := h.apiKeyUseCase.CreateAPIKey(c.UserContext(), usecase.APIKeyInput{
+		Name:      req.Name,
+		UserID:    req.UserID,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// GetAPIKeyByID maneja las peticiones GET /api/api-keys/:id
+func (h *APIKeyHandler) GetAPIKeyByID(c *fiber.Ctx) error {
+	key, err := h.apiKeyUseCase.GetAPIKeyByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(key)
+}
+
+// GetAllAPIKeys maneja las peticiones GET /api/api-keys
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks; sort: created_at, filtro: user_id
+func (h *APIKeyHandler) GetAllAPIKeys(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	query := domain.APIKeyQuery{
+		PageRequest: page,
+		Filter:      domain.APIKeyFilter{UserID: c.Query("user_id")},
+	}
+
+	keys, err := h.apiKeyUseCase.ListAPIKeys(c.UserContext(), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, keys)
+}
+
+// RevokeAPIKey maneja las peticiones DELETE /api/api-keys/:id
+// Retorna 204 No Content, también si la clave ya estaba revocada
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := h.apiKeyUseCase.RevokeAPIKey(c.UserContext(), c.Params("id")); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// Authenticate es el middleware que acepta una API key en el header X-API-Key
+//
+// 🔄 Flujo:
+// 1. Sin header → sigue de largo (RequireAuth pedirá el JWT)
+// 2. Con header: el caso de uso verifica la clave (inválida, revocada o vencida → 401)
+// 3. Guarda el usuario y la clave en c.UserContext(): los scopes acotan sus permisos
+//
+// 💡 Va ANTES de RequireAuth, que deja pasar las peticiones que ya traen usuario
+// 🛡️ Solo X-API-Key: un navegador nunca lo envía por su cuenta, así que no hay CSRF.
+// La variante con Basic es AuthenticateBasic, solo para el catálogo OPDS
+func (h *APIKeyHandler) Authenticate(c *fiber.Ctx) error {
+	return h.authenticate(c, c.Get(HeaderAPIKey))
+}
+
+// AuthenticateBasic es Authenticate para las apps de lectura (OPDS)
+//
+// 📱 Además de X-API-Key acepta la clave como contraseña de "Authorization: Basic" (el
+// usuario se ignora): es lo único que saben enviar los lectores
+// ⚠️ Registrarlo SOLO en /opds (ver routes.SetupOPDSRoutes): un navegador que respondió
+// al desafío Basic reenvía esas credenciales solo, a cualquier ruta del mismo origen.
+// En /api eso permitiría CSRF con claves de escritura; en /opds todo es de lectura.
+func (h *APIKeyHandler) AuthenticateBasic(c *fiber.Ctx) error {
+	raw := c.Get(HeaderAPIKey)
+	if raw == "" {
+		raw = basicPassword(c.Get(fiber.HeaderAuthorization))
+	}
+	return h.authenticate(c, raw)
+}
+
+// authenticate verifica la clave y guarda su usuario en el contexto ("" = sigue sin usuario)
+func (h *APIKeyHandler) authenticate(c *fiber.Ctx, raw string) error {
+	if raw == "" {
+		return c.Next()
+	}
+
+	user, key, err := h.apiKeyUseCase.Authenticate(c.UserContext(), raw)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	c.SetUserContext(usecase.ContextWithAPIKey(c.UserContext(), user, key))
+	c.Locals("user", user)
+	return c.Next()
+}
+
+// basicPassword extrae la contraseña de un header "Basic base64(usuario:contraseña)"
+// ("" si el header no es Basic o está mal formado)
+func basicPassword(header string) string {
+	scheme, encoded, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Basic") {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return ""
+	}
+	_, password, _ := strings.Cut(string(decoded), ":")
+	return password
+}
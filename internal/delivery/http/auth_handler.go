@@ -0,0 +1,145 @@
+package http
+
+import (
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthHandler maneja las peticiones HTTP de autenticación
+//
+// 🔐 Login, refresh y logout son públicos (con ellos se consigue el token);
+// RequireAuth es el middleware que protege el resto de la API
+type AuthHandler struct {
+	authUseCase *usecase.AuthUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewAuthHandler constructor para AuthHandler
+func NewAuthHandler(authUseCase *usecase.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// LoginRequest representa las credenciales de un login
+type LoginRequest struct {
+	Email    string `json:"email"`    // Email del usuario
+	Password string `json:"password"` // Contraseña
+}
+
+// RefreshRequest lleva el refresh token (para refresh y para revoke)
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Login maneja las peticiones POST /api/auth/login
+// Retorna el par de tokens; 401 si el email o la contraseña no son correctos
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	tokens, err := h.authUseCase.Login(c.UserContext(), req.Email, req.Password)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(tokens)
+}
+
+// Refresh maneja las peticiones POST /api/auth/refresh
+// 🔁 Retorna un par nuevo; el refresh token enviado deja de valer
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	tokens, err := h.authUseCase.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(tokens)
+}
+
+// Revoke maneja las peticiones POST /api/auth/revoke (logout)
+// Retorna 204 No Content, también si la sesión ya estaba cerrada
+func (h *AuthHandler) Revoke(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	if err := h.authUseCase.Revoke(c.UserContext(), req.RefreshToken); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// Me maneja las peticiones GET /api/auth/me: retorna el usuario autenticado
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	user, ok := usecase.UserFromContext(c.UserContext())
+	if !ok {
+		return respondError(c, domain.ErrAuthRequired)
+	}
+
+	return c.JSON(user)
+}
+
+// RequireAuth es el middleware que exige un access token válido
+//
+// 🔄 Flujo:
+// 1. Lee "Authorization: Bearer <token>" (sin header → 401)
+// 2. El caso de uso verifica el token y busca al usuario (inválido o vencido → 401)
+// 3. Guarda el usuario en c.UserContext() para los casos de uso
+// y en c.Locals("user") para otros middlewares
+//
+// 💡 Los handlers siguen pasando c.UserContext() como siempre: el usuario viaja adentro
+// 🔑 Si un middleware anterior ya autenticó la petición (API key), no pide el token
+func (h *AuthHandler) RequireAuth(c *fiber.Ctx) error {
+	if _, ok := usecase.UserFromContext(c.UserContext()); ok {
+		return c.Next()
+	}
+
+	token, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+	if !ok {
+		return respondError(c, domain.ErrAuthRequired)
+	}
+
+	user, err := h.authUseCase.Authenticate(c.UserContext(), token)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	c.SetUserContext(usecase.ContextWithUser(c.UserContext(), user))
+	c.Locals("user", user)
+	return c.Next()
+}
+
+// OptionalAuth es como RequireAuth, pero deja pasar las peticiones sin token
+//
+// 🔓 Para endpoints que mezclan operaciones públicas y privadas (ej: /graphql,
+// donde createUser es público): un token inválido sigue siendo 401, pero sin
+// header la petición sigue sin usuario y cada caso de uso decide si lo exige
+func (h *AuthHandler) OptionalAuth(c *fiber.Ctx) error {
+	if c.Get(fiber.HeaderAuthorization) == "" {
+		return c.Next()
+	}
+	return h.RequireAuth(c)
+}
+
+// bearerToken extrae el token de un header "Bearer <token>" (el esquema no distingue mayúsculas)
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
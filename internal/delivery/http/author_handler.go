@@ -0,0 +1,127 @@
+package http
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthorHandler maneja las peticiones HTTP relacionadas con autores
+//
+// 👥 Mismo patrón que BookHandler y UserHandler
+type AuthorHandler struct {
+	authorUseCase *usecase.AuthorUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewAuthorHandler constructor para AuthorHandler
+func NewAuthorHandler(authorUseCase *usecase.AuthorUseCase) *AuthorHandler {
+	return &AuthorHandler{
+		authorUseCase: authorUseCase,
+	}
+}
+
+// CreateAuthorRequest representa la estructura de datos esperada para crear un autor
+type CreateAuthorRequest struct {
+	Name string `json:"name"` // Nombre del autor
+	Bio  string `json:"bio"`  // Biografía breve
+}
+
+// UpdateAuthorRequest representa la estructura de datos esperada para actualizar un autor
+type UpdateAuthorRequest CreateAuthorRequest
+
+// CreateAuthor maneja las peticiones POST /api/authors
+func (h *AuthorHandler) CreateAuthor(c *fiber.Ctx) error {
+	var req CreateAuthorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	author, err := h.authorUseCase.CreateAuthor(c.UserContext(), usecase.AuthorInput{Name: req.Name, Bio: req.Bio})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(author)
+}
+
+// GetAuthorByID maneja las peticiones GET /api/authors/:id
+func (h *AuthorHandler) GetAuthorByID(c *fiber.Ctx) error {
+	author, err := h.authorUseCase.GetAuthorByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(author)
+}
+
+// GetAllAuthors maneja las peticiones GET /api/authors
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks;
+// sort: name | created_at, filtro: name
+func (h *AuthorHandler) GetAllAuthors(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	authors, err := h.authorUseCase.ListAuthors(c.UserContext(), domain.AuthorQuery{
+		PageRequest: page,
+		Filter:      domain.AuthorFilter{Name: c.Query("name")},
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, authors)
+}
+
+// GetAuthorBooks maneja las peticiones GET /api/authors/:id/books
+//
+// 📚 Libros en los que participa el autor (como autor, editor o traductor)
+// Acepta la misma paginación, orden y filtros (title, author) que GET /api/books
+func (h *AuthorHandler) GetAuthorBooks(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	books, err := h.authorUseCase.ListAuthorBooks(c.UserContext(), c.Params("id"), domain.BookQuery{
+		PageRequest: page,
+		Filter: domain.BookFilter{
+			Author: c.Query("author"),
+			Title:  c.Query("title"),
+		},
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, books)
+}
+
+// UpdateAuthor maneja las peticiones PUT /api/authors/:id
+func (h *AuthorHandler) UpdateAuthor(c *fiber.Ctx) error {
+	var req UpdateAuthorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	author, err := h.authorUseCase.UpdateAuthor(c.UserContext(), c.Params("id"), usecase.AuthorInput{Name: req.Name, Bio: req.Bio})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(author)
+}
+
+// DeleteAuthor maneja las peticiones DELETE /api/authors/:id
+//
+// 🛡️ 409 Conflict si el autor todavía tiene libros vinculados
+func (h *AuthorHandler) DeleteAuthor(c *fiber.Ctx) error {
+	if err := h.authorUseCase.DeleteAuthor(c.UserContext(), c.Params("id")); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -22,6 +22,7 @@
 package http
 
 import (
+	"go-book-clean-architecture-api/internal/domain"
 	"go-book-clean-architecture-api/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
@@ -66,15 +67,51 @@ func NewBookHandler(bookUseCase *usecase.BookUseCase) *BookHandler {
 //
 // 🏷️ Tags JSON: definen cómo se serializa/deserializa desde/hacia JSON
 type CreateBookRequest struct {
-	Title  string `json:"title"`  // Título del libro
-	Author string `json:"author"` // Autor del libro
+	Title           string              `json:"title"`            // Título del libro
+	Author          string              `json:"author"`           // Autor del libro
+	ISBN            string              `json:"isbn"`             // ISBN-10 o ISBN-13 (con o sin guiones)
+	Publisher       string              `json:"publisher"`        // Editorial
+	PublicationYear int                 `json:"publication_year"` // Año de publicación
+	Language        string              `json:"language"`         // Código de idioma ISO 639
+	PageCount       int                 `json:"page_count"`       // Cantidad de páginas
+	Description     string              `json:"description"`      // Sinopsis
+	Subjects        []string            `json:"subjects"`         // Materias
+	Edition         string              `json:"edition"`          // Edición
+	Authors         []BookAuthorRequest `json:"authors"`          // Autores vinculados, en orden de créditos
+}
+
+// BookAuthorRequest vincula el libro con un autor existente
+//
+// 📋 Ejemplo: {"author_id": "…", "role": "translator"} (sin role = "author")
+type BookAuthorRequest struct {
+	AuthorID string `json:"author_id"`
+	Role     string `json:"role"`
 }
 
 // UpdateBookRequest representa la estructura de datos esperada para actualizar un libro
 // Nota: Mismo contenido que CreateBookRequest, pero semánticamente diferente
-type UpdateBookRequest struct {
-	Title  string `json:"title"`  // Título del libro
-	Author string `json:"author"` // Autor del libro
+type UpdateBookRequest CreateBookRequest
+
+// toInput convierte la petición HTTP en la entrada del caso de uso
+func (r CreateBookRequest) toInput() usecase.BookInput {
+	var authors []domain.BookAuthor
+	for _, a := range r.Authors {
+		authors = append(authors, domain.BookAuthor{AuthorID: a.AuthorID, Role: domain.AuthorRole(a.Role)})
+	}
+
+	return usecase.BookInput{
+		Title:           r.Title,
+		Author:          r.Author,
+		ISBN:            r.ISBN,
+		Publisher:       r.Publisher,
+		PublicationYear: r.PublicationYear,
+		Language:        r.Language,
+		PageCount:       r.PageCount,
+		Description:     r.Description,
+		Subjects:        r.Subjects,
+		Edition:         r.Edition,
+		Authors:         authors,
+	}
 }
 
 // CreateBook maneja las peticiones POST /api/books
@@ -89,25 +126,24 @@ type UpdateBookRequest struct {
 // 📊 Códigos de estado HTTP utilizados:
 // - 201 Created: recurso creado exitosamente
 // - 400 Bad Request: formato de petición inválido o error de validación
+// - 409 Conflict: el libro ya existe o su ISBN ya está registrado
 // - 500 Internal Server Error: error interno del servidor
 func (h *BookHandler) CreateBook(c *fiber.Ctx) error {
 	// PASO 1: Parsear el body de la petición HTTP
 	var req CreateBookRequest
 	if err := c.BodyParser(&req); err != nil {
 		// Error de formato: el JSON no es válido o no coincide con el struct
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Formato de petición inválido",
-		})
+		return respondError(c, errInvalidBody)
 	}
 
 	// PASO 2: Llamar al caso de uso (aquí es donde ocurre la magia)
 	// El handler NO valida reglas de negocio, solo delega al caso de uso
-	book, err := h.bookUseCase.CreateBook(req.Title, req.Author)
+	// c.UserContext() lleva el deadline de la petición (ver middleware.go)
+	book, err := h.bookUseCase.CreateBook(c.UserContext(), req.toInput())
 	if err != nil {
 		// Error de negocio: título vacío, autor vacío, etc.
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// respondError decide el status según el tipo de error (ver errors.go)
+		return respondError(c, err)
 	}
 
 	// PASO 3: Retornar respuesta exitosa
@@ -119,73 +155,147 @@ func (h *BookHandler) CreateBook(c *fiber.Ctx) error {
 //
 // 🔍 Handler para obtener un recurso específico
 // Utiliza parámetros de URL para obtener el ID
+// 🔢 Envía el ETag del libro; con If-None-Match igual responde 304 (ver etag.go)
 func (h *BookHandler) GetBookByID(c *fiber.Ctx) error {
 	// PASO 1: Obtener el ID del parámetro de la URL
 	// :id en la ruta se convierte en un parámetro accesible
 	id := c.Params("id")
 
 	// PASO 2: Llamar al caso de uso
-	book, err := h.bookUseCase.GetBookByID(id)
+	book, err := h.bookUseCase.GetBookByID(c.UserContext(), id)
 	if err != nil {
-		// 404 Not Found es apropiado cuando el recurso no existe
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// 404 si el libro no existe, 500 si la base de datos falló
+		return respondError(c, err)
 	}
 
 	// PASO 3: Retornar respuesta exitosa
-	// 200 OK es el código por defecto para consultas exitosas
-	return c.JSON(book)
+	// 200 OK es el código por defecto para consultas exitosas (304 si el cliente ya lo tiene)
+	return respondVersioned(c, bookETag(book), book)
 }
 
 // GetAllBooks maneja las peticiones GET /api/books
 //
-// 📚 Handler para obtener una colección de recursos
-// En aplicaciones reales, implementarías paginación aquí
+// 📚 Handler para obtener una colección de recursos, paginada
+//
+// 🔎 Parámetros de query (todos opcionales):
+// - limit, offset: tamaño y posición de la página (por defecto 20, máximo 100)
+// - cursor: token opaco devuelto en pagination.next_cursor / prev_cursor
+// - sort: title | author | created_at (prefijo "-" = descendente)
+// - order: asc | desc
+// - author, title: filtros por coincidencia parcial sin distinguir mayúsculas
+// - author_id: solo libros vinculados a ese autor (igual que GET /api/authors/:id/books)
+// - language: código de idioma ("es", "pt-BR"); el idioma base incluye sus variantes
 func (h *BookHandler) GetAllBooks(c *fiber.Ctx) error {
-	// PASO 1: Llamar al caso de uso
-	// No necesitamos parámetros para obtener todos los libros
-	books, err := h.bookUseCase.GetAllBooks()
+	// PASO 1: Parsear la query (solo formato, las reglas las valida el caso de uso)
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	query := domain.BookQuery{PageRequest: page, Filter: bookFilter(c)}
+
+	// PASO 2: Llamar al caso de uso
+	books, err := h.bookUseCase.ListBooks(c.UserContext(), query)
+	if err != nil {
+		// 400 si los parámetros son inválidos, 500 para errores inesperados
+		return respondError(c, err)
+	}
+
+	// PASO 3: Retornar respuesta exitosa
+	// Nota: si no hay libros, "data" es un array vacío, no un error
+	return respondPage(c, books)
+}
+
+// bookFilter lee los filtros de un listado de libros: ?author, ?title, ?author_id y ?language
+//
+// 💡 Los comparten el listado, la exportación y las citas: filtrar igual en los tres
+func bookFilter(c *fiber.Ctx) domain.BookFilter {
+	return domain.BookFilter{
+		Author:   c.Query("author"),
+		Title:    c.Query("title"),
+		AuthorID: c.Query("author_id"),
+		Language: c.Query("language"),
+	}
+}
+
+// SearchBooks maneja las peticiones GET /api/books/search?q=
+//
+// 🔎 Búsqueda de texto libre en título y autor, ordenada por relevancia
+// - q: palabras a buscar (sin distinguir mayúsculas ni acentos)
+// - limit, offset, cursor: misma paginación que GET /api/books
+//
+// 📋 Cada elemento de "data" es un libro con su puntuación: {"id": ..., "title": ..., "score": 0.6}
+func (h *BookHandler) SearchBooks(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	hits, err := h.bookUseCase.SearchBooks(c.UserContext(), domain.BookSearchQuery{
+		PageRequest: page,
+		Text:        c.Query("q"),
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, hits)
+}
+
+// SuggestBooks maneja las peticiones GET /api/books/suggest?prefix=
+//
+// ⌨️ Autocompletado para el buscador del catálogo
+// - prefix: lo que el usuario lleva escrito (tolera errores: "martni" → "Martin")
+// - limit: máximo de sugerencias (por defecto 10, máximo 25)
+//
+// 📋 Respuesta: {"data": [{"text": "Robert C. Martin", "field": "author", "score": 0.8, "books": 3}]}
+func (h *BookHandler) SuggestBooks(c *fiber.Ctx) error {
+	var v domain.Validator
+	limit := queryInt(c, &v, "limit")
+	if err := v.Err(); err != nil {
+		return respondError(c, err)
+	}
+
+	suggestions, err := h.bookUseCase.SuggestBooks(c.UserContext(), domain.SuggestQuery{
+		Prefix: c.Query("prefix"),
+		Limit:  limit,
+	})
 	if err != nil {
-		// 500 Internal Server Error para errores inesperados
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
-	// PASO 2: Retornar respuesta exitosa
-	// Nota: si no hay libros, retornamos un array vacío, no un error
-	return c.JSON(books)
+	return c.JSON(fiber.Map{"data": suggestions})
 }
 
 // UpdateBook maneja las peticiones PUT /api/books/:id
 //
 // ✏️ Handler para actualizar un recurso existente
 // Combina parámetros de URL (ID) con body de petición (datos)
+// 🔢 If-Match (opcional) con el ETag leído: 412 si otro guardó antes (ver etag.go)
 func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
-	// PASO 1: Obtener el ID del parámetro de la URL
+	// PASO 1: Obtener el ID del parámetro de la URL y la versión que leyó el cliente
 	id := c.Params("id")
+	version, err := ifMatch(c, h.storedVersion(c, id))
+	if err != nil {
+		return respondError(c, err)
+	}
 
 	// PASO 2: Parsear el body de la petición
 	var req UpdateBookRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Formato de petición inválido",
-		})
+		return respondError(c, errInvalidBody)
 	}
 
 	// PASO 3: Llamar al caso de uso
-	book, err := h.bookUseCase.UpdateBook(id, req.Title, req.Author)
+	book, err := h.bookUseCase.UpdateBook(c.UserContext(), id, CreateBookRequest(req).toInput(), version)
 	if err != nil {
-		// Podría ser 400 (validación) o 404 (no existe)
-		// En este caso, simplificamos con 400
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// Podría ser 400 (validación), 404 (no existe), 412 (otra versión) o 500 (fallo técnico)
+		// respondError distingue cada caso gracias a los errores tipados del dominio
+		return respondError(c, err)
 	}
 
 	// PASO 4: Retornar respuesta exitosa
-	// 200 OK es apropiado para actualizaciones exitosas
+	// 200 OK es apropiado para actualizaciones exitosas; el ETag nuevo sirve para la próxima edición
+	c.Set(fiber.HeaderETag, bookETag(book))
 	return c.JSON(book)
 }
 
@@ -193,17 +303,20 @@ func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
 //
 // 🗑️ Handler para eliminar un recurso
 // Retorna 204 No Content en caso de éxito
+// 🔢 If-Match funciona igual que en UpdateBook
 func (h *BookHandler) DeleteBook(c *fiber.Ctx) error {
-	// PASO 1: Obtener el ID del parámetro de la URL
+	// PASO 1: Obtener el ID del parámetro de la URL y la versión que leyó el cliente
 	id := c.Params("id")
+	version, err := ifMatch(c, h.storedVersion(c, id))
+	if err != nil {
+		return respondError(c, err)
+	}
 
 	// PASO 2: Llamar al caso de uso
-	err := h.bookUseCase.DeleteBook(id)
+	err = h.bookUseCase.DeleteBook(c.UserContext(), id, version)
 	if err != nil {
-		// 404 Not Found si el libro no existe
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// 404 Not Found si el libro no existe, 412 si cambió desde que se leyó
+		return respondError(c, err)
 	}
 
 	// PASO 3: Retornar respuesta exitosa sin contenido
@@ -211,6 +324,17 @@ func (h *BookHandler) DeleteBook(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
+// storedVersion da la versión guardada del libro, para un If-Match con varias versiones
+func (h *BookHandler) storedVersion(c *fiber.Ctx, id string) func() (int, error) {
+	return func() (int, error) {
+		book, err := h.bookUseCase.GetBookByID(c.UserContext(), id)
+		if err != nil {
+			return 0, err
+		}
+		return book.Version, nil
+	}
+}
+
 // UserHandler maneja las peticiones HTTP relacionadas con usuarios
 //
 // 👤 Misma estructura que BookHandler, pero para usuarios
@@ -228,8 +352,9 @@ func NewUserHandler(userUseCase *usecase.UserUseCase) *UserHandler {
 
 // CreateUserRequest representa la estructura de datos esperada para crear un usuario
 type CreateUserRequest struct {
-	Name  string `json:"name"`  // Nombre del usuario
-	Email string `json:"email"` // Email del usuario
+	Name     string `json:"name"`     // Nombre del usuario
+	Email    string `json:"email"`    // Email del usuario
+	Password string `json:"password"` // Contraseña (se guarda solo su hash)
 }
 
 // UpdateUserRequest representa la estructura de datos esperada para actualizar un usuario
@@ -238,6 +363,11 @@ type UpdateUserRequest struct {
 	Email string `json:"email"` // Email del usuario
 }
 
+// ChangeUserRoleRequest representa el rol nuevo de un usuario
+type ChangeUserRoleRequest struct {
+	Role domain.Role `json:"role"` // admin | librarian | member
+}
+
 // CreateUser maneja las peticiones POST /api/users
 //
 // 👤 Mismo patrón que CreateBook, pero para usuarios
@@ -246,17 +376,13 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	// Parsear el body de la petición
 	var req CreateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Formato de petición inválido",
-		})
+		return respondError(c, errInvalidBody)
 	}
 
 	// Llamar al caso de uso
-	user, err := h.userUseCase.CreateUser(req.Name, req.Email)
+	user, err := h.userUseCase.CreateUser(c.UserContext(), req.Name, req.Email, req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
 	// Retornar respuesta exitosa
@@ -264,66 +390,112 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 }
 
 // GetUserByID maneja las peticiones GET /api/users/:id
+// 🔢 ETag e If-None-Match como en GetBookByID
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	user, err := h.userUseCase.GetUserByID(id)
+	user, err := h.userUseCase.GetUserByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
-	return c.JSON(user)
+	return respondVersioned(c, versionETag(user.Version), user)
 }
 
 // GetAllUsers maneja las peticiones GET /api/users
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks;
+// sort: name | email | created_at, filtros: name, email
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
-	users, err := h.userUseCase.GetAllUsers()
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	query := domain.UserQuery{
+		PageRequest: page,
+		Filter: domain.UserFilter{
+			Name:  c.Query("name"),
+			Email: c.Query("email"),
+		},
+	}
+
+	users, err := h.userUseCase.ListUsers(c.UserContext(), query)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
-	return c.JSON(users)
+	return respondPage(c, users)
 }
 
 // UpdateUser maneja las peticiones PUT /api/users/:id
+// 🔢 If-Match como en UpdateBook
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
+	version, err := ifMatch(c, h.storedVersion(c, id))
+	if err != nil {
+		return respondError(c, err)
+	}
 
 	var req UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Formato de petición inválido",
-		})
+		return respondError(c, errInvalidBody)
+	}
+
+	user, err := h.userUseCase.UpdateUser(c.UserContext(), id, req.Name, req.Email, version)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, versionETag(user.Version))
+	return c.JSON(user)
+}
+
+// ChangeUserRole maneja las peticiones PUT /api/users/:id/role
+// 🛡️ Solo un admin; 403 si no lo es o si intenta cambiar su propio rol
+func (h *UserHandler) ChangeUserRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req ChangeUserRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
 	}
 
-	user, err := h.userUseCase.UpdateUser(id, req.Name, req.Email)
+	user, err := h.userUseCase.ChangeUserRole(c.UserContext(), id, req.Role)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
 	return c.JSON(user)
 }
 
 // DeleteUser maneja las peticiones DELETE /api/users/:id
+// 🔢 If-Match como en UpdateBook
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	id := c.Params("id")
+	version, err := ifMatch(c, h.storedVersion(c, id))
+	if err != nil {
+		return respondError(c, err)
+	}
 
-	err := h.userUseCase.DeleteUser(id)
+	err = h.userUseCase.DeleteUser(c.UserContext(), id, version)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondError(c, err)
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
+// storedVersion da la versión guardada del usuario, para un If-Match con varias versiones
+func (h *UserHandler) storedVersion(c *fiber.Ctx, id string) func() (int, error) {
+	return func() (int, error) {
+		user, err := h.userUseCase.GetUserByID(c.UserContext(), id)
+		if err != nil {
+			return 0, err
+		}
+		return user.Version, nil
+	}
+}
+
 // 💡 CONSEJOS PARA PRINCIPIANTES:
 //
 // 1. 🎯 Un handler = Un endpoint específico
@@ -338,6 +510,8 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 // - 204 No Content: operación exitosa sin contenido de respuesta
 // - 400 Bad Request: error en la petición del cliente
 // - 404 Not Found: recurso no encontrado
+// - 409 Conflict: conflicto con el estado actual (ej: email duplicado)
+// - 412 Precondition Failed: el If-Match no coincide (otro cambió el recurso)
 // - 500 Internal Server Error: error interno del servidor
 //
 // 🚫 EJEMPLOS DE LO QUE NO DEBES PONER AQUÍ:
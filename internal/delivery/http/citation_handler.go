@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/citation"
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CitationHandler maneja las referencias bibliográficas de los libros (BibTeX, RIS, CSL-JSON)
+type CitationHandler struct {
+	bookUseCase *usecase.BookUseCase
+	timeout     time.Duration // Deadline de una lista completa (ver config.ExportTimeout)
+}
+
+// NewCitationHandler crea el handler de citas
+func NewCitationHandler(bookUseCase *usecase.BookUseCase, timeout time.Duration) *CitationHandler {
+	return &CitationHandler{bookUseCase: bookUseCase, timeout: timeout}
+}
+
+// CiteBook maneja las peticiones GET /api/books/:id/citation
+//
+// 🔎 format: bibtex (por defecto) | ris | csl-json
+// 📋 La respuesta es el texto de la referencia, listo para pegar en un .bib o importar
+// en un gestor de referencias (en CSL-JSON, un array con un elemento)
+func (h *CitationHandler) CiteBook(c *fiber.Ctx) error {
+	format, err := citationFormat(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	book, err := h.bookUseCase.GetBookByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	var buf bytes.Buffer
+	w, err := citation.NewWriter(&buf, format)
+	if err != nil {
+		return respondError(c, err)
+	}
+	if err := w.Write(book); err != nil {
+		return respondError(c, err)
+	}
+	if err := w.Close(); err != nil {
+		return respondError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	return c.Send(buf.Bytes())
+}
+
+// CiteBooks maneja las peticiones GET /api/books/citation
+//
+// 🔎 format como en CiteBook; author, title, author_id, language, sort y order como en GET /api/books
+// 🌊 Trae TODOS los libros que cumplen los filtros, en streaming (como GET /api/books/export):
+// el resultado es la bibliografía completa, descargable como libros.bib, libros.ris o libros.json
+func (h *CitationHandler) CiteBooks(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	format, err := citationFormat(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	newWriter := func(w io.Writer) (tabular.ItemWriter[*domain.Book], error) {
+		return citation.NewWriter(w, format)
+	}
+	filter := bookFilter(c)
+	list := func(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Book], error) {
+		return h.bookUseCase.ListBooks(ctx, domain.BookQuery{PageRequest: p, Filter: filter})
+	}
+	return export(c, h.timeout, "libros."+format.Extension(), format.ContentType(), newWriter, exportPage(page), list)
+}
+
+// citationFormat lee el formato de la query (bibtex por defecto)
+func citationFormat(c *fiber.Ctx) (citation.Format, error) {
+	return citation.ParseFormat(c.Query("format", string(citation.FormatBibTeX)))
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// CopyHandler maneja las peticiones HTTP del inventario de ejemplares
+//
+// 📚 Los ejemplares se crean y listan como subrecurso del libro (/api/books/:id/copies)
+// y se consultan o modifican por su propio ID (/api/copies/:id)
+type CopyHandler struct {
+	copyUseCase *usecase.CopyUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewCopyHandler constructor para CopyHandler
+func NewCopyHandler(copyUseCase *usecase.CopyUseCase) *CopyHandler {
+	return &CopyHandler{
+		copyUseCase: copyUseCase,
+	}
+}
+
+// CreateCopyRequest representa la estructura de datos esperada para dar de alta un ejemplar
+type CreateCopyRequest struct {
+	Barcode   string `json:"barcode"`   // Código de barras (único)
+	Condition string `json:"condition"` // new | good | fair | poor | damaged (por defecto good)
+	Location  string `json:"location"`  // Ubicación en la biblioteca
+}
+
+// UpdateCopyRequest representa la estructura de datos esperada para actualizar un ejemplar
+type UpdateCopyRequest CreateCopyRequest
+
+// ChangeCopyStatusRequest representa el cambio de estado de un ejemplar
+type ChangeCopyStatusRequest struct {
+	Status string `json:"status"` // available | lost | in_repair
+}
+
+// CreateCopy maneja las peticiones POST /api/books/:id/copies
+//
+// 📊 201 Created, 404 si el libro no existe, 409 si el código de barras ya está en uso
+func (h *CopyHandler) CreateCopy(c *fiber.Ctx) error {
+	var req CreateCopyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	// ⚠️ c.Params apunta a un buffer que Fiber reutiliza en la próxima petición:
+	// como el ID del libro se GUARDA en el ejemplar, hay que copiarlo
+	bookID := utils.CopyString(c.Params("id"))
+
+	cp, err := h.copyUseCase.AddCopy(c.UserContext(), bookID, toCopyInput(req))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(cp)
+}
+
+// GetBookCopies maneja las peticiones GET /api/books/:id/copies
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks;
+// sort: barcode (por defecto) | created_at, filtro: status
+func (h *CopyHandler) GetBookCopies(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	copies, err := h.copyUseCase.ListBookCopies(c.UserContext(), c.Params("id"), domain.CopyQuery{
+		PageRequest: page,
+		Filter:      domain.CopyFilter{Status: domain.CopyStatus(c.Query("status"))},
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, copies)
+}
+
+// GetBookAvailability maneja las peticiones GET /api/books/:id/availability
+//
+// 📦 Responde cuántos ejemplares hay en cada estado: {"total": 3, "available": 1, ...}
+func (h *CopyHandler) GetBookAvailability(c *fiber.Ctx) error {
+	counts, err := h.copyUseCase.BookAvailability(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(counts)
+}
+
+// GetCopyByID maneja las peticiones GET /api/copies/:id
+func (h *CopyHandler) GetCopyByID(c *fiber.Ctx) error {
+	cp, err := h.copyUseCase.GetCopyByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(cp)
+}
+
+// UpdateCopy maneja las peticiones PUT /api/copies/:id
+// ⚠️ El estado se cambia con PUT /api/copies/:id/status
+func (h *CopyHandler) UpdateCopy(c *fiber.Ctx) error {
+	var req UpdateCopyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	cp, err := h.copyUseCase.UpdateCopy(c.UserContext(), c.Params("id"), toCopyInput(CreateCopyRequest(req)))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(cp)
+}
+
+// ChangeCopyStatus maneja las peticiones PUT /api/copies/:id/status
+//
+// 🔒 409 Conflict si el ejemplar está prestado (se libera al registrar la devolución)
+func (h *CopyHandler) ChangeCopyStatus(c *fiber.Ctx) error {
+	var req ChangeCopyStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	cp, err := h.copyUseCase.ChangeCopyStatus(c.UserContext(), c.Params("id"), domain.CopyStatus(req.Status))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(cp)
+}
+
+// DeleteCopy maneja las peticiones DELETE /api/copies/:id
+//
+// 🔒 409 Conflict si el ejemplar está prestado
+func (h *CopyHandler) DeleteCopy(c *fiber.Ctx) error {
+	if err := h.copyUseCase.DeleteCopy(c.UserContext(), c.Params("id")); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// toCopyInput convierte el body de la petición en la entrada del caso de uso
+func toCopyInput(req CreateCopyRequest) usecase.CopyInput {
+	return usecase.CopyInput{
+		Barcode:   req.Barcode,
+		Condition: domain.CopyCondition(req.Condition),
+		Location:  req.Location,
+	}
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ProblemContentType es el Content-Type de las respuestas de error (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem es el cuerpo de TODAS las respuestas de error de la API (RFC 7807)
+//
+// 📋 Ejemplo:
+//
+//	{
+//	  "type": "/problems/validation-error",
+//	  "title": "Validation failed",
+//	  "status": 400,
+//	  "detail": "los datos enviados no son válidos",
+//	  "instance": "/api/books",
+//	  "code": "validation_error",
+//	  "errors": [
+//	    {"field": "title", "code": "required", "message": "el título del libro es obligatorio"},
+//	    {"field": "author", "code": "required", "message": "el autor del libro es obligatorio"}
+//	  ]
+//	}
+//
+// 💡 "type", "code" y errors[].code son estables y pensados para máquinas;
+// "title" se traduce según Accept-Language (en/es) y "detail" es el mensaje del dominio
+type Problem struct {
+	Type     string              `json:"type"`             // URI que identifica el tipo de problema
+	Title    string              `json:"title"`            // Resumen corto del tipo de problema
+	Status   int                 `json:"status"`           // Código HTTP
+	Detail   string              `json:"detail,omitempty"` // Explicación de ESTA ocurrencia
+	Instance string              `json:"instance"`         // Ruta de la petición que falló
+	Code     string              `json:"code"`             // Extensión: código estable (not_found, conflict, ...)
+	Errors   []domain.FieldError `json:"errors,omitempty"` // Extensión: detalle por campo
+}
+
+// problemType describe cómo se presenta una categoría de error
+type problemType struct {
+	status  int
+	code    string
+	titleEN string
+	titleES string
+}
+
+// Tipos de problema que expone la API
+var (
+	problemValidation   = problemType{fiber.StatusBadRequest, "validation_error", "Validation failed", "Datos inválidos"}
+	problemUnauthorized = problemType{fiber.StatusUnauthorized, "unauthorized", "Authentication required", "Autenticación requerida"}
+	problemForbidden    = problemType{fiber.StatusForbidden, "forbidden", "Permission denied", "Permiso denegado"}
+	problemNotFound     = problemType{fiber.StatusNotFound, "not_found", "Resource not found", "Recurso no encontrado"}
+	problemConflict     = problemType{fiber.StatusConflict, "conflict", "Conflict with current state", "Conflicto con el estado actual"}
+	problemPrecondition = problemType{fiber.StatusPreconditionFailed, "precondition_failed", "Precondition failed", "La versión no coincide"}
+	problemTimeout      = problemType{fiber.StatusGatewayTimeout, "timeout", "Request timed out", "La petición tardó demasiado"}
+	problemInternal     = problemType{fiber.StatusInternalServerError, "internal_error", "Internal server error", "Error interno del servidor"}
+)
+
+// classifyError traduce un error del dominio a su tipo de problema HTTP
+//
+// 🗺️ Tabla de traducción:
+// - domain.ErrValidation     → 400 Bad Request
+// - domain.ErrUnauthorized   → 401 Unauthorized
+// - domain.ErrForbidden      → 403 Forbidden
+// - domain.ErrNotFound       → 404 Not Found
+// - domain.ErrConflict       → 409 Conflict
+// - domain.ErrPreconditionFailed → 412 Precondition Failed (If-Match, ver etag.go)
+// - context.DeadlineExceeded → 504 Gateway Timeout
+// - cualquier otro           → 500 Internal Server Error
+//
+// 🎯 Este es el ÚNICO lugar donde se decide el status de un error de negocio
+func classifyError(err error) problemType {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return problemValidation
+	case errors.Is(err, domain.ErrUnauthorized):
+		return problemUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return problemForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return problemNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return problemConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return problemPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		return problemTimeout
+	default:
+		return problemInternal
+	}
+}
+
+// respondError escribe la respuesta problem+json correspondiente a err
+//
+// 🚨 Para errores internos NO mostramos el mensaje original al cliente
+// (podría contener detalles de la base de datos); lo registramos en el log
+func respondError(c *fiber.Ctx, err error) error {
+	pt := classifyError(err)
+
+	detail := err.Error()
+	switch pt {
+	case problemInternal:
+		log.Printf("Error interno en %s %s: %v", c.Method(), c.Path(), err)
+		detail = ""
+	case problemTimeout:
+		detail = ""
+	case problemUnauthorized:
+		// RFC 6750: un 401 indica con qué esquema autenticarse
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api"`)
+	}
+
+	problem := newProblem(c, pt, detail)
+
+	// Detalle por campo de los errores de validación
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		problem.Errors = domainErr.Fields
+	}
+
+	return writeProblem(c, problem)
+}
+
+// ErrorHandler es el manejador global de errores de Fiber
+//
+// 🔧 Se configura en main.go: fiber.New(fiber.Config{ErrorHandler: http.ErrorHandler})
+// Cubre los errores que NO pasan por un handler nuestro:
+// - Rutas inexistentes (404) o métodos no permitidos (405)
+// - Body demasiado grande (413)
+// - Cualquier error que un handler retorne sin responder
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		title := utils.StatusMessage(fiberErr.Code)
+		return writeProblem(c, Problem{
+			Type:     "about:blank", // RFC 7807: sin semántica extra más allá del status
+			Title:    title,
+			Status:   fiberErr.Code,
+			Detail:   fiberErr.Message,
+			Instance: c.OriginalURL(),
+			Code:     "http_error",
+		})
+	}
+
+	return respondError(c, err)
+}
+
+// newProblem arma un Problem con el título en el idioma que pide el cliente
+func newProblem(c *fiber.Ctx, pt problemType, detail string) Problem {
+	title := pt.titleEN
+	if c.AcceptsLanguages("en", "es") == "es" {
+		title = pt.titleES
+	}
+
+	return Problem{
+		Type:     "/problems/" + pt.slug(),
+		Title:    title,
+		Status:   pt.status,
+		Detail:   detail,
+		Instance: c.OriginalURL(),
+		Code:     pt.code,
+	}
+}
+
+// writeProblem serializa el problema con el Content-Type de RFC 7807
+func writeProblem(c *fiber.Ctx, problem Problem) error {
+	if err := c.Status(problem.Status).JSON(problem); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, ProblemContentType)
+	return nil
+}
+
+// slug convierte el código (validation_error) en el segmento de URI (validation-error)
+func (pt problemType) slug() string {
+	b := []byte(pt.code)
+	for i := range b {
+		if b[i] == '_' {
+			b[i] = '-'
+		}
+	}
+	return string(b)
+}
+
+// errInvalidBody es el error que devolvemos cuando el JSON no se puede parsear
+var errInvalidBody = domain.NewValidationError("Formato de petición inválido")
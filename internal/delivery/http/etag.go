@@ -0,0 +1,154 @@
+package http
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 🔢 Concurrencia optimista con ETag / If-Match
+//
+// Cada libro y usuario tiene una versión que sube con cada cambio. GET la envía en el
+// header ETag y la edición la devuelve en If-Match:
+//
+//	GET /api/books/123              → ETag: "3"
+//	PUT /api/books/123 If-Match: "3" → 200 OK, ETag: "4" (el mismo que dará el próximo GET)
+//	PUT /api/books/123 If-Match: "3" → 412 Precondition Failed (otro ya guardó la 4)
+//
+// 💾 Con If-None-Match el cliente revalida su copia: si el ETag no cambió, 304 sin cuerpo
+// ⚠️ Sin If-Match la edición no se verifica (como antes): el header es opcional
+
+// versionETag es el ETag de una versión ("3")
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// bookETag es el ETag de un libro
+//
+// 📦 Los ejemplares disponibles no son parte del libro (no cambian su versión), pero sí
+// de la respuesta: van después de un punto ("3.2") para que un préstamo invalide el 304.
+// 👥 Lo mismo pasa con el nombre de los autores vinculados: se completa al leer (ver
+// domain.BookAuthor), así que renombrar un autor no sube la versión del libro. Un resumen
+// de los nombres va al final ("3.2-1a2b3c4d") para que el renombre también invalide el 304.
+// If-Match solo mira la versión, así que editar sigue funcionando tras un préstamo.
+func bookETag(book *domain.Book) string {
+	tag := strconv.Itoa(book.Version)
+	if book.AvailableCopies != nil {
+		tag += "." + strconv.Itoa(*book.AvailableCopies)
+	}
+	if len(book.Authors) > 0 {
+		tag += "-" + authorsDigest(book.Authors)
+	}
+	return `"` + tag + `"`
+}
+
+// authorsDigest resume los vínculos de un libro tal como se muestran (ID, nombre y rol)
+func authorsDigest(authors []domain.BookAuthor) string {
+	h := fnv.New32a()
+	for _, a := range authors {
+		h.Write([]byte(a.AuthorID + "\x00" + a.Name + "\x00" + string(a.Role) + "\x00"))
+	}
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
+
+// ifMatch lee la versión esperada del header If-Match
+//
+// 🔍 Acepta un ETag de GET ("3", "3.2" o "3.2-1a2b3c4d") o una lista de ellos
+// ("3", "4"); sin header o con "*" retorna 0 (sin verificar). Un valor que no es un
+// ETag nuestro es un error de validación: mejor un 400 que ignorarlo y pisar cambios en silencio.
+//
+// ⚠️ If-Match NO compara el ETag completo: solo la versión ("3" de "3.2-1a2b3c4d").
+// Los ejemplares y los autores no son parte del libro, así que editar sigue valiendo
+// tras un préstamo o un renombre; lo que se protege es no pisar la edición de otro.
+// 💪 Sí se respeta que RFC 9110 pide comparación fuerte: un ETag débil (W/"3") nunca
+// coincide, así que si la lista no trae ninguno fuerte la respuesta es 412.
+// 📋 Con varias versiones, current da la guardada: si está en la lista se usa esa, y si
+// no, la primera (el repositorio la rechaza con el 412 del recurso).
+func ifMatch(c *fiber.Ctx, current func() (int, error)) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	var versions []int
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		n, ok := tagVersion(strings.TrimPrefix(tag, "W/"))
+		if !ok {
+			return 0, domain.NewFieldsError(domain.FieldError{Field: "If-Match", Code: domain.CodeInvalidFormat,
+				Message: `If-Match debe ser un ETag recibido en un GET (ej: "3")`})
+		}
+		if !weak {
+			versions = append(versions, n)
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, errWeakIfMatch
+	case 1:
+		return versions[0], nil
+	}
+	stored, err := current()
+	if err != nil {
+		return 0, err
+	}
+	if slices.Contains(versions, stored) {
+		return stored, nil
+	}
+	return versions[0], nil
+}
+
+// errWeakIfMatch es el 412 de un If-Match que solo trae ETags débiles
+var errWeakIfMatch = domain.NewPreconditionFailedError(`If-Match compara ETags fuertes: W/"3" nunca coincide, envía "3"`)
+
+// tagVersion lee la versión de un ETag fuerte nuestro ("3", "3.2" o "3.2-1a2b3c4d")
+func tagVersion(tag string) (int, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	version := tag[1 : len(tag)-1]
+	if end := strings.IndexAny(version, ".-"); end >= 0 {
+		version = version[:end]
+	}
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(version)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// notModified indica si el cliente ya tiene esta versión (If-None-Match)
+//
+// 💡 Acepta una lista de ETags o "*", y los compara sin distinguir débiles
+// (W/"3" == "3"), como pide el RFC 9110 para GET
+func notModified(c *fiber.Ctx, etag string) bool {
+	header := c.Get(fiber.HeaderIfNoneMatch)
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondVersioned responde un recurso con su ETag, o 304 si el cliente ya lo tiene
+func respondVersioned(c *fiber.Ctx, etag string, body any) error {
+	c.Set(fiber.HeaderETag, etag)
+	if notModified(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.JSON(body)
+}
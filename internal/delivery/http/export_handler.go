@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler maneja la exportación del catálogo y de los usuarios a archivos
+//
+// 🌊 La respuesta se escribe mientras se recorren las páginas del repositorio
+// (de a usecase.MaxPageLimit, siguiendo el cursor): exportar cien mil libros usa
+// la misma memoria que exportar diez
+type ExportHandler struct {
+	bookUseCase *usecase.BookUseCase
+	userUseCase *usecase.UserUseCase
+	timeout     time.Duration // Deadline de la exportación (ver config.ExportTimeout)
+}
+
+// NewExportHandler crea el handler de exportación
+func NewExportHandler(bookUseCase *usecase.BookUseCase, userUseCase *usecase.UserUseCase, timeout time.Duration) *ExportHandler {
+	return &ExportHandler{bookUseCase: bookUseCase, userUseCase: userUseCase, timeout: timeout}
+}
+
+// ExportBooks maneja las peticiones GET /api/books/export
+//
+// 🔎 Parámetros de query:
+// - format: csv (por defecto) | ndjson | xlsx | marc | marcxml
+// - author, title, author_id, language, sort, order: los mismos filtros y el mismo orden que GET /api/books
+//
+// 📋 Columnas (csv y xlsx), siempre en este orden: id, title, author, isbn, publisher,
+// publication_year, language, page_count, edition, subjects, description, created_at
+// 📚 marc y marcxml: un registro MARC21 por libro (ver delivery/marc)
+func (h *ExportHandler) ExportBooks(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	format, err := exportFormat(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	newWriter := func(w io.Writer) (tabular.ItemWriter[*domain.Book], error) {
+		return tabular.NewBookWriter(w, format)
+	}
+	filter := bookFilter(c)
+	list := func(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Book], error) {
+		return h.bookUseCase.ListBooks(ctx, domain.BookQuery{PageRequest: p, Filter: filter})
+	}
+	return export(c, h.timeout, "libros."+format.Extension(), format.ContentType(), newWriter, exportPage(page), list)
+}
+
+// ExportUsers maneja las peticiones GET /api/users/export (requiere users:read)
+//
+// 🔎 format como en ExportBooks (salvo MARC, que solo describe libros); name, email,
+// sort y order como en GET /api/users
+// 📋 Columnas: id, name, email, role, created_at
+func (h *ExportHandler) ExportUsers(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	format, err := exportFormat(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	if format.IsMARC() {
+		return respondError(c, domain.NewFieldsError(domain.FieldError{
+			Field:   "format",
+			Code:    domain.CodeInvalidFormat,
+			Message: "MARC solo describe libros: usa csv, ndjson o xlsx",
+		}))
+	}
+	newWriter := func(w io.Writer) (tabular.ItemWriter[*domain.User], error) {
+		return tabular.NewWriter(w, format, tabular.UserColumns)
+	}
+	filter := domain.UserFilter{Name: c.Query("name"), Email: c.Query("email")}
+	list := func(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.User], error) {
+		return h.userUseCase.ListUsers(ctx, domain.UserQuery{PageRequest: p, Filter: filter})
+	}
+	return export(c, h.timeout, "usuarios."+format.Extension(), format.ContentType(), newWriter, exportPage(page), list)
+}
+
+// exportFormat lee el formato de la query (csv por defecto)
+func exportFormat(c *fiber.Ctx) (tabular.Format, error) {
+	return tabular.ParseFormat(c.Query("format", string(tabular.FormatCSV)))
+}
+
+// exportPage conserva solo el orden de la query: la exportación trae TODO lo que
+// cumple los filtros, así que limit, offset y cursor no aplican
+func exportPage(p domain.PageRequest) domain.PageRequest {
+	return domain.PageRequest{Limit: usecase.MaxPageLimit, Sort: p.Sort, Desc: p.Desc}
+}
+
+// export pide la primera página y escribe el archivo en streaming
+//
+// 🚦 La primera página se pide ANTES de responder: un filtro inválido o la falta
+// de permisos llegan como un error normal (problem+json con su status)
+//
+// ⚠️ Una vez que empezó el archivo, el status 200 ya se envió: si una página
+// posterior falla, el error se registra en el log y el archivo queda cortado
+func export[T any](c *fiber.Ctx, timeout time.Duration, filename, contentType string,
+	newWriter func(io.Writer) (tabular.ItemWriter[T], error), first domain.PageRequest,
+	list func(context.Context, domain.PageRequest) (*domain.Page[T], error)) error {
+	// ⏱️ El stream se escribe DESPUÉS de que el handler retorna (y de que RequestTimeout
+	// cancela su context): la exportación tiene su propio deadline
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), timeout)
+	page, err := list(ctx, first)
+	if err != nil {
+		cancel()
+		return respondError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		if err := writeExport(ctx, w, newWriter, page, list); err != nil {
+			log.Printf("Exportación de %s cortada: %v", filename, err)
+		}
+	})
+	return nil
+}
+
+// writeExport escribe page y las páginas siguientes, hasta que no quede cursor
+func writeExport[T any](ctx context.Context, w *bufio.Writer, newWriter func(io.Writer) (tabular.ItemWriter[T], error), page *domain.Page[T],
+	list func(context.Context, domain.PageRequest) (*domain.Page[T], error)) error {
+	out, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+	for {
+		for _, item := range page.Items {
+			if err := out.Write(item); err != nil {
+				return err // Lo más común: el cliente cerró la conexión
+			}
+		}
+		if page.NextCursor == "" {
+			return out.Close()
+		}
+		if page, err = list(ctx, domain.PageRequest{Cursor: page.NextCursor}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FineHandler maneja las peticiones HTTP de multas
+//
+// 💰 Las multas no se crean por HTTP: las genera el atraso de los préstamos.
+// Por HTTP se consultan, se pagan y se perdonan.
+type FineHandler struct {
+	fineUseCase *usecase.FineUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewFineHandler constructor para FineHandler
+func NewFineHandler(fineUseCase *usecase.FineUseCase) *FineHandler {
+	return &FineHandler{
+		fineUseCase: fineUseCase,
+	}
+}
+
+// PayFineRequest representa un pago de multa
+type PayFineRequest struct {
+	Amount int64 `json:"amount_cents"` // Importe del pago en centavos
+}
+
+// WaiveFineRequest representa el perdón de una multa
+type WaiveFineRequest struct {
+	Reason string `json:"reason"` // Motivo (obligatorio)
+}
+
+// GetFineByID maneja las peticiones GET /api/fines/:id
+func (h *FineHandler) GetFineByID(c *fiber.Ctx) error {
+	fine, err := h.fineUseCase.GetFineByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fine)
+}
+
+// GetAllFines maneja las peticiones GET /api/fines
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks;
+// sort: created_at | amount, filtros: user_id, loan_id, status (open | paid | waived)
+func (h *FineHandler) GetAllFines(c *fiber.Ctx) error {
+	query, err := parseFineQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	query.Filter.UserID = c.Query("user_id")
+
+	fines, err := h.fineUseCase.ListFines(c.UserContext(), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, fines)
+}
+
+// GetUserFines maneja las peticiones GET /api/users/:id/fines
+func (h *FineHandler) GetUserFines(c *fiber.Ctx) error {
+	query, err := parseFineQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	fines, err := h.fineUseCase.ListUserFines(c.UserContext(), c.Params("id"), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, fines)
+}
+
+// GetUserBalance maneja las peticiones GET /api/users/:id/balance
+//
+// 📊 Deuda total del usuario, el límite configurado y si hoy puede pedir préstamos
+func (h *FineHandler) GetUserBalance(c *fiber.Ctx) error {
+	balance, err := h.fineUseCase.UserBalance(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(balance)
+}
+
+// PayFine maneja las peticiones POST /api/fines/:id/pay
+//
+// 📊 Códigos de estado HTTP utilizados:
+// - 200 OK: pago registrado (status pasa a paid si la multa es final y quedó en cero)
+// - 400 Bad Request: importe no positivo
+// - 409 Conflict: la multa ya está cerrada o el pago supera lo que falta pagar
+func (h *FineHandler) PayFine(c *fiber.Ctx) error {
+	var req PayFineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	fine, err := h.fineUseCase.PayFine(c.UserContext(), c.Params("id"), req.Amount)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fine)
+}
+
+// WaiveFine maneja las peticiones POST /api/fines/:id/waive
+//
+// 🔁 409 Conflict si la multa ya estaba pagada o perdonada
+func (h *FineHandler) WaiveFine(c *fiber.Ctx) error {
+	var req WaiveFineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	fine, err := h.fineUseCase.WaiveFine(c.UserContext(), c.Params("id"), req.Reason)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fine)
+}
+
+// parseFineQuery lee la paginación y los filtros comunes de los listados de multas
+func parseFineQuery(c *fiber.Ctx) (domain.FineQuery, error) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return domain.FineQuery{}, err
+	}
+
+	return domain.FineQuery{
+		PageRequest: page,
+		Filter: domain.FineFilter{
+			LoanID: c.Query("loan_id"),
+			Status: domain.FineStatus(c.Query("status")),
+		},
+	}, nil
+}
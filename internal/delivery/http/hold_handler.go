@@ -0,0 +1,122 @@
+package http
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// HoldHandler maneja las peticiones HTTP de la cola de reservas
+//
+// ⏳ Reservar es anotarse en la cola de un libro (POST /api/books/:id/holds);
+// la reserva se consulta o cancela por su propio ID (/api/holds/:id)
+type HoldHandler struct {
+	holdUseCase *usecase.HoldUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewHoldHandler constructor para HoldHandler
+func NewHoldHandler(holdUseCase *usecase.HoldUseCase) *HoldHandler {
+	return &HoldHandler{
+		holdUseCase: holdUseCase,
+	}
+}
+
+// PlaceHoldRequest representa la estructura de datos esperada para reservar un libro
+type PlaceHoldRequest struct {
+	UserID string `json:"user_id"` // Usuario que se anota en la cola ("" = el usuario autenticado)
+}
+
+// PlaceHold maneja las peticiones POST /api/books/:id/holds
+//
+// 📊 Códigos de estado HTTP utilizados:
+// - 201 Created: reserva anotada (position indica su lugar en la cola)
+// - 400 Bad Request: falta el usuario o no existe
+// - 404 Not Found: el libro no existe
+// - 409 Conflict: hay ejemplares disponibles o el usuario ya tiene una reserva activa del libro
+func (h *HoldHandler) PlaceHold(c *fiber.Ctx) error {
+	var req PlaceHoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	// ⚠️ El ID del libro se GUARDA en la reserva: hay que copiarlo del buffer de Fiber
+	bookID := utils.CopyString(c.Params("id"))
+
+	hold, err := h.holdUseCase.PlaceHold(c.UserContext(), bookID, req.UserID)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// GetHoldByID maneja las peticiones GET /api/holds/:id
+func (h *HoldHandler) GetHoldByID(c *fiber.Ctx) error {
+	hold, err := h.holdUseCase.GetHoldByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(hold)
+}
+
+// GetBookHolds maneja las peticiones GET /api/books/:id/holds
+//
+// 🔎 Por defecto en orden de la cola; filtros: status (waiting | ready | fulfilled | cancelled | expired)
+func (h *HoldHandler) GetBookHolds(c *fiber.Ctx) error {
+	query, err := parseHoldQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	holds, err := h.holdUseCase.ListBookHolds(c.UserContext(), c.Params("id"), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, holds)
+}
+
+// GetUserHolds maneja las peticiones GET /api/users/:id/holds
+func (h *HoldHandler) GetUserHolds(c *fiber.Ctx) error {
+	query, err := parseHoldQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	holds, err := h.holdUseCase.ListUserHolds(c.UserContext(), c.Params("id"), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, holds)
+}
+
+// CancelHold maneja las peticiones POST /api/holds/:id/cancel
+//
+// 🔁 409 Conflict si la reserva ya estaba retirada, cancelada o vencida
+func (h *HoldHandler) CancelHold(c *fiber.Ctx) error {
+	hold, err := h.holdUseCase.CancelHold(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(hold)
+}
+
+// parseHoldQuery lee la paginación y el filtro de estado de los listados de reservas
+func parseHoldQuery(c *fiber.Ctx) (domain.HoldQuery, error) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return domain.HoldQuery{}, err
+	}
+
+	return domain.HoldQuery{
+		PageRequest: page,
+		Filter: domain.HoldFilter{
+			Status: domain.HoldStatus(c.Query("status")),
+		},
+	}, nil
+}
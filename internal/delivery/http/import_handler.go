@@ -0,0 +1,163 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strconv"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxImportFieldBytes es el tamaño máximo de un campo del formulario que no es el archivo
+const maxImportFieldBytes = 4 << 10
+
+// BookImportHandler maneja la importación masiva de libros
+//
+// 🌊 Lee el archivo a medida que llega: el servidor corre con StreamRequestBody
+// (ver main.go), así que un catálogo de cientos de MB no se carga en memoria
+type BookImportHandler struct {
+	bookUseCase *usecase.BookUseCase
+	timeout     time.Duration // Deadline de la importación (ver config.ImportTimeout)
+}
+
+// NewBookImportHandler crea el handler de importación
+func NewBookImportHandler(bookUseCase *usecase.BookUseCase, timeout time.Duration) *BookImportHandler {
+	return &BookImportHandler{bookUseCase: bookUseCase, timeout: timeout}
+}
+
+// ImportBooks maneja las peticiones POST /api/books/import
+//
+// 📤 Cuerpo multipart/form-data con el archivo en el campo "file" (CSV, NDJSON o MARC21)
+//
+// 🔎 Opciones, en la query o como campos del formulario ANTES del archivo
+// (el archivo se procesa apenas llega, así que lo que venga después no se lee):
+// - format: csv | ndjson | marc | marcxml (por defecto, según la extensión: .mrc = marc, .xml = marcxml)
+// - mapping: columnas con otro nombre, ej: "titulo=title,autor=author" (no aplica a MARC)
+// - dry_run: true = validar sin guardar nada
+// - upsert: true = un ISBN que ya existe actualiza ese libro
+//
+// 📋 Respuesta 200 (aunque haya filas rechazadas):
+// - Por defecto, el reporte en JSON (ver domain.ImportReport)
+// - Con Accept: text/csv, las filas rechazadas como CSV descargable y los
+// totales en los headers X-Import-Total, X-Import-Created, X-Import-Updated y X-Import-Rejected
+//
+// ❌ 400 si el cuerpo no es multipart, falta el archivo, el formato es desconocido
+// o el encabezado no tiene ninguna columna conocida
+func (h *BookImportHandler) ImportBooks(c *fiber.Ctx) error {
+	mediaType, params, err := mime.ParseMediaType(string(c.Request().Header.ContentType()))
+	if err != nil || mediaType != fiber.MIMEMultipartForm || params["boundary"] == "" {
+		return respondError(c, domain.NewValidationError("se esperaba multipart/form-data con el archivo en el campo file"))
+	}
+
+	// Con StreamRequestBody el body es un stream; sin él (bodies chicos), ya está en memoria
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+	defer discardRest(c, body)
+
+	options := map[string]string{
+		"format":  c.Query("format"),
+		"mapping": c.Query("mapping"),
+		"dry_run": c.Query("dry_run"),
+		"upsert":  c.Query("upsert"),
+	}
+	form := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return respondError(c, domain.NewFieldsError(domain.FieldError{
+				Field:   "file",
+				Code:    domain.CodeRequired,
+				Message: "falta el archivo a importar (campo file del formulario)",
+			}))
+		}
+		if err != nil {
+			return respondError(c, domain.NewValidationError("el cuerpo multipart no es válido"))
+		}
+
+		if part.FormName() == "file" {
+			return h.importFile(c, part, options)
+		}
+		value, err := io.ReadAll(io.LimitReader(part, maxImportFieldBytes))
+		if err != nil {
+			return respondError(c, domain.NewValidationError("el cuerpo multipart no es válido"))
+		}
+		options[part.FormName()] = string(value)
+	}
+}
+
+// importFile importa el archivo con las opciones recibidas y escribe el reporte
+func (h *BookImportHandler) importFile(c *fiber.Ctx, file *multipart.Part, options map[string]string) error {
+	var v domain.Validator
+	opts := usecase.BookImportOptions{
+		DryRun: parseBool(&v, "dry_run", options["dry_run"]),
+		Upsert: parseBool(&v, "upsert", options["upsert"]),
+	}
+	if err := v.Err(); err != nil {
+		return respondError(c, err)
+	}
+
+	format, err := tabular.ResolveFormat(options["format"], file.FileName())
+	if err != nil {
+		return respondError(c, err)
+	}
+	mapping, err := tabular.ParseMapping(options["mapping"])
+	if err != nil {
+		return respondError(c, err)
+	}
+	rows, err := tabular.NewBookReader(file, format, mapping)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	// ⏱️ Un catálogo grande tarda más que REQUEST_TIMEOUT: la importación tiene su propio
+	// deadline (WithoutCancel conserva el usuario autenticado, pero no el deadline anterior)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), h.timeout)
+	defer cancel()
+
+	report, err := h.bookUseCase.ImportBooks(ctx, rows, opts)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	if c.Accepts(fiber.MIMEApplicationJSON, "text/csv") != "text/csv" {
+		return c.JSON(report)
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="rechazos.csv"`)
+	c.Set("X-Import-Total", strconv.Itoa(report.Total))
+	c.Set("X-Import-Created", strconv.Itoa(report.Created))
+	c.Set("X-Import-Updated", strconv.Itoa(report.Updated))
+	c.Set("X-Import-Rejected", strconv.Itoa(report.Rejected))
+	return tabular.WriteRejections(c.Response().BodyWriter(), report.Rejections)
+}
+
+// discardRest consume lo que quedó sin leer del cuerpo (el cierre del multipart, o el
+// archivo entero si hubo un error antes)
+//
+// 🔌 Si queda mucho, se cierra la conexión en lugar de leerlo: el cliente no puede
+// reusarla con un cuerpo a medio leer
+func discardRest(c *fiber.Ctx, body io.Reader) {
+	if n, _ := io.CopyN(io.Discard, body, maxImportFieldBytes+1); n > maxImportFieldBytes {
+		c.Context().SetConnectionClose()
+	}
+}
+
+// parseBool lee una opción booleana ("" = false)
+func parseBool(v *domain.Validator, key, raw string) bool {
+	if raw == "" {
+		return false
+	}
+	value, err := strconv.ParseBool(raw)
+	v.Check(err == nil, key, domain.CodeInvalidFormat, "el parámetro "+key+" debe ser true o false")
+	return value
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoanHandler maneja las peticiones HTTP de préstamos
+//
+// 🔗 Préstamos como recurso propio: prestar es crear un préstamo (POST /api/loans)
+// y devolver o renovar son acciones sobre él (POST /api/loans/:id/return)
+type LoanHandler struct {
+	loanUseCase *usecase.LoanUseCase // Dependencia inyectada del caso de uso
+}
+
+// NewLoanHandler constructor para LoanHandler
+func NewLoanHandler(loanUseCase *usecase.LoanUseCase) *LoanHandler {
+	return &LoanHandler{
+		loanUseCase: loanUseCase,
+	}
+}
+
+// CheckoutRequest representa la estructura de datos esperada para prestar un libro
+type CheckoutRequest struct {
+	BookID string `json:"book_id"` // Libro a prestar
+	CopyID string `json:"copy_id"` // Ejemplar concreto (opcional: si falta, el primero disponible)
+	UserID string `json:"user_id"` // Usuario que se lo lleva
+}
+
+// Checkout maneja las peticiones POST /api/loans
+//
+// 📊 Códigos de estado HTTP utilizados:
+// - 201 Created: préstamo registrado (due_at indica el vencimiento)
+// - 400 Bad Request: faltan IDs, el libro/usuario no existe o el ejemplar no es de ese libro
+// - 409 Conflict: no hay ejemplares disponibles o el usuario llegó a su límite
+func (h *LoanHandler) Checkout(c *fiber.Ctx) error {
+	var req CheckoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, errInvalidBody)
+	}
+
+	loan, err := h.loanUseCase.Checkout(c.UserContext(), usecase.CheckoutInput{
+		BookID: req.BookID,
+		CopyID: req.CopyID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(loan)
+}
+
+// GetLoanByID maneja las peticiones GET /api/loans/:id
+func (h *LoanHandler) GetLoanByID(c *fiber.Ctx) error {
+	loan, err := h.loanUseCase.GetLoanByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(loan)
+}
+
+// GetAllLoans maneja las peticiones GET /api/loans
+//
+// 🔎 Mismos parámetros de paginación que GetAllBooks;
+// sort: checked_out_at | due_at, filtros: user_id, book_id, status (active | returned | overdue)
+func (h *LoanHandler) GetAllLoans(c *fiber.Ctx) error {
+	query, err := parseLoanQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+	query.Filter.UserID = c.Query("user_id")
+
+	loans, err := h.loanUseCase.ListLoans(c.UserContext(), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, loans)
+}
+
+// GetUserLoans maneja las peticiones GET /api/users/:id/loans
+//
+// 👤 Historial de préstamos de un usuario; ?status=active para ver solo lo que tiene ahora
+func (h *LoanHandler) GetUserLoans(c *fiber.Ctx) error {
+	query, err := parseLoanQuery(c)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	loans, err := h.loanUseCase.ListUserLoans(c.UserContext(), c.Params("id"), query)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondPage(c, loans)
+}
+
+// ReturnLoan maneja las peticiones POST /api/loans/:id/return
+//
+// 🔁 409 Conflict si el préstamo ya estaba devuelto
+func (h *LoanHandler) ReturnLoan(c *fiber.Ctx) error {
+	loan, err := h.loanUseCase.ReturnLoan(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(loan)
+}
+
+// RenewLoan maneja las peticiones POST /api/loans/:id/renew
+//
+// 📅 409 Conflict si está devuelto, vencido o sin renovaciones disponibles
+func (h *LoanHandler) RenewLoan(c *fiber.Ctx) error {
+	loan, err := h.loanUseCase.RenewLoan(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(loan)
+}
+
+// parseLoanQuery lee la paginación y los filtros comunes de los listados de préstamos
+func parseLoanQuery(c *fiber.Ctx) (domain.LoanQuery, error) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return domain.LoanQuery{}, err
+	}
+
+	return domain.LoanQuery{
+		PageRequest: page,
+		Filter: domain.LoanFilter{
+			BookID: c.Query("book_id"),
+			Status: domain.LoanStatus(c.Query("status")),
+		},
+	}, nil
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestTimeout crea un middleware que le pone un deadline a cada petición
+//
+// ⏱️ ¿Por qué lo necesitamos?
+//   - Fiber (fasthttp) entrega por defecto un context.Background() sin deadline
+//   - Sin deadline, una query lenta puede quedarse corriendo para siempre
+//   - Con este middleware, c.UserContext() se cancela al vencer el tiempo
+//     y los repositorios abortan su trabajo (QueryRowContext, ctx.Err(), etc.)
+//
+// 💡 Los handlers solo tienen que pasar c.UserContext() a los casos de uso
+func RequestTimeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel() // Liberar el timer al terminar la petición
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+// BodyLimit crea un middleware que rechaza con 413 los bodies de más de max bytes
+//
+// 🌊 ¿Por qué no alcanza con el BodyLimit de Fiber?
+//   - El servidor corre con StreamRequestBody para que POST /api/books/import lea
+//     el archivo a medida que llega, sin cargarlo entero en memoria
+//   - Con streaming, Fiber ya no corta los bodies grandes: c.Body() leería todo
+//   - Este middleware recupera el límite para todos los demás bodies
+//
+// 💡 streamed son las rutas que leen el body en streaming y quedan afuera del límite,
+// como "POST /api/books/import" (ver routes.StreamedRoutes). Solo se exime esa ruta:
+// un multipart/form-data a cualquier otra se corta como un JSON.
+func BodyLimit(max int, streamed ...string) fiber.Handler {
+	exempt := make(map[string]bool, len(streamed))
+	for _, route := range streamed {
+		exempt[strings.ToUpper(route)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		length := c.Request().Header.ContentLength()
+		stream := c.Context().RequestBodyStream()
+		if length == 0 || stream == nil || exempt[strings.ToUpper(c.Method()+" "+strings.TrimSuffix(c.Path(), "/"))] {
+			return c.Next()
+		}
+		if length > max {
+			// ⚠️ El resto del body queda sin leer: se cierra la conexión para que no se
+			// interprete como la petición siguiente
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+
+		// Largo desconocido (chunked) o conocido y dentro del límite: se lee hasta max+1
+		body, err := io.ReadAll(io.LimitReader(stream, int64(max)+1))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		if len(body) > max {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		c.Request().SetBody(body)
+		return c.Next()
+	}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PageResponse es el sobre JSON de todos los listados paginados
+//
+// 📋 Ejemplo:
+//
+//	{
+//	  "data": [ ... ],
+//	  "pagination": {"total": 42, "limit": 20, "offset": 20, "next_cursor": "eyJv...", "prev_cursor": "eyJv..."},
+//	  "links": {"self": "/api/books?limit=20&offset=20", "next": "/api/books?cursor=eyJv...", "prev": "/api/books?cursor=eyJv..."}
+//	}
+type PageResponse[T any] struct {
+	Data       []T            `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+	Links      PageLinks      `json:"links"`
+}
+
+// PaginationInfo describe la página devuelta
+type PaginationInfo struct {
+	Total      int    `json:"total"`                 // Total de elementos que cumplen los filtros
+	Limit      int    `json:"limit"`                 // Límite aplicado
+	Offset     int    `json:"offset"`                // Offset aplicado
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor de la página siguiente
+	PrevCursor string `json:"prev_cursor,omitempty"` // Cursor de la página anterior
+}
+
+// PageLinks son URLs listas para usar (HATEOAS light)
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// parsePageRequest lee ?limit, ?offset, ?cursor, ?sort y ?order
+//
+// 🔤 El orden se puede expresar de dos formas equivalentes:
+// - ?sort=title&order=desc
+// - ?sort=-title (el prefijo "-" significa descendente)
+//
+// 💡 Aquí solo se valida el FORMATO (que limit sea un número);
+// los rangos y campos permitidos los valida el caso de uso
+func parsePageRequest(c *fiber.Ctx) (domain.PageRequest, error) {
+	var v domain.Validator
+	p := domain.PageRequest{Cursor: c.Query("cursor")}
+
+	p.Limit = queryInt(c, &v, "limit")
+	p.Offset = queryInt(c, &v, "offset")
+
+	sort := c.Query("sort")
+	if strings.HasPrefix(sort, "-") {
+		sort, p.Desc = sort[1:], true
+	}
+	p.Sort = sort
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "":
+	case "asc":
+		p.Desc = false
+	case "desc":
+		p.Desc = true
+	default:
+		v.Add("order", domain.CodeInvalidFormat, "el orden debe ser asc o desc")
+	}
+
+	return p, v.Err()
+}
+
+// queryInt lee un parámetro entero opcional y registra un error si no es un número
+func queryInt(c *fiber.Ctx, v *domain.Validator, key string) int {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	v.Check(err == nil, key, domain.CodeInvalidFormat, "el parámetro "+key+" debe ser un número entero")
+	return n
+}
+
+// respondPage escribe una página con el sobre data/pagination/links
+func respondPage[T any](c *fiber.Ctx, page *domain.Page[T]) error {
+	return c.JSON(PageResponse[T]{
+		Data: page.Items,
+		Pagination: PaginationInfo{
+			Total:      page.Total,
+			Limit:      page.Limit,
+			Offset:     page.Offset,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		},
+		Links: PageLinks{
+			Self: c.OriginalURL(),
+			Next: cursorLink(c, page.NextCursor),
+			Prev: cursorLink(c, page.PrevCursor),
+		},
+	})
+}
+
+// cursorLink arma la URL de otra página (vacía si no hay cursor)
+func cursorLink(c *fiber.Ctx, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	return c.Path() + "?cursor=" + url.QueryEscape(cursor)
+}
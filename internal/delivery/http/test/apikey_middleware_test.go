@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// adminCtx es el contexto del admin que emite las API keys de los tests
+var adminCtx = usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+
+// newAPIKey emite una API key de solo lectura para el usuario (expires nil = no vence)
+func (f *authFixture) newAPIKey(t *testing.T, userID string, expires *time.Time) *domain.IssuedAPIKey {
+	t.Helper()
+	key, err := f.apiKeys.CreateAPIKey(adminCtx, usecase.APIKeyInput{Name: "lector OPDS", UserID: userID,
+		Scopes: []domain.Permission{domain.PermBooksRead}, ExpiresAt: expires})
+	if err != nil {
+		t.Fatalf("No se pudo crear la API key: %v", err)
+	}
+	return key
+}
+
+// basic arma un header "Authorization: Basic" con la contraseña indicada
+func basic(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
+
+// TestAuthenticate_APIKey verifica X-API-Key, su precedencia sobre el Bearer y que /api no acepte Basic
+func TestAuthenticate_APIKey(t *testing.T) {
+	// Arrange: Ana tiene una API key y Beto un access token
+	f := newAuthFixture()
+	ana := f.newUser(t, "ana@example.com")
+	beto := f.newUser(t, "beto@example.com")
+	key := f.newAPIKey(t, ana, nil)
+	token := f.login(t, "beto@example.com", oldKey)
+	app := f.authApp(t, oldKey)
+
+	tests := []struct {
+		name    string
+		headers []string
+		status  int
+		user    string
+		viaKey  bool
+		detail  string
+	}{
+		{"X-API-Key", []string{api.HeaderAPIKey, key.Key}, fiber.StatusOK, ana, true, ""},
+		// 🔑 La API key se prueba ANTES que el JWT: con ambos headers gana la clave
+		{"X-API-Key y Bearer", []string{api.HeaderAPIKey, key.Key, fiber.HeaderAuthorization, "Bearer " + token}, fiber.StatusOK, ana, true, ""},
+		// 🚫 Una clave inválida es 401 aunque venga un Bearer válido: no se cae al JWT
+		{"X-API-Key inválida y Bearer", []string{api.HeaderAPIKey, key.Key + "x", fiber.HeaderAuthorization, "Bearer " + token}, fiber.StatusUnauthorized, "", false, domain.ErrInvalidAPIKey.Error()},
+		{"solo Bearer", []string{fiber.HeaderAuthorization, "Bearer " + token}, fiber.StatusOK, beto, false, ""},
+		// 🛡️ En /api, Basic no autentica aunque traiga la clave (solo /opds lo acepta)
+		{"Basic con la clave", []string{fiber.HeaderAuthorization, basic("cualquiera", key.Key)}, fiber.StatusUnauthorized, "", false, domain.ErrAuthRequired.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			status, got, problem := callWhoami(t, app, tt.headers...)
+
+			// Assert
+			if status != tt.status {
+				t.Fatalf("Se esperaba %d, pero se obtuvo: %d %+v", tt.status, status, problem)
+			}
+			if problem != nil && problem.Detail != tt.detail {
+				t.Errorf("Se esperaba el detalle %q, pero se obtuvo: %+v", tt.detail, problem)
+			}
+			if problem == nil && (got.UserID != tt.user || (got.APIKeyID == key.ID) != tt.viaKey) {
+				t.Errorf("Se esperaba el usuario %s (con API key: %v), pero se obtuvo: %+v", tt.user, tt.viaKey, got)
+			}
+		})
+	}
+}
+
+// TestAuthenticateBasic_OnlyOPDS verifica que la clave como contraseña de Basic sirva en /opds
+// y no en /api
+//
+// 🛡️ Un navegador que respondió al desafío Basic de /opds reenvía las credenciales solo:
+// si /api las aceptara, cualquier página podría escribir con una clave de escritura (CSRF)
+func TestAuthenticateBasic_OnlyOPDS(t *testing.T) {
+	// Arrange: una bibliotecaria con una clave de escritura
+	f := newAuthFixture()
+	ana := f.newUser(t, "ana@example.com")
+	librarian, _ := f.users.GetByID(context.Background(), ana)
+	librarian.Role = domain.RoleLibrarian
+	if _, err := f.users.Update(context.Background(), librarian); err != nil {
+		t.Fatalf("No se pudo cambiar el rol: %v", err)
+	}
+	key, err := f.apiKeys.CreateAPIKey(adminCtx, usecase.APIKeyInput{Name: "catálogo", UserID: ana,
+		Scopes: []domain.Permission{domain.PermBooksRead, domain.PermBooksWrite}})
+	if err != nil {
+		t.Fatalf("No se pudo crear la API key: %v", err)
+	}
+	app := f.authApp(t, oldKey)
+	credentials := basic("cualquiera", key.Key)
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		headers []string
+		status  int
+		detail  string
+	}{
+		// 📱 /opds: la clave como contraseña, el usuario se ignora (apps de lectura)
+		{"Basic en /opds", "GET", "/opds/whoami", []string{fiber.HeaderAuthorization, credentials}, fiber.StatusOK, ""},
+		{"Basic con otra contraseña en /opds", "GET", "/opds/whoami", []string{fiber.HeaderAuthorization, basic("ana@example.com", "secreto123")}, fiber.StatusUnauthorized, domain.ErrInvalidAPIKey.Error()},
+		{"Basic mal formado en /opds", "GET", "/opds/whoami", []string{fiber.HeaderAuthorization, "Basic no-es-base64!"}, fiber.StatusUnauthorized, domain.ErrAuthRequired.Error()},
+		{"X-API-Key en /opds", "GET", "/opds/whoami", []string{api.HeaderAPIKey, key.Key}, fiber.StatusOK, ""},
+		// 🚫 /api: las mismas credenciales no sirven para crear un libro
+		{"Basic en POST /api/books", "POST", "/api/books", []string{fiber.HeaderAuthorization, credentials}, fiber.StatusUnauthorized, domain.ErrAuthRequired.Error()},
+		{"X-API-Key en POST /api/books", "POST", "/api/books", []string{api.HeaderAPIKey, key.Key}, fiber.StatusCreated, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			status, got, problem := call(t, app, tt.method, tt.target, tt.headers...)
+
+			// Assert
+			if status != tt.status {
+				t.Fatalf("Se esperaba %d, pero se obtuvo: %d %+v", tt.status, status, problem)
+			}
+			if problem != nil && problem.Detail != tt.detail {
+				t.Errorf("Se esperaba el detalle %q, pero se obtuvo: %+v", tt.detail, problem)
+			}
+			if tt.method == "GET" && problem == nil && (got.UserID != ana || got.APIKeyID != key.ID) {
+				t.Errorf("Se esperaba a Ana con su API key, pero se obtuvo: %+v", got)
+			}
+		})
+	}
+}
+
+// TestAuthenticate_InactiveKeys verifica que una clave vencida o revocada dé 401
+func TestAuthenticate_InactiveKeys(t *testing.T) {
+	// Arrange
+	f := newAuthFixture()
+	ana := f.newUser(t, "ana@example.com")
+	expires := f.now.Add(time.Hour)
+	expiring := f.newAPIKey(t, ana, &expires)
+	revoked := f.newAPIKey(t, ana, nil)
+	app := f.authApp(t, oldKey)
+	if err := f.apiKeys.RevokeAPIKey(adminCtx, revoked.ID); err != nil {
+		t.Fatalf("No se pudo revocar la clave: %v", err)
+	}
+
+	// Act: antes del vencimiento, la clave vale
+	status, _, problem := callWhoami(t, app, api.HeaderAPIKey, expiring.Key)
+
+	// Assert
+	if status != fiber.StatusOK {
+		t.Fatalf("Se esperaba 200 antes del vencimiento, pero se obtuvo: %d %+v", status, problem)
+	}
+
+	// Act: pasado el vencimiento, y con la revocada
+	f.now = expires.Add(time.Second)
+	for name, raw := range map[string]string{"vencida": expiring.Key, "revocada": revoked.Key} {
+		status, _, problem := callWhoami(t, app, api.HeaderAPIKey, raw)
+
+		// Assert
+		if status != fiber.StatusUnauthorized || problem == nil || problem.Detail != domain.ErrInvalidAPIKey.Error() {
+			t.Errorf("Se esperaba 401 con la clave %s, pero se obtuvo: %d %+v", name, status, problem)
+		}
+	}
+}
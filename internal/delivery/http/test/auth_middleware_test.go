@@ -0,0 +1,246 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/config"
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/repository"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Claves de firma de los tests: la rotación pasa de oldKey a newKey
+var (
+	oldKey = config.SigningKey{ID: "2024-01", Secret: []byte("clave-de-prueba-de-32-bytes-o-mas")}
+	newKey = config.SigningKey{ID: "2024-07", Secret: []byte("otra-clave-de-prueba-de-32-bytes!!")}
+)
+
+// authFixture son los repositorios y el reloj que comparten los servidores de un test
+//
+// 🔑 Cada servidor (authApp) puede arrancar con otras claves de firma, como tras
+// una rotación, pero ve los mismos usuarios, sesiones y API keys
+type authFixture struct {
+	users    repository.UserRepository
+	sessions repository.SessionRepository
+	apiKeys  *usecase.APIKeyUseCase
+	hasher   usecase.PasswordHasher
+	now      time.Time
+}
+
+// whoami es lo que responde la ruta protegida de los tests
+type whoami struct {
+	UserID   string `json:"user_id"`
+	APIKeyID string `json:"api_key_id"`
+}
+
+// newAuthFixture arma el fixture con el reloj en el momento actual
+func newAuthFixture() *authFixture {
+	f := &authFixture{
+		users:    memory.NewInMemoryUserRepository(),
+		sessions: memory.NewInMemorySessionRepository(),
+		hasher:   security.NewBcryptHasher(config.MinBcryptCost),
+		now:      time.Now().UTC(),
+	}
+	f.apiKeys = usecase.NewAPIKeyUseCase(memory.NewInMemoryAPIKeyRepository(), f.users, usecase.ClockFunc(f.clock))
+	return f
+}
+
+// clock es el reloj del fixture: solo avanza cuando el test cambia now
+func (f *authFixture) clock() time.Time {
+	return f.now
+}
+
+// newUser crea un lector con la contraseña "secreto123" y retorna su ID
+func (f *authFixture) newUser(t *testing.T, email string) string {
+	t.Helper()
+	users := usecase.NewUserUseCase(f.users, memory.NewInMemoryLoanRepository(memory.NewInMemoryCopyRepository()),
+		memory.NewInMemoryHoldRepository(), memory.NewInMemoryFineRepository(), f.hasher)
+	user, err := users.CreateUser(context.Background(), "Lector", email, "secreto123")
+	if err != nil {
+		t.Fatalf("No se pudo crear el usuario: %v", err)
+	}
+	return user.ID
+}
+
+// authUseCase arma la autenticación con las claves de firma indicadas (la primera es la activa)
+func (f *authFixture) authUseCase(t *testing.T, keys ...config.SigningKey) *usecase.AuthUseCase {
+	t.Helper()
+	tokens, err := security.NewJWTManager(config.AuthConfig{Keys: keys, ActiveKey: keys[0].ID, Issuer: "test"}, f.clock)
+	if err != nil {
+		t.Fatalf("No se pudo crear el firmador: %v", err)
+	}
+	return usecase.NewAuthUseCase(f.users, f.sessions, f.hasher, tokens, domain.DefaultTokenPolicy(), usecase.ClockFunc(f.clock))
+}
+
+// login inicia sesión con las claves indicadas y retorna el access token
+func (f *authFixture) login(t *testing.T, email string, keys ...config.SigningKey) string {
+	t.Helper()
+	pair, err := f.authUseCase(t, keys...).Login(context.Background(), email, "secreto123")
+	if err != nil {
+		t.Fatalf("No se pudo iniciar sesión: %v", err)
+	}
+	return pair.AccessToken
+}
+
+// authApp arma /api y /opds como en routes.SetupRoutes (API key y después JWT; en /opds
+// la API key también por Basic) con una ruta protegida que responde quién quedó autenticado,
+// además de las rutas reales de libros
+func (f *authFixture) authApp(t *testing.T, keys ...config.SigningKey) *fiber.App {
+	t.Helper()
+	apiKeys, auth := api.NewAPIKeyHandler(f.apiKeys), api.NewAuthHandler(f.authUseCase(t, keys...))
+	whoamiHandler := func(c *fiber.Ctx) error {
+		var got whoami
+		if user, ok := usecase.UserFromContext(c.UserContext()); ok {
+			got.UserID = user.ID
+		}
+		if key, ok := usecase.APIKeyFromContext(c.UserContext()); ok {
+			got.APIKeyID = key.ID
+		}
+		return c.JSON(got)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Get("/opds/whoami", apiKeys.AuthenticateBasic, auth.RequireAuth, whoamiHandler)
+	app.Use("/api", apiKeys.Authenticate, auth.RequireAuth)
+	app.Get("/api/whoami", whoamiHandler)
+	books, _ := newUseCases()
+	routes.SetupBookRoutes(app, api.NewBookHandler(books))
+	return app
+}
+
+// callWhoami pide la ruta protegida de /api con los headers indicados (pares nombre, valor)
+func callWhoami(t *testing.T, app *fiber.App, headers ...string) (int, whoami, *api.Problem) {
+	t.Helper()
+	return call(t, app, "GET", "/api/whoami", headers...)
+}
+
+// call hace la petición con los headers indicados y retorna quién quedó autenticado
+// o el problem+json del error
+func call(t *testing.T, app *fiber.App, method, target string, headers ...string) (int, whoami, *api.Problem) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(`{"title": "Rayuela", "author": "Julio Cortázar"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("La petición falló: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+
+	var got whoami
+	if resp.StatusCode < fiber.StatusBadRequest {
+		_ = json.Unmarshal(raw, &got)
+		return resp.StatusCode, got, nil
+	}
+	var problem api.Problem
+	if err := json.Unmarshal(raw, &problem); err != nil || resp.Header.Get(fiber.HeaderContentType) != api.ProblemContentType {
+		t.Fatalf("Se esperaba un problem+json, pero se obtuvo: %s", raw)
+	}
+	if resp.StatusCode == fiber.StatusUnauthorized && resp.Header.Get(fiber.HeaderWWWAuthenticate) == "" {
+		t.Errorf("Se esperaba el header WWW-Authenticate en el 401")
+	}
+	return resp.StatusCode, got, &problem
+}
+
+// TestRequireAuth_BearerToken verifica el middleware con tokens ausentes, mal formados y válidos
+func TestRequireAuth_BearerToken(t *testing.T) {
+	// Arrange
+	f := newAuthFixture()
+	ana := f.newUser(t, "ana@example.com")
+	app := f.authApp(t, oldKey)
+	token := f.login(t, "ana@example.com", oldKey)
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+		detail string
+	}{
+		{"sin header", "", fiber.StatusUnauthorized, domain.ErrAuthRequired.Error()},
+		{"otro esquema", "Token " + token, fiber.StatusUnauthorized, domain.ErrAuthRequired.Error()},
+		{"Bearer vacío", "Bearer ", fiber.StatusUnauthorized, domain.ErrAuthRequired.Error()},
+		{"token mal formado", "Bearer no-es-un-jwt", fiber.StatusUnauthorized, domain.ErrInvalidToken.Error()},
+		{"token válido", "Bearer " + token, fiber.StatusOK, ""},
+		{"esquema en minúsculas", "bearer " + token, fiber.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			var headers []string
+			if tt.header != "" {
+				headers = []string{fiber.HeaderAuthorization, tt.header}
+			}
+			status, got, problem := callWhoami(t, app, headers...)
+
+			// Assert
+			if status != tt.status {
+				t.Fatalf("Se esperaba %d, pero se obtuvo: %d %+v", tt.status, status, problem)
+			}
+			if problem != nil && (problem.Code != "unauthorized" || problem.Detail != tt.detail) {
+				t.Errorf("Se esperaba unauthorized con %q, pero se obtuvo: %+v", tt.detail, problem)
+			}
+			if problem == nil && got.UserID != ana {
+				t.Errorf("Se esperaba autenticar a Ana, pero se obtuvo: %+v", got)
+			}
+		})
+	}
+}
+
+// TestRequireAuth_ExpiredToken verifica que un access token vencido dé 401
+func TestRequireAuth_ExpiredToken(t *testing.T) {
+	// Arrange
+	f := newAuthFixture()
+	f.newUser(t, "ana@example.com")
+	app := f.authApp(t, oldKey)
+	token := f.login(t, "ana@example.com", oldKey)
+
+	// Act: pasa la vida del access token
+	f.now = f.now.Add(domain.DefaultTokenPolicy().AccessTTL + time.Second)
+	status, _, problem := callWhoami(t, app, fiber.HeaderAuthorization, "Bearer "+token)
+
+	// Assert
+	if status != fiber.StatusUnauthorized || problem == nil || problem.Detail != domain.ErrInvalidToken.Error() {
+		t.Errorf("Se esperaba 401 por el token vencido, pero se obtuvo: %d %+v", status, problem)
+	}
+}
+
+// TestRequireAuth_KeyRotation verifica un token firmado con la clave anterior: vale mientras
+// esa clave siga configurada y deja de valer cuando se retira
+func TestRequireAuth_KeyRotation(t *testing.T) {
+	// Arrange: el token se firma antes de la rotación
+	f := newAuthFixture()
+	ana := f.newUser(t, "ana@example.com")
+	token := f.login(t, "ana@example.com", oldKey)
+
+	// Act: el servidor firma con la clave nueva y todavía verifica con la anterior
+	status, got, problem := callWhoami(t, f.authApp(t, newKey, oldKey), fiber.HeaderAuthorization, "Bearer "+token)
+
+	// Assert
+	if status != fiber.StatusOK || got.UserID != ana {
+		t.Errorf("Se esperaba 200 con el kid anterior aún configurado, pero se obtuvo: %d %+v %+v", status, got, problem)
+	}
+
+	// Act: la clave anterior se retira
+	status, _, problem = callWhoami(t, f.authApp(t, newKey), fiber.HeaderAuthorization, "Bearer "+token)
+
+	// Assert
+	if status != fiber.StatusUnauthorized || problem == nil || problem.Detail != domain.ErrInvalidToken.Error() {
+		t.Errorf("Se esperaba 401 con el kid retirado, pero se obtuvo: %d %+v", status, problem)
+	}
+}
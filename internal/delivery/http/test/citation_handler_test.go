@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/config"
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newUseCases arma los casos de uso de libros y usuarios sobre repositorios en memoria
+// 💡 Lo comparten todos los tests del paquete
+func newUseCases() (*usecase.BookUseCase, *usecase.UserUseCase) {
+	copies := memory.NewInMemoryCopyRepository()
+	loans := memory.NewInMemoryLoanRepository(copies)
+	holds := memory.NewInMemoryHoldRepository()
+	fines := memory.NewInMemoryFineRepository()
+	books := usecase.NewBookUseCase(memory.NewInMemoryBookRepository(), memory.NewInMemoryAuthorRepository(), copies, loans, holds, fines)
+	users := usecase.NewUserUseCase(memory.NewInMemoryUserRepository(), loans, holds, fines, security.NewBcryptHasher(config.MinBcryptCost))
+	return books, users
+}
+
+// citationApp arma las rutas de citas con un lector autenticado y retorna el ID del primer libro
+func citationApp(t *testing.T, books ...usecase.BookInput) (*fiber.App, string) {
+	t.Helper()
+	bookUseCase, _ := newUseCases()
+	admin := usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+	var firstID string
+	for _, in := range books {
+		book, err := bookUseCase.CreateBook(admin, in)
+		if err != nil {
+			t.Fatalf("No se pudo crear el libro: %v", err)
+		}
+		if firstID == "" {
+			firstID = book.ID
+		}
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(usecase.ContextWithUser(c.UserContext(), &domain.User{ID: "u1", Role: domain.RoleMember}))
+		return c.Next()
+	})
+	routes.SetupCitationRoutes(app, api.NewCitationHandler(bookUseCase, time.Minute))
+	return app, firstID
+}
+
+// TestCiteBook verifica la cita de un libro en los tres formatos
+func TestCiteBook(t *testing.T) {
+	// Arrange
+	app, id := citationApp(t, usecase.BookInput{
+		Title: "Clean Code", Author: "Martin, Robert C.", Publisher: "Prentice Hall",
+		PublicationYear: 2008, ISBN: "9780132350884",
+	})
+
+	// Act: BibTeX (el formato por defecto)
+	status, contentType, body := get(t, app, "/api/books/"+id+"/citation")
+
+	// Assert
+	if status != fiber.StatusOK || !strings.HasPrefix(contentType, "application/x-bibtex") ||
+		!strings.HasPrefix(body, "@book{martin2008clean,\n") || !strings.Contains(body, "  isbn       = {9780132350884},\n") {
+		t.Errorf("Se esperaba la entrada BibTeX, pero se obtuvo: %d %s\n%s", status, contentType, body)
+	}
+
+	// Act: RIS
+	status, _, body = get(t, app, "/api/books/"+id+"/citation?format=ris")
+
+	// Assert
+	if status != fiber.StatusOK || !strings.HasPrefix(body, "TY  - BOOK\r\nID  - martin2008clean\r\nAU  - Martin, Robert C.\r\n") {
+		t.Errorf("Se esperaba el registro RIS, pero se obtuvo: %d %q", status, body)
+	}
+
+	// Act: CSL-JSON
+	status, contentType, body = get(t, app, "/api/books/"+id+"/citation?format=csl-json")
+
+	// Assert: un array con un elemento
+	var items []map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &items); err != nil || len(items) != 1 || items[0]["id"] != "martin2008clean" {
+		t.Errorf("Se esperaba un array CSL-JSON con un libro, pero se obtuvo: %d %s (%v)\n%s", status, contentType, err, body)
+	}
+}
+
+// TestCiteBooks verifica la bibliografía de una lista filtrada y los errores
+func TestCiteBooks(t *testing.T) {
+	// Arrange: dos libros con la misma clave base y uno que no cumple el filtro
+	app, _ := citationApp(t,
+		usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", PublicationYear: 2008},
+		usecase.BookInput{Title: "Clean Coder", Author: "Robert C. Martin", PublicationYear: 2008},
+		usecase.BookInput{Title: "Rayuela", Author: "Julio Cortázar", PublicationYear: 1963},
+	)
+
+	// Act
+	status, _, body := get(t, app, "/api/books/citation?author=martin&sort=title")
+
+	// Assert: claves únicas dentro del archivo
+	if status != fiber.StatusOK || strings.Count(body, "@book{") != 2 ||
+		!strings.Contains(body, "@book{martin2008clean,") || !strings.Contains(body, "@book{martin2008cleanb,") {
+		t.Errorf("Se esperaban dos entradas con claves distintas, pero se obtuvo: %d\n%s", status, body)
+	}
+
+	// Errores: antes de empezar la respuesta
+	for _, url := range []string{"/api/books/citation?format=apa", "/api/books/nada/citation"} {
+		status, contentType, _ := get(t, app, url)
+		if status < 400 || contentType != "application/problem+json" {
+			t.Errorf("Se esperaba un error problem+json para %s, pero se obtuvo: %d %s", url, status, contentType)
+		}
+	}
+}
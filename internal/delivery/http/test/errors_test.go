@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// problemResponse hace la petición y retorna la respuesta con su problem+json ya leído
+func problemResponse(t *testing.T, app *fiber.App, method, url, body, language string) (int, string, api.Problem) {
+	t.Helper()
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if language != "" {
+		req.Header.Set(fiber.HeaderAcceptLanguage, language)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("La petición falló: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var problem api.Problem
+	raw, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(raw, &problem); err != nil {
+		t.Fatalf("Se esperaba un problem+json, pero se obtuvo: %s", raw)
+	}
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderContentType), problem
+}
+
+// TestErrorHandler_StatusPerKind verifica el status, el tipo y el código de cada error del dominio
+func TestErrorHandler_StatusPerKind(t *testing.T) {
+	// Arrange: una ruta que retorna el error pedido, como haría un handler
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"validación", domain.NewValidationError("el título es obligatorio"), fiber.StatusBadRequest, "validation_error", "el título es obligatorio"},
+		{"no autenticado", domain.ErrInvalidCredentials, fiber.StatusUnauthorized, "unauthorized", domain.ErrInvalidCredentials.Error()},
+		{"sin permiso", domain.NewForbiddenError("solo un admin"), fiber.StatusForbidden, "forbidden", "solo un admin"},
+		{"no encontrado", domain.ErrBookNotFound, fiber.StatusNotFound, "not_found", domain.ErrBookNotFound.Error()},
+		{"conflicto", domain.ErrBookHasActiveLoans, fiber.StatusConflict, "conflict", domain.ErrBookHasActiveLoans.Error()},
+		{"versión", domain.ErrBookVersionMismatch, fiber.StatusPreconditionFailed, "precondition_failed", domain.ErrBookVersionMismatch.Error()},
+		{"envuelto", fmt.Errorf("al prestar: %w", domain.ErrBookNotFound), fiber.StatusNotFound, "not_found", "al prestar: " + domain.ErrBookNotFound.Error()},
+		// 🚨 Ni el timeout ni los errores internos muestran su mensaje al cliente
+		{"timeout", context.DeadlineExceeded, fiber.StatusGatewayTimeout, "timeout", ""},
+		{"interno", domain.NewInternalError("fallo la base", fmt.Errorf("pq: password authentication failed")), fiber.StatusInternalServerError, "internal_error", ""},
+		{"desconocido", fmt.Errorf("algo inesperado"), fiber.StatusInternalServerError, "internal_error", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+			app.Get("/api/books/:id", func(c *fiber.Ctx) error { return tt.err })
+
+			// Act
+			status, contentType, problem := problemResponse(t, app, "GET", "/api/books/123", "", "")
+
+			// Assert
+			if status != tt.status || problem.Status != tt.status {
+				t.Errorf("Se esperaba el status %d, pero se obtuvo: %d (cuerpo: %d)", tt.status, status, problem.Status)
+			}
+			if contentType != api.ProblemContentType {
+				t.Errorf("Se esperaba el Content-Type %s, pero se obtuvo: %s", api.ProblemContentType, contentType)
+			}
+			wantType := "/problems/" + strings.ReplaceAll(tt.code, "_", "-")
+			if problem.Code != tt.code || problem.Type != wantType || problem.Detail != tt.detail {
+				t.Errorf("Se esperaba %s (%s) con detalle %q, pero se obtuvo: %+v", tt.code, wantType, tt.detail, problem)
+			}
+			if tt.status == fiber.StatusUnauthorized {
+				resp, _ := app.Test(httptest.NewRequest("GET", "/api/books/123", nil), -1)
+				if got := resp.Header.Get(fiber.HeaderWWWAuthenticate); got != `Bearer realm="api"` {
+					t.Errorf("Se esperaba WWW-Authenticate en el 401, pero se obtuvo: %q", got)
+				}
+			}
+			if problem.Instance != "/api/books/123" || problem.Errors != nil {
+				t.Errorf("Se esperaba la ruta como instance y sin errores por campo, pero se obtuvo: %+v", problem)
+			}
+		})
+	}
+}
+
+// TestErrorHandler_FieldErrors verifica el arreglo errors de una validación real, con el
+// título traducido según Accept-Language
+func TestErrorHandler_FieldErrors(t *testing.T) {
+	// Arrange: las rutas de libros con un admin autenticado
+	books, _ := newUseCases()
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(usecase.ContextWithUser(c.UserContext(), &domain.User{ID: "admin", Role: domain.RoleAdmin}))
+		return c.Next()
+	})
+	routes.SetupBookRoutes(app, api.NewBookHandler(books))
+
+	// Act: un libro sin título ni autor
+	status, contentType, problem := problemResponse(t, app, "POST", "/api/books", `{"title": "", "author": ""}`, "es")
+
+	// Assert: 400 problem+json con un error por campo, en orden
+	if status != fiber.StatusBadRequest || contentType != api.ProblemContentType {
+		t.Fatalf("Se esperaba 400 %s, pero se obtuvo: %d %s", api.ProblemContentType, status, contentType)
+	}
+	if problem.Code != "validation_error" || problem.Title != "Datos inválidos" {
+		t.Errorf("Se esperaba validation_error con el título en español, pero se obtuvo: %+v", problem)
+	}
+	if len(problem.Errors) != 2 || problem.Errors[0].Field != "title" || problem.Errors[1].Field != "author" {
+		t.Fatalf("Se esperaban los errores de title y author, pero se obtuvo: %+v", problem.Errors)
+	}
+	for _, field := range problem.Errors {
+		if field.Code != domain.CodeRequired || field.Message == "" {
+			t.Errorf("Se esperaba el código %s con mensaje, pero se obtuvo: %+v", domain.CodeRequired, field)
+		}
+	}
+
+	// Act: un JSON que no se puede leer
+	status, _, problem = problemResponse(t, app, "POST", "/api/books", `{"title": `, "en")
+
+	// Assert: también es una validación, con el título en inglés
+	if status != fiber.StatusBadRequest || problem.Code != "validation_error" || problem.Title != "Validation failed" {
+		t.Errorf("Se esperaba 400 validation_error en inglés, pero se obtuvo: %d %+v", status, problem)
+	}
+}
+
+// TestErrorHandler_FiberErrors verifica que los errores de Fiber (ej: ruta inexistente) también sean problem+json
+func TestErrorHandler_FiberErrors(t *testing.T) {
+	// Arrange
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Get("/api/books", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	// Act
+	status, contentType, problem := problemResponse(t, app, "GET", "/api/nada", "", "")
+
+	// Assert
+	if status != fiber.StatusNotFound || contentType != api.ProblemContentType || problem.Code != "http_error" || problem.Type != "about:blank" {
+		t.Errorf("Se esperaba 404 http_error como problem+json, pero se obtuvo: %d %s %+v", status, contentType, problem)
+	}
+}
@@ -0,0 +1,282 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/repository"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// versionApp arma las rutas de libros y usuarios con un admin autenticado
+// y retorna el ID de un libro y de un usuario ya creados
+func versionApp(t *testing.T) (*fiber.App, string, string) {
+	t.Helper()
+	bookUseCase, userUseCase := newUseCases()
+
+	admin := usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+	book, err := bookUseCase.CreateBook(admin, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin"})
+	if err != nil {
+		t.Fatalf("No se pudo crear el libro: %v", err)
+	}
+	user, err := userUseCase.CreateUser(admin, "Ana", "ana@example.com", "secreto123")
+	if err != nil {
+		t.Fatalf("No se pudo crear el usuario: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(usecase.ContextWithUser(c.UserContext(), &domain.User{ID: "admin", Role: domain.RoleAdmin}))
+		return c.Next()
+	})
+	routes.SetupBookRoutes(app, api.NewBookHandler(bookUseCase))
+	routes.SetupUserRoutes(app, api.NewUserHandler(userUseCase))
+	return app, book.ID, user.ID
+}
+
+// send hace la petición con un header opcional y retorna el status, el ETag y el cuerpo
+func send(t *testing.T, app *fiber.App, method, url, body, header, value string) (int, string, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("La petición falló: %v", err)
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderETag), string(got)
+}
+
+// TestBookETag verifica ETag, If-None-Match (304) e If-Match (412) en un libro
+func TestBookETag(t *testing.T) {
+	// Arrange
+	app, id, _ := versionApp(t)
+	url := "/api/books/" + id
+	body := `{"title": "Clean Code (2da)", "author": "Robert C. Martin"}`
+
+	// Act: GET envía el ETag (versión 1, sin ejemplares: 0 disponibles)
+	status, etag, _ := send(t, app, "GET", url, "", "", "")
+
+	// Assert
+	if status != fiber.StatusOK || etag != `"1.0"` {
+		t.Fatalf("Se esperaba 200 con ETag \"1.0\", pero se obtuvo: %d %s", status, etag)
+	}
+
+	// Act: con If-None-Match igual (débil o en una lista), 304 sin cuerpo
+	for _, value := range []string{etag, `W/` + etag, `"9", ` + etag, "*"} {
+		status, _, got := send(t, app, "GET", url, "", fiber.HeaderIfNoneMatch, value)
+		if status != fiber.StatusNotModified || got != "" {
+			t.Errorf("Se esperaba 304 sin cuerpo para If-None-Match %s, pero se obtuvo: %d %q", value, status, got)
+		}
+	}
+
+	// Act: PUT con la versión leída
+	status, newETag, got := send(t, app, "PUT", url, body, fiber.HeaderIfMatch, etag)
+
+	// Assert: la versión sube y vuelve el ETag nuevo
+	var book domain.Book
+	_ = json.Unmarshal([]byte(got), &book)
+	if status != fiber.StatusOK || book.Version != 2 || newETag != `"2.0"` {
+		t.Errorf("Se esperaba 200 con la versión 2, pero se obtuvo: %d %s %s", status, newETag, got)
+	}
+
+	// Act: otro PUT y un DELETE con la versión vieja
+	for _, method := range []string{"PUT", "DELETE"} {
+		status, _, got := send(t, app, method, url, body, fiber.HeaderIfMatch, etag)
+
+		// Assert: 412 con el código estable
+		if status != fiber.StatusPreconditionFailed || !strings.Contains(got, `"code":"precondition_failed"`) {
+			t.Errorf("Se esperaba 412 precondition_failed en %s, pero se obtuvo: %d %s", method, status, got)
+		}
+	}
+
+	// Act: un If-Match que no es un ETag
+	status, _, got = send(t, app, "PUT", url, body, fiber.HeaderIfMatch, "version-2")
+
+	// Assert
+	if status != fiber.StatusBadRequest || !strings.Contains(got, `"field":"If-Match"`) {
+		t.Errorf("Se esperaba 400 por el If-Match inválido, pero se obtuvo: %d %s", status, got)
+	}
+
+	// Act: DELETE con la versión actual
+	status, _, _ = send(t, app, "DELETE", url, "", fiber.HeaderIfMatch, `"2"`)
+
+	// Assert
+	if status != fiber.StatusNoContent {
+		t.Errorf("Se esperaba 204 al borrar con la versión actual, pero se obtuvo: %d", status)
+	}
+}
+
+// TestUserETag verifica que los usuarios usen las mismas reglas y que If-Match sea opcional
+func TestUserETag(t *testing.T) {
+	// Arrange
+	app, _, id := versionApp(t)
+	url := "/api/users/" + id
+
+	// Act
+	status, etag, _ := send(t, app, "GET", url, "", "", "")
+
+	// Assert
+	if status != fiber.StatusOK || etag != `"1"` {
+		t.Fatalf("Se esperaba 200 con ETag \"1\", pero se obtuvo: %d %s", status, etag)
+	}
+
+	// Act: PUT sin If-Match (no se verifica) y después con la versión vieja
+	status, newETag, _ := send(t, app, "PUT", url, `{"name": "Ana María", "email": "ana@example.com"}`, "", "")
+	stale, _, _ := send(t, app, "PUT", url, `{"name": "Ana", "email": "ana@example.com"}`, fiber.HeaderIfMatch, etag)
+
+	// Assert
+	if status != fiber.StatusOK || newETag != `"2"` {
+		t.Errorf("Se esperaba 200 con ETag \"2\" sin If-Match, pero se obtuvo: %d %s", status, newETag)
+	}
+	if stale != fiber.StatusPreconditionFailed {
+		t.Errorf("Se esperaba 412 con la versión vieja, pero se obtuvo: %d", stale)
+	}
+}
+
+// linkedBookApp arma las rutas de libros con un admin autenticado y un libro vinculado
+// a un autor, y retorna la app, la URL del libro y los casos de uso para cambiarlo por fuera
+func linkedBookApp(t *testing.T) (*fiber.App, string, *domain.Author, *usecase.AuthorUseCase, repository.CopyRepository) {
+	t.Helper()
+	bookRepo, authorRepo := memory.NewInMemoryBookRepository(), memory.NewInMemoryAuthorRepository()
+	copies := memory.NewInMemoryCopyRepository()
+	loans := memory.NewInMemoryLoanRepository(copies)
+	books := usecase.NewBookUseCase(bookRepo, authorRepo, copies, loans, memory.NewInMemoryHoldRepository(), memory.NewInMemoryFineRepository())
+	authors := usecase.NewAuthorUseCase(authorRepo, bookRepo)
+
+	admin := usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+	author, err := authors.CreateAuthor(admin, usecase.AuthorInput{Name: "Robert Martin"})
+	if err != nil {
+		t.Fatalf("No se pudo crear el autor: %v", err)
+	}
+	book, err := books.CreateBook(admin, usecase.BookInput{Title: "Clean Code",
+		Authors: []domain.BookAuthor{{AuthorID: author.ID, Role: domain.RoleAuthor}}})
+	if err != nil {
+		t.Fatalf("No se pudo crear el libro: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(admin)
+		return c.Next()
+	})
+	routes.SetupBookRoutes(app, api.NewBookHandler(books))
+	return app, "/api/books/" + book.ID, author, authors, copies
+}
+
+// TestBookETag_AuthorRename verifica que renombrar un autor vinculado invalide el 304
+//
+// 👥 El nombre no es parte del libro (no sube su versión), pero sí de la respuesta
+func TestBookETag_AuthorRename(t *testing.T) {
+	// Arrange: un libro vinculado a un autor
+	app, url, author, authors, _ := linkedBookApp(t)
+	admin := usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+	_, etag, _ := send(t, app, "GET", url, "", "", "")
+
+	// Act: renombrar el autor y revalidar la copia local
+	if _, err := authors.UpdateAuthor(admin, author.ID, usecase.AuthorInput{Name: "Robert C. Martin"}); err != nil {
+		t.Fatalf("No se pudo renombrar el autor: %v", err)
+	}
+	status, newETag, got := send(t, app, "GET", url, "", fiber.HeaderIfNoneMatch, etag)
+
+	// Assert: 200 con el nombre nuevo y otro ETag
+	if status != fiber.StatusOK || newETag == etag || !strings.Contains(got, "Robert C. Martin") {
+		t.Errorf("Se esperaba 200 con el nombre nuevo y otro ETag, pero se obtuvo: %d %s → %s %s", status, etag, newETag, got)
+	}
+
+	// Act: editar con el ETag completo de la lectura (la versión no cambió)
+	status, _, got = send(t, app, "PUT", url, `{"title": "Clean Code (2da)", "author": "Robert C. Martin"}`, fiber.HeaderIfMatch, newETag)
+
+	// Assert
+	if status != fiber.StatusOK {
+		t.Errorf("Se esperaba 200 al editar con el ETag del GET, pero se obtuvo: %d %s", status, got)
+	}
+}
+
+// TestBookETag_PutMatchesGet verifica que el ETag del PUT sea el mismo que da el GET
+// siguiente, con autores vinculados y ejemplares disponibles
+//
+// 💾 Así el cliente que guardó el ETag del PUT revalida con 304 en vez de bajar el libro otra vez
+func TestBookETag_PutMatchesGet(t *testing.T) {
+	// Arrange: un libro vinculado a un autor y con un ejemplar disponible
+	app, url, author, _, copies := linkedBookApp(t)
+	bookID := strings.TrimPrefix(url, "/api/books/")
+	if _, err := copies.Create(context.Background(), &domain.Copy{ID: "copia-1", BookID: bookID, Barcode: "B-001",
+		Condition: domain.ConditionGood, Status: domain.CopyAvailable}); err != nil {
+		t.Fatalf("No se pudo crear el ejemplar: %v", err)
+	}
+	_, etag, _ := send(t, app, "GET", url, "", "", "")
+
+	// Act
+	body := `{"title": "Clean Code (2da)", "authors": [{"author_id": "` + author.ID + `", "role": "author"}]}`
+	status, putETag, got := send(t, app, "PUT", url, body, fiber.HeaderIfMatch, etag)
+
+	// Assert: el PUT ya trae los ejemplares disponibles y los autores
+	var book domain.Book
+	_ = json.Unmarshal([]byte(got), &book)
+	if status != fiber.StatusOK || book.AvailableCopies == nil || *book.AvailableCopies != 1 || len(book.Authors) != 1 || book.Authors[0].Name == "" {
+		t.Fatalf("Se esperaba 200 con el ejemplar disponible y el autor, pero se obtuvo: %d %s", status, got)
+	}
+
+	// Act: el GET siguiente, revalidando con el ETag del PUT
+	status, getETag, got := send(t, app, "GET", url, "", fiber.HeaderIfNoneMatch, putETag)
+
+	// Assert
+	if putETag != getETag || status != fiber.StatusNotModified {
+		t.Errorf("Se esperaba el mismo ETag y 304, pero se obtuvo: PUT %s, GET %s, %d %s", putETag, getETag, status, got)
+	}
+}
+
+// TestBookETag_IfMatchList verifica la comparación fuerte de If-Match y las listas de ETags
+func TestBookETag_IfMatchList(t *testing.T) {
+	// Arrange: un libro recién creado (versión 1)
+	app, id, _ := versionApp(t)
+	url := "/api/books/" + id
+	body := `{"title": "Clean Code (2da)", "author": "Robert C. Martin"}`
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+		code   string
+	}{
+		// 💪 Un ETag débil nunca coincide en If-Match (RFC 9110): 412 aunque la versión sea la actual
+		{"débil", `W/"1"`, fiber.StatusPreconditionFailed, "precondition_failed"},
+		{"solo débiles", `W/"1", W/"1.0"`, fiber.StatusPreconditionFailed, "precondition_failed"},
+		{"lista sin la actual", `"5", "6"`, fiber.StatusPreconditionFailed, "precondition_failed"},
+		{"lista con un valor inválido", `"1", version-2`, fiber.StatusBadRequest, "validation_error"},
+		{"lista con la actual", `"7", W/"9", "1.0"`, fiber.StatusOK, ""},
+		{"lista con la nueva", `"2", "3"`, fiber.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			status, _, got := send(t, app, "PUT", url, body, fiber.HeaderIfMatch, tt.header)
+
+			// Assert
+			if status != tt.status || !strings.Contains(got, tt.code) {
+				t.Errorf("Se esperaba %d %s para If-Match %s, pero se obtuvo: %d %s", tt.status, tt.code, tt.header, status, got)
+			}
+		})
+	}
+
+	// Assert: solo los dos PUT que coincidieron subieron la versión
+	if _, etag, _ := send(t, app, "GET", url, "", "", ""); etag != `"3.0"` {
+		t.Errorf("Se esperaba el ETag \"3.0\", pero se obtuvo: %s", etag)
+	}
+}
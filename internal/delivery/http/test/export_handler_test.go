@@ -0,0 +1,148 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportApp arma las rutas de exportación con el usuario del rol indicado ya autenticado
+func exportApp(t *testing.T, role domain.Role, books int) *fiber.App {
+	t.Helper()
+	bookUseCase, userUseCase := newUseCases()
+
+	admin := usecase.ContextWithUser(context.Background(), &domain.User{ID: "admin", Role: domain.RoleAdmin})
+	for i := 0; i < books; i++ {
+		title := fmt.Sprintf("Libro %03d", i)
+		if i == 0 {
+			title = `Hola, "mundo"`
+		}
+		if _, err := bookUseCase.CreateBook(admin, usecase.BookInput{Title: title, Author: "Ana"}); err != nil {
+			t.Fatalf("No se pudo crear el libro: %v", err)
+		}
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(usecase.ContextWithUser(c.UserContext(), &domain.User{ID: "u1", Role: role}))
+		return c.Next()
+	})
+	routes.SetupExportRoutes(app, api.NewExportHandler(bookUseCase, userUseCase, time.Minute))
+	return app
+}
+
+// get hace la petición y retorna el status, el Content-Type y el cuerpo
+func get(t *testing.T, app *fiber.App, url string) (int, string, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", url, nil), -1)
+	if err != nil {
+		t.Fatalf("La petición falló: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderContentType), string(body)
+}
+
+// TestExportBooks_AllPages verifica que se exporten TODAS las páginas, en orden, en cada formato
+func TestExportBooks_AllPages(t *testing.T) {
+	// Arrange: más libros que una página
+	total := usecase.MaxPageLimit*2 + 5
+	app := exportApp(t, domain.RoleMember, total)
+
+	// Act: CSV ordenado por título
+	status, contentType, body := get(t, app, "/api/books/export?sort=title")
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if status != fiber.StatusOK || !strings.HasPrefix(contentType, "text/csv") || len(lines) != total+1 {
+		t.Fatalf("Se esperaban %d filas CSV, pero se obtuvo: %d %s (%d líneas)", total, status, contentType, len(lines))
+	}
+	if !strings.Contains(lines[1], `"Hola, ""mundo"""`) || !strings.Contains(lines[total], "Libro 204") {
+		t.Errorf("Se esperaban los libros en orden y bien escapados, pero se obtuvo: %q ... %q", lines[1], lines[total])
+	}
+
+	// Act: NDJSON, con un filtro
+	status, contentType, body = get(t, app, "/api/books/export?format=ndjson&title=libro%2010")
+
+	// Assert: un objeto por línea (Libro 010 ... Libro 109)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	count := 0
+	for scanner.Scan() {
+		var book domain.Book
+		if err := json.Unmarshal(scanner.Bytes(), &book); err != nil || !strings.HasPrefix(book.Title, "Libro 10") {
+			t.Errorf("Línea inesperada: %s (err: %v)", scanner.Text(), err)
+		}
+		count++
+	}
+	if status != fiber.StatusOK || contentType != "application/x-ndjson" || count != 10 {
+		t.Errorf("Se esperaban 10 libros en NDJSON, pero se obtuvo: %d %s (%d)", status, contentType, count)
+	}
+
+	// Act: xlsx
+	status, contentType, body = get(t, app, "/api/books/export?format=xlsx")
+
+	// Assert: un zip (empieza con "PK")
+	if status != fiber.StatusOK || !strings.Contains(contentType, "spreadsheetml") || !strings.HasPrefix(body, "PK") {
+		t.Errorf("Se esperaba un xlsx, pero se obtuvo: %d %s", status, contentType)
+	}
+
+	// Act: MARC21 binario y MARCXML
+	status, contentType, body = get(t, app, "/api/books/export?format=marc")
+
+	// Assert: un registro por libro, cada uno cerrado con 0x1D
+	if status != fiber.StatusOK || contentType != "application/marc" || strings.Count(body, "\x1d") != total {
+		t.Errorf("Se esperaban %d registros MARC, pero se obtuvo: %d %s (%d)", total, status, contentType, strings.Count(body, "\x1d"))
+	}
+	status, contentType, body = get(t, app, "/api/books/export?format=marcxml")
+	if status != fiber.StatusOK || contentType != "application/marcxml+xml" || strings.Count(body, "<record>") != total {
+		t.Errorf("Se esperaban %d registros MARCXML, pero se obtuvo: %d %s", total, status, contentType)
+	}
+}
+
+// TestExport_Errors verifica que los errores lleguen ANTES de empezar el archivo
+func TestExport_Errors(t *testing.T) {
+	app := exportApp(t, domain.RoleMember, 1)
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{"formato desconocido", "/api/books/export?format=pdf", fiber.StatusBadRequest},
+		{"usuarios en MARC", "/api/users/export?format=marc", fiber.StatusBadRequest},
+		{"orden inválido", "/api/books/export?sort=isbn", fiber.StatusBadRequest},
+		{"usuarios sin permiso", "/api/users/export", fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, contentType, _ := get(t, app, tt.url)
+			if status != tt.status || contentType != "application/problem+json" {
+				t.Errorf("Se esperaba %d con problem+json, pero se obtuvo: %d %s", tt.status, status, contentType)
+			}
+		})
+	}
+}
+
+// TestExportUsers verifica las columnas de la exportación de usuarios
+func TestExportUsers(t *testing.T) {
+	app := exportApp(t, domain.RoleAdmin, 0)
+
+	status, _, body := get(t, app, "/api/users/export")
+
+	if status != fiber.StatusOK || strings.TrimSpace(body) != "id,name,email,role,created_at" {
+		t.Errorf("Se esperaba solo el encabezado, pero se obtuvo: %d %q", status, body)
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/routes"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// limitApp arma un servidor en streaming (como cmd/server) con un límite de max bytes
+// y dos rutas que responden cuántos bytes del body leyeron: una JSON y la importación
+func limitApp(max int) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler, StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	app.Use(api.BodyLimit(max, routes.StreamedRoutes...))
+
+	read := func(c *fiber.Ctx) error {
+		body := c.Body()
+		if stream := c.Context().RequestBodyStream(); stream != nil {
+			body, _ = io.ReadAll(stream)
+		}
+		return c.SendString(strings.Repeat("x", len(body)))
+	}
+	app.Post("/api/books", read)
+	app.Post("/api/books/import", read)
+	return app
+}
+
+// multipartBody arma un formulario con un archivo de size bytes en el campo "file"
+func multipartBody(t *testing.T, size int) (string, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile("file", "libros.csv")
+	if err != nil {
+		t.Fatalf("No se pudo armar el formulario: %v", err)
+	}
+	part.Write(bytes.Repeat([]byte("a"), size))
+	form.Close()
+	return form.FormDataContentType(), &buf
+}
+
+// TestBodyLimit verifica el 413 fuera del límite y que solo la importación quede exenta
+func TestBodyLimit(t *testing.T) {
+	// Arrange
+	const max = 1024
+	app := limitApp(max)
+	formType, smallForm := multipartBody(t, 100)
+	_, bigForm := multipartBody(t, 8*max)
+	_, bigImport := multipartBody(t, 8*max)
+
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        io.Reader
+		status      int
+	}{
+		{"JSON dentro del límite", "/api/books", fiber.MIMEApplicationJSON, strings.NewReader(`{"title": "Clean Code"}`), fiber.StatusOK},
+		{"JSON fuera del límite", "/api/books", fiber.MIMEApplicationJSON, strings.NewReader(strings.Repeat("a", max+1)), fiber.StatusRequestEntityTooLarge},
+		{"multipart dentro del límite", "/api/books", formType, smallForm, fiber.StatusOK},
+		// 🛡️ Antes todo multipart/form-data se saltaba el límite, en cualquier ruta
+		{"multipart fuera del límite", "/api/books", formType, bigForm, fiber.StatusRequestEntityTooLarge},
+		{"importación exenta", "/api/books/import", formType, bigImport, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var size int
+			if sized, ok := tt.body.(interface{ Len() int }); ok {
+				size = sized.Len()
+			}
+			req := httptest.NewRequest("POST", tt.url, tt.body)
+			req.Header.Set(fiber.HeaderContentType, tt.contentType)
+
+			// Act
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("La petición falló: %v", err)
+			}
+			defer resp.Body.Close()
+			got, _ := io.ReadAll(resp.Body)
+
+			// Assert: el status esperado y, si pasó, el body completo
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Se esperaba %d, pero se obtuvo: %d %s", tt.status, resp.StatusCode, got)
+			}
+			if tt.status == fiber.StatusOK && len(got) != size {
+				t.Errorf("Se esperaba que la ruta leyera %d bytes, pero leyó: %d", size, len(got))
+			}
+			if tt.status == fiber.StatusRequestEntityTooLarge && resp.Header.Get(fiber.HeaderContentType) != "application/problem+json" {
+				t.Errorf("Se esperaba el 413 como problem+json, pero se obtuvo: %s", resp.Header.Get(fiber.HeaderContentType))
+			}
+		})
+	}
+}
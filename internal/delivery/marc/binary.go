@@ -0,0 +1,218 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MarshalBinary escribe el registro en MARC21 binario (ISO 2709)
+//
+// 📦 Estructura:
+//
+//	leader (24) | directorio (12 por campo) 0x1E | campo 0x1E | campo 0x1E ... 0x1D
+//
+// Cada entrada del directorio es tag (3) + longitud (4) + posición (5): por eso el
+// leader se completa al final, cuando ya se conocen todas las longitudes
+// 🧹 Los separadores (0x1D, 0x1E, 0x1F) se quitan de los valores: uno suelto en una
+// descripción pegada cortaría el campo o el registro al leerlo
+func (r *Record) MarshalBinary() ([]byte, error) {
+	var directory, data bytes.Buffer
+	entry := func(tag string, field []byte) error {
+		if len(field) > maxFieldBytes {
+			return fmt.Errorf("%w: el campo %s ocupa %d bytes", ErrRecordTooLong, tag, len(field))
+		}
+		fmt.Fprintf(&directory, "%3s%04d%05d", tag, len(field), data.Len())
+		data.Write(field)
+		return nil
+	}
+
+	for _, f := range r.Control {
+		if err := entry(f.Tag, append(stripSeparators(f.Value), fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range r.Data {
+		field := []byte{indicator(f.Ind1), indicator(f.Ind2)}
+		for _, s := range f.Subfields {
+			field = append(field, subfieldDelimiter, s.Code)
+			field = append(field, stripSeparators(s.Value)...)
+		}
+		if err := entry(f.Tag, append(field, fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + data.Len() + 1
+	if total > MaxRecordBytes {
+		return nil, fmt.Errorf("%w: %d bytes", ErrRecordTooLong, total)
+	}
+
+	leader := []byte(normalizeLeader(r.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+// UnmarshalBinary lee un registro MARC21 binario (con o sin el 0x1D final)
+//
+// 🔤 Solo se aceptan registros en Unicode (leader/09 = "a"). Los registros en
+// MARC-8 (leader/09 en blanco) se aceptan si son ASCII puro: un acento en MARC-8
+// no es UTF-8 y se leería mal
+func (r *Record) UnmarshalBinary(raw []byte) error {
+	raw = bytes.TrimSuffix(raw, []byte{recordTerminator})
+	if len(raw) < leaderLength+1 {
+		return errors.New("el registro es más corto que el leader")
+	}
+	leader := string(raw[:leaderLength])
+	base, err := parseDigits(leader[12:17])
+	if err != nil || base <= leaderLength || base > len(raw) {
+		return fmt.Errorf("la dirección base del leader (%q) no es válida", leader[12:17])
+	}
+	if leader[9] != 'a' && !isASCII(raw) {
+		return errors.New("el registro está en MARC-8: conviértelo a UTF-8 (leader/09 = a) antes de importarlo")
+	}
+	if !utf8.Valid(raw) {
+		return errors.New("el registro no es UTF-8 válido")
+	}
+
+	directory := bytes.TrimSuffix(raw[leaderLength:base], []byte{fieldTerminator})
+	if len(directory)%12 != 0 {
+		return errors.New("el directorio del registro está incompleto")
+	}
+	data := raw[base:]
+
+	*r = Record{Leader: leader}
+	for i := 0; i < len(directory); i += 12 {
+		tag := string(directory[i : i+3])
+		length, errLen := parseDigits(string(directory[i+3 : i+7]))
+		start, errStart := parseDigits(string(directory[i+7 : i+12]))
+		if errLen != nil || errStart != nil || start < 0 || length < 0 || start+length > len(data) {
+			return fmt.Errorf("la entrada del directorio del campo %s no es válida", tag)
+		}
+		field := bytes.TrimSuffix(data[start:start+length], []byte{fieldTerminator})
+
+		if isControlTag(tag) {
+			r.Control = append(r.Control, ControlField{Tag: tag, Value: string(field)})
+			continue
+		}
+		if len(field) < 2 {
+			return fmt.Errorf("el campo %s no tiene indicadores", tag)
+		}
+		f := DataField{Tag: tag, Ind1: field[0], Ind2: field[1]}
+		for _, chunk := range bytes.Split(field[2:], []byte{subfieldDelimiter}) {
+			if len(chunk) > 0 {
+				f.Subfields = append(f.Subfields, Subfield{Code: chunk[0], Value: string(chunk[1:])})
+			}
+		}
+		r.Data = append(r.Data, f)
+	}
+	return nil
+}
+
+// binaryDecoder lee registros binarios de un stream, uno por vez
+type binaryDecoder struct {
+	r *bufio.Reader
+	n int // Registros leídos
+}
+
+// next retorna el siguiente registro crudo (hasta el 0x1D)
+//
+// 💡 Un registro corrupto no arrastra a los siguientes: el 0x1D marca dónde sigue
+// ⚠️ io.ErrUnexpectedEOF = el archivo termina a mitad de un registro
+func (d *binaryDecoder) next() ([]byte, error) {
+	var raw []byte
+	for {
+		chunk, err := d.r.ReadSlice(recordTerminator)
+		raw = append(raw, chunk...)
+		switch {
+		case err == nil:
+			d.n++
+			return bytes.TrimLeft(raw, "\r\n"), nil // Algunos archivos separan registros con saltos de línea
+		case errors.Is(err, bufio.ErrBufferFull):
+			if len(raw) > MaxRecordBytes {
+				return nil, fmt.Errorf("el registro %d supera los %d bytes de ISO 2709 (¿es un archivo MARC?)", d.n+1, MaxRecordBytes)
+			}
+		case errors.Is(err, io.EOF):
+			if len(bytes.TrimSpace(raw)) == 0 {
+				return nil, io.EOF
+			}
+			d.n++
+			return raw, io.ErrUnexpectedEOF
+		default:
+			return nil, err
+		}
+	}
+}
+
+// stripSeparators retorna el valor sin los separadores de ISO 2709
+//
+// 💡 Son bytes ASCII: nunca forman parte de un carácter UTF-8 de varios bytes
+func stripSeparators(value string) []byte {
+	out := make([]byte, 0, len(value))
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case subfieldDelimiter, fieldTerminator, recordTerminator:
+			continue
+		}
+		out = append(out, value[i])
+	}
+	return out
+}
+
+// indicator escribe un indicador (el cero de Go es un blanco en MARC)
+func indicator(ind byte) byte {
+	if ind == 0 {
+		return ' '
+	}
+	return ind
+}
+
+// defaultLeader es el leader de los registros que escribe este paquete:
+// n = nuevo, a = material textual, m = monografía, a = Unicode, c = sin puntuación ISBD
+const defaultLeader = "00000nam a2200000 c 4500"
+
+// normalizeLeader completa o corta el leader a sus 24 posiciones
+func normalizeLeader(leader string) string {
+	if len(leader) != leaderLength {
+		return defaultLeader
+	}
+	return leader
+}
+
+// parseDigits lee un número del leader o del directorio
+//
+// ⚠️ strconv.Atoi acepta "-0001" o "+0001": en ISO 2709 solo hay dígitos, y una
+// posición negativa haría fallar el slice del campo
+func parseDigits(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("número vacío")
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("%q no es un número", s)
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// isASCII indica si todos los bytes son ASCII
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
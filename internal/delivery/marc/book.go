@@ -0,0 +1,294 @@
+package marc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// maxDescriptionBytes deja lugar en el registro para el resto de los campos
+// (un campo binario no puede superar maxFieldBytes)
+const maxDescriptionBytes = 8000
+
+// relatorTerms traduce el rol de un autor a su término de relación MARC ($e)
+var relatorTerms = map[domain.AuthorRole]string{
+	domain.RoleAuthor:     "author",
+	domain.RoleEditor:     "editor",
+	domain.RoleTranslator: "translator",
+}
+
+// languageCodes traduce ISO 639-1 (el del libro) a los códigos MARC de 3 letras
+//
+// 💡 Los códigos MARC son casi siempre ISO 639-2/B ("ger", no "deu"). Un idioma que
+// no está en la tabla se escribe tal cual si ya tiene 3 letras, o se omite
+var languageCodes = map[string]string{
+	"ar": "ara", "ca": "cat", "de": "ger", "en": "eng", "es": "spa", "eu": "baq",
+	"fr": "fre", "gl": "glg", "it": "ita", "ja": "jpn", "la": "lat", "nl": "dut",
+	"pt": "por", "ru": "rus", "zh": "chi",
+}
+
+// FromBook arma el registro MARC de un libro
+//
+// 👥 Autores: con autores vinculados, el primero va en 100 y el resto en 700, cada
+// uno con su rol en $e. Sin vínculos, los créditos (Book.Author) van en 100.
+// Los créditos tal cual se muestran van además en 245 $c: así vuelven iguales al importar
+//
+// ⚠️ Lo que MARC no puede guardar se pierde: la región del idioma ("pt-BR" → "por")
+// y la parte de la descripción que supere ~8 KB
+func FromBook(b *domain.Book) *Record {
+	r := &Record{Leader: defaultLeader}
+	if b.ID != "" {
+		r.Control = append(r.Control, ControlField{Tag: "001", Value: b.ID})
+	}
+	lang := marcLanguage(b.Language)
+	r.Control = append(r.Control, ControlField{Tag: "008", Value: fixedData(b, lang)})
+
+	add := func(tag string, ind1, ind2 byte, subfields ...Subfield) {
+		var kept []Subfield
+		for _, s := range subfields {
+			if s.Value != "" {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) > 0 {
+			r.Data = append(r.Data, DataField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: kept})
+		}
+	}
+
+	add("020", ' ', ' ', Subfield{'a', b.ISBN})
+	if len(lang) == 3 {
+		add("041", '0', ' ', Subfield{'a', lang})
+	}
+
+	tag := "100"
+	for _, a := range b.Authors {
+		if a.Name != "" {
+			add(tag, nameIndicator(a.Name), ' ', Subfield{'a', a.Name}, Subfield{'e', relatorTerms[a.Role]})
+			tag = "700"
+		}
+	}
+	if tag == "100" { // Sin autores vinculados (o sin sus nombres)
+		add("100", nameIndicator(b.Author), ' ', Subfield{'a', b.Author})
+	}
+
+	titleInd := byte('0') // 245 ind1: 1 = hay un asiento principal (100)
+	if len(r.Fields("100")) > 0 {
+		titleInd = '1'
+	}
+	add("245", titleInd, '0', Subfield{'a', b.Title}, Subfield{'c', b.Author})
+	add("250", ' ', ' ', Subfield{'a', b.Edition})
+	add("264", ' ', '1', Subfield{'b', b.Publisher}, Subfield{'c', formatYear(b.PublicationYear)})
+	if b.PageCount > 0 {
+		add("300", ' ', ' ', Subfield{'a', fmt.Sprintf("%d p.", b.PageCount)})
+	}
+	add("520", ' ', ' ', Subfield{'a', truncate(b.Description, maxDescriptionBytes)})
+	for _, subject := range b.Subjects {
+		add("650", ' ', '4', Subfield{'a', subject}) // ind2 4 = materia sin vocabulario controlado
+	}
+	return r
+}
+
+// fixedData arma el 008: 40 posiciones de datos codificados
+//
+//	00-05 fecha de alta (aammdd)  06 tipo de fecha  07-10 año
+//	15-17 lugar ("xx " = sin lugar)  35-37 idioma  39 fuente ("d" = otra)
+func fixedData(b *domain.Book, lang string) string {
+	field := []byte(strings.Repeat(" ", 40))
+	copy(field[0:6], "||||||")
+	if !b.CreatedAt.IsZero() {
+		copy(field[0:6], b.CreatedAt.UTC().Format("060102"))
+	}
+	field[6] = 'n' // n = fecha desconocida
+	copy(field[7:11], "uuuu")
+	if b.PublicationYear > 0 {
+		field[6] = 's' // s = una sola fecha
+		copy(field[7:11], fmt.Sprintf("%04d", b.PublicationYear))
+	}
+	copy(field[15:18], "xx ")
+	copy(field[35:38], "und") // und = sin determinar
+	if len(lang) == 3 {
+		copy(field[35:38], lang)
+	}
+	field[39] = 'd'
+	return string(field)
+}
+
+// ToInput traduce un registro MARC a los datos de un libro
+//
+// 🧹 Si el registro trae puntuación ISBD (leader/18 distinto de "c", lo habitual en
+// catálogos reales) se quita: "Clean code :" $b "a handbook /" → "Clean code: a handbook"
+//
+// 📋 Se leen 264 (o 260, el formato anterior a RDA), 020 $a hasta el primer espacio
+// ("0132350882 (pbk.)"), el primer número de 300 $a y 650 $a como materias.
+// Sin 245 $c, los créditos se arman con los nombres de 100 y 700 separados por ", ",
+// como los que arma el caso de uso con los autores vinculados
+func ToInput(r *Record) usecase.BookInput {
+	clean := strings.TrimSpace
+	if len(r.Leader) == leaderLength && r.Leader[18] != 'c' {
+		clean = trimISBD
+	}
+
+	var in usecase.BookInput
+	if f := first(r.Fields("245")); f != nil {
+		in.Title = clean(f.Value('a'))
+		if subtitle := clean(f.Value('b')); subtitle != "" {
+			in.Title += ": " + subtitle
+		}
+		in.Author = clean(f.Value('c'))
+	}
+	if in.Author == "" {
+		var names []string
+		for _, f := range append(r.Fields("100"), r.Fields("700")...) {
+			if name := trimName(f.Value('a')); name != "" {
+				names = append(names, name)
+			}
+		}
+		in.Author = strings.Join(names, ", ")
+	}
+
+	if f := first(r.Fields("020")); f != nil {
+		in.ISBN, _, _ = strings.Cut(strings.TrimSpace(f.Value('a')), " ")
+	}
+	if f := publication(r); f != nil {
+		in.Publisher = clean(f.Value('b'))
+		in.PublicationYear = firstYear(f.Value('c'))
+	}
+	fixed := r.ControlValue("008")
+	if in.PublicationYear == 0 && len(fixed) >= 11 {
+		in.PublicationYear = firstYear(fixed[7:11])
+	}
+
+	if f := first(r.Fields("041")); f != nil {
+		in.Language = bookLanguage(f.Value('a'))
+	}
+	if in.Language == "" && len(fixed) >= 38 {
+		in.Language = bookLanguage(fixed[35:38])
+	}
+
+	if f := first(r.Fields("250")); f != nil {
+		in.Edition = clean(f.Value('a'))
+	}
+	if f := first(r.Fields("300")); f != nil {
+		in.PageCount, _ = strconv.Atoi(firstNumber.FindString(f.Value('a')))
+	}
+	if f := first(r.Fields("520")); f != nil {
+		in.Description = strings.TrimSpace(f.Value('a'))
+	}
+	for _, f := range r.Fields("650") {
+		if subject := clean(f.Value('a')); subject != "" {
+			in.Subjects = append(in.Subjects, subject)
+		}
+	}
+	return in
+}
+
+// publication retorna el campo de publicación: 264 con ind2 1 (publicación) o, si no, 260
+func publication(r *Record) *DataField {
+	for _, f := range r.Fields("264") {
+		if f.Ind2 == '1' {
+			return &f
+		}
+	}
+	return first(r.Fields("260"))
+}
+
+// first retorna el primer campo (nil si no hay ninguno)
+func first(fields []DataField) *DataField {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fields[0]
+}
+
+var (
+	firstNumber = regexp.MustCompile(`\d+`)
+	yearPattern = regexp.MustCompile(`\d{4}`)
+)
+
+// firstYear extrae el año de "c2008.", "[2008]" o "2008-2010" (0 = sin año)
+func firstYear(value string) int {
+	year, _ := strconv.Atoi(yearPattern.FindString(value))
+	return year
+}
+
+// formatYear escribe el año para 264 $c ("" = sin año)
+func formatYear(year int) string {
+	if year == 0 {
+		return ""
+	}
+	return strconv.Itoa(year)
+}
+
+// trimISBD quita la puntuación ISBD del final de un subcampo (" /", " :", ",", ".")
+func trimISBD(value string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value), " /:;,=."))
+}
+
+// trimName quita la puntuación ISBD de un nombre, salvo el punto de una inicial
+// ("Martin, Robert C." queda igual; "Feathers, Michael C.," → "Feathers, Michael C.")
+func trimName(value string) string {
+	name := strings.TrimRight(strings.TrimSpace(value), " /:;,=")
+	if n := len(name); n >= 3 && name[n-1] == '.' && isInitial(name[:n-1]) {
+		return name
+	}
+	return trimISBD(name)
+}
+
+// isInitial indica si el texto termina en una inicial ("Robert C")
+func isInitial(value string) bool {
+	last, size := utf8.DecodeLastRuneInString(value)
+	before := strings.TrimSuffix(value, string(last))
+	return size > 0 && unicode.IsUpper(last) && (before == "" || strings.HasSuffix(before, " ") || strings.HasSuffix(before, "."))
+}
+
+// nameIndicator es el ind1 de 100/700: 1 = "Apellido, Nombre", 0 = nombre en orden directo
+func nameIndicator(name string) byte {
+	if strings.Contains(name, ", ") {
+		return '1'
+	}
+	return '0'
+}
+
+// marcLanguage traduce el idioma del libro a un código MARC ("" = no se puede)
+func marcLanguage(lang string) string {
+	main, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	if code, ok := languageCodes[main]; ok {
+		return code
+	}
+	if len(main) == 3 {
+		return main
+	}
+	return ""
+}
+
+// bookLanguage traduce un código MARC al idioma del libro ("und" y vacíos = sin idioma)
+func bookLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	switch code {
+	case "", "und", "zxx", "|||":
+		return ""
+	}
+	for iso, marc := range languageCodes {
+		if marc == code {
+			return iso
+		}
+	}
+	return code
+}
+
+// truncate corta un texto a max bytes sin partir un carácter
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
@@ -0,0 +1,119 @@
+package marc
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// NewBookReader lee libros de un archivo MARC (binario o MARCXML), un registro por vez
+//
+// 📋 Line de cada fila es el número de registro (binario) o la línea donde empieza
+// el <record> (MARCXML); Record es el registro en formato mnemónico, para el reporte
+// 💡 Un registro ilegible se rechaza y la lectura sigue (salvo un XML mal formado)
+func NewBookReader(r io.Reader, xmlFormat bool) usecase.BookImportSource {
+	if xmlFormat {
+		return &xmlBookReader{decoder: &xmlDecoder{d: xml.NewDecoder(r)}}
+	}
+	return &binaryBookReader{decoder: &binaryDecoder{r: bufio.NewReaderSize(r, 64*1024)}}
+}
+
+// binaryBookReader lee libros de MARC21 binario
+type binaryBookReader struct {
+	decoder *binaryDecoder
+}
+
+func (b *binaryBookReader) Next() (*usecase.BookImportRow, error) {
+	raw, err := b.decoder.next()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	row := &usecase.BookImportRow{Line: b.decoder.n}
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		row.Record = string(raw)
+		row.Err = domain.NewValidationError("el archivo termina a mitad de un registro (falta el fin de registro 0x1D)")
+		return row, nil
+	case err != nil:
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	var record Record
+	if err := record.UnmarshalBinary(raw); err != nil {
+		row.Record = string(raw)
+		row.Err = domain.NewValidationError(fmt.Sprintf("el registro MARC no es válido: %v", err))
+		return row, nil
+	}
+	row.Record, row.Input = record.String(), ToInput(&record)
+	return row, nil
+}
+
+// xmlBookReader lee libros de MARCXML
+type xmlBookReader struct {
+	decoder *xmlDecoder
+}
+
+func (x *xmlBookReader) Next() (*usecase.BookImportRow, error) {
+	record, line, err := x.decoder.next()
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, io.EOF
+	case record == nil && err != nil:
+		return nil, domain.NewValidationError(fmt.Sprintf("línea %d: %v", line, err))
+	}
+
+	row := &usecase.BookImportRow{Line: line, Record: record.String()}
+	if err != nil {
+		row.Err = domain.NewValidationError(fmt.Sprintf("el registro MARC no es válido: %v", err))
+		return row, nil
+	}
+	row.Input = ToInput(record)
+	return row, nil
+}
+
+// BookWriter escribe libros como registros MARC (binario o MARCXML)
+//
+// ⚠️ Close es obligatorio en MARCXML: cierra la <collection>
+type BookWriter struct {
+	w   io.Writer
+	xml *xmlEncoder
+}
+
+// NewBookWriter crea un BookWriter (en MARCXML escribe ya la apertura del documento)
+func NewBookWriter(w io.Writer, xmlFormat bool) (*BookWriter, error) {
+	if !xmlFormat {
+		return &BookWriter{w: w}, nil
+	}
+	encoder, err := newXMLEncoder(w)
+	if err != nil {
+		return nil, err
+	}
+	return &BookWriter{w: w, xml: encoder}, nil
+}
+
+// Write escribe un libro como un registro
+func (b *BookWriter) Write(book *domain.Book) error {
+	record := FromBook(book)
+	if b.xml != nil {
+		return b.xml.encode(record)
+	}
+	raw, err := record.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = b.w.Write(raw)
+	return err
+}
+
+// Close termina el documento
+func (b *BookWriter) Close() error {
+	if b.xml != nil {
+		return b.xml.close()
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package marc convierte libros entre domain.Book y MARC21 (binario ISO 2709 y MARCXML)
+//
+// 📚 MARC es el formato con el que los sistemas de bibliotecas intercambian registros
+// (catálogos colectivos, la Library of Congress, OCLC). Un registro es una lista de
+// campos numerados ("tags"); cada campo de datos tiene dos indicadores y subcampos:
+//
+//	=245  10$aClean Code$cRobert C. Martin
+//	 │    ││ └─ subcampos: $a título, $c mención de responsabilidad
+//	 │    └┴─── indicadores
+//	 └───────── tag
+//
+// 🗺️ Campos que se traducen (ver book.go):
+//
+//	001      ID del libro           020 $a   ISBN
+//	008      año e idioma           041 $a   idioma (MARC de 3 letras)
+//	100/700  autores ($e rol)       245 $a   título ($b subtítulo, $c créditos)
+//	250 $a   edición                260/264  editorial ($b) y año ($c)
+//	300 $a   páginas                520 $a   descripción
+//	650 $a   materias
+//
+// 💡 Es un detalle de entrega, como tabular: el caso de uso recibe BookInput y no sabe
+// de MARC. POST /api/books/import y GET /api/books/export lo usan con format=marc|marcxml
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+// Separadores del formato binario (ISO 2709)
+const (
+	subfieldDelimiter = 0x1F // Antes del código de cada subcampo
+	fieldTerminator   = 0x1E // Al final de cada campo (y del directorio)
+	recordTerminator  = 0x1D // Al final de cada registro
+)
+
+// Límites del formato binario: las longitudes van en dígitos de ancho fijo
+const (
+	leaderLength   = 24
+	MaxRecordBytes = 99999 // 5 dígitos en el leader
+	maxFieldBytes  = 9999  // 4 dígitos en el directorio
+)
+
+// ErrRecordTooLong indica que un registro no entra en los límites de ISO 2709
+var ErrRecordTooLong = errors.New("marc: el registro supera el tamaño máximo de ISO 2709")
+
+// Record es un registro MARC: el leader, los campos de control (00X) y los de datos
+type Record struct {
+	Leader  string
+	Control []ControlField
+	Data    []DataField
+}
+
+// ControlField es un campo 00X: un valor sin indicadores ni subcampos
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+// DataField es un campo 010-999: dos indicadores y subcampos
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// Subfield es un subcampo: un código de una letra (o dígito) y su valor
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// ControlValue retorna el valor del campo de control tag ("" si no está)
+func (r *Record) ControlValue(tag string) string {
+	for _, f := range r.Control {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Fields retorna los campos de datos con ese tag, en orden
+func (r *Record) Fields(tag string) []DataField {
+	var fields []DataField
+	for _, f := range r.Data {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Value retorna el primer subcampo con ese código ("" si no está)
+func (f DataField) Value(code byte) string {
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// String escribe el registro en formato "mnemónico" (el de MarcEdit), una línea por campo
+//
+//	=LDR  00000nam a2200000 c 4500
+//	=001  8a1f...
+//	=245  10$aClean Code$cRobert C. Martin
+//
+// 💡 Los indicadores en blanco se escriben "\" para que se vean
+func (r *Record) String() string {
+	var b strings.Builder
+	b.WriteString("=LDR  " + r.Leader)
+	for _, f := range r.Control {
+		b.WriteString("\n=" + f.Tag + "  " + f.Value)
+	}
+	for _, f := range r.Data {
+		b.WriteString("\n=" + f.Tag + "  " + mnemonicIndicator(f.Ind1) + mnemonicIndicator(f.Ind2))
+		for _, s := range f.Subfields {
+			b.WriteString("$" + string(s.Code) + s.Value)
+		}
+	}
+	return b.String()
+}
+
+// mnemonicIndicator muestra un indicador en blanco como "\"
+func mnemonicIndicator(ind byte) string {
+	if ind == ' ' || ind == 0 {
+		return `\`
+	}
+	return string(ind)
+}
+
+// isControlTag indica si el tag es de un campo de control (001-009)
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
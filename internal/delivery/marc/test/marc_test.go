@@ -0,0 +1,345 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/marc"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// update regenera los archivos de testdata: go test ./internal/delivery/marc/test -update
+// ⚠️ Revisa el diff de testdata antes de commitear: es el contrato con otros sistemas
+var update = flag.Bool("update", false, "regenerar los archivos golden de testdata")
+
+// goldenBooks son los libros de los archivos golden: uno completo y uno mínimo
+func goldenBooks() []*domain.Book {
+	created := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	return []*domain.Book{
+		{
+			ID:     "b1",
+			Title:  "Cien años de soledad",
+			Author: "Gabriel García Márquez; traducción de Eliane Zagury",
+			Authors: []domain.BookAuthor{
+				{AuthorID: "a1", Name: "García Márquez, Gabriel", Role: domain.RoleAuthor},
+				{AuthorID: "a2", Name: "Zagury, Eliane", Role: domain.RoleTranslator},
+			},
+			ISBN:            "9788501012074",
+			Publisher:       "Record",
+			PublicationYear: 1967,
+			Language:        "pt-BR",
+			PageCount:       448,
+			Description:     "La historia de la familia Buendía en Macondo.",
+			Subjects:        []string{"Novela", "Realismo mágico"},
+			Edition:         "2da",
+			CreatedAt:       created,
+		},
+		{ID: "b2", Title: "Rayuela", Author: "Julio Cortázar", CreatedAt: created},
+	}
+}
+
+// expectedInputs es lo que vuelve al importar los archivos golden
+//
+// 💡 Lo único que se pierde es lo que MARC no guarda: la región del idioma
+// ("pt-BR" → "pt") y los IDs de los autores vinculados
+func expectedInputs() []usecase.BookInput {
+	return []usecase.BookInput{
+		{
+			Title:           "Cien años de soledad",
+			Author:          "Gabriel García Márquez; traducción de Eliane Zagury",
+			ISBN:            "9788501012074",
+			Publisher:       "Record",
+			PublicationYear: 1967,
+			Language:        "pt",
+			PageCount:       448,
+			Description:     "La historia de la familia Buendía en Macondo.",
+			Subjects:        []string{"Novela", "Realismo mágico"},
+			Edition:         "2da",
+		},
+		{Title: "Rayuela", Author: "Julio Cortázar"},
+	}
+}
+
+// encode escribe los libros golden en el formato indicado
+func encode(t *testing.T, xmlFormat bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := marc.NewBookWriter(&buf, xmlFormat)
+	if err != nil {
+		t.Fatalf("No se esperaba error al crear el writer, pero se obtuvo: %v", err)
+	}
+	for _, b := range goldenBooks() {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("No se esperaba error al escribir %s, pero se obtuvo: %v", b.ID, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("No se esperaba error al cerrar, pero se obtuvo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// decode lee todas las filas de un archivo MARC
+func decode(t *testing.T, data []byte, xmlFormat bool) []*usecase.BookImportRow {
+	t.Helper()
+	source := marc.NewBookReader(bytes.NewReader(data), xmlFormat)
+	var rows []*usecase.BookImportRow
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("No se esperaba error al leer, pero se obtuvo: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// golden compara data con testdata/name (o lo regenera con -update)
+func golden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("No se pudo escribir %s: %v", path, err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("No se pudo leer %s (¿falta correr con -update?): %v", path, err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("Se esperaba el contenido de %s, pero se obtuvo:\n%q", path, data)
+	}
+}
+
+// TestBookWriter_Golden verifica que la exportación no cambie sin querer
+func TestBookWriter_Golden(t *testing.T) {
+	for _, tc := range []struct {
+		file      string
+		xmlFormat bool
+	}{{"books.mrc", false}, {"books.xml", true}} {
+		t.Run(tc.file, func(t *testing.T) {
+			// Act
+			data := encode(t, tc.xmlFormat)
+
+			// Assert
+			golden(t, tc.file, data)
+		})
+	}
+}
+
+// TestBookReader_Golden verifica que los archivos golden se importen con todos sus datos
+func TestBookReader_Golden(t *testing.T) {
+	for _, tc := range []struct {
+		file      string
+		xmlFormat bool
+		lines     []int
+	}{{"books.mrc", false, []int{1, 2}}, {"books.xml", true, []int{3, 45}}} {
+		t.Run(tc.file, func(t *testing.T) {
+			// Arrange
+			data, err := os.ReadFile(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatalf("No se pudo leer el archivo golden: %v", err)
+			}
+
+			// Act
+			rows := decode(t, data, tc.xmlFormat)
+
+			// Assert
+			want := expectedInputs()
+			if len(rows) != len(want) {
+				t.Fatalf("Se esperaban %d registros, pero se obtuvieron %d", len(want), len(rows))
+			}
+			for i, row := range rows {
+				if row.Err != nil {
+					t.Errorf("No se esperaba error en el registro %d, pero se obtuvo: %v", i+1, row.Err)
+				}
+				if !reflect.DeepEqual(row.Input, want[i]) {
+					t.Errorf("Se esperaba %+v, pero se obtuvo: %+v", want[i], row.Input)
+				}
+				if row.Line != tc.lines[i] {
+					t.Errorf("Se esperaba la línea %d, pero se obtuvo: %d", tc.lines[i], row.Line)
+				}
+			}
+			if !strings.Contains(rows[0].Record, "=100  1\\$aGarcía Márquez, Gabriel$eauthor") {
+				t.Errorf("Se esperaba el registro en formato mnemónico, pero se obtuvo:\n%s", rows[0].Record)
+			}
+		})
+	}
+}
+
+// TestRecord_RoundTrip verifica que binario y MARCXML guarden exactamente lo mismo
+func TestRecord_RoundTrip(t *testing.T) {
+	for _, b := range goldenBooks() {
+		// Arrange
+		record := marc.FromBook(b)
+
+		// Act: binario → Record, y MARCXML → binario
+		raw, err := record.MarshalBinary()
+		if err != nil {
+			t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+		}
+		var decoded marc.Record
+		if err := decoded.UnmarshalBinary(raw); err != nil {
+			t.Fatalf("No se esperaba error al leer, pero se obtuvo: %v", err)
+		}
+		var xmlFile bytes.Buffer
+		w, _ := marc.NewBookWriter(&xmlFile, true)
+		_ = w.Write(b)
+		_ = w.Close()
+		var binFile bytes.Buffer
+		w, _ = marc.NewBookWriter(&binFile, false)
+		_ = w.Write(b)
+		_ = w.Close()
+
+		// Assert
+		if again, _ := decoded.MarshalBinary(); !bytes.Equal(again, raw) {
+			t.Errorf("Se esperaba el mismo binario al volver a escribir %s, pero se obtuvo:\n%q", b.ID, again)
+		}
+		fromXML := decode(t, xmlFile.Bytes(), true)[0]
+		fromBinary := decode(t, binFile.Bytes(), false)[0]
+		if fromXML.Record != fromBinary.Record || fromXML.Record != decoded.String() {
+			t.Errorf("Se esperaba el mismo registro en binario y MARCXML, pero se obtuvo:\n%s\n---\n%s", fromBinary.Record, fromXML.Record)
+		}
+	}
+}
+
+// TestRecord_StripsSeparators verifica que un separador de ISO 2709 dentro de un valor
+// no corte el campo ni el registro: se quita al escribir y el archivo se lee completo
+func TestRecord_StripsSeparators(t *testing.T) {
+	// Arrange: un libro con los tres separadores pegados en sus textos, y otro después
+	books := []*domain.Book{
+		{ID: "b1", Title: "Clean\x1fCode", Author: "Robert C. Martin", Description: "Primera parte.\x1e\x1d Segunda parte.",
+			Subjects: []string{"Software\x1d"}},
+		goldenBooks()[1],
+	}
+	var file bytes.Buffer
+	w, _ := marc.NewBookWriter(&file, false)
+	for _, b := range books {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+		}
+	}
+
+	// Act
+	rows := decode(t, file.Bytes(), false)
+
+	// Assert
+	if len(rows) != 2 || rows[0].Err != nil || rows[1].Err != nil {
+		t.Fatalf("Se esperaban 2 registros válidos, pero se obtuvo: %+v", rows)
+	}
+	got := rows[0].Input
+	if got.Title != "CleanCode" || got.Description != "Primera parte. Segunda parte." || !reflect.DeepEqual(got.Subjects, []string{"Software"}) {
+		t.Errorf("Se esperaban los textos sin separadores, pero se obtuvo: %+v", got)
+	}
+	if !reflect.DeepEqual(rows[1].Input, expectedInputs()[1]) {
+		t.Errorf("Se esperaba el segundo libro intacto, pero se obtuvo: %+v", rows[1].Input)
+	}
+}
+
+// TestToInput_CatalogRecord verifica un registro como los que publica un catálogo real:
+// puntuación ISBD, 260 en lugar de 264, ISBN-10 con calificador y sin 245 $c
+func TestToInput_CatalogRecord(t *testing.T) {
+	// Arrange
+	record := &marc.Record{
+		Leader: "00000cam a2200000 a 4500",
+		Control: []marc.ControlField{
+			{Tag: "001", Value: "15003226"},
+			{Tag: "008", Value: "080326s2009    njua     b    001 0 eng  "},
+		},
+		Data: []marc.DataField{
+			{Tag: "020", Subfields: []marc.Subfield{{Code: 'a', Value: "0132350882 (pbk. : alk. paper)"}}},
+			{Tag: "100", Ind1: '1', Subfields: []marc.Subfield{{Code: 'a', Value: "Martin, Robert C."}}},
+			{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []marc.Subfield{
+				{Code: 'a', Value: "Clean code :"}, {Code: 'b', Value: "a handbook of agile software craftsmanship /"},
+			}},
+			{Tag: "260", Subfields: []marc.Subfield{
+				{Code: 'a', Value: "Upper Saddle River, NJ :"}, {Code: 'b', Value: "Prentice Hall,"}, {Code: 'c', Value: "c2009."},
+			}},
+			{Tag: "300", Subfields: []marc.Subfield{{Code: 'a', Value: "xxix, 431 p. :"}, {Code: 'b', Value: "ill. ;"}}},
+			{Tag: "650", Ind2: '0', Subfields: []marc.Subfield{{Code: 'a', Value: "Agile software development."}}},
+			{Tag: "650", Ind2: '0', Subfields: []marc.Subfield{{Code: 'a', Value: "Computer software"}, {Code: 'x', Value: "Reliability."}}},
+			{Tag: "700", Ind1: '1', Subfields: []marc.Subfield{{Code: 'a', Value: "Feathers, Michael C.,"}, {Code: 'e', Value: "contributor."}}},
+		},
+	}
+
+	// Act
+	in := marc.ToInput(record)
+
+	// Assert
+	want := usecase.BookInput{
+		Title:           "Clean code: a handbook of agile software craftsmanship",
+		Author:          "Martin, Robert C., Feathers, Michael C.",
+		ISBN:            "0132350882",
+		Publisher:       "Prentice Hall",
+		PublicationYear: 2009,
+		Language:        "en",
+		PageCount:       431,
+		Subjects:        []string{"Agile software development", "Computer software"},
+	}
+	if !reflect.DeepEqual(in, want) {
+		t.Errorf("Se esperaba %+v, pero se obtuvo: %+v", want, in)
+	}
+}
+
+// TestBookReader_InvalidRecords verifica que un registro roto no corte la importación
+func TestBookReader_InvalidRecords(t *testing.T) {
+	// Arrange: un registro con el leader roto, uno bueno y uno cortado al final
+	good, err := marc.FromBook(goldenBooks()[1]).MarshalBinary()
+	if err != nil {
+		t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+	}
+	file := append([]byte("roto\x1d"), good...)
+	file = append(file, good[:40]...)
+
+	// Act
+	rows := decode(t, file, false)
+
+	// Assert
+	if len(rows) != 3 {
+		t.Fatalf("Se esperaban 3 registros, pero se obtuvieron %d", len(rows))
+	}
+	if rows[0].Err == nil || rows[0].Line != 1 {
+		t.Errorf("Se esperaba el registro 1 rechazado, pero se obtuvo: %+v", rows[0])
+	}
+	if rows[1].Err != nil || rows[1].Input.Title != "Rayuela" {
+		t.Errorf("Se esperaba el registro 2 completo, pero se obtuvo: %+v (err: %v)", rows[1].Input, rows[1].Err)
+	}
+	if rows[2].Err == nil || !strings.Contains(rows[2].Err.Error(), "termina a mitad") {
+		t.Errorf("Se esperaba el registro 3 cortado, pero se obtuvo: %v", rows[2].Err)
+	}
+
+	// Un directorio con posiciones o longitudes negativas se rechaza sin entrar en pánico
+	for _, entry := range []string{"245" + "0010" + "-0001", "245" + "-001" + "00000", "245" + "+010" + "00000"} {
+		record := "00043nam a2200037 c 4500" + entry + "\x1e" + "10\x1fa\x1e\x1d"
+		rows := decode(t, []byte(record), false)
+		if len(rows) != 1 || rows[0].Err == nil {
+			t.Errorf("Se esperaba la entrada %q rechazada, pero se obtuvo: %+v", entry, rows)
+		}
+	}
+
+	// Un XML mal formado corta la lectura con la línea del problema
+	source := marc.NewBookReader(strings.NewReader("<collection>\n<record>\n<leader>x</leader>\n</collection>"), true)
+	if _, err := source.Next(); err == nil || !strings.Contains(err.Error(), "línea") {
+		t.Errorf("Se esperaba un error de XML con la línea, pero se obtuvo: %v", err)
+	}
+
+	// Un archivo que no es MARCXML no se lee como "cero registros"
+	for _, file := range []string{"title,author\nRayuela,Julio Cortázar\n", "<html><body/></html>"} {
+		source = marc.NewBookReader(strings.NewReader(file), true)
+		if _, err := source.Next(); err == nil || errors.Is(err, io.EOF) {
+			t.Errorf("Se esperaba un error para %q, pero se obtuvo: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Namespace es el espacio de nombres de MARCXML (MARC21 slim, Library of Congress)
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+// xmlRecord es un <record> de MARCXML
+//
+//	<record>
+//	  <leader>00000nam a2200000 c 4500</leader>
+//	  <controlfield tag="001">8a1f...</controlfield>
+//	  <datafield tag="245" ind1="1" ind2="0">
+//	    <subfield code="a">Clean Code</subfield>
+//	  </datafield>
+//	</record>
+type xmlRecord struct {
+	XMLName xml.Name          `xml:"record"`
+	Leader  string            `xml:"leader"`
+	Control []xmlControlField `xml:"controlfield"`
+	Data    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// toXML traduce el registro a su forma MARCXML
+//
+// 💡 El leader lleva las longitudes del binario equivalente (como hace la LC):
+// el mismo registro se ve igual en los dos formatos
+func (r *Record) toXML() (xmlRecord, error) {
+	raw, err := r.MarshalBinary()
+	if err != nil {
+		return xmlRecord{}, err
+	}
+	out := xmlRecord{Leader: string(raw[:leaderLength])}
+	for _, f := range r.Control {
+		out.Control = append(out.Control, xmlControlField{Tag: f.Tag, Value: f.Value})
+	}
+	for _, f := range r.Data {
+		field := xmlDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, s := range f.Subfields {
+			field.Subfields = append(field.Subfields, xmlSubfield{Code: string(s.Code), Value: s.Value})
+		}
+		out.Data = append(out.Data, field)
+	}
+	return out, nil
+}
+
+// fromXML traduce un <record> al Record
+func fromXML(in xmlRecord) (*Record, error) {
+	r := &Record{Leader: normalizeLeader(in.Leader)}
+	for _, f := range in.Control {
+		r.Control = append(r.Control, ControlField{Tag: f.Tag, Value: f.Value})
+	}
+	for _, f := range in.Data {
+		if len(f.Tag) != 3 {
+			return nil, fmt.Errorf("el tag %q no tiene 3 caracteres", f.Tag)
+		}
+		field := DataField{Tag: f.Tag, Ind1: xmlIndicator(f.Ind1), Ind2: xmlIndicator(f.Ind2)}
+		for _, s := range f.Subfields {
+			if len(s.Code) != 1 {
+				return nil, fmt.Errorf("el código de subcampo %q del campo %s no es de un carácter", s.Code, f.Tag)
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: s.Code[0], Value: s.Value})
+		}
+		r.Data = append(r.Data, field)
+	}
+	return r, nil
+}
+
+// xmlIndicator lee un indicador ("" o más de un carácter = en blanco)
+func xmlIndicator(ind string) byte {
+	if len(ind) != 1 {
+		return ' '
+	}
+	return ind[0]
+}
+
+// xmlDecoder lee los <record> de un documento MARCXML, uno por vez
+//
+// 🌊 Recorre los tokens y decodifica cada <record> por separado: una <collection>
+// de cien mil registros no se carga entera. Acepta también un <record> suelto como raíz
+type xmlDecoder struct {
+	d    *xml.Decoder
+	root bool // Ya se vio el elemento raíz
+}
+
+// next retorna el siguiente registro y la línea donde empieza
+//
+// ⚠️ Un error de sintaxis XML corta la lectura: después de eso no se sabe dónde
+// empieza el registro siguiente (a diferencia del binario)
+func (x *xmlDecoder) next() (*Record, int, error) {
+	for {
+		line, _ := x.d.InputPos()
+		token, err := x.d.Token()
+		if errors.Is(err, io.EOF) && !x.root {
+			return nil, line, errors.New("el archivo no tiene ningún elemento XML")
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, 0, io.EOF
+		}
+		if err != nil {
+			return nil, line, fmt.Errorf("el XML no es válido: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !x.root {
+			// 🛡️ Un CSV o un XHTML no se leen como "cero registros": la raíz tiene que ser MARC
+			if start.Name.Local != "collection" && start.Name.Local != "record" {
+				return nil, line, fmt.Errorf("la raíz del documento es <%s>: se esperaba <collection> o <record> de MARCXML", start.Name.Local)
+			}
+			x.root = true
+		}
+		if start.Name.Local != "record" {
+			continue
+		}
+
+		var in xmlRecord
+		if err := x.d.DecodeElement(&in, &start); err != nil {
+			return nil, line, fmt.Errorf("el XML no es válido: %w", err)
+		}
+		record, err := fromXML(in)
+		return record, line, err
+	}
+}
+
+// xmlEncoder escribe una <collection> de MARCXML, un registro por vez
+type xmlEncoder struct {
+	e *xml.Encoder
+}
+
+// collection es el elemento raíz de MARCXML
+var collection = xml.StartElement{Name: xml.Name{Local: "collection"}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}}}
+
+// newXMLEncoder escribe la declaración XML y abre la <collection>
+func newXMLEncoder(w io.Writer) (*xmlEncoder, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.EncodeToken(collection); err != nil {
+		return nil, err
+	}
+	return &xmlEncoder{e: e}, nil
+}
+
+// encode escribe un <record>
+func (x *xmlEncoder) encode(r *Record) error {
+	out, err := r.toXML()
+	if err != nil {
+		return err
+	}
+	return x.e.Encode(out)
+}
+
+// close cierra la <collection>
+func (x *xmlEncoder) close() error {
+	if err := x.e.EncodeToken(collection.End()); err != nil {
+		return err
+	}
+	return x.e.Flush()
+}
@@ -0,0 +1,179 @@
+package opds
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// catalogLinks son los links de todos los feeds: el inicio y la búsqueda
+func catalogLinks() []link {
+	return []link{
+		{Rel: "start", Href: "/opds", Type: TypeNavigation, Title: catalogName},
+		{Rel: "search", Href: "/opds/opensearch.xml", Type: TypeOpenSearch, Title: "Buscar"},
+	}
+}
+
+// navEntry crea una opción de un feed de navegación
+func navEntry(updated, id, title, content string, l link) entry {
+	return entry{
+		Title:   title,
+		ID:      "urn:opds:" + id,
+		Updated: updated,
+		Content: plain(content),
+		Links:   []link{l},
+	}
+}
+
+// bookEntry crea la entrada de un libro
+//
+// 📋 Los datos van en los elementos de Atom y Dublin Core que leen los lectores:
+// autores, ISBN (urn:isbn), idioma, año, editorial, materias y sinopsis
+// 🔗 El link de adquisición "borrow" lleva al libro en la API (con sus ejemplares
+// disponibles); el alternate, a su cita en BibTeX
+func bookEntry(b *domain.Book) entry {
+	e := entry{
+		Title:     b.Title,
+		ID:        "urn:uuid:" + b.ID,
+		Updated:   timestamp(b.CreatedAt),
+		Language:  b.Language,
+		Publisher: b.Publisher,
+		Summary:   plain(b.Description),
+		Links: []link{
+			{Rel: relBorrow, Href: "/api/books/" + b.ID, Type: "application/json"},
+			{Rel: "alternate", Href: "/api/books/" + b.ID + "/citation", Type: "application/x-bibtex", Title: "Cita (BibTeX)"},
+		},
+	}
+	for _, a := range b.Authors {
+		if a.Role == domain.RoleAuthor && a.Name != "" {
+			e.Authors = append(e.Authors, person{Name: a.Name, URI: "/opds/books?author_id=" + url.QueryEscape(a.AuthorID)})
+		}
+	}
+	if len(e.Authors) == 0 && b.Author != "" {
+		e.Authors = []person{{Name: b.Author}}
+	}
+	if b.ISBN != "" {
+		e.Identifier = "urn:isbn:" + b.ISBN
+	}
+	if b.PublicationYear > 0 {
+		e.Issued = strconv.Itoa(b.PublicationYear)
+	}
+	for _, s := range b.Subjects {
+		e.Categories = append(e.Categories, category{Term: s, Label: s})
+	}
+	return e
+}
+
+// pageLinks son los links de paginación: self, first, previous, next y last
+//
+// 📄 Van por offset (no por cursor) para que "last" apunte a una página concreta;
+// los demás parámetros (filtros, orden, limit) se conservan
+func pageLinks(path string, params url.Values, offset, limit, total int, kind string) []link {
+	href := func(offset int) string {
+		q := url.Values{}
+		for k, v := range params {
+			q[k] = v
+		}
+		if offset > 0 {
+			q.Set("offset", strconv.Itoa(offset))
+		}
+		if len(q) == 0 {
+			return path
+		}
+		return path + "?" + q.Encode()
+	}
+
+	links := []link{
+		{Rel: "self", Href: href(offset), Type: kind},
+		{Rel: "first", Href: href(0), Type: kind},
+	}
+	if offset > 0 {
+		links = append(links, link{Rel: "previous", Href: href(max(offset-limit, 0)), Type: kind})
+	}
+	if offset+limit < total {
+		links = append(links, link{Rel: "next", Href: href(offset + limit), Type: kind})
+	}
+	if total > 0 {
+		links = append(links, link{Rel: "last", Href: href((total - 1) / limit * limit), Type: kind})
+	}
+	return links
+}
+
+// setPagination agrega los totales de OpenSearch (startIndex empieza en 1)
+func (f *feed) setPagination(offset, limit, total int) {
+	start := offset + 1
+	f.TotalResults, f.ItemsPerPage, f.StartIndex = &total, &limit, &start
+}
+
+// facetLinks son los links de las facetas "Autor" e "Idioma"
+//
+// 🧭 Cada faceta cambia su filtro y conserva el resto (vuelve a la primera página);
+// la del filtro actual va marcada con opds:activeFacet
+func facetLinks(params url.Values, filter domain.BookFilter, facets *domain.BookFacets) []link {
+	facet := func(group, key, value, title, active string, count int) link {
+		q := url.Values{}
+		for k, v := range params {
+			q[k] = v
+		}
+		q.Set(key, value)
+		return link{
+			Rel:         relFacet,
+			Href:        "/opds/books?" + q.Encode(),
+			Type:        TypeAcquisition,
+			Title:       title,
+			FacetGroup:  group,
+			ActiveFacet: value == active,
+			Count:       count,
+		}
+	}
+
+	var links []link
+	for _, a := range facets.Authors {
+		links = append(links, facet("Autor", "author_id", a.Value, a.Label, filter.AuthorID, a.Count))
+	}
+	for _, l := range facets.Languages {
+		links = append(links, facet("Idioma", "language", l.Value, languageName(l.Value), filter.Language, l.Count))
+	}
+	return links
+}
+
+// booksTitle es el título del feed de libros según sus filtros ("Libros de Cortázar en español")
+func booksTitle(filter domain.BookFilter, facets *domain.BookFacets) string {
+	title := "Libros"
+	if filter.AuthorID != "" {
+		for _, a := range facets.Authors {
+			if a.Value == filter.AuthorID {
+				title += " de " + a.Label
+			}
+		}
+	}
+	if filter.Language != "" {
+		title += " en " + languageName(filter.Language)
+	}
+	return title
+}
+
+// languageName es el nombre de un idioma para mostrar (el código si no lo conocemos)
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// languageNames son los idiomas más comunes del catálogo
+var languageNames = map[string]string{
+	"es": "español", "en": "inglés", "pt": "portugués", "fr": "francés",
+	"de": "alemán", "it": "italiano", "ca": "catalán", "gl": "gallego", "eu": "euskera",
+	"la": "latín", "ja": "japonés", "zh": "chino", "ru": "ruso",
+}
+
+// bookCount describe una cantidad de libros ("1 libro", "12 libros")
+func bookCount(n int) string {
+	if n == 1 {
+		return "1 libro"
+	}
+	return fmt.Sprintf("%d libros", n)
+}
@@ -0,0 +1,263 @@
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// catalogName es el nombre del catálogo (título del feed raíz y autor de todos los feeds)
+const catalogName = "Catálogo de la biblioteca"
+
+// maxAuthorFacets limita las facetas de autor: los lectores las muestran en un menú
+const maxAuthorFacets = 20
+
+// Handler maneja las peticiones de los feeds OPDS
+//
+// 💡 Los casos de uso son las MISMAS instancias que usan los handlers REST
+type Handler struct {
+	books   *usecase.BookUseCase
+	authors *usecase.AuthorUseCase
+	now     func() time.Time // <updated> de los feeds de navegación
+}
+
+// NewHandler constructor para Handler
+func NewHandler(books *usecase.BookUseCase, authors *usecase.AuthorUseCase) *Handler {
+	return &Handler{books: books, authors: authors, now: time.Now}
+}
+
+// Challenge es el middleware que pide credenciales con "WWW-Authenticate: Basic"
+//
+// 🔐 Un 401 de la API pide un token Bearer, que ningún lector sabe conseguir; con
+// Basic, el lector muestra su diálogo de usuario y contraseña (la API key va como contraseña)
+// 💡 Va ANTES de los middlewares de autenticación: reescribe el header a la vuelta
+func Challenge(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		// El error todavía no es una respuesta: el ErrorHandler la escribe acá
+		if err := c.App().Config().ErrorHandler(c, err); err != nil {
+			return err
+		}
+	}
+	if c.Response().StatusCode() == fiber.StatusUnauthorized {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="opds", charset="UTF-8"`)
+	}
+	return nil
+}
+
+// Root maneja las peticiones GET /opds
+//
+// 🧭 El menú principal: novedades, por título, por autor y por idioma
+func (h *Handler) Root(c *fiber.Ctx) error {
+	f := newFeed(absolute(c, "/opds"), catalogName, h.now())
+	f.Links = append(catalogLinks(),
+		link{Rel: "self", Href: "/opds", Type: TypeNavigation})
+
+	f.Entries = []entry{
+		navEntry(f.Updated, "new", "Novedades", "Los últimos libros agregados al catálogo",
+			link{Rel: relSortNew, Href: "/opds/books?sort=-created_at", Type: TypeAcquisition}),
+		navEntry(f.Updated, "title", "Por título", "Todos los libros, de la A a la Z",
+			link{Rel: "subsection", Href: "/opds/books?sort=title", Type: TypeAcquisition}),
+		navEntry(f.Updated, "authors", "Por autor", "Los autores del catálogo y sus libros",
+			link{Rel: "subsection", Href: "/opds/authors", Type: TypeNavigation}),
+		navEntry(f.Updated, "languages", "Por idioma", "Los idiomas del catálogo y sus libros",
+			link{Rel: "subsection", Href: "/opds/languages", Type: TypeNavigation}),
+	}
+	return write(c, TypeNavigation, f)
+}
+
+// Books maneja las peticiones GET /opds/books
+//
+// 🔎 Parámetros de query (todos opcionales):
+// - author_id, language: los mismos filtros que GET /api/books
+// - sort: title | author | created_at (prefijo "-" = descendente; por defecto -created_at)
+// - limit, offset: tamaño y posición de la página (por defecto 20, máximo 100)
+//
+// 🧭 Las facetas "Autor" e "Idioma" cuentan los libros de cada valor respetando el
+// otro filtro: con language=es, cada autor muestra cuántos libros tiene en español
+func (h *Handler) Books(c *fiber.Ctx) error {
+	page, err := pageRequest(c)
+	if err != nil {
+		return err
+	}
+	filter := domain.BookFilter{AuthorID: c.Query("author_id"), Language: c.Query("language")}
+	if sort := c.Query("sort"); sort != "" {
+		page.Sort, page.Desc = strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+	}
+
+	books, err := h.books.ListBooks(c.UserContext(), domain.BookQuery{PageRequest: page, Filter: filter})
+	if err != nil {
+		return err
+	}
+	facets, err := h.facets(c, filter)
+	if err != nil {
+		return err
+	}
+
+	params := queryParams(c, "author_id", "language", "sort", "limit")
+	f := newFeed(absolute(c, "/opds/books?"+params.Encode()), booksTitle(filter, facets), h.now())
+	f.Links = append(catalogLinks(), pageLinks("/opds/books", params, books.Offset, books.Limit, books.Total, TypeAcquisition)...)
+	f.Links = append(f.Links, facetLinks(params, filter, facets)...)
+	f.setPagination(books.Offset, books.Limit, books.Total)
+	for _, b := range books.Items {
+		f.Entries = append(f.Entries, bookEntry(b))
+	}
+	return write(c, TypeAcquisition, f)
+}
+
+// Search maneja las peticiones GET /opds/search?q=
+//
+// 🔎 La URL que arma el lector con la plantilla de /opds/opensearch.xml;
+// los resultados van del más al menos relevante (ver GET /api/books/search)
+func (h *Handler) Search(c *fiber.Ctx) error {
+	page, err := pageRequest(c)
+	if err != nil {
+		return err
+	}
+	q := c.Query("q")
+	hits, err := h.books.SearchBooks(c.UserContext(), domain.BookSearchQuery{PageRequest: page, Text: q})
+	if err != nil {
+		return err
+	}
+
+	params := queryParams(c, "q", "limit")
+	f := newFeed(absolute(c, "/opds/search?"+params.Encode()), fmt.Sprintf("Resultados de «%s»", q), h.now())
+	f.Links = append(catalogLinks(), pageLinks("/opds/search", params, hits.Offset, hits.Limit, hits.Total, TypeAcquisition)...)
+	f.setPagination(hits.Offset, hits.Limit, hits.Total)
+	for _, hit := range hits.Items {
+		f.Entries = append(f.Entries, bookEntry(hit.Book))
+	}
+	return write(c, TypeAcquisition, f)
+}
+
+// Authors maneja las peticiones GET /opds/authors
+//
+// 👥 Un menú con los autores por nombre, paginado como /opds/books;
+// cada uno lleva a sus libros (/opds/books?author_id=...)
+func (h *Handler) Authors(c *fiber.Ctx) error {
+	page, err := pageRequest(c)
+	if err != nil {
+		return err
+	}
+	page.Sort = domain.SortByName
+	authors, err := h.authors.ListAuthors(c.UserContext(), domain.AuthorQuery{PageRequest: page})
+	if err != nil {
+		return err
+	}
+
+	params := queryParams(c, "limit")
+	f := newFeed(absolute(c, "/opds/authors"), "Autores", h.now())
+	f.Links = append(catalogLinks(), pageLinks("/opds/authors", params, authors.Offset, authors.Limit, authors.Total, TypeNavigation)...)
+	f.Links = append(f.Links, link{Rel: "up", Href: "/opds", Type: TypeNavigation})
+	f.setPagination(authors.Offset, authors.Limit, authors.Total)
+	for _, a := range authors.Items {
+		f.Entries = append(f.Entries, navEntry(timestamp(a.CreatedAt), "author:"+a.ID, a.Name, a.Bio,
+			link{Rel: "subsection", Href: "/opds/books?author_id=" + url.QueryEscape(a.ID), Type: TypeAcquisition}))
+	}
+	return write(c, TypeNavigation, f)
+}
+
+// Languages maneja las peticiones GET /opds/languages
+//
+// 🌍 Un menú con los idiomas del catálogo, de más a menos libros
+func (h *Handler) Languages(c *fiber.Ctx) error {
+	facets, err := h.books.BookFacets(c.UserContext(), domain.BookFilter{})
+	if err != nil {
+		return err
+	}
+
+	f := newFeed(absolute(c, "/opds/languages"), "Idiomas", h.now())
+	f.Links = append(catalogLinks(),
+		link{Rel: "self", Href: "/opds/languages", Type: TypeNavigation},
+		link{Rel: "up", Href: "/opds", Type: TypeNavigation})
+	for _, lang := range facets.Languages {
+		l := link{Rel: "subsection", Href: "/opds/books?language=" + url.QueryEscape(lang.Value), Type: TypeAcquisition, Count: lang.Count}
+		f.Entries = append(f.Entries, navEntry(f.Updated, "language:"+lang.Value, languageName(lang.Value), bookCount(lang.Count), l))
+	}
+	return write(c, TypeNavigation, f)
+}
+
+// OpenSearch maneja las peticiones GET /opds/opensearch.xml
+//
+// 🔎 Le dice al lector cómo buscar: la plantilla lleva la URL absoluta, porque
+// algunos lectores no la resuelven contra la del documento
+func (h *Handler) OpenSearch(c *fiber.Ctx) error {
+	doc := openSearch{
+		XMLNS:          "http://a9.com/-/spec/opensearch/1.1/",
+		ShortName:      "Biblioteca",
+		Description:    "Buscar libros por título, autor, descripción o materia",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URL:            openSearchURL{Type: TypeAcquisition, Template: absolute(c, "/opds/search?q={searchTerms}")},
+	}
+	return write(c, TypeOpenSearch, doc)
+}
+
+// facets calcula las facetas del listado: la de cada grupo ignora su propio filtro
+// (si no, con un idioma elegido la faceta "Idioma" tendría una sola opción)
+func (h *Handler) facets(c *fiber.Ctx, filter domain.BookFilter) (*domain.BookFacets, error) {
+	byAuthor := filter
+	byAuthor.AuthorID = ""
+	authors, err := h.books.BookFacets(c.UserContext(), byAuthor)
+	if err != nil {
+		return nil, err
+	}
+	byLanguage := filter
+	byLanguage.Language = ""
+	languages, err := h.books.BookFacets(c.UserContext(), byLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(authors.Authors) > maxAuthorFacets {
+		authors.Authors = authors.Authors[:maxAuthorFacets]
+	}
+	return &domain.BookFacets{Authors: authors.Authors, Languages: languages.Languages}, nil
+}
+
+// pageRequest lee ?limit y ?offset (los rangos los valida el caso de uso)
+func pageRequest(c *fiber.Ctx) (domain.PageRequest, error) {
+	var v domain.Validator
+	var p domain.PageRequest
+	for key, dst := range map[string]*int{"limit": &p.Limit, "offset": &p.Offset} {
+		if raw := c.Query(key); raw != "" {
+			n, err := strconv.Atoi(raw)
+			v.Check(err == nil, key, domain.CodeInvalidFormat, "el parámetro "+key+" debe ser un número entero")
+			*dst = n
+		}
+	}
+	return p, v.Err()
+}
+
+// queryParams copia de la query los parámetros que se conservan entre páginas
+func queryParams(c *fiber.Ctx, keys ...string) url.Values {
+	params := url.Values{}
+	for _, key := range keys {
+		if value := c.Query(key); value != "" {
+			params.Set(key, value)
+		}
+	}
+	return params
+}
+
+// absolute convierte un path en una URL absoluta (para los <id> y OpenSearch)
+func absolute(c *fiber.Ctx, path string) string {
+	return c.BaseURL() + strings.TrimSuffix(path, "?")
+}
+
+// write serializa un documento XML con su Content-Type
+func write(c *fiber.Ctx, contentType string, doc any) error {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, contentType+";charset=utf-8")
+	return c.Send(append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,151 @@
+// Package opds publica el catálogo como feeds OPDS 1.2 (Atom) bajo /opds
+//
+// 📱 OPDS es el formato que entienden las apps de lectura (KOReader, Thorium, Moon+
+// Reader, Calibre...): con la URL /opds y una API key, el lector navega el catálogo
+// desde su dispositivo como si fuera una librería
+//
+// 🧭 Dos tipos de feed:
+//   - Navegación: menús que llevan a otros feeds (/opds, /opds/authors, /opds/languages)
+//   - Adquisición: listas de libros con sus datos (/opds/books, /opds/search)
+//
+// 📄 Los feeds de adquisición se paginan con links first/previous/next/last, tienen
+// facetas por autor y por idioma, y la búsqueda se describe con OpenSearch
+// (/opds/opensearch.xml): el lector arma la URL con lo que escribe el usuario
+//
+// 🔐 Misma autenticación que la API REST, pero los lectores solo saben enviar
+// "Authorization: Basic": la API key va como contraseña (ver Challenge)
+//
+// 💡 Es otra capa de delivery sobre los MISMOS casos de uso (como HTTP, GraphQL y gRPC)
+package opds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Tipos MIME de los feeds y documentos
+const (
+	TypeNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	TypeAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	TypeOpenSearch  = "application/opensearchdescription+xml"
+)
+
+// Relaciones de los links (las que no son de Atom las define OPDS)
+const (
+	relFacet   = "http://opds-spec.org/facet"
+	relBorrow  = "http://opds-spec.org/acquisition/borrow"
+	relSortNew = "http://opds-spec.org/sort/new"
+)
+
+// feed es un documento Atom con las extensiones de OPDS
+//
+// 💡 encoding/xml no asigna prefijos propios: los espacios de nombres se declaran en
+// la raíz y los elementos usan el prefijo literal ("dc:language", "thr:count")
+type feed struct {
+	XMLName      xml.Name `xml:"feed"`
+	XMLNS        string   `xml:"xmlns,attr"`
+	XMLNSDC      string   `xml:"xmlns:dc,attr"`
+	XMLNSOPDS    string   `xml:"xmlns:opds,attr"`
+	XMLNSSearch  string   `xml:"xmlns:opensearch,attr"`
+	XMLNSThread  string   `xml:"xmlns:thr,attr"`
+	ID           string   `xml:"id"`
+	Title        string   `xml:"title"`
+	Updated      string   `xml:"updated"`
+	Author       person   `xml:"author"`
+	Links        []link   `xml:"link"`
+	TotalResults *int     `xml:"opensearch:totalResults"`
+	ItemsPerPage *int     `xml:"opensearch:itemsPerPage"`
+	StartIndex   *int     `xml:"opensearch:startIndex"`
+	Entries      []entry  `xml:"entry"`
+}
+
+// newFeed crea un feed vacío con los espacios de nombres declarados
+func newFeed(id, title string, updated time.Time) *feed {
+	return &feed{
+		XMLNS:       "http://www.w3.org/2005/Atom",
+		XMLNSDC:     "http://purl.org/dc/terms/",
+		XMLNSOPDS:   "http://opds-spec.org/2010/catalog",
+		XMLNSSearch: "http://a9.com/-/spec/opensearch/1.1/",
+		XMLNSThread: "http://purl.org/syndication/thread/1.0",
+		ID:          id,
+		Title:       title,
+		Updated:     timestamp(updated),
+		Author:      person{Name: catalogName},
+		Entries:     []entry{},
+	}
+}
+
+// entry es un elemento del feed: un libro o una opción del menú
+type entry struct {
+	Title      string     `xml:"title"`
+	ID         string     `xml:"id"`
+	Updated    string     `xml:"updated"`
+	Authors    []person   `xml:"author"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Language   string     `xml:"dc:language,omitempty"`
+	Issued     string     `xml:"dc:issued,omitempty"`
+	Publisher  string     `xml:"dc:publisher,omitempty"`
+	Categories []category `xml:"category"`
+	Summary    *text      `xml:"summary"`
+	Content    *text      `xml:"content"`
+	Links      []link     `xml:"link"`
+}
+
+// person es un autor de Atom
+type person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// link es un link de Atom; los atributos opds:* y thr:count son de las facetas
+type link struct {
+	Rel         string `xml:"rel,attr,omitempty"`
+	Href        string `xml:"href,attr"`
+	Type        string `xml:"type,attr,omitempty"`
+	Title       string `xml:"title,attr,omitempty"`
+	FacetGroup  string `xml:"opds:facetGroup,attr,omitempty"`
+	ActiveFacet bool   `xml:"opds:activeFacet,attr,omitempty"`
+	Count       int    `xml:"thr:count,attr,omitempty"`
+}
+
+// category es una materia del libro
+type category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// text es un texto plano de Atom (summary, content)
+type text struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// plain crea un texto plano (nil si está vacío: el elemento no se escribe)
+func plain(s string) *text {
+	if s == "" {
+		return nil
+	}
+	return &text{Type: "text", Value: s}
+}
+
+// openSearch es el documento que describe cómo buscar en el catálogo
+type openSearch struct {
+	XMLName        xml.Name      `xml:"OpenSearchDescription"`
+	XMLNS          string        `xml:"xmlns,attr"`
+	ShortName      string        `xml:"ShortName"`
+	Description    string        `xml:"Description"`
+	InputEncoding  string        `xml:"InputEncoding"`
+	OutputEncoding string        `xml:"OutputEncoding"`
+	URL            openSearchURL `xml:"Url"`
+}
+
+// openSearchURL es la plantilla de la búsqueda ({searchTerms} lo completa el lector)
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// timestamp formatea una fecha como pide Atom (RFC 3339)
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,289 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	api "go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/delivery/opds"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/routes"
+	"go-book-clean-architecture-api/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// feed es lo que los tests leen de un feed OPDS (sin espacio de nombres: coincide por nombre local)
+type feed struct {
+	Title        string  `xml:"title"`
+	Links        []link  `xml:"link"`
+	TotalResults int     `xml:"totalResults"`
+	StartIndex   int     `xml:"startIndex"`
+	Entries      []entry `xml:"entry"`
+}
+
+type link struct {
+	Rel         string `xml:"rel,attr"`
+	Href        string `xml:"href,attr"`
+	Type        string `xml:"type,attr"`
+	Title       string `xml:"title,attr"`
+	FacetGroup  string `xml:"facetGroup,attr"`
+	ActiveFacet bool   `xml:"activeFacet,attr"`
+	Count       int    `xml:"count,attr"`
+}
+
+type entry struct {
+	Title      string `xml:"title"`
+	ID         string `xml:"id"`
+	Identifier string `xml:"identifier"`
+	Language   string `xml:"language"`
+	Authors    []struct {
+		Name string `xml:"name"`
+		URI  string `xml:"uri"`
+	} `xml:"author"`
+	Links []link `xml:"link"`
+}
+
+// rel busca el link con esa relación
+func (f feed) rel(rel string) (link, bool) {
+	for _, l := range f.Links {
+		if l.Rel == rel {
+			return l, true
+		}
+	}
+	return link{}, false
+}
+
+// catalog es el catálogo de prueba con sus autores y la API key de un lector
+type catalog struct {
+	app      *fiber.App
+	key      string
+	cortazar string
+	borges   string
+}
+
+// newCatalog arma las rutas OPDS con la autenticación real por API key
+//
+// 📚 25 libros de Cortázar en español, 2 de Borges (uno en es-AR y uno en inglés)
+func newCatalog(t *testing.T) catalog {
+	t.Helper()
+	ctx := context.Background()
+	users := memory.NewInMemoryUserRepository()
+	admin, err := users.Create(ctx, &domain.User{ID: "admin", Name: "Admin", Email: "admin@example.com", Role: domain.RoleAdmin})
+	if err != nil {
+		t.Fatalf("No se pudo crear el admin: %v", err)
+	}
+	adminCtx := usecase.ContextWithUser(ctx, admin)
+
+	authorRepo := memory.NewInMemoryAuthorRepository()
+	bookRepo := memory.NewInMemoryBookRepository()
+	copyRepo := memory.NewInMemoryCopyRepository()
+	books := usecase.NewBookUseCase(bookRepo, authorRepo, copyRepo, memory.NewInMemoryLoanRepository(copyRepo), memory.NewInMemoryHoldRepository(), memory.NewInMemoryFineRepository())
+	authors := usecase.NewAuthorUseCase(authorRepo, bookRepo)
+	apiKeys := usecase.NewAPIKeyUseCase(memory.NewInMemoryAPIKeyRepository(), users, nil)
+
+	cortazar, _ := authors.CreateAuthor(adminCtx, usecase.AuthorInput{Name: "Julio Cortázar"})
+	borges, _ := authors.CreateAuthor(adminCtx, usecase.AuthorInput{Name: "Jorge Luis Borges"})
+	create := func(in usecase.BookInput) {
+		if _, err := books.CreateBook(adminCtx, in); err != nil {
+			t.Fatalf("No se pudo crear el libro: %v", err)
+		}
+	}
+	create(usecase.BookInput{Title: "Rayuela", Language: "es", ISBN: "9788437604572",
+		Authors: []domain.BookAuthor{{AuthorID: cortazar.ID}}})
+	for i := 1; i < 25; i++ {
+		create(usecase.BookInput{Title: "Cuentos " + strings.Repeat("I", i%5+1), Language: "es",
+			Authors: []domain.BookAuthor{{AuthorID: cortazar.ID}}})
+	}
+	create(usecase.BookInput{Title: "Ficciones", Language: "es-AR", Authors: []domain.BookAuthor{{AuthorID: borges.ID}}})
+	create(usecase.BookInput{Title: "Labyrinths", Language: "en", Authors: []domain.BookAuthor{{AuthorID: borges.ID}}})
+
+	key, err := apiKeys.CreateAPIKey(adminCtx, usecase.APIKeyInput{Name: "lector", Scopes: []domain.Permission{domain.PermBooksRead}})
+	if err != nil {
+		t.Fatalf("No se pudo crear la API key: %v", err)
+	}
+
+	// Sin RequireAuth: sin credenciales no hay usuario y el caso de uso responde 401
+	app := fiber.New(fiber.Config{ErrorHandler: api.ErrorHandler})
+	routes.SetupOPDSRoutes(app, opds.NewHandler(books, authors), api.NewAPIKeyHandler(apiKeys).AuthenticateBasic)
+	return catalog{app: app, key: key.Key, cortazar: cortazar.ID, borges: borges.ID}
+}
+
+// get hace la petición con la API key como contraseña de Basic (como un lector)
+func (c catalog) get(t *testing.T, target, password string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", target, nil)
+	if password != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Basic "+base64.StdEncoding.EncodeToString([]byte("lector:"+password)))
+	}
+	resp, err := c.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error inesperado en la petición: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// feed pide un feed y lo parsea
+func (c catalog) feed(t *testing.T, target string) feed {
+	t.Helper()
+	status, body := c.get(t, target, c.key)
+	if status != fiber.StatusOK {
+		t.Fatalf("Se esperaba 200 para %s, pero se obtuvo: %d\n%s", target, status, body)
+	}
+	var f feed
+	if err := xml.Unmarshal([]byte(body), &f); err != nil {
+		t.Fatalf("Se esperaba un feed Atom válido, pero se obtuvo: %v\n%s", err, body)
+	}
+	return f
+}
+
+// TestBooksFeed_Pagination verifica los links de paginación y los totales de OpenSearch
+func TestBooksFeed_Pagination(t *testing.T) {
+	// Arrange
+	cat := newCatalog(t)
+
+	// Act: la segunda página de 10, ordenada por título
+	f := cat.feed(t, "/opds/books?sort=title&limit=10&offset=10")
+
+	// Assert
+	if len(f.Entries) != 10 || f.TotalResults != 27 || f.StartIndex != 11 {
+		t.Errorf("Se esperaban 10 entradas de 27 desde la 11, pero se obtuvo: %d de %d desde %d", len(f.Entries), f.TotalResults, f.StartIndex)
+	}
+	want := map[string]string{
+		"first":    "/opds/books?limit=10&sort=title",
+		"previous": "/opds/books?limit=10&sort=title",
+		"next":     "/opds/books?limit=10&offset=20&sort=title",
+		"last":     "/opds/books?limit=10&offset=20&sort=title",
+		"start":    "/opds",
+		"search":   "/opds/opensearch.xml",
+	}
+	for rel, href := range want {
+		if l, ok := f.rel(rel); !ok || l.Href != href {
+			t.Errorf("Se esperaba el link %s → %s, pero se obtuvo: %+v", rel, href, l)
+		}
+	}
+
+	// Act: la última página no tiene next
+	last := cat.feed(t, "/opds/books?sort=title&limit=10&offset=20")
+
+	// Assert
+	if _, ok := last.rel("next"); ok || len(last.Entries) != 7 {
+		t.Errorf("Se esperaba la última página (7 entradas, sin next), pero se obtuvo: %d %+v", len(last.Entries), last.Links)
+	}
+}
+
+// TestBooksFeed_Facets verifica las facetas por autor e idioma y el filtro por idioma base
+func TestBooksFeed_Facets(t *testing.T) {
+	// Arrange
+	cat := newCatalog(t)
+
+	// Act: los libros en español ("es" incluye "es-AR")
+	f := cat.feed(t, "/opds/books?language=es")
+
+	// Assert
+	if f.TotalResults != 26 || f.Title != "Libros en español" {
+		t.Errorf("Se esperaban 26 libros en español, pero se obtuvo: %d %q", f.TotalResults, f.Title)
+	}
+	facets := map[string]link{}
+	for _, l := range f.Links {
+		if l.Rel == "http://opds-spec.org/facet" {
+			facets[l.FacetGroup+"/"+l.Title] = l
+		}
+	}
+	// La faceta de autor respeta el idioma; la de idioma ignora su propio filtro
+	if l := facets["Autor/Jorge Luis Borges"]; l.Count != 1 || l.Href != "/opds/books?author_id="+url.QueryEscape(cat.borges)+"&language=es" {
+		t.Errorf("Se esperaba Borges con 1 libro en español, pero se obtuvo: %+v", l)
+	}
+	if l := facets["Autor/Julio Cortázar"]; l.Count != 25 {
+		t.Errorf("Se esperaba Cortázar con 25 libros, pero se obtuvo: %+v", l)
+	}
+	if l := facets["Idioma/español"]; l.Count != 26 || !l.ActiveFacet {
+		t.Errorf("Se esperaba la faceta activa español (26), pero se obtuvo: %+v", l)
+	}
+	if l := facets["Idioma/inglés"]; l.Count != 1 || l.ActiveFacet || l.Href != "/opds/books?language=en" {
+		t.Errorf("Se esperaba la faceta inglés (1), pero se obtuvo: %+v", l)
+	}
+}
+
+// TestBooksFeed_Entry verifica los datos de un libro y sus links
+func TestBooksFeed_Entry(t *testing.T) {
+	// Arrange
+	cat := newCatalog(t)
+
+	// Act
+	f := cat.feed(t, "/opds/search?q=rayuela")
+
+	// Assert
+	if len(f.Entries) != 1 {
+		t.Fatalf("Se esperaba un resultado, pero se obtuvo: %+v", f.Entries)
+	}
+	e := f.Entries[0]
+	if e.Title != "Rayuela" || e.Identifier != "urn:isbn:9788437604572" || e.Language != "es" || !strings.HasPrefix(e.ID, "urn:uuid:") {
+		t.Errorf("Se esperaban los datos del libro, pero se obtuvo: %+v", e)
+	}
+	if len(e.Authors) != 1 || e.Authors[0].Name != "Julio Cortázar" || e.Authors[0].URI != "/opds/books?author_id="+cat.cortazar {
+		t.Errorf("Se esperaba el autor con su link, pero se obtuvo: %+v", e.Authors)
+	}
+	if len(e.Links) == 0 || e.Links[0].Rel != "http://opds-spec.org/acquisition/borrow" || e.Links[0].Href != "/api/books/"+strings.TrimPrefix(e.ID, "urn:uuid:") {
+		t.Errorf("Se esperaba el link de adquisición al libro, pero se obtuvo: %+v", e.Links)
+	}
+}
+
+// TestNavigationFeeds verifica el menú principal, los idiomas y la descripción OpenSearch
+func TestNavigationFeeds(t *testing.T) {
+	// Arrange
+	cat := newCatalog(t)
+
+	// Act
+	root := cat.feed(t, "/opds")
+	languages := cat.feed(t, "/opds/languages")
+	status, body := cat.get(t, "/opds/opensearch.xml", cat.key)
+
+	// Assert
+	if len(root.Entries) != 4 || root.Entries[0].Links[0].Href != "/opds/books?sort=-created_at" {
+		t.Errorf("Se esperaba el menú con 4 opciones, pero se obtuvo: %+v", root.Entries)
+	}
+	if len(languages.Entries) != 2 || languages.Entries[0].Title != "español" || languages.Entries[0].Links[0].Count != 26 {
+		t.Errorf("Se esperaban 2 idiomas (español primero), pero se obtuvo: %+v", languages.Entries)
+	}
+	var desc struct {
+		URL struct {
+			Template string `xml:"template,attr"`
+		} `xml:"Url"`
+	}
+	if err := xml.Unmarshal([]byte(body), &desc); err != nil || status != fiber.StatusOK ||
+		desc.URL.Template != "http://example.com/opds/search?q={searchTerms}" {
+		t.Errorf("Se esperaba la plantilla de búsqueda absoluta, pero se obtuvo: %d %v\n%s", status, err, body)
+	}
+}
+
+// TestChallenge verifica que un 401 pida Basic (la API key como contraseña)
+func TestChallenge(t *testing.T) {
+	// Arrange
+	cat := newCatalog(t)
+
+	for name, password := range map[string]string{"sin credenciales": "", "clave inválida": "bk_no_valida"} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			req := httptest.NewRequest("GET", "/opds/books", nil)
+			if password != "" {
+				req.SetBasicAuth("lector", password)
+			}
+			resp, err := cat.app.Test(req, -1)
+
+			// Assert
+			if err != nil || resp.StatusCode != fiber.StatusUnauthorized ||
+				!strings.HasPrefix(resp.Header.Get(fiber.HeaderWWWAuthenticate), `Basic realm="opds"`) {
+				t.Errorf("Se esperaba 401 con un desafío Basic, pero se obtuvo: %v %+v", err, resp)
+			}
+		})
+	}
+}
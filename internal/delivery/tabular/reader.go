@@ -0,0 +1,282 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/delivery/marc"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// MaxLineBytes es el largo máximo de una línea NDJSON (1 MiB)
+// 💡 Un libro ocupa unos pocos KB; una línea más larga casi seguro es un archivo roto
+const MaxLineBytes = 1 << 20
+
+// NewBookReader lee libros de r, una fila por vez, en el formato indicado
+//
+// 🗺️ mapping puede ser nil (las columnas se buscan por su nombre); MARC no lo usa
+// ❌ Un encabezado CSV sin columnas conocidas, o con dos columnas para el mismo
+// campo, es un error de validación: el archivo entero está mal armado (igual que
+// un xlsx, que solo se exporta)
+func NewBookReader(r io.Reader, format Format, mapping Mapping) (usecase.BookImportSource, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVBookReader(r, mapping)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), MaxLineBytes)
+		return &ndjsonBookReader{scanner: scanner, mapping: mapping}, nil
+	case FormatMARC, FormatMARCXML:
+		return marc.NewBookReader(r, format == FormatMARCXML), nil
+	case FormatXLSX:
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "format",
+			Code:    domain.CodeInvalidFormat,
+			Message: "xlsx solo se puede exportar: guarda la planilla como CSV para importarla",
+		})
+	default:
+		return nil, fmt.Errorf("tabular: formato no soportado %q", format)
+	}
+}
+
+// csvBookReader lee libros de un CSV con encabezado
+type csvBookReader struct {
+	reader  *csv.Reader
+	columns []string // Campo del libro de cada columna ("" = se ignora)
+}
+
+// newCSVBookReader lee el encabezado y resuelve el campo de cada columna
+func newCSVBookReader(r io.Reader, mapping Mapping) (*csvBookReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true // Un solo slice para todas las filas
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.NewValidationError("el archivo está vacío: se esperaba un encabezado CSV")
+	}
+	if err != nil {
+		return nil, domain.NewValidationError(fmt.Sprintf("el encabezado CSV no es válido: %v", err))
+	}
+
+	columns := make([]string, len(header))
+	var v domain.Validator
+	used := map[string]string{} // campo → columna que lo trae
+	for i, name := range header {
+		field := mapping.field(name)
+		if field == "" {
+			continue
+		}
+		if other, dup := used[field]; dup {
+			v.Add("mapping", domain.CodeInvalidFormat, fmt.Sprintf("las columnas %q y %q van al mismo campo %s", other, name, field))
+			continue
+		}
+		used[field] = name
+		columns[i] = field
+	}
+	if len(used) == 0 {
+		v.Add("mapping", domain.CodeRequired,
+			fmt.Sprintf("ninguna columna corresponde a un campo del libro (%s): usa un mapping", strings.Join(bookFields, ", ")))
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return &csvBookReader{reader: reader, columns: columns}, nil
+}
+
+// Next lee la fila siguiente
+//
+// 💡 Una fila con más o menos celdas que el encabezado se rechaza, pero la lectura sigue
+func (c *csvBookReader) Next() (*usecase.BookImportRow, error) {
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	row := &usecase.BookImportRow{Record: encodeCSVRecord(record)}
+	var parseErr *csv.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		row.Line = parseErr.StartLine
+		row.Err = domain.NewValidationError(fmt.Sprintf("la fila no es CSV válido: %v", parseErr.Err))
+		if errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			row.Err = domain.NewValidationError(fmt.Sprintf("la fila tiene %d columnas y el encabezado %d", len(record), len(c.columns)))
+		}
+		return row, nil
+	case err != nil:
+		return nil, err // No es del formato: falló la lectura (ej: la conexión se cortó)
+	}
+
+	row.Line, _ = c.reader.FieldPos(0)
+	var b inputBuilder
+	for i, field := range c.columns {
+		if field != "" {
+			b.set(field, unescapeFormula(record[i]))
+		}
+	}
+	row.Input, row.Err = b.in, b.v.Err()
+	return row, nil
+}
+
+// encodeCSVRecord vuelve a escribir la fila como CSV (para el reporte de rechazos)
+func encodeCSVRecord(record []string) string {
+	if len(record) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(record)
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ndjsonBookReader lee libros de un archivo NDJSON: un objeto por línea
+type ndjsonBookReader struct {
+	scanner *bufio.Scanner
+	mapping Mapping
+	line    int
+}
+
+// Next lee la línea siguiente (las líneas en blanco se saltean)
+//
+// 📋 Los valores pueden ser texto o número indistintamente ("2017" o 2017), y
+// "subjects" un array o un texto separado por comas; null es una celda vacía
+func (n *ndjsonBookReader) Next() (*usecase.BookImportRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row := &usecase.BookImportRow{Line: n.line, Record: string(line)}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(line, &object); err != nil {
+			row.Err = domain.NewValidationError("la línea no es un objeto JSON válido")
+			return row, nil
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys) // El orden de un map es aleatorio: los errores saldrían desordenados
+
+		var b inputBuilder
+		for _, key := range keys {
+			if field := n.mapping.field(key); field != "" {
+				b.setJSON(field, object[key])
+			}
+		}
+		row.Input, row.Err = b.in, b.v.Err()
+		return row, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, domain.NewValidationError(fmt.Sprintf("la línea %d supera el máximo de %d bytes", n.line+1, MaxLineBytes))
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// inputBuilder arma un BookInput celda por celda y acumula las celdas inválidas
+type inputBuilder struct {
+	in usecase.BookInput
+	v  domain.Validator
+}
+
+// set copia el texto de una celda en su campo
+func (b *inputBuilder) set(field, value string) {
+	value = strings.TrimSpace(value)
+	switch field {
+	case FieldTitle:
+		b.in.Title = value
+	case FieldAuthor:
+		b.in.Author = value
+	case FieldISBN:
+		b.in.ISBN = value
+	case FieldPublisher:
+		b.in.Publisher = value
+	case FieldPublicationYear:
+		b.in.PublicationYear = b.integer(field, value)
+	case FieldLanguage:
+		b.in.Language = value
+	case FieldPageCount:
+		b.in.PageCount = b.integer(field, value)
+	case FieldDescription:
+		b.in.Description = value
+	case FieldSubjects:
+		b.in.Subjects = splitSubjects(value)
+	case FieldEdition:
+		b.in.Edition = value
+	}
+}
+
+// setJSON copia un valor JSON en su campo
+func (b *inputBuilder) setJSON(field string, raw json.RawMessage) {
+	var text string
+	var number json.Number
+	var list []string
+	switch {
+	case string(raw) == "null":
+		return
+	case json.Unmarshal(raw, &text) == nil:
+		b.set(field, text)
+	case json.Unmarshal(raw, &number) == nil:
+		b.set(field, number.String())
+	case field == FieldSubjects && json.Unmarshal(raw, &list) == nil:
+		b.in.Subjects = list
+	default:
+		b.v.Add(field, domain.CodeInvalidFormat, "el valor debe ser un texto o un número")
+	}
+}
+
+// integer interpreta una celda numérica (vacía = 0, sin dato)
+func (b *inputBuilder) integer(field, value string) int {
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		b.v.Add(field, domain.CodeInvalidFormat, fmt.Sprintf("%q no es un número entero", value))
+	}
+	return n
+}
+
+// unescapeFormula quita el ' que escapeFormula antepuso a una celda CSV
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// splitSubjects separa "Arquitectura, Software" en sus materias
+//
+// 💡 Una materia entre comillas puede tener comas: `Arquitectura, "Software, pruebas de"`
+// (lo que escribe joinSubjects). Si las comillas no cierran o la celda tiene saltos de
+// línea (csv los tomaría como otra fila), se separa solo por comas
+func splitSubjects(value string) []string {
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.TrimLeadingSpace = true
+	fields, err := reader.Read()
+	if err != nil || strings.ContainsAny(value, "\r\n") {
+		fields = strings.Split(value, ",")
+	}
+
+	var subjects []string
+	for _, subject := range fields {
+		if subject = strings.TrimSpace(subject); subject != "" {
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects
+}
@@ -0,0 +1,39 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// RejectionColumns son los encabezados del reporte de filas rechazadas
+var RejectionColumns = []string{"line", "reason", "errors", "record"}
+
+// WriteRejections escribe el reporte de filas rechazadas como CSV
+//
+// 📋 Una fila por rechazo; "errors" resume el detalle por campo:
+//
+//	line,reason,errors,record
+//	4,los datos enviados no son válidos,isbn: el ISBN no es válido; title: el título es obligatorio,"Sin título,,123"
+//
+// 💡 "record" es la fila original: se puede corregir y volver a importar
+func WriteRejections(w io.Writer, rejections []domain.ImportRejection) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(RejectionColumns); err != nil {
+		return err
+	}
+	for _, r := range rejections {
+		fields := make([]string, len(r.Errors))
+		for i, f := range r.Errors {
+			fields[i] = f.Field + ": " + f.Message
+		}
+		if err := out.Write([]string{strconv.Itoa(r.Line), r.Reason, strings.Join(fields, "; "), r.Record}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,204 @@
+// Package tabular lee y escribe libros (y usuarios) en archivos: CSV, NDJSON, xlsx y MARC
+//
+// 🎯 Lo comparten las capas de delivery que mueven archivos: POST /api/books/import,
+// GET /api/books/export, GET /api/users/export y "bookctl import". El formato es un
+// detalle de entrega: el caso de uso solo ve filas ya traducidas a usecase.BookInput
+// (ver usecase.BookImportSource) o entidades del dominio
+//
+// 🌊 Todo es streaming: se lee y se escribe una fila por vez, así que un archivo de
+// un millón de filas usa la misma memoria que uno de diez
+//
+// 🗺️ Columnas: los nombres son los del JSON de la API (title, author, isbn, ...),
+// sin distinguir mayúsculas. Un archivo con otros nombres se adapta con un Mapping:
+//
+//	titulo=title,autor=author,año=publication_year
+//
+// Las columnas que no corresponden a ningún campo (id, created_at, ...) se ignoran:
+// lo que exporta GET /api/books/export (o "bookctl books list -o csv") se puede volver
+// a importar tal cual
+//
+// 📊 xlsx solo se escribe (para abrir el catálogo en una planilla); para importar, csv o ndjson
+//
+// 📚 MARC21 (binario y MARCXML) es el formato de los sistemas de bibliotecas: solo
+// describe libros y no usa columnas (ni Mapping). La traducción vive en delivery/marc
+package tabular
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Format es el formato de un archivo de filas
+type Format string
+
+// Formatos soportados
+const (
+	FormatCSV     Format = "csv"     // Valores separados por coma, con encabezado (RFC 4180)
+	FormatNDJSON  Format = "ndjson"  // Un objeto JSON por línea (también .jsonl)
+	FormatXLSX    Format = "xlsx"    // Libro de Excel (Office Open XML), solo para exportar
+	FormatMARC    Format = "marc"    // MARC21 binario (ISO 2709, .mrc), solo libros
+	FormatMARCXML Format = "marcxml" // MARC21 en XML (MARCXML), solo libros
+)
+
+// IsMARC indica si el formato es MARC21 (binario o XML)
+func (f Format) IsMARC() bool {
+	return f == FormatMARC || f == FormatMARCXML
+}
+
+// Extension es la extensión habitual de los archivos del formato
+func (f Format) Extension() string {
+	switch f {
+	case FormatMARC:
+		return "mrc"
+	case FormatMARCXML:
+		return "xml"
+	default:
+		return string(f)
+	}
+}
+
+// ContentType es el tipo MIME del formato (para el header Content-Type)
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatMARC:
+		return "application/marc"
+	case FormatMARCXML:
+		return "application/marcxml+xml"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ParseFormat valida el nombre de un formato ("csv", "ndjson", "jsonl", "xlsx", "marc",
+// "mrc", "marcxml" o "xml")
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "csv":
+		return FormatCSV, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	case "marc", "mrc":
+		return FormatMARC, nil
+	case "marcxml", "xml":
+		return FormatMARCXML, nil
+	default:
+		return "", domain.NewFieldsError(domain.FieldError{
+			Field:   "format",
+			Code:    domain.CodeInvalidFormat,
+			Message: fmt.Sprintf("formato desconocido %q: usa csv, ndjson, xlsx, marc o marcxml", name),
+		})
+	}
+}
+
+// DetectFormat deduce el formato por la extensión del archivo (libros.csv, libros.mrc, ...)
+func DetectFormat(filename string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(filename), ".")
+	if ext == "" {
+		return "", domain.NewFieldsError(domain.FieldError{
+			Field:   "format",
+			Code:    domain.CodeRequired,
+			Message: "no se puede deducir el formato sin extensión: indica csv, ndjson, marc o marcxml",
+		})
+	}
+	return ParseFormat(ext)
+}
+
+// ResolveFormat usa el formato indicado o, si no hay ninguno, lo deduce del nombre del archivo
+func ResolveFormat(name, filename string) (Format, error) {
+	if name != "" {
+		return ParseFormat(name)
+	}
+	return DetectFormat(filename)
+}
+
+// Campos de un libro que se pueden importar (los nombres del JSON de la API)
+const (
+	FieldTitle           = "title"
+	FieldAuthor          = "author"
+	FieldISBN            = "isbn"
+	FieldPublisher       = "publisher"
+	FieldPublicationYear = "publication_year"
+	FieldLanguage        = "language"
+	FieldPageCount       = "page_count"
+	FieldDescription     = "description"
+	FieldSubjects        = "subjects"
+	FieldEdition         = "edition"
+)
+
+// bookFields son los campos importables, en el orden en que se documentan
+var bookFields = []string{
+	FieldTitle, FieldAuthor, FieldISBN, FieldPublisher, FieldPublicationYear,
+	FieldLanguage, FieldPageCount, FieldDescription, FieldSubjects, FieldEdition,
+}
+
+// Mapping traduce nombres de columna del archivo a campos del libro
+//
+// 📋 Ejemplo: {"titulo": "title", "autor": "author"}
+// 💡 Las columnas que no están en el Mapping se buscan por su propio nombre
+type Mapping map[string]string
+
+// ParseMapping lee un Mapping escrito como "columna=campo,columna=campo"
+//
+// ✅ Valida que cada campo exista: un "titulo=titel" se informa antes de leer el archivo
+func ParseMapping(spec string) (Mapping, error) {
+	mapping := Mapping{}
+	var v domain.Validator
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		column, field, ok := strings.Cut(pair, "=")
+		column, field = normalizeColumn(column), normalizeColumn(field)
+		if !ok || column == "" || field == "" {
+			v.Add("mapping", domain.CodeInvalidFormat, fmt.Sprintf("%q no tiene la forma columna=campo", strings.TrimSpace(pair)))
+			continue
+		}
+		if !isBookField(field) {
+			v.Add("mapping", domain.CodeInvalidFormat,
+				fmt.Sprintf("el campo %q no existe (campos: %s)", field, strings.Join(bookFields, ", ")))
+			continue
+		}
+		mapping[column] = field
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// field retorna el campo del libro para una columna del archivo ("" = se ignora)
+func (m Mapping) field(column string) string {
+	column = normalizeColumn(column)
+	if field, ok := m[column]; ok {
+		return field
+	}
+	if isBookField(column) {
+		return column
+	}
+	return ""
+}
+
+// normalizeColumn compara nombres sin distinguir mayúsculas ni espacios alrededor
+// 💡 Quita también el BOM que Excel pone al principio de los CSV en UTF-8
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+}
+
+// isBookField indica si name es un campo importable
+func isBookField(name string) bool {
+	for _, field := range bookFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/tabular"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// readAll lee todas las filas de un archivo
+func readAll(t *testing.T, source usecase.BookImportSource) []*usecase.BookImportRow {
+	t.Helper()
+	var rows []*usecase.BookImportRow
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("No se esperaba error al leer, pero se obtuvo: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// TestBookReader_CSV verifica el mapping, el BOM de Excel y las filas mal formadas
+func TestBookReader_CSV(t *testing.T) {
+	// Arrange: columnas en castellano, una columna desconocida y una fila con una celda de más
+	file := "\ufeffTitulo,Autor,ISBN,Año,Notas\n" +
+		"\"Hola, \"\"mundo\"\"\",Ana,,2001,x\n" +
+		"Otro,Beto,,dos mil,y\n" +
+		"Roto,Carla,,2001,z,de más\n"
+	mapping, err := tabular.ParseMapping("titulo=title, autor=author, año=publication_year")
+	if err != nil {
+		t.Fatalf("No se esperaba error en el mapping, pero se obtuvo: %v", err)
+	}
+
+	// Act
+	source, err := tabular.NewBookReader(strings.NewReader(file), tabular.FormatCSV, mapping)
+	if err != nil {
+		t.Fatalf("No se esperaba error en el encabezado, pero se obtuvo: %v", err)
+	}
+	rows := readAll(t, source)
+
+	// Assert
+	if len(rows) != 3 {
+		t.Fatalf("Se esperaban 3 filas, pero se obtuvieron %d", len(rows))
+	}
+	if in := rows[0].Input; rows[0].Err != nil || in.Title != `Hola, "mundo"` || in.Author != "Ana" || in.PublicationYear != 2001 {
+		t.Errorf("Se esperaba la primera fila completa, pero se obtuvo: %+v (err: %v)", in, rows[0].Err)
+	}
+	var fieldsErr *domain.Error
+	if !errors.As(rows[1].Err, &fieldsErr) || len(fieldsErr.Fields) != 1 || fieldsErr.Fields[0].Field != "publication_year" {
+		t.Errorf("Se esperaba el error en publication_year, pero se obtuvo: %v", rows[1].Err)
+	}
+	if rows[2].Line != 4 || !errors.Is(rows[2].Err, domain.ErrValidation) || rows[2].Record != "Roto,Carla,,2001,z,de más" {
+		t.Errorf("Se esperaba rechazar la línea 4 con su contenido, pero se obtuvo: %+v", rows[2])
+	}
+}
+
+// TestBookReader_InvalidFiles verifica los errores del archivo entero
+func TestBookReader_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		format tabular.Format
+	}{
+		{"vacío", "", tabular.FormatCSV},
+		{"sin columnas conocidas", "titulo,autor\nx,y\n", tabular.FormatCSV},
+		{"dos columnas al mismo campo", "title,Title\nx,y\n", tabular.FormatCSV},
+		{"xlsx no se importa", "", tabular.FormatXLSX},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tabular.NewBookReader(strings.NewReader(tt.file), tt.format, nil)
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Se esperaba ErrValidation, pero se obtuvo: %v", err)
+			}
+		})
+	}
+}
+
+// TestBookReader_NDJSON verifica textos, números, arrays, null y líneas inválidas
+func TestBookReader_NDJSON(t *testing.T) {
+	// Arrange
+	file := `{"title":"Clean Code","author":"Robert C. Martin","publication_year":2008,"subjects":["Programación"],"isbn":null}` + "\n" +
+		"\n" +
+		`{"title":"Refactoring","author":"Martin Fowler","page_count":"448","subjects":"Diseño, Calidad"}` + "\n" +
+		`{"title":` + "\n"
+
+	// Act
+	source, _ := tabular.NewBookReader(strings.NewReader(file), tabular.FormatNDJSON, nil)
+	rows := readAll(t, source)
+
+	// Assert: la línea en blanco no cuenta, pero sí su número
+	if len(rows) != 3 {
+		t.Fatalf("Se esperaban 3 filas, pero se obtuvieron %d", len(rows))
+	}
+	if in := rows[0].Input; in.PublicationYear != 2008 || len(in.Subjects) != 1 || in.ISBN != "" {
+		t.Errorf("Se esperaba la primera línea completa, pero se obtuvo: %+v", in)
+	}
+	if in := rows[1].Input; rows[1].Line != 3 || in.PageCount != 448 || len(in.Subjects) != 2 {
+		t.Errorf("Se esperaba la línea 3 con 448 páginas y 2 materias, pero se obtuvo: %+v", rows[1])
+	}
+	if rows[2].Line != 4 || !errors.Is(rows[2].Err, domain.ErrValidation) {
+		t.Errorf("Se esperaba rechazar la línea 4, pero se obtuvo: %+v", rows[2])
+	}
+}
+
+// exportBooks son libros con los textos que rompen un CSV ingenuo
+var exportBooks = []*domain.Book{
+	{ID: "1", Title: `Hola, "mundo"`, Author: "Ana", PublicationYear: 2001, Description: "línea 1\nlínea 2 <b>&</b>",
+		Subjects: []string{"Arquitectura", "Software"}, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	{ID: "2", Title: "Sin datos", Author: "Beto"},
+}
+
+// TestWriter_CSVRoundTrip verifica que lo exportado se pueda volver a importar igual
+func TestWriter_CSVRoundTrip(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	out, err := tabular.NewWriter(&buf, tabular.FormatCSV, tabular.BookColumns)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+	}
+
+	// Act
+	for _, book := range exportBooks {
+		if err := out.Write(book); err != nil {
+			t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+		}
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("No se esperaba error al cerrar, pero se obtuvo: %v", err)
+	}
+
+	// Assert: encabezado en orden fijo y comillas donde hacen falta
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	if header != "id,title,author,isbn,publisher,publication_year,language,page_count,edition,subjects,description,created_at" {
+		t.Errorf("Encabezado inesperado: %q", header)
+	}
+	if !strings.Contains(buf.String(), `"Hola, ""mundo"""`) {
+		t.Errorf("Se esperaba el título entre comillas, pero se obtuvo: %s", buf.String())
+	}
+	source, err := tabular.NewBookReader(&buf, tabular.FormatCSV, nil)
+	if err != nil {
+		t.Fatalf("No se esperaba error al releer, pero se obtuvo: %v", err)
+	}
+	rows := readAll(t, source)
+	if len(rows) != 2 || rows[0].Input.Title != exportBooks[0].Title || rows[0].Input.Description != exportBooks[0].Description ||
+		len(rows[0].Input.Subjects) != 2 || rows[1].Line != 4 {
+		t.Errorf("Se esperaban los mismos libros al releer, pero se obtuvo: %+v", rows)
+	}
+}
+
+// TestWriter_XLSX verifica que el xlsx sea un zip con la hoja, los números y el texto escapado
+func TestWriter_XLSX(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	out, _ := tabular.NewWriter(&buf, tabular.FormatXLSX, tabular.BookColumns)
+
+	// Act
+	for _, book := range exportBooks {
+		out.Write(book)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("No se esperaba error al cerrar, pero se obtuvo: %v", err)
+	}
+
+	// Assert
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Se esperaba un zip válido, pero se obtuvo: %v", err)
+	}
+	var sheet string
+	for _, f := range archive.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			content, _ := io.ReadAll(r)
+			sheet = string(content)
+		}
+	}
+	for _, want := range []string{
+		`<c r="L1" t="inlineStr" s="1"><is><t xml:space="preserve">created_at</t></is></c>`, // Encabezado en negrita
+		`<c r="F2"><v>2001</v></c>`,        // El año es un número
+		`Hola, &#34;mundo&#34;`,            // Comillas escapadas
+		`línea 2 &lt;b&gt;&amp;&lt;/b&gt;`, // HTML escapado
+		`<row r="3">`,                      // Una fila por libro
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Se esperaba %q en la hoja, pero se obtuvo: %s", want, sheet)
+		}
+	}
+}
+
+// TestWriter_FormulaInjection verifica que un texto que la planilla tomaría como fórmula
+// salga con ' en csv y xlsx, y que al reimportar el CSV vuelva igual (con materias que tienen comas)
+func TestWriter_FormulaInjection(t *testing.T) {
+	// Arrange
+	book := &domain.Book{ID: "1", Title: `=HYPERLINK("http://example.com", "Clic")`, Author: "@Ana", Publisher: "+Editorial",
+		Description: "-ismo", Subjects: []string{"Software, pruebas de", `Guía "rápida"`, "-Historia"}}
+	write := func(format tabular.Format) string {
+		var buf bytes.Buffer
+		out, _ := tabular.NewWriter(&buf, format, tabular.BookColumns)
+		if err := out.Write(book); err != nil {
+			t.Fatalf("No se esperaba error al escribir, pero se obtuvo: %v", err)
+		}
+		if err := out.Close(); err != nil {
+			t.Fatalf("No se esperaba error al cerrar, pero se obtuvo: %v", err)
+		}
+		return buf.String()
+	}
+
+	// Act
+	file := write(tabular.FormatCSV)
+	archive := write(tabular.FormatXLSX)
+
+	// Assert: ninguna celda empieza con =, +, - o @
+	source, _ := tabular.NewBookReader(strings.NewReader(file), tabular.FormatCSV, nil)
+	cells, _ := csv.NewReader(strings.NewReader(file)).ReadAll()
+	for _, cell := range cells[1] {
+		if cell != "" && strings.ContainsAny(cell[:1], "=+-@") {
+			t.Errorf("Se esperaba la celda escapada con ', pero se obtuvo: %q", cell)
+		}
+	}
+	zipped, err := zip.NewReader(strings.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Se esperaba un zip válido, pero se obtuvo: %v", err)
+	}
+	for _, f := range zipped.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			sheet, _ := io.ReadAll(r)
+			if !strings.Contains(string(sheet), `<t xml:space="preserve">&#39;=HYPERLINK(`) {
+				t.Errorf("Se esperaba el título escapado con ' en la hoja, pero se obtuvo: %s", sheet)
+			}
+		}
+	}
+
+	// Assert: al reimportar, el ' se quita y las materias no se parten
+	rows := readAll(t, source)
+	want := usecase.BookInput{Title: book.Title, Author: book.Author, Publisher: book.Publisher,
+		Description: book.Description, Subjects: book.Subjects}
+	if len(rows) != 1 || !reflect.DeepEqual(rows[0].Input, want) {
+		t.Errorf("Se esperaba %+v al reimportar, pero se obtuvo: %+v", want, rows)
+	}
+}
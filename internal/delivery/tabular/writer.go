@@ -0,0 +1,188 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/delivery/marc"
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Column es una columna de una exportación: su encabezado y cómo sacar el valor
+type Column[T any] struct {
+	Name   string
+	Value  func(T) string
+	Number bool // En xlsx va como número (se puede sumar y ordenar en la planilla)
+}
+
+// BookColumns son las columnas de la exportación de libros
+//
+// 📋 El orden es fijo (parte del contrato): una planilla que lee la columna 4
+// sigue leyendo el ISBN después de una actualización
+// 💡 Los nombres son los campos del JSON: el archivo se puede volver a importar tal cual
+var BookColumns = []Column[*domain.Book]{
+	{Name: "id", Value: func(b *domain.Book) string { return b.ID }},
+	{Name: FieldTitle, Value: func(b *domain.Book) string { return b.Title }},
+	{Name: FieldAuthor, Value: func(b *domain.Book) string { return b.Author }},
+	{Name: FieldISBN, Value: func(b *domain.Book) string { return b.ISBN }},
+	{Name: FieldPublisher, Value: func(b *domain.Book) string { return b.Publisher }},
+	{Name: FieldPublicationYear, Value: func(b *domain.Book) string { return formatInt(b.PublicationYear) }, Number: true},
+	{Name: FieldLanguage, Value: func(b *domain.Book) string { return b.Language }},
+	{Name: FieldPageCount, Value: func(b *domain.Book) string { return formatInt(b.PageCount) }, Number: true},
+	{Name: FieldEdition, Value: func(b *domain.Book) string { return b.Edition }},
+	{Name: FieldSubjects, Value: func(b *domain.Book) string { return joinSubjects(b.Subjects) }},
+	{Name: FieldDescription, Value: func(b *domain.Book) string { return b.Description }},
+	{Name: "created_at", Value: func(b *domain.Book) string { return b.CreatedAt.Format(time.RFC3339) }},
+}
+
+// UserColumns son las columnas de la exportación de usuarios (nunca el hash de la contraseña)
+var UserColumns = []Column[*domain.User]{
+	{Name: "id", Value: func(u *domain.User) string { return u.ID }},
+	{Name: "name", Value: func(u *domain.User) string { return u.Name }},
+	{Name: "email", Value: func(u *domain.User) string { return u.Email }},
+	{Name: "role", Value: func(u *domain.User) string { return string(u.Role) }},
+	{Name: "created_at", Value: func(u *domain.User) string { return u.CreatedAt.Format(time.RFC3339) }},
+}
+
+// ItemWriter escribe elementos de a uno en un archivo (ver NewWriter y NewBookWriter)
+type ItemWriter[T any] interface {
+	Write(item T) error
+	Close() error
+}
+
+// NewBookWriter crea el escritor de libros del formato: MARC o columnas (BookColumns)
+func NewBookWriter(w io.Writer, format Format) (ItemWriter[*domain.Book], error) {
+	if format.IsMARC() {
+		return marc.NewBookWriter(w, format == FormatMARCXML)
+	}
+	return NewWriter(w, format, BookColumns)
+}
+
+// Writer escribe filas de T en un formato, una por vez
+//
+// 🌊 Nada se acumula: cada Write va directo al io.Writer (con un buffer chico)
+// ⚠️ Close es obligatorio: escribe lo que queda en el buffer y, en xlsx, el cierre del archivo
+type Writer[T any] struct {
+	columns []Column[T]
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	json    *json.Encoder
+	xlsx    *xlsxWriter
+	record  []string
+}
+
+// NewWriter crea un Writer y escribe el encabezado (una exportación vacía lo trae igual)
+//
+// 📋 Formatos:
+// - csv: RFC 4180; los textos con comas, comillas o saltos de línea van entre comillas
+// - ndjson: cada elemento como el JSON de la API (mismos campos que GET /api/books/:id)
+// - xlsx: una hoja con encabezado, para abrir directo en Excel o LibreOffice
+func NewWriter[T any](w io.Writer, format Format, columns []Column[T]) (*Writer[T], error) {
+	out := &Writer[T]{columns: columns, buf: bufio.NewWriter(w), record: make([]string, len(columns))}
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Name
+	}
+
+	switch format {
+	case FormatCSV:
+		out.csv = csv.NewWriter(out.buf)
+		return out, out.csv.Write(headers)
+	case FormatNDJSON:
+		out.json = json.NewEncoder(out.buf) // Encode agrega el salto de línea
+		return out, nil
+	case FormatXLSX:
+		numbers := make([]bool, len(columns))
+		for i, col := range columns {
+			numbers[i] = col.Number
+		}
+		xlsx, err := newXLSXWriter(out.buf, numbers)
+		if err != nil {
+			return nil, err
+		}
+		out.xlsx = xlsx
+		return out, xlsx.WriteRow(headers, true)
+	default:
+		return nil, fmt.Errorf("tabular: el formato %q no es de columnas", format)
+	}
+}
+
+// Write escribe un elemento
+//
+// 🛡️ En csv y xlsx los textos pasan por escapeFormula: la planilla no los ejecuta
+func (w *Writer[T]) Write(item T) error {
+	if w.json != nil {
+		return w.json.Encode(item)
+	}
+
+	for i, col := range w.columns {
+		value := col.Value(item)
+		if !col.Number {
+			value = escapeFormula(value)
+		}
+		w.record[i] = value
+	}
+	if w.xlsx != nil {
+		return w.xlsx.WriteRow(w.record, false)
+	}
+	return w.csv.Write(w.record)
+}
+
+// Close termina el archivo y vacía el buffer
+func (w *Writer[T]) Close() error {
+	switch {
+	case w.csv != nil:
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	case w.xlsx != nil:
+		if err := w.xlsx.Close(); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+// formulaPrefixes son los caracteres con los que Excel y LibreOffice empiezan una fórmula
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula antepone ' a un texto que la planilla tomaría como fórmula
+//
+// 🛡️ Un título "=HYPERLINK(...)" se ejecutaría al abrir el archivo (inyección de
+// fórmulas): con ' la planilla lo muestra como texto. Al importar se quita (unescapeFormula)
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// joinSubjects une las materias con ", " (ver splitSubjects)
+//
+// 💡 Una materia con coma o comillas va entre comillas, como en CSV: "Software, pruebas de"
+// no se parte en dos al volver a importar
+func joinSubjects(subjects []string) string {
+	quoted := make([]string, len(subjects))
+	for i, subject := range subjects {
+		if strings.ContainsAny(subject, `,"`) {
+			subject = `"` + strings.ReplaceAll(subject, `"`, `""`) + `"`
+		}
+		quoted[i] = subject
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// formatInt escribe un entero opcional (0 = sin dato, celda vacía)
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,161 @@
+package tabular
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Límites de una hoja de Excel: un archivo que los supera no abre
+const (
+	MaxXLSXRows      = 1 << 20 // Filas por hoja, encabezado incluido
+	maxXLSXCellChars = 32767   // Caracteres por celda (una descripción más larga se corta)
+)
+
+// xlsxWriter escribe un libro de Excel de una sola hoja, fila por fila
+//
+// 📦 Un .xlsx es un zip de archivos XML (Office Open XML). Las partes fijas se
+// escriben al empezar y la hoja va al final, mientras llegan las filas: archive/zip
+// no necesita volver atrás, así que el archivo sale en streaming
+//
+// 💡 Los textos van como "inline strings": no hace falta la tabla de textos
+// compartidos (que obligaría a tener todas las filas antes de escribir)
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   io.Writer
+	numbers []bool // Columnas que van como número
+	rows    int
+}
+
+// xlsxParts son las partes fijas del archivo (todo menos la hoja)
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Datos" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// Estilo 1 = negrita (el encabezado)
+	{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font/><font><b/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf/><xf fontId="1" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// newXLSXWriter escribe las partes fijas y abre la hoja
+func newXLSXWriter(w io.Writer, numbers []bool) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	now := time.Now()
+	create := func(name string) (io.Writer, error) {
+		return archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+	}
+	for _, part := range xlsxParts {
+		f, err := create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	// El encabezado queda fijo al desplazarse (pane congelado en la fila 1)
+	_, err = io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`+
+		`<sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{zip: archive, sheet: sheet, numbers: numbers}, nil
+}
+
+// WriteRow escribe una fila (header = en negrita, todo como texto)
+func (x *xlsxWriter) WriteRow(values []string, header bool) error {
+	if x.rows == MaxXLSXRows {
+		return fmt.Errorf("tabular: una hoja xlsx admite hasta %d filas: usa csv o ndjson", MaxXLSXRows)
+	}
+	x.rows++
+
+	row := strconv.Itoa(x.rows)
+	if _, err := io.WriteString(x.sheet, `<row r="`+row+`">`); err != nil {
+		return err
+	}
+	for i, value := range values {
+		if value == "" {
+			continue // Una celda vacía simplemente no se escribe
+		}
+		ref := columnName(i) + row
+		if !header && x.numbers[i] {
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				if _, err := io.WriteString(x.sheet, `<c r="`+ref+`"><v>`+value+`</v></c>`); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		style := ""
+		if header {
+			style = ` s="1"`
+		}
+		if _, err := io.WriteString(x.sheet, `<c r="`+ref+`" t="inlineStr"`+style+`><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		// EscapeText escapa <, >, & y comillas, y reemplaza los caracteres que XML no admite
+		if err := xml.EscapeText(x.sheet, []byte(truncateCell(value))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(x.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(x.sheet, `</row>`)
+	return err
+}
+
+// Close cierra la hoja y el zip
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// columnName traduce el índice de una columna a su letra: 0 → A, 25 → Z, 26 → AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// truncateCell corta un texto al máximo que admite una celda
+func truncateCell(value string) string {
+	if utf8.RuneCountInString(value) <= maxXLSXCellChars {
+		return value
+	}
+	return string([]rune(value)[:maxXLSXCellChars])
+}
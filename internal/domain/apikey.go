@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// APIKey es una credencial de larga duración para clientes que no pueden hacer login
+// (scripts de integración, sistemas de socios)
+//
+// 🔑 Formato de la clave: "bk_<prefijo>_<secreto>"
+// - El prefijo es público: identifica la clave (se guarda tal cual y se muestra en los listados)
+// - El secreto solo se muestra UNA vez, al crearla; se guarda únicamente su hash
+//
+// 🛡️ Una clave actúa en nombre de su usuario, pero acotada a sus Scopes:
+// puede hacer lo que el rol del usuario permite Y la clave también
+type APIKey struct {
+	ID         string       `json:"id"`                     // Identificador único
+	UserID     string       `json:"user_id"`                // Usuario en cuyo nombre actúa
+	Name       string       `json:"name"`                   // Para qué es (ej: "sincronización del catálogo")
+	Prefix     string       `json:"prefix"`                 // Parte pública de la clave
+	Hash       string       `json:"-"`                      // Hash de la clave completa (nunca se expone)
+	Scopes     []Permission `json:"scopes"`                 // Permisos que la clave habilita
+	CreatedAt  time.Time    `json:"created_at"`             // Cuándo se creó
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`   // Vencimiento (nil = no vence)
+	LastUsedAt *time.Time   `json:"last_used_at,omitempty"` // Último uso (aproximado, ver APIKeyUseCase)
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`   // Cuándo se revocó
+}
+
+// Active indica si la clave se puede usar en now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Allows indica si la clave habilita el permiso
+//
+// 💡 Escribir implica leer: books:write habilita books:read y users:admin habilita users:read
+func (k *APIKey) Allows(p Permission) bool {
+	for _, scope := range k.Scopes {
+		if scope == p || impliedScopes[scope] == p {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyScopes son los permisos que se le pueden dar a una API key
+//
+// 🚫 circulation:manage no está: prestar y cobrar multas es trabajo de mostrador,
+// con una persona que hace login
+var APIKeyScopes = []Permission{PermBooksRead, PermBooksWrite, PermUsersAdmin}
+
+// ValidAPIKeyScope indica si el permiso se le puede dar a una API key
+func ValidAPIKeyScope(p Permission) bool {
+	for _, scope := range APIKeyScopes {
+		if scope == p {
+			return true
+		}
+	}
+	return false
+}
+
+// impliedScopes es el permiso de lectura que acompaña a cada permiso de escritura
+var impliedScopes = map[Permission]Permission{
+	PermBooksWrite: PermBooksRead,
+	PermUsersAdmin: PermUsersRead,
+}
+
+// IssuedAPIKey es una API key recién creada junto con la clave en texto plano
+//
+// ⚠️ Es la única vez que se ve la clave: el servidor no puede volver a mostrarla
+type IssuedAPIKey struct {
+	*APIKey
+	Key string `json:"key"` // La clave completa: "bk_<prefijo>_<secreto>"
+}
+
+// APIKeyFilter filtra API keys por usuario
+type APIKeyFilter struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// APIKeyQuery combina paginación, orden y filtros para listar API keys
+type APIKeyQuery struct {
+	PageRequest
+	Filter APIKeyFilter
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// Session es una sesión iniciada con login
+//
+// 🔑 Cada login emite dos tokens:
+// - access token: corto (minutos), va en cada petición y NO se guarda en el servidor
+// - refresh token: largo (días), solo sirve para pedir un par nuevo; su ID es el de la sesión
+//
+// 🔁 Rotación: cada refresh cierra la sesión (RevokedAt, ReplacedBy) y abre otra.
+// Si alguien presenta un refresh token ya rotado, es que se filtró: se cierran todas
+// las sesiones del usuario (detección de reutilización).
+type Session struct {
+	ID         string     `json:"id"`                    // Identificador (jti del refresh token)
+	UserID     string     `json:"user_id"`               // Dueño de la sesión
+	CreatedAt  time.Time  `json:"created_at"`            // Cuándo se emitió el refresh token
+	ExpiresAt  time.Time  `json:"expires_at"`            // Hasta cuándo se puede usar
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`  // Cuándo se cerró (logout, rotación o reutilización)
+	ReplacedBy string     `json:"replaced_by,omitempty"` // Sesión que la reemplazó al rotar
+}
+
+// Active indica si el refresh token de la sesión todavía se puede usar en now
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// TokenKind distingue los dos tipos de token (un refresh token no sirve como access token)
+type TokenKind string
+
+// Tipos de token
+const (
+	TokenAccess  TokenKind = "access"
+	TokenRefresh TokenKind = "refresh"
+)
+
+// TokenClaims son los datos que viajan firmados dentro de un token
+//
+// 💡 Es la vista del dominio: el formato concreto (JWT, claims registrados,
+// header kid) lo decide la infraestructura
+type TokenClaims struct {
+	ID        string    // Identificador único del token (jti)
+	Subject   string    // ID del usuario (sub)
+	Kind      TokenKind // access o refresh
+	IssuedAt  time.Time // Emisión (iat)
+	ExpiresAt time.Time // Vencimiento (exp)
+}
+
+// TokenPair es la respuesta de login y de refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"` // Siempre "Bearer"
+	ExpiresIn    int64  `json:"expires_in"` // Segundos de vida del access token
+}
+
+// TokenPolicy define cuánto duran los tokens
+type TokenPolicy struct {
+	AccessTTL  time.Duration // Vida del access token
+	RefreshTTL time.Duration // Vida del refresh token (la sesión)
+}
+
+// DefaultTokenPolicy retorna la duración por defecto: 15 minutos y 30 días
+func DefaultTokenPolicy() TokenPolicy {
+	return TokenPolicy{
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 30 * 24 * time.Hour,
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Author es una persona (o entidad) que participa en la creación de libros
+//
+// 👥 ¿Por qué una entidad y no un texto en Book?
+// - Con texto libre, "Robert C. Martin" y "Robert Martin" serían dos personas distintas
+// - Con una entidad, todos sus libros apuntan al MISMO ID
+// - Permite preguntar "¿qué libros tiene este autor?" sin comparar textos
+type Author struct {
+	ID        string    `json:"id"`            // Identificador único del autor
+	Name      string    `json:"name"`          // Nombre tal como se muestra ("Robert C. Martin")
+	Bio       string    `json:"bio,omitempty"` // Biografía breve
+	CreatedAt time.Time `json:"created_at"`    // Fecha de alta
+}
+
+// AuthorRole es el papel de un autor en un libro concreto
+//
+// 📚 Una misma persona puede ser autora de un libro y traductora de otro
+type AuthorRole string
+
+// Roles de autoría admitidos
+const (
+	RoleAuthor     AuthorRole = "author"     // Autor/a de la obra
+	RoleEditor     AuthorRole = "editor"     // Editor/a o compilador/a
+	RoleTranslator AuthorRole = "translator" // Traductor/a
+)
+
+// Valid indica si el rol es uno de los admitidos
+func (r AuthorRole) Valid() bool {
+	switch r {
+	case RoleAuthor, RoleEditor, RoleTranslator:
+		return true
+	default:
+		return false
+	}
+}
+
+// BookAuthor vincula un libro con un autor en un rol
+//
+// 🔗 Es la relación muchos-a-muchos: un libro tiene varios autores
+// y un autor tiene varios libros. El orden del slice en Book.Authors
+// es el orden de los créditos.
+//
+// 💡 Name se completa al leer (no se guarda): si el autor cambia de nombre,
+// todos sus libros muestran el nombre nuevo
+type BookAuthor struct {
+	AuthorID string     `json:"author_id"`
+	Name     string     `json:"name,omitempty"`
+	Role     AuthorRole `json:"role"`
+}
+
+// AuthorFilter filtra autores por coincidencia parcial del nombre
+type AuthorFilter struct {
+	Name string `json:"name,omitempty"`
+}
+
+// AuthorQuery combina paginación, orden y filtros para listar autores
+type AuthorQuery struct {
+	PageRequest
+	Filter AuthorFilter
+}
@@ -13,6 +13,8 @@
 // - Definen qué datos son importantes para nuestro sistema
 package domain
 
+import "time"
+
 // Book representa la entidad principal de nuestro dominio de libros
 //
 // 📖 ¿Qué es una entidad en Clean Architecture?
@@ -21,13 +23,40 @@ package domain
 // - En este caso simple, solo contiene datos, pero podría tener métodos de validación
 //
 // 🎯 Ejemplo de método que podríamos agregar:
-// func (b *Book) IsValid() bool {
-//     return b.Title != "" && b.Author != ""
-// }
+//
+//	func (b *Book) IsValid() bool {
+//	    return b.Title != "" && b.Author != ""
+//	}
+//
+// 📚 Datos bibliográficos opcionales: el "valor cero" ("" o 0) significa desconocido
+// y se omite del JSON
+//
+// 👥 Author es el texto de créditos; Authors son los vínculos a entidades Author.
+// Si se cargan vínculos sin texto de créditos, el caso de uso lo arma con los nombres.
+//
+// 📦 AvailableCopies no se guarda: lo calcula el caso de uso a partir de los ejemplares
+// (ver copy.go). Es un puntero para distinguir "0 disponibles" de "no calculado".
+//
+// 🔢 Version empieza en 1 y el repositorio la sube con cada cambio. Al actualizar o
+// borrar, la versión que trae el libro es la que el cliente leyó: si no coincide con la
+// guardada, otro la cambió antes y la operación falla con ErrPreconditionFailed
+// (0 = sin verificar). Así dos bibliotecarios no se pisan los cambios en silencio.
 type Book struct {
-	ID     string `json:"id"`     // Identificador único del libro
-	Title  string `json:"title"`  // Título del libro
-	Author string `json:"author"` // Autor del libro
+	ID              string       `json:"id"`                         // Identificador único del libro
+	Title           string       `json:"title"`                      // Título del libro
+	Author          string       `json:"author"`                     // Créditos tal como se muestran ("Martin, R. y otros")
+	Authors         []BookAuthor `json:"authors,omitempty"`          // Autores vinculados con su rol (ver author.go)
+	ISBN            string       `json:"isbn,omitempty"`             // ISBN-13 normalizado, sin guiones (ver isbn.go)
+	Publisher       string       `json:"publisher,omitempty"`        // Editorial
+	PublicationYear int          `json:"publication_year,omitempty"` // Año de publicación
+	Language        string       `json:"language,omitempty"`         // Código de idioma ISO 639 ("es", "en", "pt-BR")
+	PageCount       int          `json:"page_count,omitempty"`       // Cantidad de páginas
+	Description     string       `json:"description,omitempty"`      // Sinopsis o resumen
+	Subjects        []string     `json:"subjects,omitempty"`         // Materias o temas ("Programación", "Arquitectura")
+	Edition         string       `json:"edition,omitempty"`          // Edición ("2da", "Revisada")
+	CreatedAt       time.Time    `json:"created_at"`                 // Fecha de alta (permite ordenar por antigüedad)
+	Version         int          `json:"version"`                    // Versión del registro (concurrencia optimista)
+	AvailableCopies *int         `json:"available_copies,omitempty"` // Ejemplares disponibles (solo al pedir UN libro)
 }
 
 // User representa la entidad de usuario en nuestro dominio
@@ -38,10 +67,17 @@ type Book struct {
 // - Cada entidad se maneja con el mismo patrón
 //
 // 🔍 Nota: Mantenemos las entidades simples y enfocadas en una sola responsabilidad
+//
+// 🔐 La contraseña NUNCA se guarda: solo su hash (bcrypt), que además no se serializa a JSON
+// 🔢 Version funciona igual que en Book
 type User struct {
-	ID    string `json:"id"`    // Identificador único del usuario
-	Name  string `json:"name"`  // Nombre del usuario
-	Email string `json:"email"` // Email del usuario
+	ID           string    `json:"id"`         // Identificador único del usuario
+	Name         string    `json:"name"`       // Nombre del usuario
+	Email        string    `json:"email"`      // Email del usuario
+	PasswordHash string    `json:"-"`          // Hash de la contraseña (vacío = no puede iniciar sesión)
+	Role         Role      `json:"role"`       // Rol (define sus permisos, ver role.go)
+	CreatedAt    time.Time `json:"created_at"` // Fecha de alta
+	Version      int       `json:"version"`    // Versión del registro (concurrencia optimista)
 }
 
 // 💡 CONSEJOS PARA PRINCIPIANTES:
@@ -54,7 +90,7 @@ type User struct {
 // 🌟 EJEMPLO DE LO QUE PODRÍAS AGREGAR:
 // - Métodos de validación: IsValidEmail(), HasRequiredFields()
 // - Comportamientos del negocio: CalculateAge(), FormatFullName()
-// - Constantes del dominio: MaxTitleLength, ValidEmailRegex
+// - Constantes del dominio: MaxTitleLength, ValidEmailRegex (ver isbn.go para un ejemplo real)
 //
 // 🚫 EJEMPLO DE LO QUE NO DEBES AGREGAR:
 // - Anotaciones de base de datos: @Table, @Column
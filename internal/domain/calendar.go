@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// DateLayout es el formato de las fechas sin hora (feriados, días de cierre)
+const DateLayout = "2006-01-02"
+
+// Calendar indica qué días la biblioteca está cerrada
+//
+// 📅 Un día cerrado no cuenta como día de atraso: si el préstamo vence el viernes y la
+// biblioteca no abre el fin de semana, devolverlo el lunes cuenta un solo día.
+//
+// 💡 El valor cero es "abierto todos los días". Los días se toman en UTC.
+type Calendar struct {
+	closedWeekdays map[time.Weekday]bool // Días de la semana que no abre (ej: domingo)
+	holidays       map[string]bool       // Feriados puntuales, como "2006-01-02"
+}
+
+// NewCalendar arma el calendario con los días de la semana que no abre y los feriados
+func NewCalendar(closedWeekdays []time.Weekday, holidays []time.Time) Calendar {
+	c := Calendar{
+		closedWeekdays: make(map[time.Weekday]bool, len(closedWeekdays)),
+		holidays:       make(map[string]bool, len(holidays)),
+	}
+	for _, day := range closedWeekdays {
+		c.closedWeekdays[day] = true
+	}
+	for _, day := range holidays {
+		c.holidays[day.UTC().Format(DateLayout)] = true
+	}
+	return c
+}
+
+// IsClosed indica si la biblioteca está cerrada el día de t
+func (c Calendar) IsClosed(t time.Time) bool {
+	t = t.UTC()
+	return c.closedWeekdays[t.Weekday()] || c.holidays[t.Format(DateLayout)]
+}
+
+// OpenDaysBetween cuenta los días abiertos después del día de from y hasta el día de to inclusive
+//
+// 📋 Ejemplo: de un lunes a un miércoles son 2 días (martes y miércoles);
+// si el martes es feriado, 1. Si to no es posterior a from, 0.
+func (c Calendar) OpenDaysBetween(from, to time.Time) int {
+	day := truncateDay(from).AddDate(0, 0, 1)
+	last := truncateDay(to)
+
+	days := 0
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !c.IsClosed(day) {
+			days++
+		}
+	}
+	return days
+}
+
+// truncateDay lleva t a las 00:00 UTC de su día
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,112 @@
+package domain
+
+import "time"
+
+// Copy es un ejemplar físico de un libro
+//
+// 📚 ¿Por qué separar Copy de Book?
+// - Book es el registro BIBLIOGRÁFICO: título, autores, ISBN (uno por obra)
+// - Copy es el objeto en el estante: tiene código de barras, estado y ubicación
+// - La biblioteca puede tener 5 ejemplares de "Clean Architecture": 1 Book, 5 Copy
+//
+// 🔁 Lo que se presta es una copia, no el libro (ver Loan.CopyID)
+type Copy struct {
+	ID        string        `json:"id"`                 // Identificador único del ejemplar
+	BookID    string        `json:"book_id"`            // Libro al que pertenece
+	Barcode   string        `json:"barcode"`            // Código de barras (único en toda la biblioteca)
+	Condition CopyCondition `json:"condition"`          // Estado físico
+	Location  string        `json:"location,omitempty"` // Dónde está ("Sala 2, estante B-3")
+	Status    CopyStatus    `json:"status"`             // Disponibilidad
+	CreatedAt time.Time     `json:"created_at"`         // Fecha de alta
+}
+
+// CopyStatus es la disponibilidad de un ejemplar
+type CopyStatus string
+
+// Estados de un ejemplar
+//
+// 🔒 on_loan solo lo ponen y quitan los préstamos (checkout/return) y on_hold la cola de reservas;
+// el resto se puede cambiar a mano (ej: se encontró un ejemplar perdido)
+const (
+	CopyAvailable CopyStatus = "available" // En el estante, se puede prestar
+	CopyOnLoan    CopyStatus = "on_loan"   // Prestado
+	CopyOnHold    CopyStatus = "on_hold"   // Apartado para una reserva lista para retirar
+	CopyLost      CopyStatus = "lost"      // Perdido
+	CopyInRepair  CopyStatus = "in_repair" // En reparación
+)
+
+// Valid indica si el estado es uno de los admitidos
+func (s CopyStatus) Valid() bool {
+	switch s {
+	case CopyAvailable, CopyOnLoan, CopyOnHold, CopyLost, CopyInRepair:
+		return true
+	default:
+		return false
+	}
+}
+
+// CopyCondition es el estado físico de un ejemplar
+type CopyCondition string
+
+// Estados físicos admitidos, de mejor a peor
+const (
+	ConditionNew     CopyCondition = "new"
+	ConditionGood    CopyCondition = "good"
+	ConditionFair    CopyCondition = "fair"
+	ConditionPoor    CopyCondition = "poor"
+	ConditionDamaged CopyCondition = "damaged"
+)
+
+// Valid indica si el estado físico es uno de los admitidos
+func (c CopyCondition) Valid() bool {
+	switch c {
+	case ConditionNew, ConditionGood, ConditionFair, ConditionPoor, ConditionDamaged:
+		return true
+	default:
+		return false
+	}
+}
+
+// CopyCounts resume cuántos ejemplares de un libro hay en cada estado
+//
+// 📋 Ejemplo: {"total": 5, "available": 1, "on_loan": 2, "on_hold": 1, "lost": 0, "in_repair": 1}
+type CopyCounts struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	OnLoan    int `json:"on_loan"`
+	OnHold    int `json:"on_hold"`
+	Lost      int `json:"lost"`
+	InRepair  int `json:"in_repair"`
+}
+
+// Add suma un ejemplar en el estado indicado
+func (c *CopyCounts) Add(status CopyStatus, n int) {
+	c.Total += n
+	switch status {
+	case CopyAvailable:
+		c.Available += n
+	case CopyOnLoan:
+		c.OnLoan += n
+	case CopyOnHold:
+		c.OnHold += n
+	case CopyLost:
+		c.Lost += n
+	case CopyInRepair:
+		c.InRepair += n
+	}
+}
+
+// Campos por los que se pueden ordenar los ejemplares
+const SortByBarcode = "barcode"
+
+// CopyFilter filtra ejemplares por libro o estado
+type CopyFilter struct {
+	BookID string     `json:"book_id,omitempty"`
+	Status CopyStatus `json:"status,omitempty"`
+}
+
+// CopyQuery combina paginación, orden y filtros para listar ejemplares
+type CopyQuery struct {
+	PageRequest
+	Filter CopyFilter
+}
@@ -0,0 +1,165 @@
+package domain
+
+import "errors"
+
+// Categorías de errores del dominio
+//
+// 🎯 ¿Por qué errores tipados?
+// - Con errors.New("libro no encontrado") el handler NO puede saber si es 404 o 400
+// - Con categorías, cada capa pregunta errors.Is(err, domain.ErrNotFound)
+// - El dominio define QUÉ pasó; la capa de delivery decide CÓMO mostrarlo (HTTP, gRPC, CLI)
+//
+// 📋 Categorías:
+// - ErrNotFound:   el recurso no existe
+// - ErrValidation: los datos de entrada no cumplen las reglas de negocio
+// - ErrConflict:   la operación choca con el estado actual (ID, email o ISBN duplicado, libro prestado)
+// - ErrUnauthorized: no se sabe quién es el cliente (sin credenciales, o inválidas o vencidas)
+// - ErrForbidden:  se sabe quién es, pero no tiene permiso para la operación
+// - ErrPreconditionFailed: el recurso cambió desde que el cliente lo leyó (otra versión)
+// - ErrInternal:   fallo técnico inesperado (base de datos caída, etc.)
+var (
+	ErrNotFound     = errors.New("recurso no encontrado")
+	ErrValidation   = errors.New("datos inválidos")
+	ErrConflict     = errors.New("conflicto con el estado actual")
+	ErrUnauthorized = errors.New("no autenticado")
+	ErrForbidden    = errors.New("sin permiso")
+	ErrInternal     = errors.New("error interno")
+
+	ErrPreconditionFailed = errors.New("la versión no coincide")
+)
+
+// Errores concretos que comparten todos los repositorios
+//
+// 💡 Son valores únicos: se pueden comparar con errors.Is(err, domain.ErrBookNotFound)
+// y además pertenecen a su categoría: errors.Is(err, domain.ErrNotFound) == true
+var (
+	ErrBookNotFound         = NewNotFoundError("libro no encontrado")
+	ErrUserNotFound         = NewNotFoundError("usuario no encontrado")
+	ErrBookAlreadyExists    = NewConflictError("el libro con este ID ya existe")
+	ErrUserAlreadyExists    = NewConflictError("el usuario con este ID ya existe")
+	ErrBookHasActiveLoans   = NewConflictError("el libro tiene préstamos activos: registra las devoluciones antes de eliminarlo")
+	ErrBookHasUnpaidFines   = NewConflictError("el libro tiene multas impagas: cóbralas o perdónalas antes de eliminarlo")
+	ErrBookHasCirculation   = NewConflictError("el libro tiene préstamos o reservas registrados: eliminarlo borraría su historial de circulación")
+	ErrBookHasCopies        = NewConflictError("el libro tiene ejemplares: dalos de baja antes de eliminarlo")
+	ErrUserHasActiveLoans   = NewConflictError("el usuario tiene préstamos activos: registra las devoluciones antes de eliminarlo")
+	ErrUserHasUnpaidFines   = NewConflictError("el usuario tiene multas impagas: cóbralas o perdónalas antes de eliminarlo")
+	ErrUserHasCirculation   = NewConflictError("el usuario tiene préstamos o reservas registrados: eliminarlo borraría su historial de circulación")
+	ErrEmailAlreadyInUse    = NewConflictError("el email ya está registrado")
+	ErrISBNAlreadyInUse     = NewConflictError("ya existe un libro con este ISBN")
+	ErrAuthorNotFound       = NewNotFoundError("autor no encontrado")
+	ErrAuthorAlreadyExists  = NewConflictError("el autor con este ID ya existe")
+	ErrAuthorHasBooks       = NewConflictError("el autor tiene libros vinculados: desvincúlalos antes de eliminarlo")
+	ErrLoanNotFound         = NewNotFoundError("préstamo no encontrado")
+	ErrLoanAlreadyExists    = NewConflictError("el préstamo con este ID ya existe")
+	ErrCopyNotFound         = NewNotFoundError("ejemplar no encontrado")
+	ErrCopyAlreadyExists    = NewConflictError("el ejemplar con este ID ya existe")
+	ErrBarcodeAlreadyInUse  = NewConflictError("ya existe un ejemplar con este código de barras")
+	ErrCopyNotAvailable     = NewConflictError("el ejemplar no está disponible para préstamo")
+	ErrNoCopiesAvailable    = NewConflictError("no hay ejemplares disponibles de este libro")
+	ErrCopyOnLoan           = NewConflictError("el ejemplar está prestado: registra la devolución antes de cambiarlo")
+	ErrCopyHasLoans         = NewConflictError("el ejemplar tiene préstamos registrados: márcalo como perdido o en reparación en vez de eliminarlo")
+	ErrCopyStatusChanged    = NewConflictError("el estado del ejemplar cambió mientras se procesaba la operación: reintenta")
+	ErrLoanLimitReached     = NewConflictError("el usuario alcanzó el máximo de préstamos activos")
+	ErrLoanAlreadyReturned  = NewConflictError("el préstamo ya fue devuelto")
+	ErrRenewalLimitReached  = NewConflictError("el préstamo alcanzó el máximo de renovaciones")
+	ErrLoanOverdue          = NewConflictError("el préstamo está vencido: hay que devolverlo, no se puede renovar")
+	ErrCopyOnHold           = NewConflictError("el ejemplar está apartado para una reserva: cancélala antes de cambiarlo")
+	ErrHoldNotFound         = NewNotFoundError("reserva no encontrada")
+	ErrHoldAlreadyExists    = NewConflictError("el usuario ya tiene una reserva activa de este libro")
+	ErrHoldNotNeeded        = NewConflictError("hay ejemplares disponibles: se puede prestar sin reservar")
+	ErrHoldNotActive        = NewConflictError("la reserva ya fue retirada, cancelada o venció")
+	ErrHoldStatusChanged    = NewConflictError("el estado de la reserva cambió mientras se procesaba la operación: reintenta")
+	ErrFineNotFound         = NewNotFoundError("multa no encontrada")
+	ErrFineAlreadyExists    = NewConflictError("el préstamo ya tiene una multa")
+	ErrFineNotOpen          = NewConflictError("la multa ya fue pagada o perdonada")
+	ErrFineOverpayment      = NewConflictError("el pago supera lo que falta pagar de la multa")
+	ErrFineDebtExceeded     = NewConflictError("el usuario tiene multas impagas por encima del límite: debe pagarlas antes de pedir otro préstamo")
+	ErrSessionNotFound      = NewNotFoundError("sesión no encontrada")
+	ErrSessionAlreadyExists = NewConflictError("la sesión con este ID ya existe")
+	ErrSessionRevoked       = NewConflictError("la sesión ya fue cerrada")
+	ErrAPIKeyNotFound       = NewNotFoundError("API key no encontrada")
+	ErrAPIKeyAlreadyExists  = NewConflictError("la API key con este ID o prefijo ya existe")
+	ErrAPIKeyRevoked        = NewConflictError("la API key ya fue revocada")
+	ErrInvalidAPIKey        = NewUnauthorizedError("la API key es inválida, fue revocada o está vencida")
+	ErrInvalidCredentials   = NewUnauthorizedError("email o contraseña incorrectos")
+	ErrInvalidToken         = NewUnauthorizedError("el token es inválido o está vencido")
+	ErrAuthRequired         = NewUnauthorizedError("se requiere autenticación: envía el header Authorization: Bearer <token> o X-API-Key")
+	ErrPermissionDenied     = NewForbiddenError("no tienes permiso para realizar esta operación")
+	ErrOwnRoleChange        = NewForbiddenError("no puedes cambiar tu propio rol")
+	ErrBookVersionMismatch  = NewPreconditionFailedError("el libro cambió desde que lo leíste: vuelve a pedirlo y aplica tus cambios sobre la versión nueva")
+	ErrUserVersionMismatch  = NewPreconditionFailedError("el usuario cambió desde que lo leíste: vuelve a pedirlo y aplica tus cambios sobre la versión nueva")
+)
+
+// Error es un error del dominio con categoría y mensaje legible
+//
+// 🔍 Campos:
+// - Kind: la categoría (ErrNotFound, ErrValidation, ErrConflict, ErrUnauthorized, ErrForbidden,
+// ErrPreconditionFailed, ErrInternal)
+// - Message: mensaje pensado para el usuario final
+// - Err: causa original opcional (ej: el error del driver de PostgreSQL)
+// - Fields: detalle por campo, solo en errores de validación (ver validation.go)
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+	Fields  []FieldError
+}
+
+// Error implementa la interfaz error
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Kind.Error()
+}
+
+// Is permite que errors.Is(err, domain.ErrNotFound) funcione con cualquier *Error de esa categoría
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap expone la causa original para errors.Is / errors.As
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFoundError crea un error de recurso inexistente
+func NewNotFoundError(message string) *Error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+// NewValidationError crea un error de validación de reglas de negocio
+func NewValidationError(message string) *Error {
+	return &Error{Kind: ErrValidation, Message: message}
+}
+
+// NewConflictError crea un error de conflicto con el estado actual
+func NewConflictError(message string) *Error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// NewUnauthorizedError crea un error de autenticación (credenciales ausentes, inválidas o vencidas)
+func NewUnauthorizedError(message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// NewForbiddenError crea un error de permisos (el usuario está autenticado pero no autorizado)
+func NewForbiddenError(message string) *Error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+// NewPreconditionFailedError crea un error de versión: el cliente editó una copia vieja del recurso
+func NewPreconditionFailedError(message string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Message: message}
+}
+
+// NewInternalError envuelve un fallo técnico inesperado
+//
+// 🚨 El mensaje es genérico a propósito: NO queremos filtrar detalles
+// de la base de datos al cliente. La causa queda en Err para los logs.
+func NewInternalError(message string, err error) *Error {
+	return &Error{Kind: ErrInternal, Message: message, Err: err}
+}
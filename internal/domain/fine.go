@@ -0,0 +1,139 @@
+package domain
+
+import "time"
+
+// Fine es la multa por devolver tarde un préstamo
+//
+// 💰 Los importes son enteros en CENTAVOS: float64 no representa bien el dinero
+// (0.1 + 0.2 != 0.3) y una suma de multas tiene que cerrar exacta.
+//
+// 📋 Ciclo de vida:
+// - Se crea la primera vez que el préstamo acumula atraso cobrable (ver FinePolicy.Assess)
+// - Mientras el libro no vuelve, el importe sigue creciendo (Final = false)
+// - Al devolverlo se calcula por última vez (Final = true)
+// - Queda paid cuando es final y se pagó todo; waived si la biblioteca la perdona
+//
+// 🔗 Hay como máximo una multa por préstamo
+type Fine struct {
+	ID          string     `json:"id"`                     // Identificador único de la multa
+	LoanID      string     `json:"loan_id"`                // Préstamo que la generó
+	UserID      string     `json:"user_id"`                // Usuario que la debe
+	BookID      string     `json:"book_id"`                // Libro del préstamo
+	Status      FineStatus `json:"status"`                 // open, paid o waived
+	OverdueDays int        `json:"overdue_days"`           // Días de atraso cobrados (sin cerrados ni gracia)
+	Amount      int64      `json:"amount_cents"`           // Importe total en centavos
+	Paid        int64      `json:"paid_cents"`             // Cuánto se pagó hasta ahora
+	Final       bool       `json:"final"`                  // El libro ya volvió: el importe no cambia más
+	WaiveReason string     `json:"waive_reason,omitempty"` // Por qué se perdonó
+	CreatedAt   time.Time  `json:"created_at"`             // Primera vez que se calculó
+	UpdatedAt   time.Time  `json:"updated_at"`             // Último cálculo, pago o perdón
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`    // Cuándo quedó pagada o perdonada
+}
+
+// Balance es lo que falta pagar (cero si está cerrada)
+func (f *Fine) Balance() int64 {
+	if f.Status != FineOpen {
+		return 0
+	}
+	return f.Amount - f.Paid
+}
+
+// FineStatus es el estado de una multa
+type FineStatus string
+
+// Estados de una multa
+const (
+	FineOpen   FineStatus = "open"   // Debe algo o todavía puede crecer
+	FinePaid   FineStatus = "paid"   // Pagada por completo
+	FineWaived FineStatus = "waived" // Perdonada por la biblioteca
+)
+
+// Valid indica si el estado es uno de los admitidos
+func (s FineStatus) Valid() bool {
+	switch s {
+	case FineOpen, FinePaid, FineWaived:
+		return true
+	default:
+		return false
+	}
+}
+
+// FinePolicy son las reglas de multas de la biblioteca
+//
+// 📋 Reglas:
+// - DailyRate: centavos por día de atraso
+// - GraceDays: primeros días de atraso que no se cobran
+// - MaxPerItem: tope de la multa de un préstamo (0 = sin tope)
+// - DebtThreshold: con una deuda MAYOR a este importe no se prestan más libros
+// - Calendar: los días que la biblioteca está cerrada no cuentan como atraso
+type FinePolicy struct {
+	DailyRate     int64
+	GraceDays     int
+	MaxPerItem    int64
+	DebtThreshold int64
+	Calendar      Calendar
+}
+
+// DefaultFinePolicy son las reglas por defecto: 25 centavos por día, 1 día de gracia,
+// tope de 10.00 por préstamo, bloqueo con más de 5.00 de deuda y cerrado los domingos
+func DefaultFinePolicy() FinePolicy {
+	return FinePolicy{
+		DailyRate:     25,
+		GraceDays:     1,
+		MaxPerItem:    1000,
+		DebtThreshold: 500,
+		Calendar:      NewCalendar([]time.Weekday{time.Sunday}, nil),
+	}
+}
+
+// Assess calcula la multa de un préstamo que vencía en dueAt y se devolvió (o se evalúa) en until
+//
+// 🧮 Cálculo (función pura: no lee el reloj ni guarda nada):
+// 1. Días de atraso = días abiertos después del vencimiento hasta until inclusive
+// 2. Se descuentan los GraceDays (si no los supera, no hay multa)
+// 3. Importe = días cobrables × DailyRate, con tope MaxPerItem
+//
+// 📋 Ejemplo: vence el lunes, 25 centavos, 1 día de gracia, se devuelve el jueves → 3 días
+// de atraso, 2 cobrables, 50 centavos
+func (p FinePolicy) Assess(dueAt, until time.Time) (days int, amount int64) {
+	days = p.Calendar.OpenDaysBetween(dueAt, until) - p.GraceDays
+	if days <= 0 {
+		return 0, 0
+	}
+
+	amount = int64(days) * p.DailyRate
+	if p.MaxPerItem > 0 && amount > p.MaxPerItem {
+		amount = p.MaxPerItem
+	}
+	return days, amount
+}
+
+// Blocks indica si una deuda impide pedir libros prestados
+func (p FinePolicy) Blocks(debt int64) bool {
+	return debt > p.DebtThreshold
+}
+
+// SortByAmount ordena las multas por importe
+const SortByAmount = "amount"
+
+// FineFilter filtra multas por usuario, libro, préstamo o estado
+type FineFilter struct {
+	UserID string     `json:"user_id,omitempty"`
+	BookID string     `json:"book_id,omitempty"`
+	LoanID string     `json:"loan_id,omitempty"`
+	Status FineStatus `json:"status,omitempty"`
+}
+
+// FineQuery combina paginación, orden y filtros para listar multas
+type FineQuery struct {
+	PageRequest
+	Filter FineFilter
+}
+
+// UserBalance es el resumen de la deuda de un usuario
+type UserBalance struct {
+	UserID        string `json:"user_id"`
+	Balance       int64  `json:"balance_cents"`        // Suma de lo que falta pagar de las multas abiertas
+	DebtThreshold int64  `json:"debt_threshold_cents"` // Límite a partir del cual no se presta
+	Blocked       bool   `json:"blocked"`              // Si hoy se le negaría un préstamo
+}
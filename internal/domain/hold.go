@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Hold es la reserva de un libro por parte de un usuario
+//
+// 📋 Cuando todos los ejemplares están prestados, el usuario se anota en la cola del libro:
+// - waiting: esperando turno (Position indica su lugar en la cola, empezando en 1)
+// - ready: se devolvió un ejemplar y quedó apartado para él hasta ExpiresAt
+// - fulfilled: retiró el ejemplar (se convirtió en préstamo)
+// - cancelled / expired: la canceló o no la retiró a tiempo
+//
+// 🔁 La cola es FIFO por libro: el primero que reservó es el primero en recibir un ejemplar
+type Hold struct {
+	ID        string     `json:"id"`                   // Identificador único de la reserva
+	BookID    string     `json:"book_id"`              // Libro reservado
+	UserID    string     `json:"user_id"`              // Usuario que reservó
+	Status    HoldStatus `json:"status"`               // Estado de la reserva
+	Position  int        `json:"position,omitempty"`   // Lugar en la cola (solo waiting; no se guarda)
+	CopyID    string     `json:"copy_id,omitempty"`    // Ejemplar apartado (desde que pasa a ready)
+	CreatedAt time.Time  `json:"created_at"`           // Cuándo se anotó (define el orden de la cola)
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`   // Cuándo quedó lista para retirar
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Hasta cuándo se guarda el ejemplar
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`  // Cuándo terminó (retirada, cancelada o vencida)
+}
+
+// HoldStatus es el estado de una reserva
+type HoldStatus string
+
+// Estados de una reserva
+const (
+	HoldWaiting   HoldStatus = "waiting"   // En la cola
+	HoldReady     HoldStatus = "ready"     // Ejemplar apartado, listo para retirar
+	HoldFulfilled HoldStatus = "fulfilled" // Retirada: se convirtió en préstamo
+	HoldCancelled HoldStatus = "cancelled" // Cancelada por el usuario o la biblioteca
+	HoldExpired   HoldStatus = "expired"   // No se retiró a tiempo
+)
+
+// Valid indica si el estado es uno de los admitidos
+func (s HoldStatus) Valid() bool {
+	switch s {
+	case HoldWaiting, HoldReady, HoldFulfilled, HoldCancelled, HoldExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// Active indica si la reserva sigue vigente (en la cola o esperando retiro)
+func (s HoldStatus) Active() bool {
+	return s == HoldWaiting || s == HoldReady
+}
+
+// HoldFilter filtra reservas por libro, usuario, estado o vencimiento del retiro
+type HoldFilter struct {
+	BookID        string     `json:"book_id,omitempty"`
+	UserID        string     `json:"user_id,omitempty"`
+	Status        HoldStatus `json:"status,omitempty"`
+	ExpiresBefore time.Time  `json:"expires_before,omitempty"` // Solo reservas cuyo retiro vence antes (cero = sin filtro)
+}
+
+// HoldQuery combina paginación, orden y filtros para listar reservas
+// 📄 Se ordenan por created_at: en ascendente es el orden de la cola
+type HoldQuery struct {
+	PageRequest
+	Filter HoldFilter
+}
@@ -0,0 +1,38 @@
+package domain
+
+// ImportReport es el resultado de una importación masiva de libros
+//
+// 📋 Ejemplo:
+//
+//	{
+//	  "dry_run": false, "upsert": true,
+//	  "total": 3, "created": 1, "updated": 1, "rejected": 1,
+//	  "rejections": [
+//	    {"line": 4, "reason": "el ISBN no es un ISBN-10 o ISBN-13 válido",
+//	     "errors": [{"field": "isbn", "code": "invalid_format", "message": "..."}],
+//	     "record": "Sin ISBN,Anónimo,123"}
+//	  ]
+//	}
+//
+// 💡 Con dry_run, created y updated cuentan lo que SE HARÍA: no se guardó nada
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Upsert     bool              `json:"upsert"`
+	Total      int               `json:"total"`    // Filas leídas (sin contar el encabezado)
+	Created    int               `json:"created"`  // Libros nuevos
+	Updated    int               `json:"updated"`  // Libros existentes actualizados por ISBN (solo con upsert)
+	Rejected   int               `json:"rejected"` // Filas rechazadas
+	Rejections []ImportRejection `json:"rejections"`
+
+	// RejectionsTruncated indica que hubo más rechazos de los que se detallan
+	// (Rejected los cuenta todos; Rejections guarda solo los primeros)
+	RejectionsTruncated bool `json:"rejections_truncated,omitempty"`
+}
+
+// ImportRejection es una fila rechazada y el motivo
+type ImportRejection struct {
+	Line   int          `json:"line"`             // Línea del archivo (la 1 es el encabezado del CSV)
+	Reason string       `json:"reason"`           // Mensaje del error
+	Errors []FieldError `json:"errors,omitempty"` // Detalle por campo (errores de validación)
+	Record string       `json:"record"`           // La fila tal como venía, para corregirla y reintentar
+}
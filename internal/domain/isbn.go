@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidISBN indica que un texto no es un ISBN-10 ni un ISBN-13 válido
+var ErrInvalidISBN = errors.New("ISBN inválido")
+
+// NormalizeISBN valida un ISBN-10 o ISBN-13 y lo devuelve como ISBN-13 sin separadores
+//
+// 🔢 ¿Por qué normalizar a ISBN-13?
+// - El mismo libro puede escribirse "0-13-235088-2", "0132350882" o "978-0-13-235088-4"
+// - Guardando siempre la forma canónica funcionan la unicidad y las búsquedas por ISBN
+//
+// ✅ Acepta guiones y espacios, y la "x" minúscula como dígito de control del ISBN-10
+// ❌ Rechaza longitudes incorrectas, caracteres extraños y dígitos de control que no cuadran
+//
+// 📋 Ejemplo: NormalizeISBN("0-13-235088-2") → "9780132350884"
+func NormalizeISBN(raw string) (string, error) {
+	isbn := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(raw)))
+
+	switch len(isbn) {
+	case 10:
+		if !validISBN10(isbn) {
+			return "", ErrInvalidISBN
+		}
+		return ISBN10To13(isbn), nil
+	case 13:
+		if !validISBN13(isbn) {
+			return "", ErrInvalidISBN
+		}
+		return isbn, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// ISBN10To13 convierte un ISBN-10 (ya validado) a ISBN-13 con el prefijo 978
+func ISBN10To13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + string(rune('0'+isbn13Check(body)))
+}
+
+// ISBN13To10 convierte un ISBN-13 con prefijo 978 a ISBN-10
+// Retorna "" si no tiene equivalente (los prefijos 979 no existen en ISBN-10)
+func ISBN13To10(isbn13 string) string {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return ""
+	}
+	body := isbn13[3:12]
+	sum := 0
+	for i, r := range body {
+		sum += int(r-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X"
+	}
+	return body + string(rune('0'+check))
+}
+
+// validISBN10 verifica el dígito de control: Σ dígito × peso (10..1) debe ser múltiplo de 11
+// El último carácter puede ser X (vale 10)
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// validISBN13 verifica que sean 13 dígitos y que el último sea el dígito de control
+func validISBN13(isbn string) bool {
+	for _, r := range isbn {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return int(isbn[12]-'0') == isbn13Check(isbn[:12])
+}
+
+// isbn13Check calcula el dígito de control de los 12 primeros dígitos
+// (pesos alternados 1 y 3, igual que un código de barras EAN-13)
+func isbn13Check(body string) int {
+	sum := 0
+	for i, r := range body[:12] {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+	return (10 - sum%10) % 10
+}
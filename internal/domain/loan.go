@@ -0,0 +1,91 @@
+package domain
+
+import "time"
+
+// Loan es el préstamo de un libro a un usuario
+//
+// 🔗 Es la entidad que conecta User y Book:
+// - Se crea al prestar (checkout) con una fecha de vencimiento
+// - Se puede renovar un número limitado de veces (corre el vencimiento)
+// - Termina al devolver (ReturnedAt deja de ser nil)
+//
+// 💡 Un préstamo devuelto NO se borra: queda como historial del usuario
+// 📚 Se presta un EJEMPLAR (CopyID); BookID se guarda para consultar el historial por libro
+type Loan struct {
+	ID           string     `json:"id"`                    // Identificador único del préstamo
+	BookID       string     `json:"book_id"`               // Libro prestado
+	CopyID       string     `json:"copy_id"`               // Ejemplar concreto que se llevó el usuario
+	UserID       string     `json:"user_id"`               // Usuario que lo tiene
+	Status       LoanStatus `json:"status"`                // active o returned
+	CheckedOutAt time.Time  `json:"checked_out_at"`        // Cuándo se prestó
+	DueAt        time.Time  `json:"due_at"`                // Cuándo vence
+	ReturnedAt   *time.Time `json:"returned_at,omitempty"` // Cuándo se devolvió (nil = todavía prestado)
+	Renewals     int        `json:"renewals"`              // Cuántas veces se renovó
+}
+
+// LoanStatus es el estado de un préstamo
+type LoanStatus string
+
+// Estados de un préstamo
+//
+// ⏰ "Vencido" no es un estado guardado: es un préstamo activo con DueAt en el pasado
+// (ver IsOverdue). LoanOverdue solo existe para pedirlo como filtro en los listados.
+const (
+	LoanActive   LoanStatus = "active"   // El usuario todavía tiene el libro
+	LoanReturned LoanStatus = "returned" // El libro ya volvió a la biblioteca
+	LoanOverdue  LoanStatus = "overdue"  // Solo filtro: activo y con el vencimiento pasado
+)
+
+// IsOverdue indica si el préstamo sigue activo después de su vencimiento
+func (l *Loan) IsOverdue(now time.Time) bool {
+	return l.Status == LoanActive && now.After(l.DueAt)
+}
+
+// LoanPolicy son las reglas de préstamo de la biblioteca
+//
+// 📋 Reglas:
+// - Period: duración de un préstamo (y de cada renovación)
+// - MaxActiveLoans: cuántos libros puede tener un usuario a la vez
+// - MaxRenewals: cuántas veces se puede renovar un mismo préstamo
+// - HoldPickupPeriod: cuánto se guarda un ejemplar apartado para una reserva
+// - Fines: cuánto se cobra por devolver tarde (ver FinePolicy)
+type LoanPolicy struct {
+	Period           time.Duration
+	MaxActiveLoans   int
+	MaxRenewals      int
+	HoldPickupPeriod time.Duration
+	Fines            FinePolicy
+}
+
+// DefaultLoanPolicy son las reglas por defecto: 14 días, 5 libros, 2 renovaciones, 3 días para retirar
+// y las multas de DefaultFinePolicy
+func DefaultLoanPolicy() LoanPolicy {
+	return LoanPolicy{
+		Period:           14 * 24 * time.Hour,
+		MaxActiveLoans:   5,
+		MaxRenewals:      2,
+		HoldPickupPeriod: 3 * 24 * time.Hour,
+		Fines:            DefaultFinePolicy(),
+	}
+}
+
+// Campos por los que se pueden ordenar los préstamos
+const (
+	SortByCheckedOutAt = "checked_out_at"
+	SortByDueAt        = "due_at"
+)
+
+// LoanFilter filtra préstamos por usuario, libro, ejemplar, estado o vencimiento
+type LoanFilter struct {
+	UserID    string     `json:"user_id,omitempty"`
+	BookID    string     `json:"book_id,omitempty"`
+	CopyID    string     `json:"copy_id,omitempty"`
+	Status    LoanStatus `json:"status,omitempty"`
+	DueBefore time.Time  `json:"due_before,omitempty"` // Solo préstamos que vencen antes (cero = sin filtro)
+}
+
+// LoanQuery combina paginación, orden y filtros para listar préstamos
+type LoanQuery struct {
+	PageRequest
+	Filter LoanFilter
+}
@@ -0,0 +1,82 @@
+package domain
+
+// Campos por los que se pueden ordenar los listados
+const (
+	SortByTitle     = "title"
+	SortByAuthor    = "author"
+	SortByName      = "name"
+	SortByEmail     = "email"
+	SortByCreatedAt = "created_at"
+)
+
+// PageRequest describe QUÉ porción de un listado se quiere obtener
+//
+// 📄 Dos formas de paginar:
+// - Limit/Offset: simple y explícito (?limit=20&offset=40)
+// - Cursor: un token opaco que devuelve la página anterior (?cursor=...)
+//
+// 💡 El cursor lo interpreta el caso de uso; los repositorios solo ven Limit/Offset
+type PageRequest struct {
+	Limit  int    // Cantidad máxima de elementos
+	Offset int    // Cuántos elementos saltar
+	Cursor string // Token opaco (si viene, tiene prioridad sobre Offset/Sort/Filter)
+	Sort   string // Campo de ordenamiento (ver constantes SortBy*)
+	Desc   bool   // true = descendente
+}
+
+// BookFilter filtra libros por coincidencia parcial (sin distinguir mayúsculas),
+// por autor vinculado o por idioma
+type BookFilter struct {
+	Author   string `json:"author,omitempty"`    // Subcadena del autor
+	Title    string `json:"title,omitempty"`     // Subcadena del título
+	AuthorID string `json:"author_id,omitempty"` // Solo libros vinculados a este autor (cualquier rol)
+	Language string `json:"language,omitempty"`  // Idioma exacto o su idioma base ("pt" incluye "pt-BR")
+}
+
+// FacetCount es un valor de una faceta y cuántos libros lo tienen
+type FacetCount struct {
+	Value string // Lo que se filtra (el ID del autor, el código de idioma)
+	Label string // Lo que se muestra (el nombre del autor; vacío = Value)
+	Count int    // Libros con ese valor que cumplen el resto de los filtros
+}
+
+// BookFacets son las facetas de un listado de libros: "por autor" y "por idioma"
+//
+// 🧭 Permiten navegar un catálogo sin saber qué buscar: "español (120) · inglés (45)"
+// 💡 Los idiomas se agrupan por su idioma base ("pt-BR" cuenta como "pt"); los autores
+// son los vinculados (ver BookAuthor), de mayor a menor cantidad de libros
+type BookFacets struct {
+	Authors   []FacetCount
+	Languages []FacetCount
+}
+
+// BookQuery combina paginación, orden y filtros para listar libros
+type BookQuery struct {
+	PageRequest
+	Filter BookFilter
+}
+
+// UserFilter filtra usuarios por coincidencia parcial (sin distinguir mayúsculas)
+type UserFilter struct {
+	Name  string `json:"name,omitempty"`  // Subcadena del nombre
+	Email string `json:"email,omitempty"` // Subcadena del email
+}
+
+// UserQuery combina paginación, orden y filtros para listar usuarios
+type UserQuery struct {
+	PageRequest
+	Filter UserFilter
+}
+
+// Page es el resultado paginado de un listado
+//
+// 📋 Además de los elementos, informa el total (para "página 3 de 10")
+// y los cursores para moverse a la página siguiente/anterior
+type Page[T any] struct {
+	Items      []T    // Elementos de esta página
+	Total      int    // Total de elementos que cumplen los filtros
+	Limit      int    // Límite aplicado
+	Offset     int    // Offset aplicado
+	NextCursor string // Vacío si no hay página siguiente
+	PrevCursor string // Vacío si no hay página anterior
+}
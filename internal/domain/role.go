@@ -0,0 +1,57 @@
+package domain
+
+// Role es el rol de un usuario en la biblioteca
+//
+// 👥 Roles:
+// - member: socio; consulta el catálogo y gestiona SOLO lo suyo (perfil, reservas, renovaciones)
+// - librarian: bibliotecario; además mantiene el catálogo y atiende el mostrador (préstamos, multas)
+// - admin: todo lo anterior y además administra usuarios (editar, borrar, cambiar roles)
+type Role string
+
+// Roles disponibles
+const (
+	RoleAdmin     Role = "admin"
+	RoleLibrarian Role = "librarian"
+	RoleMember    Role = "member"
+)
+
+// Valid indica si el rol es uno de los admitidos
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permission es una acción que un rol puede o no realizar
+//
+// 💡 Los casos de uso piden permisos, no roles: "¿puede escribir el catálogo?" en lugar de
+// "¿es bibliotecario?". Así, cambiar quién puede hacer qué se hace en UN lugar (rolePermissions)
+type Permission string
+
+// Permisos de la aplicación
+const (
+	PermBooksRead   Permission = "books:read"         // Consultar el catálogo (libros, autores, ejemplares)
+	PermBooksWrite  Permission = "books:write"        // Altas, cambios y bajas del catálogo
+	PermUsersRead   Permission = "users:read"         // Ver a cualquier usuario (no solo a uno mismo)
+	PermUsersAdmin  Permission = "users:admin"        // Editar, borrar y cambiar el rol de cualquier usuario
+	PermCirculation Permission = "circulation:manage" // Préstamos, reservas y multas de cualquier usuario
+)
+
+// rolePermissions es la política: qué permisos tiene cada rol
+//
+// 📋 Lo que un usuario hace sobre SÍ MISMO (ver y editar su perfil, sus préstamos,
+// reservas y multas) no necesita permiso: lo resuelven los casos de uso comparando IDs
+var rolePermissions = map[Role][]Permission{
+	RoleMember:    {PermBooksRead},
+	RoleLibrarian: {PermBooksRead, PermBooksWrite, PermUsersRead, PermCirculation},
+	RoleAdmin:     {PermBooksRead, PermBooksWrite, PermUsersRead, PermUsersAdmin, PermCirculation},
+}
+
+// Can indica si el rol tiene el permiso
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
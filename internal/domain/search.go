@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SortByRelevance ordena los resultados de búsqueda por puntuación (el más relevante primero)
+const SortByRelevance = "relevance"
+
+// BookSearchQuery es una búsqueda de texto libre sobre el catálogo
+//
+// 🔎 Text se divide en palabras (ver SearchTerms) y un libro coincide
+// si contiene TODAS las palabras (o palabras que empiezan por ellas)
+// en su título, autor, descripción o materias: "martin arquitectura" → Martin + Arquitectura
+type BookSearchQuery struct {
+	PageRequest
+	Text string
+}
+
+// BookHit es un libro encontrado junto con su puntuación de relevancia
+//
+// 💡 Book va embebido: en JSON los campos del libro quedan al mismo nivel que "score"
+type BookHit struct {
+	*Book
+	Score float64 `json:"score"` // Mayor = más relevante (la escala depende del almacenamiento)
+}
+
+// SearchTerms normaliza un texto de búsqueda en palabras comparables
+//
+// 🔤 Normalización:
+// - Minúsculas: "Martin" → "martin"
+// - Sin acentos: "Martín" → "martin", "Ñandú" → "nandu"
+// - Separa por todo lo que no sea letra o dígito: "Clean-Code, 2ª ed." → clean, code, 2a, ed
+// - Sin repetidos, en el orden en que aparecen
+//
+// 🎯 Los repositorios usan ESTA función tanto al indexar como al buscar,
+// así "arquitectura" encuentra "Arquitectura" y "martin" encuentra "Martín"
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(FoldAccents(strings.ToLower(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := words[:0]
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// FoldAccents reemplaza las letras acentuadas del alfabeto latino por su letra base
+func FoldAccents(s string) string {
+	return accentFolder.Replace(s)
+}
+
+// accentFolder cubre las letras latinas más comunes (español, portugués, francés, alemán...)
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "ª", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "º", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c", "ý", "y", "ÿ", "y", "ß", "ss", "æ", "ae", "œ", "oe",
+	"Á", "a", "À", "a", "Â", "a", "Ä", "a", "Ã", "a", "Å", "a",
+	"É", "e", "È", "e", "Ê", "e", "Ë", "e",
+	"Í", "i", "Ì", "i", "Î", "i", "Ï", "i",
+	"Ó", "o", "Ò", "o", "Ô", "o", "Ö", "o", "Õ", "o", "Ø", "o",
+	"Ú", "u", "Ù", "u", "Û", "u", "Ü", "u",
+	"Ñ", "n", "Ç", "c", "Ý", "y",
+)
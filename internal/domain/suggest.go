@@ -0,0 +1,28 @@
+package domain
+
+// Campos de los que salen las sugerencias de autocompletado
+const (
+	SuggestFieldTitle  = "title"
+	SuggestFieldAuthor = "author"
+)
+
+// SuggestQuery pide sugerencias de autocompletado para lo que el usuario lleva escrito
+type SuggestQuery struct {
+	Prefix string // Texto tecleado hasta ahora ("rob mar", "martni")
+	Limit  int    // Máximo de sugerencias
+}
+
+// Suggestion es una sugerencia de autocompletado (un título o un autor del catálogo)
+//
+// 📋 Ejemplo: {"text": "Robert C. Martin", "field": "author", "score": 0.8, "books": 3}
+//
+// 🎯 Score (0-1) permite ordenar:
+// - 1.0: el texto empieza por lo escrito ("clean a" → "Clean Architecture")
+// - 0.8: cada palabra escrita empieza una palabra del texto ("mar" → "Robert C. Martin")
+// - <0.6: coincidencia aproximada por trigramas, tolera errores ("martni" → "Martin")
+type Suggestion struct {
+	Text  string  `json:"text"`  // Título o autor tal como está guardado
+	Field string  `json:"field"` // SuggestFieldTitle o SuggestFieldAuthor
+	Score float64 `json:"score"` // Relevancia (mayor = mejor)
+	Books int     `json:"books"` // Cantidad de libros con ese título/autor
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"go-book-clean-architecture-api/internal/domain"
+	"testing"
+	"time"
+)
+
+// TestFinePolicy_Assess prueba días de atraso, gracia, días cerrados y tope
+//
+// 📅 Calendario de marzo de 2026: el lunes 2 vence el préstamo, el miércoles 4 es feriado
+// y los domingos la biblioteca cierra
+func TestFinePolicy_Assess(t *testing.T) {
+	holiday := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	policy := domain.FinePolicy{
+		DailyRate:  25,
+		GraceDays:  1,
+		MaxPerItem: 300,
+		Calendar:   domain.NewCalendar([]time.Weekday{time.Sunday}, []time.Time{holiday}),
+	}
+	due := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		returnedAt time.Time
+		wantDays   int
+		wantAmount int64
+	}{
+		{"antes del vencimiento", due.Add(-time.Hour), 0, 0},
+		{"el mismo día, más tarde", due.Add(8 * time.Hour), 0, 0},
+		{"un día de atraso: lo cubre la gracia", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), 0, 0},
+		{"el feriado no cuenta", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), 1, 25},
+		{"el domingo tampoco", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), 4, 100},
+		{"con tope por préstamo", time.Date(2026, 4, 30, 9, 0, 0, 0, time.UTC), 49, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, amount := policy.Assess(due, tt.returnedAt)
+
+			if days != tt.wantDays || amount != tt.wantAmount {
+				t.Errorf("Se esperaban %d días y %d centavos, pero se obtuvo: %d días y %d centavos",
+					tt.wantDays, tt.wantAmount, days, amount)
+			}
+		})
+	}
+}
+
+// TestFinePolicy_NoCapNoGrace prueba una política sin tope ni gracia y abierta todos los días
+func TestFinePolicy_NoCapNoGrace(t *testing.T) {
+	policy := domain.FinePolicy{DailyRate: 10}
+	due := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	days, amount := policy.Assess(due, due.AddDate(0, 0, 100))
+
+	if days != 100 || amount != 1000 {
+		t.Errorf("Se esperaban 100 días y 1000 centavos, pero se obtuvo: %d días y %d centavos", days, amount)
+	}
+}
+
+// TestFinePolicy_Blocks prueba que solo una deuda MAYOR al límite bloquea
+func TestFinePolicy_Blocks(t *testing.T) {
+	policy := domain.FinePolicy{DebtThreshold: 500}
+
+	if policy.Blocks(500) {
+		t.Error("Se esperaba que una deuda igual al límite no bloqueara")
+	}
+	if !policy.Blocks(501) {
+		t.Error("Se esperaba que una deuda mayor al límite bloqueara")
+	}
+}
@@ -0,0 +1,60 @@
+// Package test contiene los tests de las reglas puras del dominio
+//
+// 🧪 El dominio no depende de nada: estos tests no necesitan mocks ni repositorios
+package test
+
+import (
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"testing"
+)
+
+// TestNormalizeISBN prueba la validación y normalización de ISBN-10 e ISBN-13
+//
+// 📋 Table-driven test: un caso por fila
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // "" = se espera ErrInvalidISBN
+	}{
+		{"ISBN-13 con guiones", "978-0-13-235088-4", "9780132350884"},
+		{"ISBN-13 con espacios", " 978 0 13 235088 4 ", "9780132350884"},
+		{"ISBN-10 se convierte a ISBN-13", "0-13-235088-2", "9780132350884"},
+		{"ISBN-10 con X como control", "0-8044-2957-X", "9780804429573"},
+		{"ISBN-10 con x minúscula", "080442957x", "9780804429573"},
+		{"ISBN-13 con prefijo 979", "979-10-90636-07-1", "9791090636071"},
+		{"control de ISBN-13 incorrecto", "978-0-13-235088-5", ""},
+		{"control de ISBN-10 incorrecto", "0-13-235088-3", ""},
+		{"X fuera de la última posición", "X132350882", ""},
+		{"longitud incorrecta", "978013235088", ""},
+		{"letras", "97801323508AB", ""},
+		{"vacío", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.NormalizeISBN(tt.input)
+
+			if tt.want == "" {
+				if !errors.Is(err, domain.ErrInvalidISBN) {
+					t.Errorf("Se esperaba ErrInvalidISBN para %q, pero se obtuvo: %q, %v", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Se esperaba %q para %q, pero se obtuvo: %q, %v", tt.want, tt.input, got, err)
+			}
+		})
+	}
+}
+
+// TestISBN13To10 prueba la conversión inversa (solo posible con prefijo 978)
+func TestISBN13To10(t *testing.T) {
+	if got := domain.ISBN13To10("9780804429573"); got != "080442957X" {
+		t.Errorf("Se esperaba 080442957X, pero se obtuvo: %q", got)
+	}
+	if got := domain.ISBN13To10("9791090636071"); got != "" {
+		t.Errorf("Un ISBN 979 no tiene ISBN-10, pero se obtuvo: %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Códigos estables de errores de campo
+//
+// 💡 Los mensajes están en español y pueden cambiar; los códigos NO.
+// El frontend debe usar el código (y el nombre del campo) para decidir qué mostrar.
+const (
+	CodeRequired      = "required"       // El campo es obligatorio
+	CodeInvalidFormat = "invalid_format" // El formato no es válido (email, ISBN, etc.)
+	CodeOutOfRange    = "out_of_range"   // El valor está fuera del rango permitido
+	CodeTooLong       = "too_long"       // El texto supera la longitud máxima
+	CodeUnknownRef    = "unknown_ref"    // Hace referencia a algo que no existe (ej: un autor)
+)
+
+// FieldError describe por qué un campo concreto es inválido
+//
+// 📋 Ejemplo: {"field": "title", "code": "required", "message": "el título del libro es obligatorio"}
+type FieldError struct {
+	Field   string `json:"field"`   // Nombre del campo tal como lo envía el cliente (JSON)
+	Code    string `json:"code"`    // Código estable (ver constantes Code*)
+	Message string `json:"message"` // Mensaje legible
+}
+
+// Validator acumula errores de campo para reportarlos TODOS de una vez
+//
+// 🎯 ¿Por qué acumular en lugar de cortar en el primer error?
+// - El usuario corrige todo el formulario en un solo intento
+// - El frontend puede resaltar cada campo inválido
+//
+// 🔧 Uso típico en un caso de uso:
+//
+//	var v domain.Validator
+//	v.Required("title", title, "el título del libro es obligatorio")
+//	v.Required("author", author, "el autor del libro es obligatorio")
+//	if err := v.Err(); err != nil {
+//	    return nil, err
+//	}
+type Validator struct {
+	fields []FieldError
+}
+
+// Add registra un error en un campo
+func (v *Validator) Add(field, code, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required registra un error si value está vacío (o solo tiene espacios)
+func (v *Validator) Required(field, value, message string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, message)
+	}
+}
+
+// MaxLength registra un error si value supera max caracteres (runas, no bytes: "ñ" cuenta 1)
+func (v *Validator) MaxLength(field, value string, max int, message string) {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, CodeTooLong, message)
+	}
+}
+
+// Check registra un error si la condición NO se cumple
+func (v *Validator) Check(ok bool, field, code, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Err retorna nil si no hubo errores, o un error de validación con todos los campos
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return NewFieldsError(v.fields...)
+}
+
+// NewFieldsError crea un error de validación con detalle por campo
+//
+// 📝 El mensaje del error es el del primer campo (si hay uno solo)
+// o un resumen si hay varios; el detalle completo está en Fields
+func NewFieldsError(fields ...FieldError) *Error {
+	message := "los datos enviados no son válidos"
+	if len(fields) == 1 {
+		message = fields[0].Message
+	}
+	return &Error{Kind: ErrValidation, Message: message, Fields: fields}
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+	"time"
+)
+
+// InMemoryAPIKeyRepository es una implementación en memoria del APIKeyRepository
+type InMemoryAPIKeyRepository struct {
+	keys  map[string]*domain.APIKey // Almacenamiento en memoria usando un map
+	mutex sync.RWMutex              // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryAPIKeyRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryAPIKeyRepository() repository.APIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys:  make(map[string]*domain.APIKey),
+		mutex: sync.RWMutex{},
+	}
+}
+
+// Create guarda una API key verificando que el ID y el prefijo sean únicos
+func (r *InMemoryAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return nil, domain.ErrAPIKeyAlreadyExists
+	}
+	for _, existing := range r.keys {
+		if existing.Prefix == key.Prefix {
+			return nil, domain.ErrAPIKeyAlreadyExists
+		}
+	}
+
+	r.keys[key.ID] = key
+	return key, nil
+}
+
+// GetByID busca una API key por su ID
+func (r *InMemoryAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// GetByPrefix busca una API key por su prefijo
+func (r *InMemoryAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+// List retorna una página de API keys filtrada y ordenada por fecha de creación
+func (r *InMemoryAPIKeyRepository) List(ctx context.Context, q domain.APIKeyQuery) ([]*domain.APIKey, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	matches := make([]*domain.APIKey, 0)
+	for _, key := range r.keys {
+		if q.Filter.UserID == "" || key.UserID == q.Filter.UserID {
+			matches = append(matches, key)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}, func(k *domain.APIKey) string { return k.ID })
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Revoke revoca la clave solo si seguía activa
+//
+// 💡 Guarda una COPIA: quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemoryAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) (*domain.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.keys[id]
+	if !exists {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if existing.RevokedAt != nil {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	updated := *existing
+	updated.RevokedAt = &at
+	r.keys[id] = &updated
+	return &updated, nil
+}
+
+// Touch anota el último uso de la clave
+func (r *InMemoryAPIKeyRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.keys[id]
+	if !exists {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	updated := *existing
+	updated.LastUsedAt = &at
+	r.keys[id] = &updated
+	return nil
+}
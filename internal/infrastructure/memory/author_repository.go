@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+)
+
+// InMemoryAuthorRepository es una implementación en memoria del AuthorRepository
+type InMemoryAuthorRepository struct {
+	authors map[string]*domain.Author // Almacenamiento en memoria usando un map
+	mutex   sync.RWMutex              // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryAuthorRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryAuthorRepository() repository.AuthorRepository {
+	return &InMemoryAuthorRepository{
+		authors: make(map[string]*domain.Author),
+		mutex:   sync.RWMutex{},
+	}
+}
+
+// Create almacena un nuevo autor en memoria
+func (r *InMemoryAuthorRepository) Create(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.authors[author.ID]; exists {
+		return nil, domain.ErrAuthorAlreadyExists
+	}
+
+	r.authors[author.ID] = author
+	return author, nil
+}
+
+// GetByID busca un autor por su ID
+func (r *InMemoryAuthorRepository) GetByID(ctx context.Context, id string) (*domain.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	author, exists := r.authors[id]
+	if !exists {
+		return nil, domain.ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+// GetByIDs busca varios autores de una vez (los inexistentes se omiten)
+func (r *InMemoryAuthorRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	authors := make([]*domain.Author, 0, len(ids))
+	for _, id := range ids {
+		if author, exists := r.authors[id]; exists {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+// List retorna una página de autores filtrada y ordenada
+func (r *InMemoryAuthorRepository) List(ctx context.Context, q domain.AuthorQuery) ([]*domain.Author, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	matches := make([]*domain.Author, 0, len(r.authors))
+	for _, author := range r.authors {
+		if containsFold(author.Name, q.Filter.Name) {
+			matches = append(matches, author)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Author) int {
+		if q.Sort == domain.SortByName {
+			return compareFold(a.Name, b.Name)
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}, func(a *domain.Author) string { return a.ID })
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Update modifica un autor existente
+func (r *InMemoryAuthorRepository) Update(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.authors[author.ID]
+	if !exists {
+		return nil, domain.ErrAuthorNotFound
+	}
+
+	// La fecha de alta no cambia
+	author.CreatedAt = existing.CreatedAt
+	r.authors[author.ID] = author
+	return author, nil
+}
+
+// Delete elimina un autor por su ID
+func (r *InMemoryAuthorRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.authors[id]; !exists {
+		return domain.ErrAuthorNotFound
+	}
+
+	delete(r.authors, id)
+	return nil
+}
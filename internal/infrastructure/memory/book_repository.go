@@ -1,99 +1,372 @@
 package memory
 
 import (
-	"errors"
+	"context"
 	"go-book-clean-architecture-api/internal/domain"
 	"go-book-clean-architecture-api/internal/repository"
+	"sort"
+	"strings"
 	"sync"
 )
 
 // InMemoryBookRepository es una implementación en memoria del BookRepository
 // Esta implementación está en la capa de infraestructura
 // En un caso real, aquí tendríamos implementaciones para PostgreSQL, MongoDB, etc.
+//
+// ⏱️ Aunque no hay I/O, cada método revisa ctx.Err() antes de trabajar:
+// una petición cancelada no debe producir efectos (ni crear, ni borrar)
+//
+// 🔎 Mantiene además dos índices, actualizados en cada Create/Update/Delete bajo el mismo mutex:
+// - un índice invertido para la búsqueda de texto (ver search.go)
+// - un trie + trigramas para el autocompletado (ver suggest.go)
 type InMemoryBookRepository struct {
-	books map[string]*domain.Book // Almacenamiento en memoria usando un map
-	mutex sync.RWMutex            // Para manejar concurrencia de manera segura
+	books   map[string]*domain.Book // Almacenamiento en memoria usando un map
+	index   *searchIndex            // Índice invertido para Search
+	suggest *suggestIndex           // Trie y trigramas para Suggest
+	mutex   sync.RWMutex            // Para manejar concurrencia de manera segura
 }
 
 // NewInMemoryBookRepository crea una nueva instancia del repositorio en memoria
 func NewInMemoryBookRepository() repository.BookRepository {
 	return &InMemoryBookRepository{
-		books: make(map[string]*domain.Book),
-		mutex: sync.RWMutex{},
+		books:   make(map[string]*domain.Book),
+		index:   newSearchIndex(),
+		suggest: newSuggestIndex(),
+		mutex:   sync.RWMutex{},
 	}
 }
 
 // Create almacena un nuevo libro en memoria
-func (r *InMemoryBookRepository) Create(book *domain.Book) (*domain.Book, error) {
+func (r *InMemoryBookRepository) Create(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err // La petición ya fue cancelada o venció
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el libro ya existe
 	if _, exists := r.books[book.ID]; exists {
-		return nil, errors.New("el libro con este ID ya existe")
+		return nil, domain.ErrBookAlreadyExists
 	}
 
-	// Almacenar el libro
+	// El ISBN es único, igual que el índice UNIQUE de PostgreSQL
+	if r.isbnTaken(book.ISBN, book.ID) {
+		return nil, domain.ErrISBNAlreadyInUse
+	}
+
+	// Almacenar el libro (todo registro nuevo empieza en la versión 1)
+	book.Version = 1
 	r.books[book.ID] = book
+	r.index.add(book.ID, bookFields(book)...)
+	r.suggest.add(book)
 	return book, nil
 }
 
 // GetByID busca un libro por su ID
-func (r *InMemoryBookRepository) GetByID(id string) (*domain.Book, error) {
+func (r *InMemoryBookRepository) GetByID(ctx context.Context, id string) (*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()         // Bloquear solo para lectura
 	defer r.mutex.RUnlock() // Asegurar que se desbloquee al final
 
 	book, exists := r.books[id]
 	if !exists {
-		return nil, errors.New("libro no encontrado")
+		return nil, domain.ErrBookNotFound
 	}
 
 	return book, nil
 }
 
+// GetByIDs busca varios libros de una vez (los inexistentes se omiten)
+func (r *InMemoryBookRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	books := make([]*domain.Book, 0, len(ids))
+	for _, id := range ids {
+		if book, exists := r.books[id]; exists {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+// GetByISBN busca un libro por su ISBN (recorre el map: no hay índice por ISBN)
+func (r *InMemoryBookRepository) GetByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if isbn != "" {
+		for _, book := range r.books {
+			if book.ISBN == isbn {
+				return book, nil
+			}
+		}
+	}
+	return nil, domain.ErrBookNotFound
+}
+
 // GetAll retorna todos los libros almacenados
-func (r *InMemoryBookRepository) GetAll() ([]*domain.Book, error) {
+func (r *InMemoryBookRepository) GetAll(ctx context.Context) ([]*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()         // Bloquear solo para lectura
 	defer r.mutex.RUnlock() // Asegurar que se desbloquee al final
 
 	books := make([]*domain.Book, 0, len(r.books))
 	for _, book := range r.books {
+		if err := ctx.Err(); err != nil {
+			return nil, err // Cortar el recorrido si la petición se canceló
+		}
 		books = append(books, book)
 	}
 
 	return books, nil
 }
 
+// List retorna una página de libros filtrada y ordenada
+//
+// 🔄 Pasos: filtrar → ordenar (estable) → contar → recortar la página
+// 💡 Ordenar siempre es necesario: el orden de un map en Go es aleatorio
+func (r *InMemoryBookRepository) List(ctx context.Context, q domain.BookQuery) ([]*domain.Book, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	matches := make([]*domain.Book, 0, len(r.books))
+	for _, book := range r.books {
+		if matchesFilter(book, q.Filter) {
+			matches = append(matches, book)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Book) int {
+		switch q.Sort {
+		case domain.SortByTitle:
+			return compareFold(a.Title, b.Title)
+		case domain.SortByAuthor:
+			return compareFold(a.Author, b.Author)
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}, func(b *domain.Book) string { return b.ID })
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Facets cuenta los libros que cumplen el filtro por idioma base y por autor vinculado
+func (r *InMemoryBookRepository) Facets(ctx context.Context, filter domain.BookFilter) (*domain.BookFacets, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	languages, authors := map[string]int{}, map[string]int{}
+	r.mutex.RLock()
+	for _, book := range r.books {
+		if !matchesFilter(book, filter) {
+			continue
+		}
+		if book.Language != "" {
+			languages[baseLanguage(book.Language)]++
+		}
+		seen := map[string]bool{} // Un autor con dos roles en el mismo libro cuenta una vez
+		for _, a := range book.Authors {
+			if !seen[a.AuthorID] {
+				seen[a.AuthorID] = true
+				authors[a.AuthorID]++
+			}
+		}
+	}
+	r.mutex.RUnlock()
+
+	return &domain.BookFacets{Authors: facetCounts(authors), Languages: facetCounts(languages)}, nil
+}
+
+// facetCounts ordena los conteos de mayor a menor (a igual cantidad, por valor)
+func facetCounts(counts map[string]int) []domain.FacetCount {
+	facets := make([]domain.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, domain.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+// Search busca libros por texto libre usando el índice invertido
+//
+// 🎯 Coinciden los libros que contienen TODAS las palabras (completas o como prefijo);
+// se ordenan por relevancia: una palabra en el título pesa más que en el autor
+func (r *InMemoryBookRepository) Search(ctx context.Context, q domain.BookSearchQuery) ([]domain.BookHit, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	terms := domain.SearchTerms(q.Text)
+	if len(terms) == 0 {
+		return []domain.BookHit{}, 0, nil
+	}
+
+	r.mutex.RLock()
+	scores := r.index.search(terms)
+	hits := make([]domain.BookHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, domain.BookHit{Book: r.books[id], Score: score})
+	}
+	r.mutex.RUnlock()
+
+	sortHits(hits)
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return paginate(hits, q.Offset, q.Limit), len(hits), nil
+}
+
+// Suggest retorna sugerencias de autocompletado de títulos y autores
+//
+// ⚡ Usa el trie para completar prefijos y, si faltan resultados, los trigramas
+// para tolerar errores de tipeo ("martni" → "Martin")
+func (r *InMemoryBookRepository) Suggest(ctx context.Context, q domain.SuggestQuery) ([]domain.Suggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.suggest.suggest(domain.SearchTerms(q.Prefix), q.Limit), nil
+}
+
 // Update modifica un libro existente
-func (r *InMemoryBookRepository) Update(book *domain.Book) (*domain.Book, error) {
+func (r *InMemoryBookRepository) Update(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el libro existe
-	if _, exists := r.books[book.ID]; !exists {
-		return nil, errors.New("libro no encontrado")
+	existing, exists := r.books[book.ID]
+	if !exists {
+		return nil, domain.ErrBookNotFound
+	}
+	if book.Version != 0 && book.Version != existing.Version {
+		return nil, domain.ErrBookVersionMismatch
+	}
+	if r.isbnTaken(book.ISBN, book.ID) {
+		return nil, domain.ErrISBNAlreadyInUse
 	}
 
-	// Actualizar el libro
+	// Actualizar el libro (la fecha de alta no cambia y la versión sube)
+	book.CreatedAt = existing.CreatedAt
+	book.Version = existing.Version + 1
 	r.books[book.ID] = book
+	r.index.add(book.ID, bookFields(book)...)
+	r.suggest.remove(existing)
+	r.suggest.add(book)
 	return book, nil
 }
 
-// Delete elimina un libro por su ID
-func (r *InMemoryBookRepository) Delete(id string) error {
+// Delete elimina un libro por su ID (version 0 = sin verificar)
+func (r *InMemoryBookRepository) Delete(ctx context.Context, id string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el libro existe
-	if _, exists := r.books[id]; !exists {
-		return errors.New("libro no encontrado")
+	existing, exists := r.books[id]
+	if !exists {
+		return domain.ErrBookNotFound
+	}
+	if version != 0 && version != existing.Version {
+		return domain.ErrBookVersionMismatch
 	}
 
 	// Eliminar el libro
 	delete(r.books, id)
+	r.index.remove(id)
+	r.suggest.remove(existing)
 	return nil
 }
 
+// matchesFilter indica si el libro cumple todos los filtros de un listado
+func matchesFilter(book *domain.Book, f domain.BookFilter) bool {
+	return containsFold(book.Title, f.Title) && containsFold(book.Author, f.Author) &&
+		hasAuthor(book, f.AuthorID) && hasLanguage(book, f.Language)
+}
+
+// hasLanguage indica si el libro está en el idioma o en una variante ("pt" incluye "pt-BR")
+func hasLanguage(book *domain.Book, lang string) bool {
+	if lang == "" {
+		return true
+	}
+	lang = strings.ToLower(lang)
+	return strings.EqualFold(book.Language, lang) || baseLanguage(book.Language) == lang
+}
+
+// baseLanguage es el idioma base de un código ("pt-BR" → "pt")
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return strings.ToLower(base)
+}
+
+// hasAuthor indica si el libro está vinculado al autor (un filtro vacío siempre coincide)
+func hasAuthor(book *domain.Book, authorID string) bool {
+	if authorID == "" {
+		return true
+	}
+	for _, a := range book.Authors {
+		if a.AuthorID == authorID {
+			return true
+		}
+	}
+	return false
+}
+
+// isbnTaken indica si otro libro (distinto de exceptID) ya usa ese ISBN
+// Un ISBN vacío (desconocido) nunca choca
+// ⚠️ Debe llamarse con el mutex tomado
+func (r *InMemoryBookRepository) isbnTaken(isbn, exceptID string) bool {
+	if isbn == "" {
+		return false
+	}
+	for id, b := range r.books {
+		if id != exceptID && b.ISBN == isbn {
+			return true
+		}
+	}
+	return false
+}
+
 // InMemoryUserRepository es una implementación en memoria del UserRepository
 type InMemoryUserRepository struct {
 	users map[string]*domain.User // Almacenamiento en memoria usando un map
@@ -109,72 +382,217 @@ func NewInMemoryUserRepository() repository.UserRepository {
 }
 
 // Create almacena un nuevo usuario en memoria
-func (r *InMemoryUserRepository) Create(user *domain.User) (*domain.User, error) {
+func (r *InMemoryUserRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err // La petición ya fue cancelada o venció
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el usuario ya existe
 	if _, exists := r.users[user.ID]; exists {
-		return nil, errors.New("el usuario con este ID ya existe")
+		return nil, domain.ErrUserAlreadyExists
 	}
 
-	// Almacenar el usuario
+	// El email es único, igual que la restricción UNIQUE de PostgreSQL
+	if r.emailTaken(user.Email, user.ID) {
+		return nil, domain.ErrEmailAlreadyInUse
+	}
+
+	// Almacenar el usuario (todo registro nuevo empieza en la versión 1)
+	user.Version = 1
 	r.users[user.ID] = user
 	return user, nil
 }
 
 // GetByID busca un usuario por su ID
-func (r *InMemoryUserRepository) GetByID(id string) (*domain.User, error) {
+func (r *InMemoryUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()         // Bloquear solo para lectura
 	defer r.mutex.RUnlock() // Asegurar que se desbloquee al final
 
 	user, exists := r.users[id]
 	if !exists {
-		return nil, errors.New("usuario no encontrado")
+		return nil, domain.ErrUserNotFound
 	}
 
 	return user, nil
 }
 
+// GetByIDs busca varios usuarios de una vez (los inexistentes se omiten)
+func (r *InMemoryUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := make([]*domain.User, 0, len(ids))
+	for _, id := range ids {
+		if user, exists := r.users[id]; exists {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// GetByEmail busca un usuario por su email
+func (r *InMemoryUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
 // GetAll retorna todos los usuarios almacenados
-func (r *InMemoryUserRepository) GetAll() ([]*domain.User, error) {
+func (r *InMemoryUserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()         // Bloquear solo para lectura
 	defer r.mutex.RUnlock() // Asegurar que se desbloquee al final
 
 	users := make([]*domain.User, 0, len(r.users))
 	for _, user := range r.users {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		users = append(users, user)
 	}
 
 	return users, nil
 }
 
+// List retorna una página de usuarios filtrada y ordenada
+func (r *InMemoryUserRepository) List(ctx context.Context, q domain.UserQuery) ([]*domain.User, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	matches := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		if containsFold(user.Name, q.Filter.Name) && containsFold(user.Email, q.Filter.Email) {
+			matches = append(matches, user)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.User) int {
+		switch q.Sort {
+		case domain.SortByName:
+			return compareFold(a.Name, b.Name)
+		case domain.SortByEmail:
+			return compareFold(a.Email, b.Email)
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}, func(u *domain.User) string { return u.ID })
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
 // Update modifica un usuario existente
-func (r *InMemoryUserRepository) Update(user *domain.User) (*domain.User, error) {
+func (r *InMemoryUserRepository) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el usuario existe
-	if _, exists := r.users[user.ID]; !exists {
-		return nil, errors.New("usuario no encontrado")
+	existing, exists := r.users[user.ID]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.Version != 0 && user.Version != existing.Version {
+		return nil, domain.ErrUserVersionMismatch
+	}
+	if r.emailTaken(user.Email, user.ID) {
+		return nil, domain.ErrEmailAlreadyInUse
 	}
 
-	// Actualizar el usuario
+	// Actualizar el usuario (la fecha de alta, la contraseña y el rol no cambian; la versión sube)
+	user.CreatedAt = existing.CreatedAt
+	user.Version = existing.Version + 1
+	user.PasswordHash = existing.PasswordHash
+	user.Role = existing.Role
 	r.users[user.ID] = user
 	return user, nil
 }
 
-// Delete elimina un usuario por su ID
-func (r *InMemoryUserRepository) Delete(id string) error {
+// UpdateRole cambia el rol de un usuario
+// 💡 Guarda una COPIA: quien ya tenía el puntero anterior no ve el cambio a medias
+func (r *InMemoryUserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.users[id]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+
+	updated := *existing
+	updated.Role = role
+	updated.Version++
+	r.users[id] = &updated
+	return &updated, nil
+}
+
+// Delete elimina un usuario por su ID (version 0 = sin verificar)
+func (r *InMemoryUserRepository) Delete(ctx context.Context, id string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mutex.Lock()         // Bloquear para escritura
 	defer r.mutex.Unlock() // Asegurar que se desbloquee al final
 
 	// Verificar si el usuario existe
-	if _, exists := r.users[id]; !exists {
-		return errors.New("usuario no encontrado")
+	existing, exists := r.users[id]
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+	if version != 0 && version != existing.Version {
+		return domain.ErrUserVersionMismatch
 	}
 
 	// Eliminar el usuario
 	delete(r.users, id)
 	return nil
 }
+
+// emailTaken indica si otro usuario (distinto de exceptID) ya usa ese email
+// ⚠️ Debe llamarse con el mutex tomado
+func (r *InMemoryUserRepository) emailTaken(email, exceptID string) bool {
+	for id, u := range r.users {
+		if id != exceptID && u.Email == email {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+)
+
+// InMemoryCopyRepository es una implementación en memoria del CopyRepository
+type InMemoryCopyRepository struct {
+	copies map[string]*domain.Copy // Almacenamiento en memoria usando un map
+	mutex  sync.RWMutex            // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryCopyRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryCopyRepository() repository.CopyRepository {
+	return &InMemoryCopyRepository{
+		copies: make(map[string]*domain.Copy),
+		mutex:  sync.RWMutex{},
+	}
+}
+
+// Create almacena un nuevo ejemplar en memoria
+func (r *InMemoryCopyRepository) Create(ctx context.Context, cp *domain.Copy) (*domain.Copy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.copies[cp.ID]; exists {
+		return nil, domain.ErrCopyAlreadyExists
+	}
+	if r.barcodeTaken(cp.Barcode, cp.ID) {
+		return nil, domain.ErrBarcodeAlreadyInUse
+	}
+
+	r.copies[cp.ID] = cp
+	return cp, nil
+}
+
+// GetByID busca un ejemplar por su ID
+func (r *InMemoryCopyRepository) GetByID(ctx context.Context, id string) (*domain.Copy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	cp, exists := r.copies[id]
+	if !exists {
+		return nil, domain.ErrCopyNotFound
+	}
+	return cp, nil
+}
+
+// GetByBarcode busca un ejemplar por su código de barras
+func (r *InMemoryCopyRepository) GetByBarcode(ctx context.Context, barcode string) (*domain.Copy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, cp := range r.copies {
+		if cp.Barcode == barcode {
+			return cp, nil
+		}
+	}
+	return nil, domain.ErrCopyNotFound
+}
+
+// List retorna una página de ejemplares filtrada y ordenada
+func (r *InMemoryCopyRepository) List(ctx context.Context, q domain.CopyQuery) ([]*domain.Copy, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	matches := make([]*domain.Copy, 0)
+	for _, cp := range r.copies {
+		if (q.Filter.BookID == "" || cp.BookID == q.Filter.BookID) &&
+			(q.Filter.Status == "" || cp.Status == q.Filter.Status) {
+			matches = append(matches, cp)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Copy) int {
+		if q.Sort == domain.SortByBarcode {
+			return compareFold(a.Barcode, b.Barcode)
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}, func(c *domain.Copy) string { return c.ID })
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Counts cuenta los ejemplares por estado de cada libro
+func (r *InMemoryCopyRepository) Counts(ctx context.Context, bookIDs ...string) (map[string]domain.CopyCounts, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]domain.CopyCounts, len(bookIDs))
+	for _, id := range bookIDs {
+		counts[id] = domain.CopyCounts{}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, cp := range r.copies {
+		if c, wanted := counts[cp.BookID]; wanted {
+			c.Add(cp.Status, 1)
+			counts[cp.BookID] = c
+		}
+	}
+	return counts, nil
+}
+
+// Update modifica los datos del ejemplar conservando estado y fecha de alta
+func (r *InMemoryCopyRepository) Update(ctx context.Context, cp *domain.Copy) (*domain.Copy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.copies[cp.ID]
+	if !exists {
+		return nil, domain.ErrCopyNotFound
+	}
+	if r.barcodeTaken(cp.Barcode, cp.ID) {
+		return nil, domain.ErrBarcodeAlreadyInUse
+	}
+
+	// El libro, el estado y la fecha de alta no se cambian con Update
+	cp.BookID = existing.BookID
+	cp.Status = existing.Status
+	cp.CreatedAt = existing.CreatedAt
+	r.copies[cp.ID] = cp
+	return cp, nil
+}
+
+// SetStatus cambia el estado solo si el ejemplar sigue en from (compare-and-swap)
+//
+// 💡 Guarda una COPIA modificada: quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemoryCopyRepository) SetStatus(ctx context.Context, id string, from, to domain.CopyStatus) (*domain.Copy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.copies[id]
+	if !exists {
+		return nil, domain.ErrCopyNotFound
+	}
+	if existing.Status != from {
+		return nil, domain.ErrCopyStatusChanged
+	}
+
+	updated := *existing
+	updated.Status = to
+	r.copies[id] = &updated
+	return &updated, nil
+}
+
+// Delete elimina un ejemplar por su ID
+func (r *InMemoryCopyRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.copies[id]; !exists {
+		return domain.ErrCopyNotFound
+	}
+
+	delete(r.copies, id)
+	return nil
+}
+
+// barcodeTaken indica si otro ejemplar (distinto de exceptID) ya usa el código de barras
+// ⚠️ Debe llamarse con el mutex tomado
+func (r *InMemoryCopyRepository) barcodeTaken(barcode, exceptID string) bool {
+	for id, cp := range r.copies {
+		if id != exceptID && cp.Barcode == barcode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+	"time"
+)
+
+// InMemoryFineRepository es una implementación en memoria del FineRepository
+//
+// 🔒 El mutex hace atómicos los cambios: verificar que la multa siga abierta (y que el pago
+// no supere la deuda) y guardar ocurre sin que otra petición se meta en el medio
+type InMemoryFineRepository struct {
+	fines map[string]*domain.Fine // Almacenamiento en memoria usando un map
+	mutex sync.RWMutex            // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryFineRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryFineRepository() repository.FineRepository {
+	return &InMemoryFineRepository{
+		fines: make(map[string]*domain.Fine),
+		mutex: sync.RWMutex{},
+	}
+}
+
+// Create guarda una multa verificando que el préstamo no tenga otra
+func (r *InMemoryFineRepository) Create(ctx context.Context, fine *domain.Fine) (*domain.Fine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.fines[fine.ID]; exists {
+		return nil, domain.ErrFineAlreadyExists
+	}
+	for _, existing := range r.fines {
+		if existing.LoanID == fine.LoanID {
+			return nil, domain.ErrFineAlreadyExists
+		}
+	}
+
+	r.fines[fine.ID] = fine
+	return fine, nil
+}
+
+// GetByID busca una multa por su ID
+func (r *InMemoryFineRepository) GetByID(ctx context.Context, id string) (*domain.Fine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	fine, exists := r.fines[id]
+	if !exists {
+		return nil, domain.ErrFineNotFound
+	}
+	return fine, nil
+}
+
+// GetByLoanID busca la multa de un préstamo
+func (r *InMemoryFineRepository) GetByLoanID(ctx context.Context, loanID string) (*domain.Fine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, fine := range r.fines {
+		if fine.LoanID == loanID {
+			return fine, nil
+		}
+	}
+	return nil, domain.ErrFineNotFound
+}
+
+// List retorna una página de multas filtrada y ordenada
+func (r *InMemoryFineRepository) List(ctx context.Context, q domain.FineQuery) ([]*domain.Fine, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	f := q.Filter
+	r.mutex.RLock()
+	matches := make([]*domain.Fine, 0)
+	for _, fine := range r.fines {
+		if (f.UserID == "" || fine.UserID == f.UserID) &&
+			(f.BookID == "" || fine.BookID == f.BookID) &&
+			(f.LoanID == "" || fine.LoanID == f.LoanID) &&
+			(f.Status == "" || fine.Status == f.Status) {
+			matches = append(matches, fine)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Fine) int {
+		switch q.Sort {
+		case domain.SortByAmount:
+			return cmp.Compare(a.Amount, b.Amount)
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}, func(f *domain.Fine) string { return f.ID })
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Reassess guarda el nuevo cálculo de una multa abierta
+func (r *InMemoryFineRepository) Reassess(ctx context.Context, fine *domain.Fine) (*domain.Fine, error) {
+	return r.update(ctx, fine.ID, func(updated *domain.Fine) error {
+		updated.OverdueDays = fine.OverdueDays
+		updated.Amount = fine.Amount
+		updated.Final = fine.Final
+		updated.UpdatedAt = fine.UpdatedAt
+		return nil
+	})
+}
+
+// Pay suma un pago a una multa abierta sin superar lo que falta pagar
+func (r *InMemoryFineRepository) Pay(ctx context.Context, id string, amount int64, at time.Time) (*domain.Fine, error) {
+	return r.update(ctx, id, func(updated *domain.Fine) error {
+		if updated.Paid+amount > updated.Amount {
+			return domain.ErrFineOverpayment
+		}
+		updated.Paid += amount
+		updated.UpdatedAt = at
+		return nil
+	})
+}
+
+// Close cierra una multa abierta como paid o waived
+func (r *InMemoryFineRepository) Close(ctx context.Context, id string, status domain.FineStatus, reason string, at time.Time) (*domain.Fine, error) {
+	return r.update(ctx, id, func(updated *domain.Fine) error {
+		updated.Status = status
+		updated.WaiveReason = reason
+		updated.UpdatedAt = at
+		updated.ClosedAt = &at
+		return nil
+	})
+}
+
+// Debt suma lo que falta pagar de las multas abiertas de un usuario
+func (r *InMemoryFineRepository) Debt(ctx context.Context, userID string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var debt int64
+	for _, fine := range r.fines {
+		if fine.UserID == userID {
+			debt += fine.Balance()
+		}
+	}
+	return debt, nil
+}
+
+// update aplica change a una COPIA de la multa, solo si sigue abierta, y la guarda
+// 💡 Quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemoryFineRepository) update(ctx context.Context, id string, change func(*domain.Fine) error) (*domain.Fine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.fines[id]
+	if !exists {
+		return nil, domain.ErrFineNotFound
+	}
+	if existing.Status != domain.FineOpen {
+		return nil, domain.ErrFineNotOpen
+	}
+
+	updated := *existing
+	if err := change(&updated); err != nil {
+		return nil, err
+	}
+	r.fines[id] = &updated
+	return &updated, nil
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+	"sync"
+)
+
+// InMemoryHoldRepository es una implementación en memoria del HoldRepository
+//
+// 🔒 El mutex hace atómicas las operaciones sobre la cola: verificar que el usuario no tenga
+// otra reserva activa y anotarlo, o cambiar el estado solo si sigue siendo el esperado
+type InMemoryHoldRepository struct {
+	holds map[string]*domain.Hold // Almacenamiento en memoria usando un map
+	mutex sync.RWMutex            // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryHoldRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryHoldRepository() repository.HoldRepository {
+	return &InMemoryHoldRepository{
+		holds: make(map[string]*domain.Hold),
+		mutex: sync.RWMutex{},
+	}
+}
+
+// Create anota una reserva verificando que el usuario no tenga otra activa del mismo libro
+func (r *InMemoryHoldRepository) Create(ctx context.Context, hold *domain.Hold) (*domain.Hold, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.holds[hold.ID]; exists {
+		return nil, domain.ErrHoldAlreadyExists
+	}
+	for _, existing := range r.holds {
+		if existing.BookID == hold.BookID && existing.UserID == hold.UserID && existing.Status.Active() {
+			return nil, domain.ErrHoldAlreadyExists
+		}
+	}
+
+	r.holds[hold.ID] = hold
+	return hold, nil
+}
+
+// GetByID busca una reserva por su ID
+func (r *InMemoryHoldRepository) GetByID(ctx context.Context, id string) (*domain.Hold, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	hold, exists := r.holds[id]
+	if !exists {
+		return nil, domain.ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+// List retorna una página de reservas filtrada y ordenada por fecha de alta
+func (r *InMemoryHoldRepository) List(ctx context.Context, q domain.HoldQuery) ([]*domain.Hold, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	f := q.Filter
+	r.mutex.RLock()
+	matches := make([]*domain.Hold, 0)
+	for _, hold := range r.holds {
+		if (f.BookID == "" || hold.BookID == f.BookID) &&
+			(f.UserID == "" || hold.UserID == f.UserID) &&
+			(f.Status == "" || hold.Status == f.Status) &&
+			(f.ExpiresBefore.IsZero() || hold.ExpiresAt != nil && hold.ExpiresAt.Before(f.ExpiresBefore)) {
+			matches = append(matches, hold)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Hold) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}, func(h *domain.Hold) string { return h.ID })
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Positions calcula el lugar en la cola de cada reserva en espera
+//
+// 📋 La posición es 1 + cuántas reservas en espera del mismo libro se anotaron antes
+// (a igual fecha desempata el ID, igual que el ORDER BY de PostgreSQL)
+func (r *InMemoryHoldRepository) Positions(ctx context.Context, ids ...string) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	positions := make(map[string]int, len(ids))
+	for _, id := range ids {
+		hold, exists := r.holds[id]
+		if !exists || hold.Status != domain.HoldWaiting {
+			continue
+		}
+
+		position := 1
+		for _, other := range r.holds {
+			if other.BookID == hold.BookID && other.Status == domain.HoldWaiting && queuedBefore(other, hold) {
+				position++
+			}
+		}
+		positions[id] = position
+	}
+	return positions, nil
+}
+
+// Transition guarda el nuevo estado solo si la reserva sigue en from (compare-and-swap)
+//
+// 💡 Guarda una COPIA: quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemoryHoldRepository) Transition(ctx context.Context, hold *domain.Hold, from domain.HoldStatus) (*domain.Hold, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.holds[hold.ID]
+	if !exists {
+		return nil, domain.ErrHoldNotFound
+	}
+	if existing.Status != from {
+		return nil, domain.ErrHoldStatusChanged
+	}
+
+	updated := *existing
+	updated.Status = hold.Status
+	updated.CopyID = hold.CopyID
+	updated.ReadyAt = hold.ReadyAt
+	updated.ExpiresAt = hold.ExpiresAt
+	updated.ClosedAt = hold.ClosedAt
+	r.holds[hold.ID] = &updated
+	return &updated, nil
+}
+
+// queuedBefore indica si a está antes que b en la cola (por fecha de alta y luego por ID)
+func queuedBefore(a, b *domain.Hold) bool {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c < 0
+	}
+	return strings.Compare(a.ID, b.ID) < 0
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+	"time"
+)
+
+// InMemoryLoanRepository es una implementación en memoria del LoanRepository
+//
+// 🔒 El mutex hace atómicas las reglas de Checkout: contar los préstamos activos,
+// tomar el ejemplar y guardar el préstamo ocurre sin que otra petición pueda meterse en el medio
+//
+// 📚 Recibe el repositorio de ejemplares porque prestar y devolver cambian su estado
+// (en PostgreSQL eso ocurre en la misma transacción)
+type InMemoryLoanRepository struct {
+	loans  map[string]*domain.Loan   // Almacenamiento en memoria usando un map
+	copies repository.CopyRepository // Ejemplares cuyo estado cambia al prestar/devolver
+	mutex  sync.RWMutex              // Para manejar concurrencia de manera segura
+}
+
+// NewInMemoryLoanRepository crea una nueva instancia del repositorio en memoria
+func NewInMemoryLoanRepository(copies repository.CopyRepository) repository.LoanRepository {
+	return &InMemoryLoanRepository{
+		loans:  make(map[string]*domain.Loan),
+		copies: copies,
+		mutex:  sync.RWMutex{},
+	}
+}
+
+// Checkout registra un préstamo verificando el límite del usuario y tomando el ejemplar
+func (r *InMemoryLoanRepository) Checkout(ctx context.Context, loan *domain.Loan, from domain.CopyStatus, maxActive int) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.loans[loan.ID]; exists {
+		return nil, domain.ErrLoanAlreadyExists
+	}
+
+	active := 0
+	for _, existing := range r.loans {
+		if existing.Status == domain.LoanActive && existing.UserID == loan.UserID {
+			active++
+		}
+	}
+	if active >= maxActive {
+		return nil, domain.ErrLoanLimitReached
+	}
+
+	// Compare-and-swap: si otro préstamo se llevó el ejemplar, no está disponible
+	_, err := r.copies.SetStatus(ctx, loan.CopyID, from, domain.CopyOnLoan)
+	if errors.Is(err, domain.ErrCopyStatusChanged) {
+		return nil, domain.ErrCopyNotAvailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.loans[loan.ID] = loan
+	return loan, nil
+}
+
+// GetByID busca un préstamo por su ID
+func (r *InMemoryLoanRepository) GetByID(ctx context.Context, id string) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	loan, exists := r.loans[id]
+	if !exists {
+		return nil, domain.ErrLoanNotFound
+	}
+	return loan, nil
+}
+
+// List retorna una página de préstamos filtrada y ordenada
+func (r *InMemoryLoanRepository) List(ctx context.Context, q domain.LoanQuery) ([]*domain.Loan, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	f := q.Filter
+	r.mutex.RLock()
+	matches := make([]*domain.Loan, 0)
+	for _, loan := range r.loans {
+		if (f.UserID == "" || loan.UserID == f.UserID) &&
+			(f.BookID == "" || loan.BookID == f.BookID) &&
+			(f.CopyID == "" || loan.CopyID == f.CopyID) &&
+			(f.Status == "" || loan.Status == f.Status) &&
+			(f.DueBefore.IsZero() || loan.DueAt.Before(f.DueBefore)) {
+			matches = append(matches, loan)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sortItems(matches, q.Desc, func(a, b *domain.Loan) int {
+		if q.Sort == domain.SortByDueAt {
+			return a.DueAt.Compare(b.DueAt)
+		}
+		return a.CheckedOutAt.Compare(b.CheckedOutAt)
+	}, func(l *domain.Loan) string { return l.ID })
+
+	return paginate(matches, q.Offset, q.Limit), len(matches), nil
+}
+
+// Return marca el préstamo como devuelto y su ejemplar como disponible
+//
+// 💡 Guarda una COPIA modificada: quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemoryLoanRepository) Return(ctx context.Context, id string, returnedAt time.Time) (*domain.Loan, error) {
+	return r.modify(ctx, id, func(loan *domain.Loan) error {
+		// Si el ejemplar se borró o ya no figuraba como prestado, la devolución igual se registra
+		_, err := r.copies.SetStatus(ctx, loan.CopyID, domain.CopyOnLoan, domain.CopyAvailable)
+		if err != nil && !errors.Is(err, domain.ErrCopyNotFound) && !errors.Is(err, domain.ErrCopyStatusChanged) {
+			return err
+		}
+		loan.Status = domain.LoanReturned
+		loan.ReturnedAt = &returnedAt
+		return nil
+	})
+}
+
+// Renew corre el vencimiento si no se superó el máximo de renovaciones
+func (r *InMemoryLoanRepository) Renew(ctx context.Context, id string, dueAt time.Time, maxRenewals int) (*domain.Loan, error) {
+	return r.modify(ctx, id, func(loan *domain.Loan) error {
+		if loan.Renewals >= maxRenewals {
+			return domain.ErrRenewalLimitReached
+		}
+		loan.DueAt = dueAt
+		loan.Renewals++
+		return nil
+	})
+}
+
+// modify aplica change a una copia de un préstamo ACTIVO y la guarda si no hubo error
+func (r *InMemoryLoanRepository) modify(ctx context.Context, id string, change func(*domain.Loan) error) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.loans[id]
+	if !exists {
+		return nil, domain.ErrLoanNotFound
+	}
+	if existing.Status != domain.LoanActive {
+		return nil, domain.ErrLoanAlreadyReturned
+	}
+
+	loan := *existing
+	if err := change(&loan); err != nil {
+		return nil, err
+	}
+	r.loans[id] = &loan
+	return &loan, nil
+}
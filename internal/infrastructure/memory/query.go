@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+)
+
+// containsFold indica si s contiene sub sin distinguir mayúsculas
+// Un filtro vacío siempre coincide (equivale a "sin filtro")
+func containsFold(s, sub string) bool {
+	if sub == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
+
+// compareFold compara dos textos sin distinguir mayúsculas (-1, 0, 1)
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// sortItems ordena items con cmp y desempata por ID para que el orden sea estable
+//
+// 🔁 ¿Por qué desempatar? Si dos libros tienen el mismo título y no desempatamos,
+// podrían "saltar" de una página a otra entre peticiones
+func sortItems[T any](items []T, desc bool, cmp func(a, b T) int, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		c := cmp(items[i], items[j])
+		if c == 0 {
+			c = strings.Compare(id(items[i]), id(items[j]))
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// paginate recorta la página [offset, offset+limit) sin salirse de los límites
+// Un limit <= 0 significa "sin límite"
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Pesos de cada campo en la relevancia
+// 💡 Mismas proporciones que los pesos A/B/C de ts_rank en PostgreSQL
+const (
+	weightTitle       = 1.0
+	weightAuthor      = 0.4
+	weightDescription = 0.2 // Descripción y materias
+
+	// prefixPenalty reduce la puntuación cuando la palabra solo coincide por prefijo
+	// ("arq" encuentra "arquitectura", pero vale menos que la palabra completa)
+	prefixPenalty = 0.5
+)
+
+// weightedText es el texto de un campo junto con su peso
+type weightedText struct {
+	text   string
+	weight float64
+}
+
+// searchIndex es un ÍNDICE INVERTIDO: en lugar de "libro → palabras"
+// guarda "palabra → libros que la contienen"
+//
+// 📇 Ejemplo:
+//
+//	"martin"       → {id1: 0.4, id7: 1.0}
+//	"arquitectura" → {id1: 1.0}
+//
+// 🚀 Buscar ya no exige recorrer todos los libros: solo las listas de las palabras buscadas
+// ⚠️ No es seguro para uso concurrente: lo protege el mutex del repositorio
+type searchIndex struct {
+	postings map[string]map[string]float64 // palabra → id → peso acumulado
+	docs     map[string][]string           // id → palabras (para des-indexar)
+}
+
+// newSearchIndex crea un índice vacío
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[string]float64),
+		docs:     make(map[string][]string),
+	}
+}
+
+// add indexa (o re-indexa) un documento
+func (ix *searchIndex) add(id string, fields ...weightedText) {
+	ix.remove(id)
+
+	var terms []string
+	for _, f := range fields {
+		for _, term := range domain.SearchTerms(f.text) {
+			docs, ok := ix.postings[term]
+			if !ok {
+				docs = make(map[string]float64)
+				ix.postings[term] = docs
+			}
+			if _, seen := docs[id]; !seen {
+				terms = append(terms, term)
+			}
+			docs[id] += f.weight
+		}
+	}
+	ix.docs[id] = terms
+}
+
+// remove quita un documento del índice
+func (ix *searchIndex) remove(id string) {
+	for _, term := range ix.docs[id] {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, id)
+}
+
+// search retorna los IDs que contienen TODAS las palabras, con su puntuación
+//
+// 🔄 Por cada palabra buscada:
+// 1. Juntar los documentos de cada palabra indexada que empieza por ella
+// 2. Intersectar con los candidatos de las palabras anteriores
+// 3. Sumar la puntuación
+func (ix *searchIndex) search(terms []string) map[string]float64 {
+	var scores map[string]float64
+	for _, term := range terms {
+		matches := ix.match(term)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id := range scores {
+			if s, ok := matches[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// match retorna los documentos que contienen term o una palabra que empieza por term
+// Por cada documento se queda con la mejor coincidencia
+func (ix *searchIndex) match(term string) map[string]float64 {
+	matches := make(map[string]float64)
+	for indexed, docs := range ix.postings {
+		if !strings.HasPrefix(indexed, term) {
+			continue
+		}
+		factor := 1.0
+		if indexed != term {
+			factor = prefixPenalty
+		}
+		for id, weight := range docs {
+			if s := weight * factor; s > matches[id] {
+				matches[id] = s
+			}
+		}
+	}
+	return matches
+}
+
+// bookFields son los campos buscables de un libro con su peso
+func bookFields(book *domain.Book) []weightedText {
+	return []weightedText{
+		{book.Title, weightTitle},
+		{book.Author, weightAuthor},
+		{book.Description + " " + strings.Join(book.Subjects, " "), weightDescription},
+	}
+}
+
+// sortHits ordena por puntuación descendente y desempata por título e ID
+func sortHits(hits []domain.BookHit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if c := compareFold(hits[i].Title, hits[j].Title); c != 0 {
+			return c < 0
+		}
+		return hits[i].ID < hits[j].ID
+	})
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"sync"
+	"time"
+)
+
+// InMemorySessionRepository es una implementación en memoria del SessionRepository
+type InMemorySessionRepository struct {
+	sessions map[string]*domain.Session // Almacenamiento en memoria usando un map
+	mutex    sync.RWMutex               // Para manejar concurrencia de manera segura
+}
+
+// NewInMemorySessionRepository crea una nueva instancia del repositorio en memoria
+func NewInMemorySessionRepository() repository.SessionRepository {
+	return &InMemorySessionRepository{
+		sessions: make(map[string]*domain.Session),
+		mutex:    sync.RWMutex{},
+	}
+}
+
+// Create guarda una sesión nueva
+func (r *InMemorySessionRepository) Create(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.sessions[session.ID]; exists {
+		return nil, domain.ErrSessionAlreadyExists
+	}
+
+	r.sessions[session.ID] = session
+	return session, nil
+}
+
+// GetByID busca una sesión por su ID
+func (r *InMemorySessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, domain.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Revoke cierra la sesión solo si seguía abierta (compare-and-swap)
+//
+// 💡 Guarda una COPIA: quien ya tenía el puntero anterior no ve cambios a medias
+func (r *InMemorySessionRepository) Revoke(ctx context.Context, id, replacedBy string, at time.Time) (*domain.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.sessions[id]
+	if !exists {
+		return nil, domain.ErrSessionNotFound
+	}
+	if existing.RevokedAt != nil {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	updated := *existing
+	updated.RevokedAt = &at
+	updated.ReplacedBy = replacedBy
+	r.sessions[id] = &updated
+	return &updated, nil
+}
+
+// RevokeAll cierra todas las sesiones abiertas del usuario
+func (r *InMemorySessionRepository) RevokeAll(ctx context.Context, userID string, at time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	revoked := 0
+	for id, session := range r.sessions {
+		if session.UserID != userID || session.RevokedAt != nil {
+			continue
+		}
+		updated := *session
+		updated.RevokedAt = &at
+		r.sessions[id] = &updated
+		revoked++
+	}
+	return revoked, nil
+}
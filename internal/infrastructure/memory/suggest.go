@@ -0,0 +1,318 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Puntuaciones de las sugerencias (ver domain.Suggestion)
+const (
+	scoreFullPrefix = 1.0
+	scoreWordPrefix = 0.8
+	scoreFuzzyMax   = 0.6
+
+	// similarityThreshold es la similitud mínima de trigramas (mismo valor por defecto que pg_trgm)
+	similarityThreshold = 0.3
+)
+
+// suggestEntry es un título o autor distinto del catálogo
+type suggestEntry struct {
+	field string
+	text  string
+	norm  string   // Texto normalizado (minúsculas, sin acentos)
+	words []string // domain.SearchTerms(text)
+	books int      // Cuántos libros lo usan (al llegar a 0 se borra)
+}
+
+// trieNode es un nodo del TRIE (árbol de prefijos) de palabras
+//
+// 🌳 Cada arista es una letra; bajar por "m" → "a" → "r" lleva a todas
+// las palabras que empiezan por "mar" sin recorrer el vocabulario completo
+type trieNode struct {
+	children map[rune]*trieNode
+	word     string // No vacío si una palabra termina en este nodo
+}
+
+// suggestIndex es el índice de autocompletado del repositorio en memoria
+//
+// 🔧 Tres estructuras que se mantienen juntas en Create/Update/Delete:
+// - trie:     palabra por prefijo → completado instantáneo
+// - trigrams: trigrama → palabras que lo contienen → tolerancia a errores
+// - words:    palabra → entradas (títulos/autores) que la contienen
+//
+// ⚠️ No es seguro para uso concurrente: lo protege el mutex del repositorio
+type suggestIndex struct {
+	entries  map[string]*suggestEntry       // field + texto → entrada
+	words    map[string]map[string]struct{} // palabra → claves de entradas
+	trigrams map[string]map[string]struct{} // trigrama → palabras
+	trie     *trieNode
+}
+
+// newSuggestIndex crea un índice de sugerencias vacío
+func newSuggestIndex() *suggestIndex {
+	return &suggestIndex{
+		entries:  make(map[string]*suggestEntry),
+		words:    make(map[string]map[string]struct{}),
+		trigrams: make(map[string]map[string]struct{}),
+		trie:     &trieNode{},
+	}
+}
+
+// add registra el título y el autor de un libro
+func (ix *suggestIndex) add(book *domain.Book) {
+	ix.addEntry(domain.SuggestFieldTitle, book.Title)
+	ix.addEntry(domain.SuggestFieldAuthor, book.Author)
+}
+
+// remove quita el título y el autor de un libro (cuenta referencias: otros libros pueden compartirlos)
+func (ix *suggestIndex) remove(book *domain.Book) {
+	ix.removeEntry(domain.SuggestFieldTitle, book.Title)
+	ix.removeEntry(domain.SuggestFieldAuthor, book.Author)
+}
+
+func (ix *suggestIndex) addEntry(field, text string) {
+	key := field + "\x00" + text
+	if e, ok := ix.entries[key]; ok {
+		e.books++
+		return
+	}
+
+	e := &suggestEntry{
+		field: field,
+		text:  text,
+		norm:  strings.Join(domain.SearchTerms(text), " "),
+		words: domain.SearchTerms(text),
+		books: 1,
+	}
+	ix.entries[key] = e
+
+	for _, w := range e.words {
+		keys, ok := ix.words[w]
+		if !ok {
+			keys = make(map[string]struct{})
+			ix.words[w] = keys
+			ix.indexWord(w)
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (ix *suggestIndex) removeEntry(field, text string) {
+	key := field + "\x00" + text
+	e, ok := ix.entries[key]
+	if !ok {
+		return
+	}
+	if e.books--; e.books > 0 {
+		return
+	}
+
+	delete(ix.entries, key)
+	for _, w := range e.words {
+		delete(ix.words[w], key)
+		if len(ix.words[w]) == 0 {
+			delete(ix.words, w)
+			ix.unindexWord(w)
+		}
+	}
+}
+
+// indexWord agrega una palabra nueva al trie y al índice de trigramas
+func (ix *suggestIndex) indexWord(w string) {
+	node := ix.trie
+	for _, r := range w {
+		if node.children == nil {
+			node.children = make(map[rune]*trieNode)
+		}
+		child, ok := node.children[r]
+		if !ok {
+			child = &trieNode{}
+			node.children[r] = child
+		}
+		node = child
+	}
+	node.word = w
+
+	for t := range trigrams(w) {
+		if ix.trigrams[t] == nil {
+			ix.trigrams[t] = make(map[string]struct{})
+		}
+		ix.trigrams[t][w] = struct{}{}
+	}
+}
+
+// unindexWord quita una palabra que ya no usa ninguna entrada
+// 💡 Los nodos vacíos del trie se dejan: son inofensivos y se reutilizan
+func (ix *suggestIndex) unindexWord(w string) {
+	if node := ix.trie.find(w); node != nil {
+		node.word = ""
+	}
+	for t := range trigrams(w) {
+		delete(ix.trigrams[t], w)
+		if len(ix.trigrams[t]) == 0 {
+			delete(ix.trigrams, t)
+		}
+	}
+}
+
+// suggest retorna las mejores sugerencias para lo escrito
+//
+// 🔄 Algoritmo:
+// 1. Completado: entradas donde CADA palabra escrita empieza alguna de sus palabras (trie)
+// 2. Si faltan resultados, aproximado: entradas con palabras parecidas por trigramas
+// 3. Ordenar por puntuación, popularidad (libros) y longitud
+func (ix *suggestIndex) suggest(terms []string, limit int) []domain.Suggestion {
+	if len(terms) == 0 {
+		return []domain.Suggestion{}
+	}
+	query := strings.Join(terms, " ")
+	scores := make(map[string]float64)
+
+	for key := range ix.entriesMatching(terms, ix.prefixWords) {
+		scores[key] = scoreWordPrefix
+		if strings.HasPrefix(ix.entries[key].norm, query) {
+			scores[key] = scoreFullPrefix
+		}
+	}
+
+	if len(scores) < limit {
+		for key, sim := range ix.entriesMatching(terms, ix.similarWords) {
+			if _, found := scores[key]; !found {
+				scores[key] = scoreFuzzyMax * sim
+			}
+		}
+	}
+
+	suggestions := make([]domain.Suggestion, 0, len(scores))
+	for key, score := range scores {
+		e := ix.entries[key]
+		suggestions = append(suggestions, domain.Suggestion{Text: e.text, Field: e.field, Score: score, Books: e.books})
+	}
+	sortSuggestions(suggestions)
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// entriesMatching retorna las entradas donde TODOS los términos encuentran palabra,
+// con la puntuación media de cada término
+func (ix *suggestIndex) entriesMatching(terms []string, words func(term string) map[string]float64) map[string]float64 {
+	var result map[string]float64
+	for _, term := range terms {
+		matches := make(map[string]float64)
+		for w, s := range words(term) {
+			for key := range ix.words[w] {
+				if s > matches[key] {
+					matches[key] = s
+				}
+			}
+		}
+
+		if result == nil {
+			result = matches
+			continue
+		}
+		for key := range result {
+			if s, ok := matches[key]; ok {
+				result[key] += s
+			} else {
+				delete(result, key)
+			}
+		}
+	}
+
+	for key := range result {
+		result[key] /= float64(len(terms))
+	}
+	return result
+}
+
+// prefixWords retorna las palabras que empiezan por term (bajando por el trie)
+func (ix *suggestIndex) prefixWords(term string) map[string]float64 {
+	words := make(map[string]float64)
+	node := ix.trie.find(term)
+	if node == nil {
+		return words
+	}
+
+	stack := []*trieNode{node}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.word != "" {
+			words[n.word] = 1
+		}
+		for _, child := range n.children {
+			stack = append(stack, child)
+		}
+	}
+	return words
+}
+
+// similarWords retorna las palabras cuya similitud de trigramas con term supera el umbral
+//
+// 📐 similitud = trigramas en común / trigramas totales (índice de Jaccard, como pg_trgm)
+// "martni" y "martin" comparten "  m", " ma", "mar", "art" → 4/10 = 0.4
+func (ix *suggestIndex) similarWords(term string) map[string]float64 {
+	query := trigrams(term)
+	shared := make(map[string]int)
+	for t := range query {
+		for w := range ix.trigrams[t] {
+			shared[w]++
+		}
+	}
+
+	words := make(map[string]float64)
+	for w, n := range shared {
+		sim := float64(n) / float64(len(query)+len(trigrams(w))-n)
+		if sim >= similarityThreshold {
+			words[w] = sim
+		}
+	}
+	return words
+}
+
+// find baja por el trie siguiendo las letras de s
+func (n *trieNode) find(s string) *trieNode {
+	node := n
+	for _, r := range s {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// trigrams retorna los trigramas de una palabra con el mismo relleno que pg_trgm
+// ("  " al principio y " " al final): "gol" → {"  g", " go", "gol", "ol "}
+func trigrams(word string) map[string]struct{} {
+	padded := []rune("  " + word + " ")
+	set := make(map[string]struct{}, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = struct{}{}
+	}
+	return set
+}
+
+// sortSuggestions ordena por puntuación, luego por popularidad, luego el texto más corto
+func sortSuggestions(s []domain.Suggestion) {
+	sort.Slice(s, func(i, j int) bool {
+		switch {
+		case s[i].Score != s[j].Score:
+			return s[i].Score > s[j].Score
+		case s[i].Books != s[j].Books:
+			return s[i].Books > s[j].Books
+		case len(s[i].Text) != len(s[j].Text):
+			return len(s[i].Text) < len(s[j].Text)
+		case s[i].Text != s[j].Text:
+			return s[i].Text < s[j].Text
+		default:
+			return s[i].Field > s[j].Field // title antes que author
+		}
+	})
+}
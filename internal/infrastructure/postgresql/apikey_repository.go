@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// PostgresAPIKeyRepository implementa APIKeyRepository usando PostgreSQL
+type PostgresAPIKeyRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresAPIKeyRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		db: db,
+	}
+}
+
+// apiKeyColumns son las columnas de una API key, en el orden que espera scanAPIKey
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at`
+
+// scanAPIKey lee una fila con apiKeyColumns
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var scopes []string
+	if err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, pq.Array(&scopes),
+		&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt); err != nil {
+		return nil, translateAPIKeyError(err)
+	}
+	k.Scopes = make([]domain.Permission, len(scopes))
+	for i, scope := range scopes {
+		k.Scopes[i] = domain.Permission(scope)
+	}
+	return &k, nil
+}
+
+// Create guarda una API key nueva en PostgreSQL
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, CURRENT_TIMESTAMP), $8)
+		RETURNING ` + apiKeyColumns
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, query,
+		key.ID, key.UserID, key.Name, key.Prefix, key.Hash, pq.Array(scopes), nullTime(key.CreatedAt), key.ExpiresAt))
+}
+
+// GetByID busca una API key por su ID en PostgreSQL
+func (r *PostgresAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id::text = $1`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByPrefix busca una API key por su prefijo en PostgreSQL
+func (r *PostgresAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, prefix))
+}
+
+// apiKeySortColumns es la lista blanca de columnas de ordenamiento de API keys
+var apiKeySortColumns = map[string]string{
+	domain.SortByCreatedAt: "created_at",
+}
+
+// List retorna una página de API keys filtrada y ordenada desde PostgreSQL
+func (r *PostgresAPIKeyRepository) List(ctx context.Context, q domain.APIKeyQuery) ([]*domain.APIKey, int, error) {
+	var where whereBuilder
+	if q.Filter.UserID != "" {
+		where.conds = append(where.conds, "user_id::text = "+where.arg(q.Filter.UserID))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM api_keys` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateAPIKeyError(err)
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys` + where.sql() +
+		orderBy(apiKeySortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateAPIKeyError(err)
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.APIKey, 0)
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateAPIKeyError(err)
+	}
+
+	return keys, total, nil
+}
+
+// Revoke revoca la clave solo si seguía activa
+//
+// 🔒 "AND revoked_at IS NULL" hace el compare-and-swap en un único UPDATE atómico
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) (*domain.APIKey, error) {
+	revoked, err := scanAPIKey(r.db.QueryRowContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = $2
+		WHERE id::text = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns,
+		id, at))
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return revoked, err
+	}
+
+	// Ninguna fila afectada: ¿no existe o ya estaba revocada?
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return nil, domain.ErrAPIKeyRevoked
+}
+
+// Touch anota el último uso de la clave en PostgreSQL
+func (r *PostgresAPIKeyRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id::text = $1`, id, at)
+	if err != nil {
+		return translateAPIKeyError(err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return translateAPIKeyError(err)
+	}
+	if n == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
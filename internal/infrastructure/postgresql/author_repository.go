@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// PostgresAuthorRepository implementa AuthorRepository usando PostgreSQL
+type PostgresAuthorRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresAuthorRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresAuthorRepository(db *sql.DB) repository.AuthorRepository {
+	return &PostgresAuthorRepository{
+		db: db,
+	}
+}
+
+// authorColumns son las columnas de un autor, en el orden que espera scanAuthor
+const authorColumns = `id, name, bio, created_at`
+
+// scanAuthor lee una fila con authorColumns
+func scanAuthor(row rowScanner) (*domain.Author, error) {
+	var author domain.Author
+	if err := row.Scan(&author.ID, &author.Name, &author.Bio, &author.CreatedAt); err != nil {
+		return nil, translateAuthorError(err)
+	}
+	return &author, nil
+}
+
+// Create almacena un nuevo autor en PostgreSQL
+func (r *PostgresAuthorRepository) Create(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	query := `
+		INSERT INTO authors (id, name, bio, created_at)
+		VALUES ($1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP))
+		RETURNING ` + authorColumns
+
+	return scanAuthor(r.db.QueryRowContext(ctx, query, author.ID, author.Name, author.Bio, nullTime(author.CreatedAt)))
+}
+
+// GetByID busca un autor por su ID en PostgreSQL
+func (r *PostgresAuthorRepository) GetByID(ctx context.Context, id string) (*domain.Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM authors WHERE id = $1`
+	return scanAuthor(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByIDs busca varios autores con una sola consulta (id = ANY($1))
+func (r *PostgresAuthorRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Author, error) {
+	authors := make([]*domain.Author, 0, len(ids))
+	if len(ids) == 0 {
+		return authors, nil
+	}
+
+	// ::text[] y no ::uuid[]: un ID mal formado simplemente no coincide (no es error)
+	query := `SELECT ` + authorColumns + ` FROM authors WHERE id::text = ANY($1::text[])`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, translateAuthorError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateAuthorError(err)
+	}
+
+	return authors, nil
+}
+
+// authorSortColumns es la lista blanca de columnas de ordenamiento de autores
+var authorSortColumns = map[string]string{
+	domain.SortByName:      "LOWER(name)",
+	domain.SortByCreatedAt: "created_at",
+}
+
+// List retorna una página de autores filtrada y ordenada desde PostgreSQL
+func (r *PostgresAuthorRepository) List(ctx context.Context, q domain.AuthorQuery) ([]*domain.Author, int, error) {
+	var where whereBuilder
+	where.ilike("name", q.Filter.Name)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM authors` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateAuthorError(err)
+	}
+
+	query := `SELECT ` + authorColumns + ` FROM authors` + where.sql() +
+		orderBy(authorSortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateAuthorError(err)
+	}
+	defer rows.Close()
+
+	authors := make([]*domain.Author, 0)
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateAuthorError(err)
+	}
+
+	return authors, total, nil
+}
+
+// Update modifica un autor existente en PostgreSQL
+func (r *PostgresAuthorRepository) Update(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	query := `
+		UPDATE authors
+		SET name = $2, bio = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + authorColumns
+
+	return scanAuthor(r.db.QueryRowContext(ctx, query, author.ID, author.Name, author.Bio))
+}
+
+// Delete elimina un autor por su ID en PostgreSQL
+// 🔗 Si tiene libros vinculados, la FK RESTRICT lo impide → domain.ErrAuthorHasBooks
+func (r *PostgresAuthorRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM authors WHERE id = $1`, id)
+	if err != nil {
+		return translateAuthorError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateAuthorError(err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAuthorNotFound
+	}
+
+	return nil
+}
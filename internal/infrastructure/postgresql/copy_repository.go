@@ -0,0 +1,202 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// PostgresCopyRepository implementa CopyRepository usando PostgreSQL
+type PostgresCopyRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresCopyRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresCopyRepository(db *sql.DB) repository.CopyRepository {
+	return &PostgresCopyRepository{
+		db: db,
+	}
+}
+
+// copyColumns son las columnas de un ejemplar, en el orden que espera scanCopy
+const copyColumns = `id, book_id, barcode, condition, location, status, created_at`
+
+// scanCopy lee una fila con copyColumns
+func scanCopy(row rowScanner) (*domain.Copy, error) {
+	var c domain.Copy
+	err := row.Scan(&c.ID, &c.BookID, &c.Barcode, &c.Condition, &c.Location, &c.Status, &c.CreatedAt)
+	if err != nil {
+		return nil, translateCopyError(err)
+	}
+	return &c, nil
+}
+
+// Create almacena un nuevo ejemplar en PostgreSQL
+// 🔗 Si el libro no existe, la FK lo impide → domain.ErrBookNotFound
+func (r *PostgresCopyRepository) Create(ctx context.Context, cp *domain.Copy) (*domain.Copy, error) {
+	query := `
+		INSERT INTO copies (id, book_id, barcode, condition, location, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, CURRENT_TIMESTAMP))
+		RETURNING ` + copyColumns
+
+	return scanCopy(r.db.QueryRowContext(ctx, query,
+		cp.ID, cp.BookID, cp.Barcode, string(cp.Condition), cp.Location, string(cp.Status), nullTime(cp.CreatedAt)))
+}
+
+// GetByID busca un ejemplar por su ID en PostgreSQL
+func (r *PostgresCopyRepository) GetByID(ctx context.Context, id string) (*domain.Copy, error) {
+	query := `SELECT ` + copyColumns + ` FROM copies WHERE id = $1`
+	return scanCopy(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByBarcode busca un ejemplar por su código de barras en PostgreSQL
+func (r *PostgresCopyRepository) GetByBarcode(ctx context.Context, barcode string) (*domain.Copy, error) {
+	query := `SELECT ` + copyColumns + ` FROM copies WHERE barcode = $1`
+	return scanCopy(r.db.QueryRowContext(ctx, query, barcode))
+}
+
+// copySortColumns es la lista blanca de columnas de ordenamiento de ejemplares
+var copySortColumns = map[string]string{
+	domain.SortByBarcode:   "barcode",
+	domain.SortByCreatedAt: "created_at",
+}
+
+// List retorna una página de ejemplares filtrada y ordenada desde PostgreSQL
+func (r *PostgresCopyRepository) List(ctx context.Context, q domain.CopyQuery) ([]*domain.Copy, int, error) {
+	var where whereBuilder
+	if q.Filter.BookID != "" {
+		where.conds = append(where.conds, "book_id::text = "+where.arg(q.Filter.BookID))
+	}
+	if q.Filter.Status != "" {
+		where.conds = append(where.conds, "status = "+where.arg(string(q.Filter.Status)))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM copies` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateCopyError(err)
+	}
+
+	query := `SELECT ` + copyColumns + ` FROM copies` + where.sql() +
+		orderBy(copySortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateCopyError(err)
+	}
+	defer rows.Close()
+
+	copies := make([]*domain.Copy, 0)
+	for rows.Next() {
+		c, err := scanCopy(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		copies = append(copies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateCopyError(err)
+	}
+
+	return copies, total, nil
+}
+
+// Counts cuenta los ejemplares por estado con un GROUP BY (una sola consulta)
+func (r *PostgresCopyRepository) Counts(ctx context.Context, bookIDs ...string) (map[string]domain.CopyCounts, error) {
+	counts := make(map[string]domain.CopyCounts, len(bookIDs))
+	for _, id := range bookIDs {
+		counts[id] = domain.CopyCounts{}
+	}
+	if len(bookIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT book_id, status, COUNT(*) FROM copies
+		WHERE book_id::text = ANY($1::text[])
+		GROUP BY book_id, status`, pq.Array(bookIDs))
+	if err != nil {
+		return nil, translateCopyError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookID string
+		var status domain.CopyStatus
+		var n int
+		if err := rows.Scan(&bookID, &status, &n); err != nil {
+			return nil, translateCopyError(err)
+		}
+		c := counts[bookID]
+		c.Add(status, n)
+		counts[bookID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateCopyError(err)
+	}
+
+	return counts, nil
+}
+
+// Update modifica código de barras, estado físico y ubicación en PostgreSQL
+func (r *PostgresCopyRepository) Update(ctx context.Context, cp *domain.Copy) (*domain.Copy, error) {
+	query := `
+		UPDATE copies
+		SET barcode = $2, condition = $3, location = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + copyColumns
+
+	return scanCopy(r.db.QueryRowContext(ctx, query, cp.ID, cp.Barcode, string(cp.Condition), cp.Location))
+}
+
+// SetStatus cambia el estado solo si el ejemplar sigue en from
+//
+// 🔒 "AND status = $2" hace el compare-and-swap en un único UPDATE atómico
+func (r *PostgresCopyRepository) SetStatus(ctx context.Context, id string, from, to domain.CopyStatus) (*domain.Copy, error) {
+	return setCopyStatus(ctx, r.db, id, from, to)
+}
+
+// queryer es lo que tienen en común *sql.DB y *sql.Tx para leer una fila
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// setCopyStatus implementa SetStatus sobre una conexión o una transacción
+// 💡 El repositorio de préstamos la usa dentro de su propia transacción
+func setCopyStatus(ctx context.Context, q queryer, id string, from, to domain.CopyStatus) (*domain.Copy, error) {
+	updated, err := scanCopy(q.QueryRowContext(ctx, `
+		UPDATE copies SET status = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2
+		RETURNING `+copyColumns, id, string(from), string(to)))
+	if !errors.Is(err, domain.ErrCopyNotFound) {
+		return updated, err
+	}
+
+	// Ninguna fila afectada: ¿no existe o cambió de estado?
+	if _, err := scanCopy(q.QueryRowContext(ctx, `SELECT `+copyColumns+` FROM copies WHERE id = $1`, id)); err != nil {
+		return nil, err
+	}
+	return nil, domain.ErrCopyStatusChanged
+}
+
+// Delete elimina un ejemplar por su ID en PostgreSQL
+func (r *PostgresCopyRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM copies WHERE id = $1`, id)
+	if err != nil {
+		return translateCopyError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateCopyError(err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrCopyNotFound
+	}
+
+	return nil
+}
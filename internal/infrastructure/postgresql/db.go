@@ -0,0 +1,132 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/config"
+
+	_ "github.com/lib/pq" // Driver PostgreSQL para database/sql
+)
+
+// migrationsFS contiene los archivos .sql de migraciones embebidos en el binario
+//
+// 💡 Con embed el binario es autosuficiente: no hace falta copiar
+// la carpeta migrations/ junto al ejecutable en producción
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockID es la llave del advisory lock que evita que dos instancias
+// de la aplicación ejecuten migraciones al mismo tiempo
+const migrationLockID = 7245193
+
+// Open abre un pool de conexiones a PostgreSQL y verifica que la base responda
+//
+// 🔧 Pasos:
+// 1. sql.Open solo valida el DSN, NO se conecta todavía
+// 2. Configuramos el pool (conexiones abiertas, inactivas, tiempos de vida)
+// 3. Hacemos Ping con timeout para fallar rápido si la base no está disponible
+func Open(ctx context.Context, cfg config.StorageConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: DSN inválido: %w", err)
+	}
+
+	// Configuración del pool de conexiones
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	// Ping de arranque: si la base no responde, no tiene sentido levantar el servidor
+	pingCtx := ctx
+	if cfg.PingTimeout > 0 {
+		var cancel context.CancelFunc
+		pingCtx, cancel = context.WithTimeout(ctx, cfg.PingTimeout)
+		defer cancel()
+	}
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("postgresql: no se pudo conectar: %w", err)
+	}
+
+	return db, nil
+}
+
+// Migrate aplica en orden las migraciones embebidas que aún no se han ejecutado
+//
+// 📋 Cada migración:
+// - Se identifica por el nombre de su archivo (ej: 0001_init.sql)
+// - Se ejecuta dentro de una transacción junto con su registro en schema_migrations
+// - Se aplica una sola vez, aunque Migrate se llame en cada arranque
+func Migrate(ctx context.Context, db *sql.DB) error {
+	// Usamos una única conexión para que el advisory lock aplique a toda la operación
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("postgresql: no se pudo obtener el lock de migraciones: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+
+		var applied bool
+		err := conn.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version,
+		).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		script, err := migrationsFS.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("postgresql: migración %s falló: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// Códigos de error de PostgreSQL que traducimos a errores del dominio
+// Lista completa: https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgInvalidTextRepr     = "22P02" // ej: un ID que no es un UUID válido
+)
+
+// translateError convierte errores de database/sql y del driver en errores del dominio
+//
+// 🎯 ¿Por qué traducir?
+// - Los casos de uso y handlers NO deben conocer sql.ErrNoRows ni códigos SQLSTATE
+// - Así memoria y PostgreSQL devuelven exactamente los mismos errores
+//
+// 📋 Parámetros:
+// - notFound: el error a devolver si no hay filas (ej: domain.ErrBookNotFound)
+// - conflict: el error a devolver ante una violación de unicidad
+func translateError(err error, notFound, conflict error) error {
+	if err == nil {
+		return nil
+	}
+
+	// La cancelación/deadline de la petición se propaga tal cual
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pgUniqueViolation:
+			return conflict
+		case pgInvalidTextRepr:
+			// Un ID mal formado nunca puede existir: para el cliente es "no encontrado"
+			return notFound
+		case pgForeignKeyViolation, pgCheckViolation:
+			return &domain.Error{
+				Kind:    domain.ErrConflict,
+				Message: "la operación viola una restricción de integridad",
+				Err:     err,
+			}
+		}
+	}
+
+	return domain.NewInternalError("error de base de datos", err)
+}
+
+// translateBookError aplica translateError con los errores propios de libros
+// 💡 Distingue entre ID duplicado e ISBN duplicado mirando el nombre de la restricción
+func translateBookError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation && strings.Contains(pqErr.Constraint, "isbn") {
+		return domain.ErrISBNAlreadyInUse
+	}
+	return translateError(err, domain.ErrBookNotFound, domain.ErrBookAlreadyExists)
+}
+
+// translateAuthorError aplica translateError con los errores propios de autores
+// 🔗 Borrar un autor referenciado por book_authors viola la FK → ErrAuthorHasBooks
+func translateAuthorError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+		return domain.ErrAuthorHasBooks
+	}
+	return translateError(err, domain.ErrAuthorNotFound, domain.ErrAuthorAlreadyExists)
+}
+
+// translateUserError aplica translateError con los errores propios de usuarios
+// 💡 Distingue entre ID duplicado y email duplicado mirando el nombre de la restricción
+func translateUserError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation && strings.Contains(pqErr.Constraint, "email") {
+		return domain.ErrEmailAlreadyInUse
+	}
+	return translateError(err, domain.ErrUserNotFound, domain.ErrUserAlreadyExists)
+}
+
+// translateLoanError aplica translateError con los errores propios de préstamos
+// 🔒 El índice único parcial idx_loans_active_copy es el que impide prestar dos veces un ejemplar
+func translateLoanError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == pgUniqueViolation && strings.Contains(pqErr.Constraint, "active_copy"):
+			return domain.ErrCopyNotAvailable
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "copy"):
+			return domain.ErrCopyNotFound
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "book"):
+			return domain.ErrBookNotFound
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "user"):
+			return domain.ErrUserNotFound
+		}
+	}
+	return translateError(err, domain.ErrLoanNotFound, domain.ErrLoanAlreadyExists)
+}
+
+// translateCopyError aplica translateError con los errores propios de ejemplares
+// 💡 Distingue entre ID duplicado y código de barras duplicado mirando el nombre de la restricción
+// 🔗 Crear un ejemplar de un libro inexistente viola la FK → ErrBookNotFound
+func translateCopyError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == pgUniqueViolation && strings.Contains(pqErr.Constraint, "barcode"):
+			return domain.ErrBarcodeAlreadyInUse
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "book"):
+			return domain.ErrBookNotFound
+		}
+	}
+	return translateError(err, domain.ErrCopyNotFound, domain.ErrCopyAlreadyExists)
+}
+
+// translateHoldError aplica translateError con los errores propios de reservas
+// 🔒 El índice único parcial idx_holds_active_user_book impide dos reservas activas del mismo libro
+func translateHoldError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "book"):
+			return domain.ErrBookNotFound
+		case pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "user"):
+			return domain.ErrUserNotFound
+		}
+	}
+	return translateError(err, domain.ErrHoldNotFound, domain.ErrHoldAlreadyExists)
+}
+
+// translateFineError aplica translateError con los errores propios de multas
+// 🔒 La restricción UNIQUE de loan_id impide dos multas del mismo préstamo
+func translateFineError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "loan") {
+		return domain.ErrLoanNotFound
+	}
+	return translateError(err, domain.ErrFineNotFound, domain.ErrFineAlreadyExists)
+}
+
+// translateSessionError aplica translateError con los errores propios de sesiones
+// 🔗 Abrir una sesión de un usuario inexistente viola la FK → ErrUserNotFound
+func translateSessionError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "user") {
+		return domain.ErrUserNotFound
+	}
+	return translateError(err, domain.ErrSessionNotFound, domain.ErrSessionAlreadyExists)
+}
+
+// translateAPIKeyError aplica translateError con los errores propios de API keys
+// 🔗 Crear una clave para un usuario inexistente viola la FK → ErrUserNotFound
+func translateAPIKeyError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation && strings.Contains(pqErr.Constraint, "user") {
+		return domain.ErrUserNotFound
+	}
+	return translateError(err, domain.ErrAPIKeyNotFound, domain.ErrAPIKeyAlreadyExists)
+}
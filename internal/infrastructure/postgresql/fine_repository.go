@@ -0,0 +1,181 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"time"
+)
+
+// PostgresFineRepository implementa FineRepository usando PostgreSQL
+type PostgresFineRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresFineRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresFineRepository(db *sql.DB) repository.FineRepository {
+	return &PostgresFineRepository{
+		db: db,
+	}
+}
+
+// fineColumns son las columnas de una multa, en el orden que espera scanFine
+const fineColumns = `id, loan_id, user_id, book_id, status, overdue_days, amount_cents, paid_cents, final,
+	waive_reason, created_at, updated_at, closed_at`
+
+// scanFine lee una fila con fineColumns
+func scanFine(row rowScanner) (*domain.Fine, error) {
+	var f domain.Fine
+	err := row.Scan(&f.ID, &f.LoanID, &f.UserID, &f.BookID, &f.Status, &f.OverdueDays, &f.Amount, &f.Paid, &f.Final,
+		&f.WaiveReason, &f.CreatedAt, &f.UpdatedAt, &f.ClosedAt)
+	if err != nil {
+		return nil, translateFineError(err)
+	}
+	return &f, nil
+}
+
+// Create guarda la multa de un préstamo
+// 🔒 La restricción UNIQUE de loan_id rechaza una segunda multa (aunque lleguen a la vez)
+func (r *PostgresFineRepository) Create(ctx context.Context, fine *domain.Fine) (*domain.Fine, error) {
+	query := `
+		INSERT INTO fines (id, loan_id, user_id, book_id, status, overdue_days, amount_cents, paid_cents, final, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, CURRENT_TIMESTAMP), COALESCE($11, CURRENT_TIMESTAMP))
+		RETURNING ` + fineColumns
+
+	return scanFine(r.db.QueryRowContext(ctx, query,
+		fine.ID, fine.LoanID, fine.UserID, fine.BookID, string(fine.Status), fine.OverdueDays, fine.Amount, fine.Paid,
+		fine.Final, nullTime(fine.CreatedAt), nullTime(fine.UpdatedAt)))
+}
+
+// GetByID busca una multa por su ID en PostgreSQL
+func (r *PostgresFineRepository) GetByID(ctx context.Context, id string) (*domain.Fine, error) {
+	query := `SELECT ` + fineColumns + ` FROM fines WHERE id = $1`
+	return scanFine(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByLoanID busca la multa de un préstamo en PostgreSQL
+func (r *PostgresFineRepository) GetByLoanID(ctx context.Context, loanID string) (*domain.Fine, error) {
+	query := `SELECT ` + fineColumns + ` FROM fines WHERE loan_id = $1`
+	return scanFine(r.db.QueryRowContext(ctx, query, loanID))
+}
+
+// fineSortColumns es la lista blanca de columnas de ordenamiento de multas
+var fineSortColumns = map[string]string{
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByAmount:    "amount_cents",
+}
+
+// List retorna una página de multas filtrada y ordenada desde PostgreSQL
+func (r *PostgresFineRepository) List(ctx context.Context, q domain.FineQuery) ([]*domain.Fine, int, error) {
+	var where whereBuilder
+	if q.Filter.UserID != "" {
+		where.conds = append(where.conds, "user_id::text = "+where.arg(q.Filter.UserID))
+	}
+	if q.Filter.BookID != "" {
+		where.conds = append(where.conds, "book_id::text = "+where.arg(q.Filter.BookID))
+	}
+	if q.Filter.LoanID != "" {
+		where.conds = append(where.conds, "loan_id::text = "+where.arg(q.Filter.LoanID))
+	}
+	if q.Filter.Status != "" {
+		where.conds = append(where.conds, "status = "+where.arg(string(q.Filter.Status)))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM fines` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateFineError(err)
+	}
+
+	query := `SELECT ` + fineColumns + ` FROM fines` + where.sql() +
+		orderBy(fineSortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateFineError(err)
+	}
+	defer rows.Close()
+
+	fines := make([]*domain.Fine, 0)
+	for rows.Next() {
+		f, err := scanFine(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		fines = append(fines, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateFineError(err)
+	}
+
+	return fines, total, nil
+}
+
+// Reassess guarda el nuevo cálculo de una multa abierta
+func (r *PostgresFineRepository) Reassess(ctx context.Context, fine *domain.Fine) (*domain.Fine, error) {
+	updated, err := scanFine(r.db.QueryRowContext(ctx, `
+		UPDATE fines SET overdue_days = $2, amount_cents = $3, final = $4, updated_at = $5
+		WHERE id = $1 AND status = 'open'
+		RETURNING `+fineColumns,
+		fine.ID, fine.OverdueDays, fine.Amount, fine.Final, fine.UpdatedAt))
+	if !errors.Is(err, domain.ErrFineNotFound) {
+		return updated, err
+	}
+	return nil, r.whyNotUpdated(ctx, fine.ID, domain.ErrFineNotOpen)
+}
+
+// Pay suma un pago a una multa abierta
+//
+// 🔒 El UPDATE verifica estado y saldo en la misma sentencia: dos pagos simultáneos
+// que juntos superan la deuda no pueden aplicarse los dos
+func (r *PostgresFineRepository) Pay(ctx context.Context, id string, amount int64, at time.Time) (*domain.Fine, error) {
+	updated, err := scanFine(r.db.QueryRowContext(ctx, `
+		UPDATE fines SET paid_cents = paid_cents + $2, updated_at = $3
+		WHERE id = $1 AND status = 'open' AND paid_cents + $2 <= amount_cents
+		RETURNING `+fineColumns,
+		id, amount, at))
+	if !errors.Is(err, domain.ErrFineNotFound) {
+		return updated, err
+	}
+	return nil, r.whyNotUpdated(ctx, id, domain.ErrFineOverpayment)
+}
+
+// Close cierra una multa abierta como paid o waived
+func (r *PostgresFineRepository) Close(ctx context.Context, id string, status domain.FineStatus, reason string, at time.Time) (*domain.Fine, error) {
+	updated, err := scanFine(r.db.QueryRowContext(ctx, `
+		UPDATE fines SET status = $2, waive_reason = $3, updated_at = $4, closed_at = $4
+		WHERE id = $1 AND status = 'open'
+		RETURNING `+fineColumns,
+		id, string(status), reason, at))
+	if !errors.Is(err, domain.ErrFineNotFound) {
+		return updated, err
+	}
+	return nil, r.whyNotUpdated(ctx, id, domain.ErrFineNotOpen)
+}
+
+// Debt suma lo que falta pagar de las multas abiertas de un usuario
+func (r *PostgresFineRepository) Debt(ctx context.Context, userID string) (int64, error) {
+	var debt int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount_cents - paid_cents), 0) FROM fines
+		WHERE user_id::text = $1 AND status = 'open'`, userID).Scan(&debt)
+	if err != nil {
+		return 0, translateFineError(err)
+	}
+	return debt, nil
+}
+
+// whyNotUpdated explica un UPDATE que no afectó filas: la multa no existe, ya estaba
+// cerrada o (si seguía abierta) falló la otra condición → otherwise
+func (r *PostgresFineRepository) whyNotUpdated(ctx context.Context, id string, otherwise error) error {
+	fine, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if fine.Status != domain.FineOpen {
+		return domain.ErrFineNotOpen
+	}
+	return otherwise
+}
@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// PostgresHoldRepository implementa HoldRepository usando PostgreSQL
+type PostgresHoldRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresHoldRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresHoldRepository(db *sql.DB) repository.HoldRepository {
+	return &PostgresHoldRepository{
+		db: db,
+	}
+}
+
+// holdColumns son las columnas de una reserva, en el orden que espera scanHold
+const holdColumns = `id, book_id, user_id, status, COALESCE(copy_id::text, ''), created_at, ready_at, expires_at, closed_at`
+
+// scanHold lee una fila con holdColumns
+// 💡 Las fechas opcionales se leen en punteros: NULL queda como nil
+func scanHold(row rowScanner) (*domain.Hold, error) {
+	var h domain.Hold
+	err := row.Scan(&h.ID, &h.BookID, &h.UserID, &h.Status, &h.CopyID, &h.CreatedAt, &h.ReadyAt, &h.ExpiresAt, &h.ClosedAt)
+	if err != nil {
+		return nil, translateHoldError(err)
+	}
+	return &h, nil
+}
+
+// Create anota una reserva en PostgreSQL
+//
+// 🔒 Si el usuario ya tiene una reserva activa del libro, el índice único parcial
+// rechaza el INSERT (aunque lleguen dos peticiones a la vez) → domain.ErrHoldAlreadyExists
+func (r *PostgresHoldRepository) Create(ctx context.Context, hold *domain.Hold) (*domain.Hold, error) {
+	query := `
+		INSERT INTO holds (id, book_id, user_id, status, created_at)
+		VALUES ($1, $2, $3, $4, COALESCE($5, CURRENT_TIMESTAMP))
+		RETURNING ` + holdColumns
+
+	return scanHold(r.db.QueryRowContext(ctx, query,
+		hold.ID, hold.BookID, hold.UserID, string(hold.Status), nullTime(hold.CreatedAt)))
+}
+
+// GetByID busca una reserva por su ID en PostgreSQL
+func (r *PostgresHoldRepository) GetByID(ctx context.Context, id string) (*domain.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM holds WHERE id = $1`
+	return scanHold(r.db.QueryRowContext(ctx, query, id))
+}
+
+// holdSortColumns es la lista blanca de columnas de ordenamiento de reservas
+var holdSortColumns = map[string]string{
+	domain.SortByCreatedAt: "created_at",
+}
+
+// List retorna una página de reservas filtrada y ordenada desde PostgreSQL
+func (r *PostgresHoldRepository) List(ctx context.Context, q domain.HoldQuery) ([]*domain.Hold, int, error) {
+	var where whereBuilder
+	if q.Filter.BookID != "" {
+		where.conds = append(where.conds, "book_id::text = "+where.arg(q.Filter.BookID))
+	}
+	if q.Filter.UserID != "" {
+		where.conds = append(where.conds, "user_id::text = "+where.arg(q.Filter.UserID))
+	}
+	if q.Filter.Status != "" {
+		where.conds = append(where.conds, "status = "+where.arg(string(q.Filter.Status)))
+	}
+	if !q.Filter.ExpiresBefore.IsZero() {
+		where.conds = append(where.conds, "expires_at < "+where.arg(q.Filter.ExpiresBefore))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM holds` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateHoldError(err)
+	}
+
+	query := `SELECT ` + holdColumns + ` FROM holds` + where.sql() +
+		orderBy(holdSortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateHoldError(err)
+	}
+	defer rows.Close()
+
+	holds := make([]*domain.Hold, 0)
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		holds = append(holds, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateHoldError(err)
+	}
+
+	return holds, total, nil
+}
+
+// Positions calcula el lugar en la cola con ROW_NUMBER() (una sola consulta)
+//
+// 📋 Se numeran las reservas en espera de cada libro involucrado, en el mismo orden
+// que la cola (created_at y luego id), y se devuelven solo las pedidas
+func (r *PostgresHoldRepository) Positions(ctx context.Context, ids ...string) (map[string]int, error) {
+	positions := make(map[string]int, len(ids))
+	if len(ids) == 0 {
+		return positions, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, position FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY book_id ORDER BY created_at, id) AS position
+			FROM holds
+			WHERE status = 'waiting'
+				AND book_id IN (SELECT book_id FROM holds WHERE id::text = ANY($1::text[]))
+		) queue
+		WHERE id::text = ANY($1::text[])`, pq.Array(ids))
+	if err != nil {
+		return nil, translateHoldError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var position int
+		if err := rows.Scan(&id, &position); err != nil {
+			return nil, translateHoldError(err)
+		}
+		positions[id] = position
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateHoldError(err)
+	}
+
+	return positions, nil
+}
+
+// Transition guarda el nuevo estado solo si la reserva sigue en from
+//
+// 🔒 "AND status = $2" hace el compare-and-swap en un único UPDATE atómico
+func (r *PostgresHoldRepository) Transition(ctx context.Context, hold *domain.Hold, from domain.HoldStatus) (*domain.Hold, error) {
+	updated, err := scanHold(r.db.QueryRowContext(ctx, `
+		UPDATE holds
+		SET status = $3, copy_id = $4, ready_at = $5, expires_at = $6, closed_at = $7
+		WHERE id = $1 AND status = $2
+		RETURNING `+holdColumns,
+		hold.ID, string(from), string(hold.Status), nullString(hold.CopyID), hold.ReadyAt, hold.ExpiresAt, hold.ClosedAt))
+	if !errors.Is(err, domain.ErrHoldNotFound) {
+		return updated, err
+	}
+
+	// Ninguna fila afectada: ¿no existe o cambió de estado?
+	if _, err := r.GetByID(ctx, hold.ID); err != nil {
+		return nil, err
+	}
+	return nil, domain.ErrHoldStatusChanged
+}
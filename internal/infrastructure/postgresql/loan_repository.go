@@ -0,0 +1,225 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"time"
+)
+
+// PostgresLoanRepository implementa LoanRepository usando PostgreSQL
+type PostgresLoanRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresLoanRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresLoanRepository(db *sql.DB) repository.LoanRepository {
+	return &PostgresLoanRepository{
+		db: db,
+	}
+}
+
+// loanColumns son las columnas de un préstamo, en el orden que espera scanLoan
+const loanColumns = `id, book_id, copy_id, user_id, checked_out_at, due_at, returned_at, renewals`
+
+// scanLoan lee una fila con loanColumns; el estado se deduce de returned_at
+func scanLoan(row rowScanner) (*domain.Loan, error) {
+	var loan domain.Loan
+	var returnedAt sql.NullTime
+	err := row.Scan(&loan.ID, &loan.BookID, &loan.CopyID, &loan.UserID, &loan.CheckedOutAt, &loan.DueAt, &returnedAt, &loan.Renewals)
+	if err != nil {
+		return nil, translateLoanError(err)
+	}
+
+	loan.Status = domain.LoanActive
+	if returnedAt.Valid {
+		loan.Status = domain.LoanReturned
+		loan.ReturnedAt = &returnedAt.Time
+	}
+	return &loan, nil
+}
+
+// Checkout registra un préstamo en una transacción
+//
+// 🔒 ¿Cómo se evita que dos peticiones simultáneas superen el límite?
+// - SELECT ... FOR UPDATE bloquea la fila del usuario hasta el COMMIT
+// - La segunda petición del MISMO usuario espera y luego cuenta el préstamo de la primera
+// - El ejemplar pasa de from a on_loan con un UPDATE condicional (si ya no estaba en from → ErrCopyNotAvailable)
+// - Además, el índice único parcial impide dos préstamos activos del mismo ejemplar
+func (r *PostgresLoanRepository) Checkout(ctx context.Context, loan *domain.Loan, from domain.CopyStatus, maxActive int) (*domain.Loan, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateLoanError(err)
+	}
+	defer tx.Rollback() // No hace nada si ya se hizo Commit
+
+	var locked string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, loan.UserID).Scan(&locked)
+	if err != nil {
+		return nil, translateError(err, domain.ErrUserNotFound, domain.ErrLoanAlreadyExists)
+	}
+
+	var active int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM loans WHERE user_id = $1 AND returned_at IS NULL`, loan.UserID).Scan(&active)
+	if err != nil {
+		return nil, translateLoanError(err)
+	}
+	if active >= maxActive {
+		return nil, domain.ErrLoanLimitReached
+	}
+
+	_, err = setCopyStatus(ctx, tx, loan.CopyID, from, domain.CopyOnLoan)
+	if errors.Is(err, domain.ErrCopyStatusChanged) {
+		return nil, domain.ErrCopyNotAvailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := scanLoan(tx.QueryRowContext(ctx, `
+		INSERT INTO loans (id, book_id, copy_id, user_id, checked_out_at, due_at, renewals)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+loanColumns,
+		loan.ID, loan.BookID, loan.CopyID, loan.UserID, loan.CheckedOutAt, loan.DueAt, loan.Renewals))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateLoanError(err)
+	}
+	return saved, nil
+}
+
+// GetByID busca un préstamo por su ID en PostgreSQL
+func (r *PostgresLoanRepository) GetByID(ctx context.Context, id string) (*domain.Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM loans WHERE id = $1`
+	return scanLoan(r.db.QueryRowContext(ctx, query, id))
+}
+
+// loanSortColumns es la lista blanca de columnas de ordenamiento de préstamos
+var loanSortColumns = map[string]string{
+	domain.SortByCheckedOutAt: "checked_out_at",
+	domain.SortByDueAt:        "due_at",
+}
+
+// List retorna una página de préstamos filtrada y ordenada desde PostgreSQL
+func (r *PostgresLoanRepository) List(ctx context.Context, q domain.LoanQuery) ([]*domain.Loan, int, error) {
+	var where whereBuilder
+	if q.Filter.UserID != "" {
+		where.conds = append(where.conds, "user_id::text = "+where.arg(q.Filter.UserID))
+	}
+	if q.Filter.BookID != "" {
+		where.conds = append(where.conds, "book_id::text = "+where.arg(q.Filter.BookID))
+	}
+	if q.Filter.CopyID != "" {
+		where.conds = append(where.conds, "copy_id::text = "+where.arg(q.Filter.CopyID))
+	}
+	switch q.Filter.Status {
+	case domain.LoanActive:
+		where.conds = append(where.conds, "returned_at IS NULL")
+	case domain.LoanReturned:
+		where.conds = append(where.conds, "returned_at IS NOT NULL")
+	}
+	if !q.Filter.DueBefore.IsZero() {
+		where.conds = append(where.conds, "due_at < "+where.arg(q.Filter.DueBefore))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM loans` + where.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, where.args...).Scan(&total); err != nil {
+		return nil, 0, translateLoanError(err)
+	}
+
+	query := `SELECT ` + loanColumns + ` FROM loans` + where.sql() +
+		orderBy(loanSortColumns, q.Sort, q.Desc) + where.limitOffset(q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, translateLoanError(err)
+	}
+	defer rows.Close()
+
+	loans := make([]*domain.Loan, 0)
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		loans = append(loans, loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateLoanError(err)
+	}
+
+	return loans, total, nil
+}
+
+// Return marca el préstamo como devuelto y su ejemplar como disponible (en una transacción)
+//
+// 🔒 "AND returned_at IS NULL" hace que de dos devoluciones simultáneas solo una tenga éxito
+func (r *PostgresLoanRepository) Return(ctx context.Context, id string, returnedAt time.Time) (*domain.Loan, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateLoanError(err)
+	}
+	defer tx.Rollback() // No hace nada si ya se hizo Commit
+
+	loan, err := scanLoan(tx.QueryRowContext(ctx, `
+		UPDATE loans SET returned_at = $2
+		WHERE id = $1 AND returned_at IS NULL
+		RETURNING `+loanColumns, id, returnedAt))
+	if errors.Is(err, domain.ErrLoanNotFound) {
+		if reason := r.whyNotActive(ctx, id); reason != nil {
+			return nil, reason
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Si el ejemplar ya no figuraba como prestado (ej: se marcó perdido), no se toca
+	_, err = tx.ExecContext(ctx,
+		`UPDATE copies SET status = 'available', updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = 'on_loan'`,
+		loan.CopyID)
+	if err != nil {
+		return nil, translateCopyError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateLoanError(err)
+	}
+	return loan, nil
+}
+
+// Renew corre el vencimiento y suma una renovación si no se superó el máximo
+func (r *PostgresLoanRepository) Renew(ctx context.Context, id string, dueAt time.Time, maxRenewals int) (*domain.Loan, error) {
+	loan, err := scanLoan(r.db.QueryRowContext(ctx, `
+		UPDATE loans SET due_at = $2, renewals = renewals + 1
+		WHERE id = $1 AND returned_at IS NULL AND renewals < $3
+		RETURNING `+loanColumns, id, dueAt, maxRenewals))
+	if errors.Is(err, domain.ErrLoanNotFound) {
+		if err := r.whyNotActive(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRenewalLimitReached
+	}
+	return loan, err
+}
+
+// whyNotActive explica por qué un UPDATE condicional no afectó ninguna fila:
+// el préstamo no existe (ErrLoanNotFound) o ya fue devuelto (ErrLoanAlreadyReturned).
+// Retorna nil si el préstamo sigue activo.
+func (r *PostgresLoanRepository) whyNotActive(ctx context.Context, id string) error {
+	loan, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if loan.Status == domain.LoanReturned {
+		return domain.ErrLoanAlreadyReturned
+	}
+	return nil
+}
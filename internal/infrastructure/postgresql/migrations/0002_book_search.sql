@@ -0,0 +1,22 @@
+-- 0002: búsqueda de texto completo sobre libros
+--
+-- 🔎 Cada libro guarda un tsvector (lista de palabras normalizadas con su peso)
+-- calculado por PostgreSQL a partir del título (peso A) y el autor (peso B).
+-- El índice GIN es un índice invertido: palabra → filas que la contienen.
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() no es IMMUTABLE (depende del diccionario configurado) y las columnas
+-- generadas/índices exigen funciones inmutables: fijamos el diccionario explícitamente
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Configuración 'simple': minúsculas sin stemming, igual para cualquier idioma
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', immutable_unaccent(coalesce(title, ''))), 'A') ||
+        setweight(to_tsvector('simple', immutable_unaccent(coalesce(author, ''))), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN (search_vector);
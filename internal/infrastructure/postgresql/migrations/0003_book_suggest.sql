@@ -0,0 +1,13 @@
+-- 0003: autocompletado y búsqueda aproximada de títulos y autores
+--
+-- 🔤 pg_trgm divide el texto en trigramas ("gol" → "  g", " go", "gol", "ol ")
+-- y permite medir similitud entre textos: "martni" se parece a "martin".
+-- Los índices GIN de trigramas aceleran LIKE, ~ (regex) y los operadores de similitud.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm
+    ON books USING GIN (immutable_unaccent(lower(title)) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_books_author_trgm
+    ON books USING GIN (immutable_unaccent(lower(author)) gin_trgm_ops);
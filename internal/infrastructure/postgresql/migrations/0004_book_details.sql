@@ -0,0 +1,36 @@
+-- 0004: datos bibliográficos de los libros
+--
+-- 📚 ISBN (normalizado a ISBN-13), editorial, año, idioma, páginas,
+-- descripción, materias y edición. Los valores "desconocidos" son '' o 0,
+-- salvo el ISBN que es NULL para que UNIQUE solo aplique a los cargados.
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS isbn VARCHAR(13),
+    ADD COLUMN IF NOT EXISTS publisher VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS publication_year INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS language VARCHAR(12) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS page_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS subjects TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS edition VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn_unique ON books (isbn);
+
+-- La búsqueda de texto ahora también cubre descripción y materias (peso C).
+-- Una columna generada no se puede modificar: se recrea junto con su índice.
+-- array_to_string no es IMMUTABLE para cualquier tipo; para text[] es seguro envolverla.
+CREATE OR REPLACE FUNCTION immutable_array_to_string(text[], text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT array_to_string($1, $2) $$;
+
+DROP INDEX IF EXISTS idx_books_search;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE books ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', immutable_unaccent(coalesce(title, ''))), 'A') ||
+        setweight(to_tsvector('simple', immutable_unaccent(coalesce(author, ''))), 'B') ||
+        setweight(to_tsvector('simple', immutable_unaccent(
+            description || ' ' || coalesce(immutable_array_to_string(subjects, ' '), '')
+        )), 'C')
+    ) STORED;
+CREATE INDEX idx_books_search ON books USING GIN (search_vector);
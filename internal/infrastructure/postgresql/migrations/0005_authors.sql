@@ -0,0 +1,40 @@
+-- 0005: autores como entidades y vínculos libro ↔ autor (muchos a muchos)
+--
+-- 🔗 book_authors es la tabla intermedia: una fila por (libro, autor, rol).
+-- - Al borrar un libro se borran sus vínculos (CASCADE)
+-- - Un autor con libros NO se puede borrar (RESTRICT)
+-- - position conserva el orden de los créditos
+
+CREATE TABLE IF NOT EXISTS authors (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_name ON authors (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES authors (id) ON DELETE RESTRICT,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('author', 'editor', 'translator')),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+-- Para "libros de un autor" (la PK ya cubre "autores de un libro")
+CREATE INDEX IF NOT EXISTS idx_book_authors_author ON book_authors (author_id);
+
+-- 📦 Migración de datos: cada texto de autor distinto pasa a ser un autor vinculado.
+-- Los duplicados ("Robert Martin" vs "Robert C. Martin") se pueden fusionar después
+-- volviendo a vincular los libros y borrando el autor sobrante.
+INSERT INTO authors (id, name)
+SELECT gen_random_uuid(), author
+FROM (SELECT DISTINCT author FROM books WHERE author <> '') AS distinct_authors;
+
+INSERT INTO book_authors (book_id, author_id, role)
+SELECT b.id, a.id, 'author'
+FROM books b
+JOIN authors a ON a.name = b.author
+ON CONFLICT DO NOTHING;
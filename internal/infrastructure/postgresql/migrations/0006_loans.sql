@@ -0,0 +1,25 @@
+-- 0006: préstamos (checkout/return/renew) que conectan usuarios y libros
+--
+-- 🔒 Reglas garantizadas por la base:
+-- - Un libro no puede tener dos préstamos activos: índice único PARCIAL
+--   sobre book_id solo para las filas con returned_at IS NULL
+-- - El límite de préstamos por usuario lo verifica el repositorio dentro de una
+--   transacción que bloquea la fila del usuario (SELECT ... FOR UPDATE)
+-- - El historial se borra junto con el libro o el usuario (CASCADE)
+
+CREATE TABLE IF NOT EXISTS loans (
+    id UUID PRIMARY KEY,
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    checked_out_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_at TIMESTAMP NOT NULL,
+    returned_at TIMESTAMP,
+    renewals INTEGER NOT NULL DEFAULT 0 CHECK (renewals >= 0),
+    CHECK (due_at > checked_out_at)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_book ON loans (book_id) WHERE returned_at IS NULL;
+
+-- "Préstamos de un usuario" y "préstamos activos de un usuario"
+CREATE INDEX IF NOT EXISTS idx_loans_user ON loans (user_id, checked_out_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loans_due_active ON loans (due_at) WHERE returned_at IS NULL;
@@ -0,0 +1,38 @@
+-- 0007: ejemplares físicos (inventario) separados del registro bibliográfico
+--
+-- 📚 Un libro (books) tiene 0..N ejemplares (copies); lo que se presta es un ejemplar.
+-- 🔒 Un ejemplar no puede tener dos préstamos activos: el índice único parcial pasa
+--   de loans.book_id a loans.copy_id
+
+CREATE TABLE IF NOT EXISTS copies (
+    id UUID PRIMARY KEY,
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    barcode VARCHAR(64) NOT NULL,
+    condition VARCHAR(16) NOT NULL DEFAULT 'good'
+        CHECK (condition IN ('new', 'good', 'fair', 'poor', 'damaged')),
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'available'
+        CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_copies_barcode_unique ON copies (barcode);
+-- Para "ejemplares de un libro" y los contadores de disponibilidad
+CREATE INDEX IF NOT EXISTS idx_copies_book_status ON copies (book_id, status);
+
+-- 📦 Migración de datos: hasta ahora cada libro era un único ejemplar.
+-- Se crea uno por libro (prestado si el libro tenía un préstamo activo).
+INSERT INTO copies (id, book_id, barcode, status)
+SELECT gen_random_uuid(), b.id, 'MIG-' || UPPER(REPLACE(b.id::text, '-', '')),
+    CASE WHEN EXISTS (SELECT 1 FROM loans l WHERE l.book_id = b.id AND l.returned_at IS NULL)
+        THEN 'on_loan' ELSE 'available' END
+FROM books b
+WHERE NOT EXISTS (SELECT 1 FROM copies c WHERE c.book_id = b.id);
+
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS copy_id UUID REFERENCES copies (id) ON DELETE CASCADE;
+UPDATE loans l SET copy_id = c.id FROM copies c WHERE c.book_id = l.book_id AND l.copy_id IS NULL;
+ALTER TABLE loans ALTER COLUMN copy_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_loans_active_book;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_copy ON loans (copy_id) WHERE returned_at IS NULL;
@@ -0,0 +1,31 @@
+-- 0008: cola de reservas (holds) por libro
+--
+-- 📋 Una reserva espera (waiting) hasta que se devuelve un ejemplar; entonces queda lista
+--   (ready) con el ejemplar apartado (copies.status = 'on_hold') hasta expires_at.
+-- 🔒 Un usuario no puede tener dos reservas activas del mismo libro: índice único parcial
+
+ALTER TABLE copies DROP CONSTRAINT IF EXISTS copies_status_check;
+ALTER TABLE copies ADD CONSTRAINT copies_status_check
+    CHECK (status IN ('available', 'on_loan', 'on_hold', 'lost', 'in_repair'));
+
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY,
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    copy_id UUID REFERENCES copies (id) ON DELETE SET NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ready_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_active_user_book ON holds (book_id, user_id)
+    WHERE status IN ('waiting', 'ready');
+-- La cola de un libro, en orden de llegada
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds (book_id, created_at, id) WHERE status = 'waiting';
+-- "Mis reservas"
+CREATE INDEX IF NOT EXISTS idx_holds_user ON holds (user_id, created_at);
+-- El barrido de reservas no retiradas
+CREATE INDEX IF NOT EXISTS idx_holds_expiry ON holds (expires_at) WHERE status = 'ready';
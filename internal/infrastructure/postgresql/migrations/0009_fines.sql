@@ -0,0 +1,27 @@
+-- 0009: multas por devolución tardía (una por préstamo)
+--
+-- 💰 Importes en centavos (BIGINT): nada de tipos de punto flotante para dinero
+-- 🔒 Reglas garantizadas por la base:
+-- - Un préstamo tiene como máximo una multa: loan_id es UNIQUE
+-- - Nunca se paga más de lo que se debe: CHECK (paid_cents <= amount_cents)
+
+CREATE TABLE IF NOT EXISTS fines (
+    id UUID PRIMARY KEY,
+    loan_id UUID NOT NULL UNIQUE REFERENCES loans (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid', 'waived')),
+    overdue_days INTEGER NOT NULL DEFAULT 0 CHECK (overdue_days >= 0),
+    amount_cents BIGINT NOT NULL DEFAULT 0 CHECK (amount_cents >= 0),
+    paid_cents BIGINT NOT NULL DEFAULT 0 CHECK (paid_cents >= 0),
+    final BOOLEAN NOT NULL DEFAULT FALSE,
+    waive_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    CHECK (paid_cents <= amount_cents)
+);
+
+-- "Multas de un usuario" y la suma de su deuda
+CREATE INDEX IF NOT EXISTS idx_fines_user ON fines (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_fines_open_user ON fines (user_id) WHERE status = 'open';
@@ -0,0 +1,19 @@
+-- 0010: credenciales de usuario y sesiones (refresh tokens)
+--
+-- 🔐 Solo se guarda el hash bcrypt de la contraseña; '' = el usuario no puede iniciar sesión
+-- 🔁 Cada refresh token es una fila de sessions: rotarlo la cierra (revoked_at) y anota
+-- cuál la reemplazó (replaced_by)
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    replaced_by UUID
+);
+
+-- "Cerrar todas las sesiones del usuario" (logout global y reutilización de un token)
+CREATE INDEX IF NOT EXISTS idx_sessions_open_user ON sessions (user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,7 @@
+-- 0011: roles de usuario (admin, librarian, member)
+--
+-- 👥 Los usuarios existentes quedan como member; los permisos de cada rol
+-- los define el dominio (domain/role.go), la base solo guarda el rol
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('admin', 'librarian', 'member'));
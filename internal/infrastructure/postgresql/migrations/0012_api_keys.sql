@@ -0,0 +1,20 @@
+-- 0012: API keys para clientes que no pueden hacer login (scripts, sistemas de socios)
+--
+-- 🔑 La clave es "bk_<prefix>_<secreto>": prefix es público y único (con él se busca la fila)
+-- y del total solo se guarda el hash SHA-256 en key_hash
+-- 🛡️ scopes son los permisos que habilita (books:read, books:write, users:admin)
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    key_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
@@ -0,0 +1,11 @@
+-- 0013: versión de libros y usuarios (concurrencia optimista)
+--
+-- 🔢 Empieza en 1 y cada UPDATE la sube en 1. Quien edita manda la versión que leyó
+-- (header If-Match): si ya no es la guardada, otro cambió el registro y la API
+-- responde 412 en vez de pisar sus cambios. Las filas existentes arrancan en 1.
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
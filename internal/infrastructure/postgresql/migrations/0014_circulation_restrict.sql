@@ -0,0 +1,30 @@
+-- 0014: el historial de circulación no se borra con el libro, el usuario o el ejemplar
+--
+-- 🔒 0006, 0007 y 0008 crearon las claves foráneas con ON DELETE CASCADE: borrar un libro
+--   o un usuario se llevaba sus préstamos y reservas. Pasan a RESTRICT: el caso de uso
+--   rechaza antes el borrado con un 409, esto es la red de seguridad.
+-- 📋 Los nombres son los que PostgreSQL les dio por defecto (<tabla>_<columna>_fkey)
+
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_book_id_fkey;
+ALTER TABLE loans ADD CONSTRAINT loans_book_id_fkey
+    FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE RESTRICT;
+
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_user_id_fkey;
+ALTER TABLE loans ADD CONSTRAINT loans_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
+
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_copy_id_fkey;
+ALTER TABLE loans ADD CONSTRAINT loans_copy_id_fkey
+    FOREIGN KEY (copy_id) REFERENCES copies (id) ON DELETE RESTRICT;
+
+ALTER TABLE copies DROP CONSTRAINT IF EXISTS copies_book_id_fkey;
+ALTER TABLE copies ADD CONSTRAINT copies_book_id_fkey
+    FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE RESTRICT;
+
+ALTER TABLE holds DROP CONSTRAINT IF EXISTS holds_book_id_fkey;
+ALTER TABLE holds ADD CONSTRAINT holds_book_id_fkey
+    FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE RESTRICT;
+
+ALTER TABLE holds DROP CONSTRAINT IF EXISTS holds_user_id_fkey;
+ALTER TABLE holds ADD CONSTRAINT holds_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
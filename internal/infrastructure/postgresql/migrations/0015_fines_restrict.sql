@@ -0,0 +1,18 @@
+-- 0015: lo que se debe no se borra con el préstamo, el usuario o el libro
+--
+-- 🔒 0009 creó las claves foráneas de fines con ON DELETE CASCADE: borrar un usuario
+--   se llevaba sus multas impagas. Pasan a RESTRICT: el caso de uso rechaza antes el
+--   borrado con un 409, esto es la red de seguridad.
+-- 📋 Los nombres son los que PostgreSQL les dio por defecto (<tabla>_<columna>_fkey)
+
+ALTER TABLE fines DROP CONSTRAINT IF EXISTS fines_loan_id_fkey;
+ALTER TABLE fines ADD CONSTRAINT fines_loan_id_fkey
+    FOREIGN KEY (loan_id) REFERENCES loans (id) ON DELETE RESTRICT;
+
+ALTER TABLE fines DROP CONSTRAINT IF EXISTS fines_user_id_fkey;
+ALTER TABLE fines ADD CONSTRAINT fines_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
+
+ALTER TABLE fines DROP CONSTRAINT IF EXISTS fines_book_id_fkey;
+ALTER TABLE fines ADD CONSTRAINT fines_book_id_fkey
+    FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE RESTRICT;
@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// whereBuilder arma cláusulas WHERE con parámetros posicionales ($1, $2, ...)
+//
+// 🛡️ Los valores del cliente SIEMPRE viajan como parámetros, nunca concatenados:
+// así evitamos inyección SQL
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg agrega un parámetro y retorna su marcador ($n)
+func (w *whereBuilder) arg(v any) string {
+	w.args = append(w.args, v)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+// ilike agrega "column ILIKE %value%" si value no está vacío
+// Los comodines % y _ del valor se escapan para que se busquen literalmente
+func (w *whereBuilder) ilike(column, value string) {
+	if value == "" {
+		return
+	}
+	w.conds = append(w.conds, column+` ILIKE '%' || `+w.arg(escapeLike(value))+` || '%'`)
+}
+
+// sql retorna la cláusula WHERE completa (o vacía si no hay condiciones)
+func (w *whereBuilder) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// limitOffset agrega LIMIT/OFFSET como parámetros (limit <= 0 = sin límite)
+func (w *whereBuilder) limitOffset(limit, offset int) string {
+	clause := ""
+	if limit > 0 {
+		clause += " LIMIT " + w.arg(limit)
+	}
+	if offset > 0 {
+		clause += " OFFSET " + w.arg(offset)
+	}
+	return clause
+}
+
+// orderBy arma ORDER BY a partir de una lista blanca de columnas
+// Si sort no está en la lista se usa created_at; siempre desempata por id
+func orderBy(columns map[string]string, sort string, desc bool) string {
+	column, ok := columns[sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	return " ORDER BY " + column + direction + ", id" + direction
+}
+
+// escapeLike escapa los comodines de LIKE (\ es el carácter de escape por defecto)
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// buildTSQuery arma una consulta de to_tsquery donde todas las palabras son obligatorias
+// y se aceptan como prefijo: [martin arq] → "martin:* & arq:*"
+//
+// 🛡️ Es seguro porque domain.SearchTerms solo deja letras y dígitos:
+// ningún operador de tsquery (&, |, !, :, paréntesis) llega desde el cliente
+func buildTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// nullTime convierte la fecha cero en NULL para que aplique el DEFAULT de la columna
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// nullString convierte el texto vacío en NULL (ej: un ISBN desconocido no choca con UNIQUE)
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nonNilStrings evita guardar NULL en columnas TEXT[] NOT NULL
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
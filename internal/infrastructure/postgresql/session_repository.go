@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+)
+
+// PostgresSessionRepository implementa SessionRepository usando PostgreSQL
+type PostgresSessionRepository struct {
+	db *sql.DB // Conexión a PostgreSQL
+}
+
+// NewPostgresSessionRepository crea una nueva instancia del repositorio PostgreSQL
+func NewPostgresSessionRepository(db *sql.DB) repository.SessionRepository {
+	return &PostgresSessionRepository{
+		db: db,
+	}
+}
+
+// sessionColumns son las columnas de una sesión, en el orden que espera scanSession
+const sessionColumns = `id, user_id, created_at, expires_at, revoked_at, COALESCE(replaced_by::text, '')`
+
+// scanSession lee una fila con sessionColumns
+func scanSession(row rowScanner) (*domain.Session, error) {
+	var s domain.Session
+	if err := row.Scan(&s.ID, &s.UserID, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt, &s.ReplacedBy); err != nil {
+		return nil, translateSessionError(err)
+	}
+	return &s, nil
+}
+
+// Create guarda una sesión nueva en PostgreSQL
+func (r *PostgresSessionRepository) Create(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	query := `
+		INSERT INTO sessions (id, user_id, created_at, expires_at)
+		VALUES ($1, $2, COALESCE($3, CURRENT_TIMESTAMP), $4)
+		RETURNING ` + sessionColumns
+
+	return scanSession(r.db.QueryRowContext(ctx, query,
+		session.ID, session.UserID, nullTime(session.CreatedAt), session.ExpiresAt))
+}
+
+// GetByID busca una sesión por su ID en PostgreSQL
+func (r *PostgresSessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+	return scanSession(r.db.QueryRowContext(ctx, query, id))
+}
+
+// Revoke cierra la sesión solo si seguía abierta
+//
+// 🔒 "AND revoked_at IS NULL" hace el compare-and-swap en un único UPDATE atómico
+func (r *PostgresSessionRepository) Revoke(ctx context.Context, id, replacedBy string, at time.Time) (*domain.Session, error) {
+	revoked, err := scanSession(r.db.QueryRowContext(ctx, `
+		UPDATE sessions
+		SET revoked_at = $2, replaced_by = $3
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING `+sessionColumns,
+		id, at, nullString(replacedBy)))
+	if !errors.Is(err, domain.ErrSessionNotFound) {
+		return revoked, err
+	}
+
+	// Ninguna fila afectada: ¿no existe o ya estaba cerrada?
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return nil, domain.ErrSessionRevoked
+}
+
+// RevokeAll cierra todas las sesiones abiertas del usuario en PostgreSQL
+func (r *PostgresSessionRepository) RevokeAll(ctx context.Context, userID string, at time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`, userID, at)
+	if err != nil {
+		return 0, translateSessionError(err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, translateSessionError(err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresAPIKeyRepository verifica la búsqueda por prefijo, los scopes, Touch y Revoke
+func TestPostgresAPIKeyRepository(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := openTestDB(t)
+	users := postgresql.NewPostgresUserRepository(db)
+	keys := postgresql.NewPostgresAPIKeyRepository(db)
+	user := &domain.User{ID: uuid.New().String(), Name: "Ana", Email: "ana@example.com"}
+	users.Create(ctx, user)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key := &domain.APIKey{
+		ID: uuid.New().String(), UserID: user.ID, Name: "sync", Prefix: "0123456789ab", Hash: "hash",
+		Scopes: []domain.Permission{domain.PermBooksRead, domain.PermBooksWrite}, CreatedAt: now,
+	}
+
+	// Act + Assert
+	if _, err := keys.Create(ctx, key); err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	if _, err := keys.Create(ctx, &domain.APIKey{ID: uuid.New().String(), UserID: user.ID, Name: "otra", Prefix: key.Prefix, Hash: "h", Scopes: key.Scopes}); !errors.Is(err, domain.ErrAPIKeyAlreadyExists) {
+		t.Errorf("Se esperaba ErrAPIKeyAlreadyExists al repetir el prefijo, pero se obtuvo: %v", err)
+	}
+	found, err := keys.GetByPrefix(ctx, key.Prefix)
+	if err != nil || found.ID != key.ID || len(found.Scopes) != 2 || found.Scopes[1] != domain.PermBooksWrite {
+		t.Errorf("Se esperaba la clave con sus scopes, pero se obtuvo: %+v (err: %v)", found, err)
+	}
+	if err := keys.Touch(ctx, key.ID, now); err != nil {
+		t.Errorf("Touch falló: %v", err)
+	}
+	if revoked, err := keys.Revoke(ctx, key.ID, now); err != nil || revoked.RevokedAt == nil || revoked.LastUsedAt == nil {
+		t.Errorf("Se esperaba la clave revocada y con último uso, pero se obtuvo: %+v (err: %v)", revoked, err)
+	}
+	if _, err := keys.Revoke(ctx, key.ID, now); !errors.Is(err, domain.ErrAPIKeyRevoked) {
+		t.Errorf("Se esperaba ErrAPIKeyRevoked, pero se obtuvo: %v", err)
+	}
+	if _, total, err := keys.List(ctx, domain.APIKeyQuery{Filter: domain.APIKeyFilter{UserID: user.ID}}); err != nil || total != 1 {
+		t.Errorf("Se esperaba 1 clave, pero se obtuvieron %d (err: %v)", total, err)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresAuthorRepository_BookLinks verifica los vínculos libro ↔ autor con roles
+func TestPostgresAuthorRepository_BookLinks(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	authors := postgresql.NewPostgresAuthorRepository(db)
+	marquez := &domain.Author{ID: uuid.New().String(), Name: "Gabriel García Márquez", CreatedAt: time.Now().UTC()}
+	rabassa := &domain.Author{ID: uuid.New().String(), Name: "Gregory Rabassa", CreatedAt: time.Now().UTC()}
+	for _, a := range []*domain.Author{marquez, rabassa} {
+		if _, err := authors.Create(ctx, a); err != nil {
+			t.Fatalf("Create del autor falló: %v", err)
+		}
+	}
+	book := &domain.Book{ID: uuid.New().String(), Title: "One Hundred Years of Solitude", Author: marquez.Name,
+		Authors: []domain.BookAuthor{
+			{AuthorID: marquez.ID, Role: domain.RoleAuthor},
+			{AuthorID: rabassa.ID, Role: domain.RoleTranslator},
+		}}
+	if _, err := books.Create(ctx, book); err != nil {
+		t.Fatalf("Create del libro falló: %v", err)
+	}
+
+	// Act + Assert: los vínculos vuelven en orden de créditos
+	got, err := books.GetByID(ctx, book.ID)
+	if err != nil || len(got.Authors) != 2 || got.Authors[1].AuthorID != rabassa.ID || got.Authors[1].Role != domain.RoleTranslator {
+		t.Fatalf("Se esperaban los 2 vínculos en orden, pero se obtuvo: %+v (err: %v)", got, err)
+	}
+
+	// Filtro por autor (cualquier rol)
+	_, total, err := books.List(ctx, domain.BookQuery{
+		PageRequest: domain.PageRequest{Limit: 10, Sort: domain.SortByTitle},
+		Filter:      domain.BookFilter{AuthorID: rabassa.ID},
+	})
+	if err != nil || total != 1 {
+		t.Errorf("Se esperaba 1 libro del traductor, pero se obtuvo: %d (err: %v)", total, err)
+	}
+
+	// La FK impide borrar un autor con libros
+	if err := authors.Delete(ctx, rabassa.ID); !errors.Is(err, domain.ErrAuthorHasBooks) {
+		t.Errorf("Se esperaba ErrAuthorHasBooks, pero se obtuvo: %v", err)
+	}
+
+	// Al actualizar el libro se reemplazan los vínculos
+	book.Authors = book.Authors[:1]
+	if _, err := books.Update(ctx, book); err != nil {
+		t.Fatalf("Update falló: %v", err)
+	}
+	if err := authors.Delete(ctx, rabassa.ID); err != nil {
+		t.Errorf("Se esperaba poder borrar al traductor desvinculado, pero se obtuvo: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresBookRepository_CRUD recorre el ciclo completo de un libro
+func TestPostgresBookRepository_CRUD(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	book := &domain.Book{ID: uuid.New().String(), Title: "Clean Architecture", Author: "Robert C. Martin"}
+
+	// Act + Assert: Create
+	if _, err := repo.Create(ctx, book); err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+
+	// GetByID
+	found, err := repo.GetByID(ctx, book.ID)
+	if err != nil {
+		t.Fatalf("GetByID falló: %v", err)
+	}
+	if found.Title != book.Title {
+		t.Errorf("Se esperaba título '%s', pero se obtuvo: %s", book.Title, found.Title)
+	}
+
+	// Update con la versión leída: sube a 2; con la vieja, no se pisa nada
+	book.Title = "Clean Architecture (2da edición)"
+	book.Version = found.Version
+	updated, err := repo.Update(ctx, book)
+	if err != nil {
+		t.Fatalf("Update falló: %v", err)
+	}
+	if found.Version != 1 || updated.Version != 2 {
+		t.Errorf("Se esperaban las versiones 1 y 2, pero se obtuvo: %d y %d", found.Version, updated.Version)
+	}
+	if _, err := repo.Update(ctx, &domain.Book{ID: book.ID, Title: "Otro", Author: "Otro", Version: 1}); !errors.Is(err, domain.ErrBookVersionMismatch) {
+		t.Errorf("Se esperaba ErrBookVersionMismatch con la versión vieja, pero se obtuvo: %v", err)
+	}
+	if err := repo.Delete(ctx, book.ID, 1); !errors.Is(err, domain.ErrBookVersionMismatch) {
+		t.Errorf("Se esperaba ErrBookVersionMismatch al borrar con la versión vieja, pero se obtuvo: %v", err)
+	}
+
+	// GetAll
+	books, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll falló: %v", err)
+	}
+	if len(books) != 1 || books[0].Title != book.Title {
+		t.Errorf("Se esperaba 1 libro actualizado, pero se obtuvo: %+v", books)
+	}
+
+	// Delete
+	if err := repo.Delete(ctx, book.ID, 2); err != nil {
+		t.Fatalf("Delete falló: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, book.ID); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound al buscar un libro eliminado, pero se obtuvo: %v", err)
+	}
+}
+
+// TestPostgresBookRepository_BibliographicFields verifica que los datos nuevos se guardan
+// y que el ISBN es único
+func TestPostgresBookRepository_BibliographicFields(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	book := &domain.Book{
+		ID: uuid.New().String(), Title: "Clean Code", Author: "Robert C. Martin",
+		ISBN: "9780132350884", Publisher: "Prentice Hall", PublicationYear: 2008,
+		Language: "en", PageCount: 464, Description: "Un manual de buenas prácticas",
+		Subjects: []string{"Programación", "Calidad"}, Edition: "1ra",
+	}
+
+	// Act
+	if _, err := repo.Create(ctx, book); err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	found, err := repo.GetByID(ctx, book.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetByID falló: %v", err)
+	}
+	if found.ISBN != book.ISBN || found.PageCount != 464 || len(found.Subjects) != 2 || found.Edition != "1ra" {
+		t.Errorf("Se esperaban los datos bibliográficos guardados, pero se obtuvo: %+v", found)
+	}
+
+	// Dos libros sin ISBN no chocan; dos con el mismo ISBN sí
+	for i := 0; i < 2; i++ {
+		if _, err := repo.Create(ctx, &domain.Book{ID: uuid.New().String(), Title: "Sin ISBN", Author: "Anónimo"}); err != nil {
+			t.Fatalf("Se esperaba poder crear libros sin ISBN, pero se obtuvo: %v", err)
+		}
+	}
+	_, err = repo.Create(ctx, &domain.Book{ID: uuid.New().String(), Title: "Otro", Author: "Otro", ISBN: book.ISBN})
+	if !errors.Is(err, domain.ErrISBNAlreadyInUse) {
+		t.Errorf("Se esperaba ErrISBNAlreadyInUse, pero se obtuvo: %v", err)
+	}
+
+	// La importación busca por ISBN
+	if byISBN, err := repo.GetByISBN(ctx, book.ISBN); err != nil || byISBN.ID != book.ID {
+		t.Errorf("Se esperaba encontrar el libro por su ISBN, pero se obtuvo: %v", err)
+	}
+	if _, err := repo.GetByISBN(ctx, "9780134494166"); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound, pero se obtuvo: %v", err)
+	}
+
+	// La descripción también es buscable
+	if hits, total, err := repo.Search(ctx, domain.BookSearchQuery{Text: "practicas"}); err != nil || total != 1 || hits[0].ID != book.ID {
+		t.Errorf("Se esperaba encontrar el libro por su descripción, pero se obtuvo %d (err: %v)", total, err)
+	}
+}
+
+// TestPostgresBookRepository_List verifica filtros, orden y paginación en SQL
+func TestPostgresBookRepository_List(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	for _, title := range []string{"C", "a", "B", "100% Go"} {
+		if _, err := repo.Create(ctx, &domain.Book{ID: uuid.New().String(), Title: title, Author: "Autor"}); err != nil {
+			t.Fatalf("Create falló: %v", err)
+		}
+	}
+
+	// Act: orden por título sin distinguir mayúsculas, segunda página de 2
+	books, total, err := repo.List(ctx, domain.BookQuery{
+		PageRequest: domain.PageRequest{Limit: 2, Offset: 1, Sort: domain.SortByTitle},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("List falló: %v", err)
+	}
+	if total != 4 || len(books) != 2 || books[0].Title != "a" || books[1].Title != "B" {
+		t.Errorf("Se esperaba [a B] de 4, pero se obtuvo %d: %+v", total, books)
+	}
+
+	// El % del filtro se busca literalmente, no como comodín
+	books, total, err = repo.List(ctx, domain.BookQuery{Filter: domain.BookFilter{Title: "0%"}})
+	if err != nil || total != 1 || len(books) != 1 {
+		t.Errorf("Se esperaba 1 libro con '0%%' en el título, pero se obtuvo %d (err: %v)", total, err)
+	}
+}
+
+// TestPostgresBookRepository_Search verifica la búsqueda de texto completo con unaccent
+func TestPostgresBookRepository_Search(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	for _, b := range [][2]string{
+		{"Arquitectura Limpia", "Robert C. Martin"},
+		{"Clean Architecture", "Robert C. Martin"},
+		{"Martín Fierro", "José Hernández"},
+	} {
+		if _, err := repo.Create(ctx, &domain.Book{ID: uuid.New().String(), Title: b[0], Author: b[1]}); err != nil {
+			t.Fatalf("Create falló: %v", err)
+		}
+	}
+
+	// Act
+	hits, total, err := repo.Search(ctx, domain.BookSearchQuery{Text: "martin"})
+
+	// Assert: sin acentos y con el título pesando más que el autor
+	if err != nil {
+		t.Fatalf("Search falló: %v", err)
+	}
+	if total != 3 || hits[0].Title != "Martín Fierro" {
+		t.Errorf("Se esperaba 'Martín Fierro' primero de 3, pero se obtuvo %d: %+v", total, hits)
+	}
+
+	hits, total, err = repo.Search(ctx, domain.BookSearchQuery{Text: "MARTIN arquitec"})
+	if err != nil || total != 1 || hits[0].Title != "Arquitectura Limpia" {
+		t.Errorf("Se esperaba solo 'Arquitectura Limpia', pero se obtuvo %d: %+v (err: %v)", total, hits, err)
+	}
+}
+
+// TestPostgresBookRepository_Suggest verifica el autocompletado con pg_trgm
+func TestPostgresBookRepository_Suggest(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	for _, title := range []string{"Clean Architecture", "Clean Code"} {
+		if _, err := repo.Create(ctx, &domain.Book{ID: uuid.New().String(), Title: title, Author: "Robert C. Martin"}); err != nil {
+			t.Fatalf("Create falló: %v", err)
+		}
+	}
+
+	// Act + Assert: completado de prefijos, agrupando el autor repetido
+	suggestions, err := repo.Suggest(ctx, domain.SuggestQuery{Prefix: "rob mar", Limit: 5})
+	if err != nil {
+		t.Fatalf("Suggest falló: %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Text != "Robert C. Martin" || suggestions[0].Books != 2 {
+		t.Errorf("Se esperaba 'Robert C. Martin' con 2 libros, pero se obtuvo: %+v", suggestions)
+	}
+
+	// Error de tipeo
+	suggestions, err = repo.Suggest(ctx, domain.SuggestQuery{Prefix: "martni", Limit: 5})
+	if err != nil || len(suggestions) == 0 || suggestions[0].Text != "Robert C. Martin" {
+		t.Errorf("Se esperaba 'Robert C. Martin' para 'martni', pero se obtuvo: %+v (err: %v)", suggestions, err)
+	}
+}
+
+// TestPostgresBookRepository_Facets verifica los conteos por idioma base y por autor, y el filtro por idioma
+func TestPostgresBookRepository_Facets(t *testing.T) {
+	// Arrange: un autor con dos roles en el mismo libro cuenta una vez
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	authors := postgresql.NewPostgresAuthorRepository(db)
+	amado := &domain.Author{ID: uuid.New().String(), Name: "Jorge Amado", CreatedAt: time.Now().UTC()}
+	if _, err := authors.Create(ctx, amado); err != nil {
+		t.Fatalf("Create del autor falló: %v", err)
+	}
+	for _, b := range []*domain.Book{
+		{Title: "Gabriela", Language: "pt-BR", Authors: []domain.BookAuthor{{AuthorID: amado.ID, Role: domain.RoleAuthor}}},
+		{Title: "Capitães da Areia", Language: "pt", Authors: []domain.BookAuthor{
+			{AuthorID: amado.ID, Role: domain.RoleAuthor}, {AuthorID: amado.ID, Role: domain.RoleEditor}}},
+		{Title: "Dona Flor", Language: "en"},
+		{Title: "Sin idioma"},
+	} {
+		b.ID, b.Author = uuid.New().String(), "Autor"
+		if _, err := books.Create(ctx, b); err != nil {
+			t.Fatalf("Create falló: %v", err)
+		}
+	}
+
+	// Act
+	facets, err := books.Facets(ctx, domain.BookFilter{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Facets falló: %v", err)
+	}
+	if len(facets.Languages) != 2 || facets.Languages[0] != (domain.FacetCount{Value: "pt", Count: 2}) {
+		t.Errorf("Se esperaban pt (2) y en (1), pero se obtuvo: %+v", facets.Languages)
+	}
+	if len(facets.Authors) != 1 || facets.Authors[0] != (domain.FacetCount{Value: amado.ID, Count: 2}) {
+		t.Errorf("Se esperaba Amado con 2 libros, pero se obtuvo: %+v", facets.Authors)
+	}
+
+	// El idioma base incluye sus variantes; el código completo, solo el suyo
+	for lang, want := range map[string]int{"pt": 2, "PT-br": 1, "en": 1} {
+		_, total, err := books.List(ctx, domain.BookQuery{
+			PageRequest: domain.PageRequest{Limit: 10, Sort: domain.SortByTitle},
+			Filter:      domain.BookFilter{Language: lang},
+		})
+		if err != nil || total != want {
+			t.Errorf("Se esperaban %d libros en %q, pero se obtuvo: %d (err: %v)", want, lang, total, err)
+		}
+	}
+}
+
+// TestPostgresBookRepository_CancelledContext verifica que una query con contexto
+// cancelado no llega a ejecutarse
+func TestPostgresBookRepository_CancelledContext(t *testing.T) {
+	repo := postgresql.NewPostgresBookRepository(openTestDB(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetAll(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Se esperaba context.Canceled, pero se obtuvo: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresCopyRepository_StatusAndCounts verifica barcode único, compare-and-swap y contadores
+func TestPostgresCopyRepository_StatusAndCounts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	copies := postgresql.NewPostgresCopyRepository(db)
+	book := &domain.Book{ID: uuid.New().String(), Title: "Clean Architecture", Author: "Robert C. Martin"}
+	books.Create(ctx, book)
+	newCopy := func(bookID, barcode string) *domain.Copy {
+		return &domain.Copy{ID: uuid.New().String(), BookID: bookID, Barcode: barcode,
+			Condition: domain.ConditionGood, Status: domain.CopyAvailable}
+	}
+
+	// Act + Assert: alta, código repetido y libro inexistente
+	first, err := copies.Create(ctx, newCopy(book.ID, "CA-1"))
+	if err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	copies.Create(ctx, newCopy(book.ID, "CA-2"))
+	if _, err := copies.Create(ctx, newCopy(book.ID, "CA-1")); !errors.Is(err, domain.ErrBarcodeAlreadyInUse) {
+		t.Errorf("Se esperaba ErrBarcodeAlreadyInUse, pero se obtuvo: %v", err)
+	}
+	if _, err := copies.Create(ctx, newCopy(uuid.New().String(), "CA-3")); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound, pero se obtuvo: %v", err)
+	}
+
+	// Compare-and-swap: el segundo cambio desde available falla
+	if _, err := copies.SetStatus(ctx, first.ID, domain.CopyAvailable, domain.CopyLost); err != nil {
+		t.Fatalf("SetStatus falló: %v", err)
+	}
+	if _, err := copies.SetStatus(ctx, first.ID, domain.CopyAvailable, domain.CopyInRepair); !errors.Is(err, domain.ErrCopyStatusChanged) {
+		t.Errorf("Se esperaba ErrCopyStatusChanged, pero se obtuvo: %v", err)
+	}
+	if _, err := copies.SetStatus(ctx, uuid.New().String(), domain.CopyAvailable, domain.CopyLost); !errors.Is(err, domain.ErrCopyNotFound) {
+		t.Errorf("Se esperaba ErrCopyNotFound, pero se obtuvo: %v", err)
+	}
+
+	// Contadores en una consulta (los libros sin ejemplares aparecen en cero)
+	empty := uuid.New().String()
+	counts, err := copies.Counts(ctx, book.ID, empty)
+	want := domain.CopyCounts{Total: 2, Available: 1, Lost: 1}
+	if err != nil || counts[book.ID] != want || counts[empty] != (domain.CopyCounts{}) {
+		t.Errorf("Se esperaba %+v, pero se obtuvo: %+v (err: %v)", want, counts, err)
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresFineRepository_PayAndClose verifica multa única por préstamo, pagos atómicos y deuda
+func TestPostgresFineRepository_PayAndClose(t *testing.T) {
+	// Arrange: un préstamo devuelto tarde
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	users := postgresql.NewPostgresUserRepository(db)
+	copies := postgresql.NewPostgresCopyRepository(db)
+	loans := postgresql.NewPostgresLoanRepository(db)
+	fines := postgresql.NewPostgresFineRepository(db)
+	book := &domain.Book{ID: uuid.New().String(), Title: "Dune", Author: "Frank Herbert"}
+	user := &domain.User{ID: uuid.New().String(), Name: "Ana", Email: "ana@example.com"}
+	books.Create(ctx, book)
+	users.Create(ctx, user)
+	cp := &domain.Copy{ID: uuid.New().String(), BookID: book.ID, Barcode: "DU-1",
+		Condition: domain.ConditionGood, Status: domain.CopyAvailable}
+	copies.Create(ctx, cp)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	loan, err := loans.Checkout(ctx, &domain.Loan{ID: uuid.New().String(), BookID: book.ID, CopyID: cp.ID, UserID: user.ID,
+		Status: domain.LoanActive, CheckedOutAt: now, DueAt: now.Add(time.Hour)}, domain.CopyAvailable, 5)
+	if err != nil {
+		t.Fatalf("Checkout falló: %v", err)
+	}
+	newFine := func() *domain.Fine {
+		return &domain.Fine{ID: uuid.New().String(), LoanID: loan.ID, UserID: user.ID, BookID: book.ID,
+			Status: domain.FineOpen, OverdueDays: 3, Amount: 300, Final: true, CreatedAt: now, UpdatedAt: now}
+	}
+
+	// Act + Assert: una sola multa por préstamo
+	fine, err := fines.Create(ctx, newFine())
+	if err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	if _, err := fines.Create(ctx, newFine()); !errors.Is(err, domain.ErrFineAlreadyExists) {
+		t.Errorf("Se esperaba ErrFineAlreadyExists, pero se obtuvo: %v", err)
+	}
+
+	// Pagos: nunca más de lo que se debe
+	if paid, err := fines.Pay(ctx, fine.ID, 100, now); err != nil || paid.Paid != 100 {
+		t.Errorf("Se esperaba 100 pagado, pero se obtuvo: %+v (err: %v)", paid, err)
+	}
+	if _, err := fines.Pay(ctx, fine.ID, 201, now); !errors.Is(err, domain.ErrFineOverpayment) {
+		t.Errorf("Se esperaba ErrFineOverpayment, pero se obtuvo: %v", err)
+	}
+	if debt, err := fines.Debt(ctx, user.ID); err != nil || debt != 200 {
+		t.Errorf("Se esperaba una deuda de 200, pero se obtuvo: %d (err: %v)", debt, err)
+	}
+
+	// Perdón: cerrada no admite más cambios y deja de contar como deuda
+	if waived, err := fines.Close(ctx, fine.ID, domain.FineWaived, "Primera vez", now); err != nil || waived.ClosedAt == nil {
+		t.Errorf("Se esperaba la multa perdonada, pero se obtuvo: %+v (err: %v)", waived, err)
+	}
+	if _, err := fines.Pay(ctx, fine.ID, 1, now); !errors.Is(err, domain.ErrFineNotOpen) {
+		t.Errorf("Se esperaba ErrFineNotOpen, pero se obtuvo: %v", err)
+	}
+	if debt, _ := fines.Debt(ctx, user.ID); debt != 0 {
+		t.Errorf("Se esperaba deuda cero, pero se obtuvo: %d", debt)
+	}
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresHoldRepository_Queue verifica la cola de reservas: unicidad, posiciones y transiciones
+func TestPostgresHoldRepository_Queue(t *testing.T) {
+	// Arrange: un libro con un ejemplar y tres lectores
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	users := postgresql.NewPostgresUserRepository(db)
+	copies := postgresql.NewPostgresCopyRepository(db)
+	holds := postgresql.NewPostgresHoldRepository(db)
+	book := &domain.Book{ID: uuid.New().String(), Title: "Rayuela", Author: "Julio Cortázar"}
+	books.Create(ctx, book)
+	bookCopy := &domain.Copy{ID: uuid.New().String(), BookID: book.ID, Barcode: "RAY-1",
+		Condition: domain.ConditionGood, Status: domain.CopyAvailable}
+	copies.Create(ctx, bookCopy)
+	var readers []*domain.User
+	for _, email := range []string{"ana@example.com", "beto@example.com", "carla@example.com"} {
+		user := &domain.User{ID: uuid.New().String(), Name: "Lector", Email: email}
+		if _, err := users.Create(ctx, user); err != nil {
+			t.Fatalf("Create de usuario falló: %v", err)
+		}
+		readers = append(readers, user)
+	}
+
+	// Act: tres reservas en orden de llegada
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	var queue []*domain.Hold
+	for i, user := range readers {
+		hold, err := holds.Create(ctx, &domain.Hold{ID: uuid.New().String(), BookID: book.ID, UserID: user.ID,
+			Status: domain.HoldWaiting, CreatedAt: now.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatalf("Create falló: %v", err)
+		}
+		queue = append(queue, hold)
+	}
+
+	// Assert: una sola reserva activa por usuario y libro; referencias inexistentes
+	_, err := holds.Create(ctx, &domain.Hold{ID: uuid.New().String(), BookID: book.ID, UserID: readers[0].ID, Status: domain.HoldWaiting})
+	if !errors.Is(err, domain.ErrHoldAlreadyExists) {
+		t.Errorf("Se esperaba ErrHoldAlreadyExists, pero se obtuvo: %v", err)
+	}
+	_, err = holds.Create(ctx, &domain.Hold{ID: uuid.New().String(), BookID: uuid.New().String(), UserID: readers[0].ID, Status: domain.HoldWaiting})
+	if !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound, pero se obtuvo: %v", err)
+	}
+
+	// Posiciones en la cola, en orden de llegada
+	positions, err := holds.Positions(ctx, queue[0].ID, queue[2].ID)
+	if err != nil || positions[queue[0].ID] != 1 || positions[queue[2].ID] != 3 {
+		t.Errorf("Se esperaban las posiciones 1 y 3, pero se obtuvo: %v (err: %v)", positions, err)
+	}
+
+	// La primera pasa a ready con el ejemplar apartado; la segunda avanza al primer lugar
+	ready := *queue[0]
+	expires := now.Add(72 * time.Hour)
+	ready.Status, ready.CopyID, ready.ReadyAt, ready.ExpiresAt = domain.HoldReady, bookCopy.ID, &now, &expires
+	got, err := holds.Transition(ctx, &ready, domain.HoldWaiting)
+	if err != nil || got.Status != domain.HoldReady || got.CopyID != bookCopy.ID || got.ExpiresAt == nil {
+		t.Errorf("Se esperaba la reserva ready con el ejemplar, pero se obtuvo: %+v (err: %v)", got, err)
+	}
+	if _, err := holds.Transition(ctx, &ready, domain.HoldWaiting); !errors.Is(err, domain.ErrHoldStatusChanged) {
+		t.Errorf("Se esperaba ErrHoldStatusChanged al repetir la transición, pero se obtuvo: %v", err)
+	}
+	if positions, _ := holds.Positions(ctx, queue[1].ID); positions[queue[1].ID] != 1 {
+		t.Errorf("Se esperaba la segunda reserva en el primer lugar, pero se obtuvo: %v", positions)
+	}
+
+	// El barrido encuentra las reservas listas que vencen
+	expiring, total, err := holds.List(ctx, domain.HoldQuery{
+		PageRequest: domain.PageRequest{Limit: 10},
+		Filter:      domain.HoldFilter{Status: domain.HoldReady, ExpiresBefore: expires.Add(time.Second)},
+	})
+	if err != nil || total != 1 || expiring[0].ID != queue[0].ID {
+		t.Errorf("Se esperaba 1 reserva por vencer, pero se obtuvo %d: %+v (err: %v)", total, expiring, err)
+	}
+
+	// Una reserva es historial: el usuario no se borra (ON DELETE RESTRICT)
+	if err := users.Delete(ctx, readers[2].ID, 0); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Se esperaba un conflicto al borrar un usuario con reservas, pero se obtuvo: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresLoanRepository_Rules verifica las reglas atómicas de préstamo en PostgreSQL
+func TestPostgresLoanRepository_Rules(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := openTestDB(t)
+	books := postgresql.NewPostgresBookRepository(db)
+	users := postgresql.NewPostgresUserRepository(db)
+	loans := postgresql.NewPostgresLoanRepository(db)
+	copies := postgresql.NewPostgresCopyRepository(db)
+	book := &domain.Book{ID: uuid.New().String(), Title: "Refactoring", Author: "Martin Fowler"}
+	other := &domain.Book{ID: uuid.New().String(), Title: "TDD", Author: "Kent Beck"}
+	user := &domain.User{ID: uuid.New().String(), Name: "Ana", Email: "ana@example.com"}
+	books.Create(ctx, book)
+	books.Create(ctx, other)
+	users.Create(ctx, user)
+	bookCopy := &domain.Copy{ID: uuid.New().String(), BookID: book.ID, Barcode: "RF-1",
+		Condition: domain.ConditionGood, Status: domain.CopyAvailable}
+	otherCopy := &domain.Copy{ID: uuid.New().String(), BookID: other.ID, Barcode: "TDD-1",
+		Condition: domain.ConditionGood, Status: domain.CopyAvailable}
+	copies.Create(ctx, bookCopy)
+	copies.Create(ctx, otherCopy)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	newLoan := func(cp *domain.Copy) *domain.Loan {
+		return &domain.Loan{ID: uuid.New().String(), BookID: cp.BookID, CopyID: cp.ID, UserID: user.ID,
+			Status: domain.LoanActive, CheckedOutAt: now, DueAt: now.Add(24 * time.Hour)}
+	}
+
+	// Act + Assert: préstamo, ejemplar ocupado y límite del usuario
+	loan, err := loans.Checkout(ctx, newLoan(bookCopy), domain.CopyAvailable, 1)
+	if err != nil {
+		t.Fatalf("Checkout falló: %v", err)
+	}
+	if got, _ := copies.GetByID(ctx, bookCopy.ID); got == nil || got.Status != domain.CopyOnLoan {
+		t.Errorf("Se esperaba el ejemplar on_loan, pero se obtuvo: %+v", got)
+	}
+	if _, err := loans.Checkout(ctx, newLoan(bookCopy), domain.CopyAvailable, 5); !errors.Is(err, domain.ErrCopyNotAvailable) {
+		t.Errorf("Se esperaba ErrCopyNotAvailable, pero se obtuvo: %v", err)
+	}
+	if _, err := loans.Checkout(ctx, newLoan(otherCopy), domain.CopyAvailable, 1); !errors.Is(err, domain.ErrLoanLimitReached) {
+		t.Errorf("Se esperaba ErrLoanLimitReached, pero se obtuvo: %v", err)
+	}
+
+	// Renovación con límite
+	if renewed, err := loans.Renew(ctx, loan.ID, now.Add(48*time.Hour), 1); err != nil || renewed.Renewals != 1 {
+		t.Errorf("Se esperaba 1 renovación, pero se obtuvo: %+v (err: %v)", renewed, err)
+	}
+	if _, err := loans.Renew(ctx, loan.ID, now.Add(72*time.Hour), 1); !errors.Is(err, domain.ErrRenewalLimitReached) {
+		t.Errorf("Se esperaba ErrRenewalLimitReached, pero se obtuvo: %v", err)
+	}
+
+	// Devolución única
+	if returned, err := loans.Return(ctx, loan.ID, now.Add(time.Hour)); err != nil || returned.Status != domain.LoanReturned {
+		t.Errorf("Se esperaba el préstamo devuelto, pero se obtuvo: %+v (err: %v)", returned, err)
+	}
+	if _, err := loans.Return(ctx, loan.ID, now); !errors.Is(err, domain.ErrLoanAlreadyReturned) {
+		t.Errorf("Se esperaba ErrLoanAlreadyReturned, pero se obtuvo: %v", err)
+	}
+	if got, _ := copies.GetByID(ctx, bookCopy.ID); got == nil || got.Status != domain.CopyAvailable {
+		t.Errorf("Se esperaba el ejemplar disponible tras la devolución, pero se obtuvo: %+v", got)
+	}
+
+	// Historial del usuario
+	_, total, err := loans.List(ctx, domain.LoanQuery{
+		PageRequest: domain.PageRequest{Limit: 10, Sort: domain.SortByDueAt},
+		Filter:      domain.LoanFilter{UserID: user.ID, Status: domain.LoanReturned},
+	})
+	if err != nil || total != 1 {
+		t.Errorf("Se esperaba 1 préstamo devuelto, pero se obtuvo: %d (err: %v)", total, err)
+	}
+}
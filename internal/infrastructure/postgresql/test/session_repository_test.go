@@ -0,0 +1,50 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresSessionRepository_Revoke verifica el cierre compare-and-swap de sesiones
+func TestPostgresSessionRepository_Revoke(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := openTestDB(t)
+	users := postgresql.NewPostgresUserRepository(db)
+	sessions := postgresql.NewPostgresSessionRepository(db)
+	user := &domain.User{ID: uuid.New().String(), Name: "Ana", Email: "ana@example.com"}
+	users.Create(ctx, user)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	newSession := func() *domain.Session {
+		return &domain.Session{ID: uuid.New().String(), UserID: user.ID, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	}
+	first, err := sessions.Create(ctx, newSession())
+	if err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	sessions.Create(ctx, newSession())
+
+	// Act + Assert: la primera rotación gana, la segunda ve la sesión cerrada
+	next := uuid.New().String()
+	if revoked, err := sessions.Revoke(ctx, first.ID, next, now); err != nil || revoked.ReplacedBy != next || revoked.RevokedAt == nil {
+		t.Errorf("Se esperaba la sesión cerrada y reemplazada, pero se obtuvo: %+v (err: %v)", revoked, err)
+	}
+	if _, err := sessions.Revoke(ctx, first.ID, "", now); !errors.Is(err, domain.ErrSessionRevoked) {
+		t.Errorf("Se esperaba ErrSessionRevoked, pero se obtuvo: %v", err)
+	}
+	if _, err := sessions.Revoke(ctx, uuid.New().String(), "", now); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("Se esperaba ErrSessionNotFound, pero se obtuvo: %v", err)
+	}
+
+	// Solo queda abierta la segunda
+	if n, err := sessions.RevokeAll(ctx, user.ID, now); err != nil || n != 1 {
+		t.Errorf("Se esperaba cerrar 1 sesión, pero se cerraron %d (err: %v)", n, err)
+	}
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+
+	"github.com/google/uuid"
+)
+
+// TestPostgresUserRepository_CRUD recorre el ciclo completo de un usuario
+func TestPostgresUserRepository_CRUD(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := postgresql.NewPostgresUserRepository(openTestDB(t))
+	user := &domain.User{ID: uuid.New().String(), Name: "Juan Pérez", Email: "juan@example.com", PasswordHash: "$2a$04$hash"}
+
+	// Act + Assert
+	if _, err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create falló: %v", err)
+	}
+	if _, err := repo.Update(ctx, &domain.User{ID: user.ID, Name: "Juan P.", Email: user.Email}); err != nil {
+		t.Fatalf("Update falló: %v", err)
+	}
+	if found, err := repo.GetByEmail(ctx, user.Email); err != nil || found.ID != user.ID || found.PasswordHash != user.PasswordHash {
+		t.Errorf("Se esperaba el usuario con su hash intacto tras Update, pero se obtuvo: %+v (err: %v)", found, err)
+	}
+	if found, _ := repo.GetByID(ctx, user.ID); found == nil || found.Role != domain.RoleMember {
+		t.Errorf("Se esperaba el rol member por defecto, pero se obtuvo: %+v", found)
+	}
+	if updated, err := repo.UpdateRole(ctx, user.ID, domain.RoleLibrarian); err != nil || updated.Role != domain.RoleLibrarian || updated.Version != 3 {
+		t.Errorf("Se esperaba el rol librarian en la versión 3, pero se obtuvo: %+v (err: %v)", updated, err)
+	}
+	if _, err := repo.UpdateRole(ctx, uuid.New().String(), domain.RoleAdmin); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Se esperaba ErrUserNotFound, pero se obtuvo: %v", err)
+	}
+	if _, err := repo.Create(ctx, &domain.User{ID: uuid.New().String(), Name: "Otro", Email: user.Email}); !errors.Is(err, domain.ErrEmailAlreadyInUse) {
+		t.Errorf("Se esperaba ErrEmailAlreadyInUse al repetir el email, pero se obtuvo: %v", err)
+	}
+	if err := repo.Delete(ctx, user.ID, 0); err != nil {
+		t.Fatalf("Delete falló: %v", err)
+	}
+	if err := repo.Delete(ctx, user.ID, 0); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Se esperaba un error NotFound al eliminar un usuario inexistente, pero se obtuvo: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "no-es-un-uuid"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Se esperaba ErrUserNotFound para un ID mal formado, pero se obtuvo: %v", err)
+	}
+}
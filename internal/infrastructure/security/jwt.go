@@ -0,0 +1,124 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTManager firma y verifica tokens JWT con HMAC-SHA256
+//
+// 🔑 Rotación de claves:
+// - Cada token lleva en el header el kid de la clave que lo firmó
+// - Se firma SIEMPRE con la clave activa
+// - Se verifica con la clave que indica el kid, mientras siga configurada
+// Así se puede agregar una clave nueva, activarla, y retirar la vieja cuando
+// vencen los últimos tokens que firmó, sin cerrar la sesión de nadie.
+type JWTManager struct {
+	keys   map[string][]byte // Claves por kid
+	active string            // kid de la clave con la que se firma
+	issuer string            // Claim iss que se emite y se exige
+	now    func() time.Time  // Reloj para validar exp (inyectable en tests)
+}
+
+// jwtClaims es el formato de los claims dentro del JWT
+// 💡 token_use impide usar un refresh token como access token (y al revés)
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Use domain.TokenKind `json:"token_use"`
+}
+
+// NewJWTManager crea el firmador con las claves configuradas
+//
+// 🎲 Sin claves genera una aleatoria en memoria: los tokens dejan de valer al reiniciar
+// 💡 now es el reloj con el que se valida el vencimiento (nil = time.Now)
+func NewJWTManager(cfg config.AuthConfig, now func() time.Time) (*JWTManager, error) {
+	if now == nil {
+		now = time.Now
+	}
+	m := &JWTManager{
+		keys:   make(map[string][]byte, len(cfg.Keys)),
+		active: cfg.ActiveKey,
+		issuer: cfg.Issuer,
+		now:    now,
+	}
+	for _, key := range cfg.Keys {
+		m.keys[key.ID] = key.Secret
+	}
+
+	if len(m.keys) == 0 {
+		secret := make([]byte, config.MinSigningKeyLength)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("security: no se pudo generar la clave de firma: %w", err)
+		}
+		m.active = "ephemeral-" + hex.EncodeToString(secret[:4])
+		m.keys[m.active] = secret
+	}
+	if _, ok := m.keys[m.active]; !ok {
+		return nil, fmt.Errorf("security: la clave activa %q no está configurada", m.active)
+	}
+	return m, nil
+}
+
+// Issue firma los claims con la clave activa
+func (m *JWTManager) Issue(claims domain.TokenClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.ID,
+			Subject:   claims.Subject,
+			Issuer:    m.issuer,
+			IssuedAt:  jwt.NewNumericDate(claims.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+		Use: claims.Kind,
+	})
+	token.Header["kid"] = m.active
+
+	signed, err := token.SignedString(m.keys[m.active])
+	if err != nil {
+		return "", domain.NewInternalError("no se pudo firmar el token", err)
+	}
+	return signed, nil
+}
+
+// Parse verifica firma, algoritmo, emisor y vencimiento, y retorna los claims
+//
+// 🚨 Cualquier problema (firma, kid desconocido, vencido, mal formado) es domain.ErrInvalidToken:
+// al cliente no le damos pistas de por qué falló
+func (m *JWTManager) Parse(raw string) (*domain.TokenClaims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, m.key,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil || claims.ID == "" || claims.Subject == "" || claims.IssuedAt == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return &domain.TokenClaims{
+		ID:        claims.ID,
+		Subject:   claims.Subject,
+		Kind:      claims.Use,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// key elige la clave de verificación según el kid del header
+func (m *JWTManager) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	secret, ok := m.keys[kid]
+	if !ok {
+		return nil, errors.New("kid desconocido")
+	}
+	return secret, nil
+}
@@ -0,0 +1,38 @@
+// Package security implementa los puertos criptográficos de los casos de uso:
+// hash de contraseñas (bcrypt) y firma de tokens (JWT)
+//
+// 🎯 Los casos de uso solo conocen las interfaces usecase.PasswordHasher y usecase.TokenManager;
+// cambiar bcrypt por argon2 o HS256 por RS256 se hace acá, sin tocar la lógica de negocio
+package security
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher calcula y verifica hashes bcrypt
+//
+// 🔐 bcrypt incluye la sal y el costo dentro del propio hash ($2a$10$...):
+// subir el costo no invalida los hashes viejos, que se siguen verificando con el suyo
+type BcryptHasher struct {
+	cost int // Cada +1 duplica el tiempo de cálculo
+}
+
+// NewBcryptHasher crea un hasher con el costo indicado (bcrypt.MinCost..bcrypt.MaxCost)
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash calcula el hash de la contraseña
+// ⚠️ bcrypt solo mira los primeros 72 bytes: el caso de uso rechaza contraseñas más largas
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify indica si la contraseña corresponde al hash (la comparación es de tiempo constante)
+func (h *BcryptHasher) Verify(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
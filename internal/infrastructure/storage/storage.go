@@ -0,0 +1,98 @@
+// Package storage elige y construye la implementación de persistencia según la configuración
+//
+// 🎯 ¿Por qué un paquete aparte?
+// - main.go solo pide "dame los repositorios" sin saber si son de memoria o PostgreSQL
+// - La decisión memory vs postgres queda en UN solo lugar
+// - Otros puntos de entrada (CLI, workers, etc.) pueden reutilizar el mismo arranque
+//
+// 🔄 Flujo:
+// config.StorageConfig → storage.New() → Repositories{Books, Users, Authors, Loans, Copies, Holds, Fines, Sessions, APIKeys}
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/postgresql"
+	"go-book-clean-architecture-api/internal/repository"
+)
+
+// Repositories agrupa todos los repositorios de la aplicación
+//
+// 💡 Los consumidores solo ven las interfaces de repository,
+// nunca las implementaciones concretas
+type Repositories struct {
+	Books    repository.BookRepository
+	Users    repository.UserRepository
+	Authors  repository.AuthorRepository
+	Loans    repository.LoanRepository
+	Copies   repository.CopyRepository
+	Holds    repository.HoldRepository
+	Fines    repository.FineRepository
+	Sessions repository.SessionRepository
+	APIKeys  repository.APIKeyRepository
+
+	db *sql.DB // Solo se usa con el driver postgres
+}
+
+// New construye los repositorios del driver configurado
+//
+// 🔧 Con "postgres" además:
+// - Abre el pool de conexiones y hace ping (falla rápido si la base no responde)
+// - Aplica las migraciones pendientes si AutoMigrate está activo
+func New(ctx context.Context, cfg config.StorageConfig) (*Repositories, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Driver {
+	case config.StoragePostgres:
+		db, err := postgresql.Open(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.AutoMigrate {
+			if err := postgresql.Migrate(ctx, db); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+		return &Repositories{
+			Books:    postgresql.NewPostgresBookRepository(db),
+			Users:    postgresql.NewPostgresUserRepository(db),
+			Authors:  postgresql.NewPostgresAuthorRepository(db),
+			Loans:    postgresql.NewPostgresLoanRepository(db),
+			Copies:   postgresql.NewPostgresCopyRepository(db),
+			Holds:    postgresql.NewPostgresHoldRepository(db),
+			Fines:    postgresql.NewPostgresFineRepository(db),
+			Sessions: postgresql.NewPostgresSessionRepository(db),
+			APIKeys:  postgresql.NewPostgresAPIKeyRepository(db),
+			db:       db,
+		}, nil
+
+	default:
+		// Los préstamos cambian el estado de los ejemplares: comparten el mismo repositorio
+		copies := memory.NewInMemoryCopyRepository()
+		return &Repositories{
+			Books:    memory.NewInMemoryBookRepository(),
+			Users:    memory.NewInMemoryUserRepository(),
+			Authors:  memory.NewInMemoryAuthorRepository(),
+			Loans:    memory.NewInMemoryLoanRepository(copies),
+			Copies:   copies,
+			Holds:    memory.NewInMemoryHoldRepository(),
+			Fines:    memory.NewInMemoryFineRepository(),
+			Sessions: memory.NewInMemorySessionRepository(),
+			APIKeys:  memory.NewInMemoryAPIKeyRepository(),
+		}, nil
+	}
+}
+
+// Close libera los recursos del backend (el pool de conexiones en PostgreSQL)
+func (r *Repositories) Close() error {
+	if r.db != nil {
+		return r.db.Close()
+	}
+	return nil
+}
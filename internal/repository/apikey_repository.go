@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// APIKeyRepository define el contrato para las operaciones de persistencia de API keys
+//
+// 🔍 Las claves se buscan por su prefijo (GetByPrefix): es la parte pública y única,
+// así que basta un índice para encontrar la clave y después comparar el hash
+type APIKeyRepository interface {
+	// Create guarda una API key nueva
+	// 🔍 Retorna domain.ErrAPIKeyAlreadyExists si el ID o el prefijo ya existen
+	Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+
+	// GetByID busca una API key por su ID
+	// 🔍 Retorna domain.ErrAPIKeyNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+
+	// GetByPrefix busca una API key por su prefijo
+	// 🔍 Retorna domain.ErrAPIKeyNotFound si no existe
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+
+	// List retorna una página de API keys filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.APIKeyQuery) ([]*domain.APIKey, int, error)
+
+	// Revoke revoca la clave solo si seguía activa (compare-and-swap)
+	// 🔍 Retorna domain.ErrAPIKeyRevoked si ya estaba revocada
+	Revoke(ctx context.Context, id string, at time.Time) (*domain.APIKey, error)
+
+	// Touch anota el último uso de la clave
+	Touch(ctx context.Context, id string, at time.Time) error
+}
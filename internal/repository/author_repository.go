@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// AuthorRepository define el contrato para las operaciones de persistencia de autores
+//
+// 🔗 Los vínculos libro ↔ autor NO están aquí: se guardan con el libro
+// (BookRepository, campo Book.Authors) y se consultan con BookFilter.AuthorID
+type AuthorRepository interface {
+	// Create almacena un nuevo autor
+	Create(ctx context.Context, author *domain.Author) (*domain.Author, error)
+
+	// GetByID busca un autor por su ID único
+	// 🔍 Retorna domain.ErrAuthorNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Author, error)
+
+	// GetByIDs busca varios autores en UNA sola operación
+	// 📦 Los IDs inexistentes se omiten (no es error); el orden del resultado no está garantizado
+	// 💡 Evita el problema N+1 al completar los nombres de los autores de una página de libros
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Author, error)
+
+	// List retorna una página de autores filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.AuthorQuery) ([]*domain.Author, int, error)
+
+	// Update modifica un autor existente
+	Update(ctx context.Context, author *domain.Author) (*domain.Author, error)
+
+	// Delete elimina un autor por su ID
+	// ⚠️ Que no tenga libros vinculados lo verifica el caso de uso
+	Delete(ctx context.Context, id string) error
+}
@@ -15,7 +15,11 @@
 // 💡 REGLA DE ORO: "Depend on abstractions, not concretions"
 package repository
 
-import "go-book-clean-architecture-api/internal/domain"
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
 
 // BookRepository define el CONTRATO para las operaciones de persistencia de libros
 //
@@ -31,26 +35,64 @@ import "go-book-clean-architecture-api/internal/domain"
 // - El caso de uso NO conoce si usamos memoria, PostgreSQL, etc.
 // - Podemos cambiar la implementación sin tocar la lógica de negocio
 // - Podemos testear fácilmente usando mocks
+//
+// ⏱️ ¿Por qué todos los métodos reciben context.Context?
+// - Si la petición HTTP se cancela o vence su deadline, el trabajo en la DB se aborta
+// - Las implementaciones DEBEN respetar ctx (las de memoria también)
+// - Es el primer parámetro por convención en Go
 type BookRepository interface {
 	// Create almacena un nuevo libro y retorna el libro creado o un error
 	// 📝 Nota: Recibe una entidad completa, no campos separados
-	Create(book *domain.Book) (*domain.Book, error)
+	// 🔑 Un ISBN ya usado por otro libro → domain.ErrISBNAlreadyInUse (también en Update)
+	Create(ctx context.Context, book *domain.Book) (*domain.Book, error)
 
 	// GetByID busca un libro por su ID único
 	// 🔍 Retorna error si el libro no existe
-	GetByID(id string) (*domain.Book, error)
+	GetByID(ctx context.Context, id string) (*domain.Book, error)
+
+	// GetByIDs busca varios libros en UNA sola operación
+	// 📦 Los IDs inexistentes se omiten (no es error); el orden del resultado no está garantizado
+	// 💡 Lo usa el dataloader de GraphQL para no pedir los libros de a uno (problema N+1)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Book, error)
+
+	// GetByISBN busca un libro por su ISBN-13 normalizado (ver domain.NormalizeISBN)
+	// 🔍 Retorna domain.ErrBookNotFound si ningún libro lo usa
+	// 💡 La usa la importación masiva para actualizar por ISBN (upsert)
+	GetByISBN(ctx context.Context, isbn string) (*domain.Book, error)
 
 	// GetAll retorna todos los libros disponibles
-	// 📚 En aplicaciones reales, implementarías paginación aquí
-	GetAll() ([]*domain.Book, error)
+	// ⚠️ Sin límite: para listados de cara al cliente usa List
+	GetAll(ctx context.Context) ([]*domain.Book, error)
+
+	// List retorna una página de libros filtrada y ordenada, junto con el total
+	// 📄 Usa q.Limit, q.Offset, q.Sort, q.Desc y q.Filter (el cursor ya viene resuelto)
+	// 🔁 El orden debe ser estable: a igual valor de ordenamiento, desempata por ID
+	List(ctx context.Context, q domain.BookQuery) ([]*domain.Book, int, error)
+
+	// Facets cuenta los libros que cumplen el filtro por idioma base y por autor vinculado
+	// 🧭 Ordenadas de mayor a menor cantidad (a igual cantidad, por valor); sin Label:
+	// los nombres de los autores los completa el caso de uso. Los libros sin idioma no cuentan
+	Facets(ctx context.Context, filter domain.BookFilter) (*domain.BookFacets, error)
+
+	// Search busca libros por texto libre, ordenados por relevancia, junto con el total
+	// 🔎 Sin distinguir mayúsculas ni acentos; deben coincidir TODAS las palabras
+	// (ver domain.SearchTerms) en el título, el autor, la descripción o las materias
+	Search(ctx context.Context, q domain.BookSearchQuery) ([]domain.BookHit, int, error)
+
+	// Suggest retorna hasta q.Limit títulos/autores distintos para autocompletar q.Prefix
+	// ⌨️ Completa prefijos de palabras y tolera errores de tipeo (similitud de trigramas)
+	Suggest(ctx context.Context, q domain.SuggestQuery) ([]domain.Suggestion, error)
 
 	// Update modifica un libro existente
 	// ✏️ Debe verificar que el libro existe antes de actualizar
-	Update(book *domain.Book) (*domain.Book, error)
+	// 🔢 book.Version es la versión que leyó el cliente (0 = sin verificar): si la guardada
+	// es otra, retorna domain.ErrBookVersionMismatch; si no, la sube en 1
+	Update(ctx context.Context, book *domain.Book) (*domain.Book, error)
 
 	// Delete elimina un libro por su ID
 	// 🗑️ Retorna error si el libro no existe
-	Delete(id string) error
+	// 🔢 version funciona como en Update (0 = sin verificar)
+	Delete(ctx context.Context, id string, version int) error
 }
 
 // UserRepository define el contrato para las operaciones de persistencia de usuarios
@@ -66,19 +108,37 @@ type BookRepository interface {
 // - Cada uno enfocado en una entidad específica
 type UserRepository interface {
 	// Create almacena un nuevo usuario y retorna el usuario creado o un error
-	Create(user *domain.User) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 
 	// GetByID busca un usuario por su ID único
-	GetByID(id string) (*domain.User, error)
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+
+	// GetByIDs busca varios usuarios en UNA sola operación (mismas reglas que BookRepository.GetByIDs)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.User, error)
+
+	// GetByEmail busca un usuario por su email (lo usa el login)
+	// 🔍 Retorna domain.ErrUserNotFound si no existe
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 
 	// GetAll retorna todos los usuarios disponibles
-	GetAll() ([]*domain.User, error)
+	GetAll(ctx context.Context) ([]*domain.User, error)
+
+	// List retorna una página de usuarios filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.UserQuery) ([]*domain.User, int, error)
 
 	// Update modifica un usuario existente
-	Update(user *domain.User) (*domain.User, error)
+	// 💡 Guarda nombre y email; el hash de la contraseña y el rol no cambian
+	// 🔢 Verifica y sube la versión como BookRepository.Update (domain.ErrUserVersionMismatch)
+	Update(ctx context.Context, user *domain.User) (*domain.User, error)
+
+	// UpdateRole cambia el rol de un usuario
+	// 🔍 Retorna domain.ErrUserNotFound si no existe
+	// 🔢 Sube la versión, pero no la verifica: el rol nuevo no depende del anterior
+	UpdateRole(ctx context.Context, id string, role domain.Role) (*domain.User, error)
 
 	// Delete elimina un usuario por su ID
-	Delete(id string) error
+	// 🔢 version funciona como en Update (0 = sin verificar)
+	Delete(ctx context.Context, id string, version int) error
 }
 
 // 💡 CONSEJOS PARA PRINCIPIANTES:
@@ -89,10 +149,9 @@ type UserRepository interface {
 // 4. ✅ SÍ piensa en las operaciones que realmente necesitas
 //
 // 🌟 EJEMPLOS DE MÉTODOS QUE PODRÍAS AGREGAR:
-// - GetByAuthor(author string) ([]*domain.Book, error)
-// - GetByTitle(title string) (*domain.Book, error)
-// - GetByEmailAddress(email string) (*domain.User, error)
-// - CountBooks() (int, error)
+// - GetByAuthor(ctx context.Context, author string) ([]*domain.Book, error)
+// - GetByTitle(ctx context.Context, title string) (*domain.Book, error)
+// - CountBooks(ctx context.Context) (int, error)
 //
 // 🚫 EJEMPLOS DE LO QUE NO DEBES PONER AQUÍ:
 // - Validaciones de negocio (van en los casos de uso)
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// CopyRepository define el contrato para las operaciones de persistencia de ejemplares
+//
+// 🔒 El estado se cambia SOLO con SetStatus, que es una operación "compare-and-swap":
+// cambia de from a to únicamente si el ejemplar sigue en from. Así dos operaciones
+// simultáneas (dos préstamos, un préstamo y una baja) no se pisan.
+type CopyRepository interface {
+	// Create almacena un nuevo ejemplar
+	// 🔍 Retorna domain.ErrBarcodeAlreadyInUse si el código de barras ya existe
+	Create(ctx context.Context, cp *domain.Copy) (*domain.Copy, error)
+
+	// GetByID busca un ejemplar por su ID único
+	// 🔍 Retorna domain.ErrCopyNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Copy, error)
+
+	// GetByBarcode busca un ejemplar por su código de barras (lo que lee el escáner del mostrador)
+	GetByBarcode(ctx context.Context, barcode string) (*domain.Copy, error)
+
+	// List retorna una página de ejemplares filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.CopyQuery) ([]*domain.Copy, int, error)
+
+	// Counts cuenta los ejemplares por estado de cada libro en UNA operación
+	// 📦 Los libros sin ejemplares aparecen con todos los contadores en cero
+	Counts(ctx context.Context, bookIDs ...string) (map[string]domain.CopyCounts, error)
+
+	// Update modifica código de barras, estado físico y ubicación (NO el estado)
+	Update(ctx context.Context, cp *domain.Copy) (*domain.Copy, error)
+
+	// SetStatus cambia el estado de from a to solo si el ejemplar sigue en from
+	// 🔍 Retorna domain.ErrCopyStatusChanged si el estado ya no era from
+	SetStatus(ctx context.Context, id string, from, to domain.CopyStatus) (*domain.Copy, error)
+
+	// Delete elimina un ejemplar por su ID
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// FineRepository define el contrato para las operaciones de persistencia de multas
+//
+// 🔒 Los cambios son operaciones atómicas que solo tocan multas abiertas: un pago que llega
+// junto con el perdón de la misma multa, o dos pagos que juntos superan la deuda,
+// no pueden tener éxito los dos.
+type FineRepository interface {
+	// Create guarda la multa de un préstamo
+	// 🔍 Retorna domain.ErrFineAlreadyExists si el préstamo ya tiene una
+	Create(ctx context.Context, fine *domain.Fine) (*domain.Fine, error)
+
+	// GetByID busca una multa por su ID único
+	// 🔍 Retorna domain.ErrFineNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Fine, error)
+
+	// GetByLoanID busca la multa de un préstamo
+	// 🔍 Retorna domain.ErrFineNotFound si el préstamo no tiene multa
+	GetByLoanID(ctx context.Context, loanID string) (*domain.Fine, error)
+
+	// List retorna una página de multas filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.FineQuery) ([]*domain.Fine, int, error)
+
+	// Reassess guarda un nuevo cálculo (OverdueDays, Amount, Final) de una multa abierta
+	// 🔍 Retorna domain.ErrFineNotOpen si ya estaba pagada o perdonada
+	Reassess(ctx context.Context, fine *domain.Fine) (*domain.Fine, error)
+
+	// Pay suma un pago a una multa abierta
+	// 🔍 Retorna domain.ErrFineNotOpen si ya estaba cerrada
+	// y domain.ErrFineOverpayment si el pago supera lo que falta pagar
+	Pay(ctx context.Context, id string, amount int64, at time.Time) (*domain.Fine, error)
+
+	// Close cierra una multa abierta como paid o waived (reason solo aplica a waived)
+	// 🔍 Retorna domain.ErrFineNotOpen si ya estaba cerrada
+	Close(ctx context.Context, id string, status domain.FineStatus, reason string, at time.Time) (*domain.Fine, error)
+
+	// Debt suma lo que falta pagar de las multas abiertas de un usuario
+	Debt(ctx context.Context, userID string) (int64, error)
+}
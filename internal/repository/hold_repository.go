@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// HoldRepository define el contrato para las operaciones de persistencia de reservas
+//
+// 🔒 Igual que con los ejemplares, el estado se cambia con una operación "compare-and-swap"
+// (Transition): dos operaciones simultáneas sobre la misma reserva (retirarla y vencerla,
+// cancelarla y asignarle un ejemplar) no pueden tener éxito las dos.
+type HoldRepository interface {
+	// Create anota una reserva nueva al final de la cola del libro
+	// 🔍 Retorna domain.ErrHoldAlreadyExists si el usuario ya tiene una reserva ACTIVA
+	// de ese libro (la verificación es atómica con el alta)
+	Create(ctx context.Context, hold *domain.Hold) (*domain.Hold, error)
+
+	// GetByID busca una reserva por su ID único
+	// 🔍 Retorna domain.ErrHoldNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Hold, error)
+
+	// List retorna una página de reservas filtrada y ordenada por created_at, junto con el total
+	List(ctx context.Context, q domain.HoldQuery) ([]*domain.Hold, int, error)
+
+	// Positions calcula el lugar en la cola de cada reserva en UNA operación
+	// 📦 Solo incluye las reservas en waiting (1 = la próxima en recibir un ejemplar)
+	Positions(ctx context.Context, ids ...string) (map[string]int, error)
+
+	// Transition guarda el nuevo estado de la reserva solo si seguía en from
+	// 💡 Guarda Status, CopyID, ReadyAt, ExpiresAt y ClosedAt; el resto no cambia
+	// 🔍 Retorna domain.ErrHoldStatusChanged si el estado ya no era from
+	Transition(ctx context.Context, hold *domain.Hold, from domain.HoldStatus) (*domain.Hold, error)
+}
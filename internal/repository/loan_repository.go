@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// LoanRepository define el contrato para las operaciones de persistencia de préstamos
+//
+// 🔒 Las reglas que dependen de OTROS préstamos se verifican aquí y no en el caso de uso:
+// entre "contar" y "guardar" podría colarse otra petición concurrente. El repositorio
+// las hace atómicas (un mutex en memoria, una transacción e índices en PostgreSQL).
+type LoanRepository interface {
+	// Checkout registra un préstamo nuevo y pasa su ejemplar de from a prestado, de forma atómica
+	// 💡 from es available en un préstamo normal y on_hold al retirar una reserva
+	// 🔍 Retorna domain.ErrCopyNotAvailable si el ejemplar ya no estaba en from
+	// y domain.ErrLoanLimitReached si el usuario ya tiene maxActive préstamos activos
+	Checkout(ctx context.Context, loan *domain.Loan, from domain.CopyStatus, maxActive int) (*domain.Loan, error)
+
+	// GetByID busca un préstamo por su ID único
+	// 🔍 Retorna domain.ErrLoanNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Loan, error)
+
+	// List retorna una página de préstamos filtrada y ordenada, junto con el total
+	List(ctx context.Context, q domain.LoanQuery) ([]*domain.Loan, int, error)
+
+	// Return marca el préstamo como devuelto en returnedAt y su ejemplar como disponible
+	// 🔍 Retorna domain.ErrLoanAlreadyReturned si ya estaba devuelto (dos devoluciones simultáneas
+	// no pueden tener éxito las dos)
+	Return(ctx context.Context, id string, returnedAt time.Time) (*domain.Loan, error)
+
+	// Renew corre el vencimiento de un préstamo activo y suma una renovación
+	// 🔍 Retorna domain.ErrLoanAlreadyReturned si ya estaba devuelto
+	// y domain.ErrRenewalLimitReached si ya tenía maxRenewals renovaciones
+	Renew(ctx context.Context, id string, dueAt time.Time, maxRenewals int) (*domain.Loan, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// SessionRepository define el contrato para las operaciones de persistencia de sesiones
+//
+// 🔒 Cerrar una sesión es un "compare-and-swap" (Revoke): si dos peticiones rotan
+// el mismo refresh token a la vez, solo una lo logra; la otra ve ErrSessionRevoked
+// y se trata como una reutilización del token.
+type SessionRepository interface {
+	// Create guarda una sesión nueva
+	Create(ctx context.Context, session *domain.Session) (*domain.Session, error)
+
+	// GetByID busca una sesión por su ID (el jti del refresh token)
+	// 🔍 Retorna domain.ErrSessionNotFound si no existe
+	GetByID(ctx context.Context, id string) (*domain.Session, error)
+
+	// Revoke cierra la sesión solo si seguía abierta, anotando quién la reemplazó ("" = ninguna)
+	// 🔍 Retorna domain.ErrSessionRevoked si ya estaba cerrada
+	Revoke(ctx context.Context, id, replacedBy string, at time.Time) (*domain.Session, error)
+
+	// RevokeAll cierra todas las sesiones abiertas del usuario y retorna cuántas cerró
+	RevokeAll(ctx context.Context, userID string, at time.Time) (int, error)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAPIKeyRoutes configura las rutas de administración de API keys (solo admins)
+// 💡 El middleware que acepta X-API-Key se registra en SetupRoutes, junto al del JWT
+func SetupAPIKeyRoutes(app *fiber.App, apiKeyHandler *http.APIKeyHandler) {
+	// Crear un grupo de rutas para API keys con prefijo /api/api-keys
+	keys := app.Group("/api/api-keys")
+
+	keys.Post("/", apiKeyHandler.CreateAPIKey)      // POST /api/api-keys - Crear una API key
+	keys.Get("/", apiKeyHandler.GetAllAPIKeys)      // GET /api/api-keys - Listar API keys
+	keys.Get("/:id", apiKeyHandler.GetAPIKeyByID)   // GET /api/api-keys/:id - Obtener una API key
+	keys.Delete("/:id", apiKeyHandler.RevokeAPIKey) // DELETE /api/api-keys/:id - Revocar
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAuthRoutes configura las rutas de autenticación
+//
+// 🔓 Login, refresh y revoke son públicas: sirven justamente para conseguir o cerrar un token
+func SetupAuthRoutes(app *fiber.App, authHandler *http.AuthHandler) {
+	// Crear un grupo de rutas para autenticación con prefijo /api/auth
+	auth := app.Group("/api/auth")
+
+	auth.Post("/login", authHandler.Login)                   // POST /api/auth/login - Email y contraseña → tokens
+	auth.Post("/refresh", authHandler.Refresh)               // POST /api/auth/refresh - Rotar el refresh token
+	auth.Post("/revoke", authHandler.Revoke)                 // POST /api/auth/revoke - Logout
+	auth.Get("/me", authHandler.RequireAuth, authHandler.Me) // GET /api/auth/me - Usuario autenticado
+}
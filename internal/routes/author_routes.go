@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAuthorRoutes configura todas las rutas relacionadas con autores
+func SetupAuthorRoutes(app *fiber.App, authorHandler *http.AuthorHandler) {
+	// Crear un grupo de rutas para autores con prefijo /api/authors
+	authors := app.Group("/api/authors")
+
+	authors.Post("/", authorHandler.CreateAuthor)           // POST /api/authors - Crear autor
+	authors.Get("/", authorHandler.GetAllAuthors)           // GET /api/authors - Listar autores
+	authors.Get("/:id", authorHandler.GetAuthorByID)        // GET /api/authors/:id - Obtener autor por ID
+	authors.Get("/:id/books", authorHandler.GetAuthorBooks) // GET /api/authors/:id/books - Libros del autor
+	authors.Put("/:id", authorHandler.UpdateAuthor)         // PUT /api/authors/:id - Actualizar autor
+	authors.Delete("/:id", authorHandler.DeleteAuthor)      // DELETE /api/authors/:id - Eliminar autor
+}
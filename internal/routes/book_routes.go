@@ -1,7 +1,9 @@
 package routes
 
 import (
+	"go-book-clean-architecture-api/internal/delivery/graphql"
 	"go-book-clean-architecture-api/internal/delivery/http"
+	"go-book-clean-architecture-api/internal/delivery/opds"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,29 +16,80 @@ func SetupBookRoutes(app *fiber.App, bookHandler *http.BookHandler) {
 	books := app.Group("/api/books")
 
 	// Configurar las rutas CRUD para libros
-	books.Post("/", bookHandler.CreateBook)      // POST /api/books - Crear libro
-	books.Get("/", bookHandler.GetAllBooks)      // GET /api/books - Obtener todos los libros
-	books.Get("/:id", bookHandler.GetBookByID)   // GET /api/books/:id - Obtener libro por ID
-	books.Put("/:id", bookHandler.UpdateBook)    // PUT /api/books/:id - Actualizar libro
-	books.Delete("/:id", bookHandler.DeleteBook) // DELETE /api/books/:id - Eliminar libro
+	books.Post("/", bookHandler.CreateBook)         // POST /api/books - Crear libro
+	books.Get("/", bookHandler.GetAllBooks)         // GET /api/books - Obtener todos los libros
+	books.Get("/search", bookHandler.SearchBooks)   // GET /api/books/search?q= - Buscar libros (antes de /:id)
+	books.Get("/suggest", bookHandler.SuggestBooks) // GET /api/books/suggest?prefix= - Autocompletado
+	books.Get("/:id", bookHandler.GetBookByID)      // GET /api/books/:id - Obtener libro por ID
+	books.Put("/:id", bookHandler.UpdateBook)       // PUT /api/books/:id - Actualizar libro
+	books.Delete("/:id", bookHandler.DeleteBook)    // DELETE /api/books/:id - Eliminar libro
+}
+
+// StreamedRoutes son las rutas que leen el body en streaming: http.BodyLimit no las corta
+// 🌊 Agregar aquí cualquier ruta nueva que procese archivos grandes a medida que llegan
+var StreamedRoutes = []string{"POST /api/books/import"}
+
+// SetupBookImportRoutes configura la importación masiva de libros
+// 💡 /api/books/import no choca con /api/books/:id: ese path no acepta POST
+// 🌊 Está en StreamedRoutes: el archivo no tiene el límite de 4 MB de los demás bodies
+func SetupBookImportRoutes(app *fiber.App, importHandler *http.BookImportHandler) {
+	app.Post("/api/books/import", importHandler.ImportBooks) // POST /api/books/import - Importar CSV o NDJSON
+}
+
+// SetupExportRoutes configura la exportación de libros y usuarios a archivos
+// ⚠️ Va ANTES de SetupBookRoutes y SetupUserRoutes: si no, GET /api/books/:id
+// atraparía /api/books/export (con id = "export")
+func SetupExportRoutes(app *fiber.App, exportHandler *http.ExportHandler) {
+	app.Get("/api/books/export", exportHandler.ExportBooks) // GET /api/books/export - Exportar el catálogo
+	app.Get("/api/users/export", exportHandler.ExportUsers) // GET /api/users/export - Exportar los usuarios
+}
+
+// SetupCitationRoutes configura las referencias bibliográficas de los libros
+// ⚠️ Como SetupExportRoutes, va ANTES de SetupBookRoutes (/api/books/citation no es un :id)
+func SetupCitationRoutes(app *fiber.App, citationHandler *http.CitationHandler) {
+	app.Get("/api/books/citation", citationHandler.CiteBooks)    // GET /api/books/citation - Bibliografía de una lista filtrada
+	app.Get("/api/books/:id/citation", citationHandler.CiteBook) // GET /api/books/:id/citation - Cita de un libro
 }
 
 // SetupUserRoutes configura todas las rutas relacionadas con usuarios
+// 💡 POST /api/users (el registro) es público y se configura aparte, en SetupRoutes
 func SetupUserRoutes(app *fiber.App, userHandler *http.UserHandler) {
 	// Crear un grupo de rutas para usuarios con prefijo /api/users
 	users := app.Group("/api/users")
 
 	// Configurar las rutas CRUD para usuarios
-	users.Post("/", userHandler.CreateUser)      // POST /api/users - Crear usuario
 	users.Get("/", userHandler.GetAllUsers)      // GET /api/users - Obtener todos los usuarios
 	users.Get("/:id", userHandler.GetUserByID)   // GET /api/users/:id - Obtener usuario por ID
 	users.Put("/:id", userHandler.UpdateUser)    // PUT /api/users/:id - Actualizar usuario
 	users.Delete("/:id", userHandler.DeleteUser) // DELETE /api/users/:id - Eliminar usuario
+
+	users.Put("/:id/role", userHandler.ChangeUserRole) // PUT /api/users/:id/role - Cambiar el rol (solo admin)
+}
+
+// Handlers agrupa los handlers de todos los recursos de la API
+//
+// 💡 Un struct en lugar de un parámetro por handler: agregar un recurso nuevo
+// no cambia la firma de SetupRoutes
+type Handlers struct {
+	Books     *http.BookHandler
+	Imports   *http.BookImportHandler
+	Exports   *http.ExportHandler
+	Citations *http.CitationHandler
+	Users     *http.UserHandler
+	Authors   *http.AuthorHandler
+	Loans     *http.LoanHandler
+	Copies    *http.CopyHandler
+	Holds     *http.HoldHandler
+	Fines     *http.FineHandler
+	Auth      *http.AuthHandler
+	APIKeys   *http.APIKeyHandler
+	GraphQL   *graphql.Handler
+	OPDS      *opds.Handler
 }
 
 // SetupRoutes configura todas las rutas de la aplicación
 // Esta función central configura todos los endpoints de la API
-func SetupRoutes(app *fiber.App, bookHandler *http.BookHandler, userHandler *http.UserHandler) {
+func SetupRoutes(app *fiber.App, h Handlers) {
 	// Ruta de health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -45,7 +98,31 @@ func SetupRoutes(app *fiber.App, bookHandler *http.BookHandler, userHandler *htt
 		})
 	})
 
+	// 🔓 Rutas públicas: conseguir un token y registrarse
+	SetupAuthRoutes(app, h.Auth)
+	app.Post("/api/users", h.Users.CreateUser) // POST /api/users - Registro (crear usuario)
+
+	// 🕸️ GraphQL: fuera de /api, con autenticación opcional (createUser es público)
+	SetupGraphQLRoutes(app, h.GraphQL, h.APIKeys.Authenticate, h.Auth.OptionalAuth)
+
+	// 📱 OPDS: el catálogo para apps de lectura, fuera de /api y siempre autenticado
+	SetupOPDSRoutes(app, h.OPDS, h.APIKeys.AuthenticateBasic, h.Auth.RequireAuth)
+
+	// 🔐 Todo lo demás bajo /api exige autenticarse: API key (X-API-Key) o access token
+	// 💡 Fiber ejecuta en orden de registro: las rutas públicas de arriba responden
+	// antes de llegar a estos middlewares, y la API key se prueba antes que el JWT
+	app.Use("/api", h.APIKeys.Authenticate, h.Auth.RequireAuth)
+
 	// Configurar rutas específicas para cada dominio
-	SetupBookRoutes(app, bookHandler)
-	SetupUserRoutes(app, userHandler)
+	SetupExportRoutes(app, h.Exports)
+	SetupCitationRoutes(app, h.Citations)
+	SetupBookRoutes(app, h.Books)
+	SetupBookImportRoutes(app, h.Imports)
+	SetupUserRoutes(app, h.Users)
+	SetupAuthorRoutes(app, h.Authors)
+	SetupLoanRoutes(app, h.Loans)
+	SetupCopyRoutes(app, h.Copies)
+	SetupHoldRoutes(app, h.Holds)
+	SetupFineRoutes(app, h.Fines)
+	SetupAPIKeyRoutes(app, h.APIKeys)
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCopyRoutes configura todas las rutas del inventario de ejemplares
+func SetupCopyRoutes(app *fiber.App, copyHandler *http.CopyHandler) {
+	// Ejemplares de un libro (subrecurso de /api/books)
+	app.Post("/api/books/:id/copies", copyHandler.CreateCopy)               // POST /api/books/:id/copies - Alta de ejemplar
+	app.Get("/api/books/:id/copies", copyHandler.GetBookCopies)             // GET /api/books/:id/copies - Ejemplares del libro
+	app.Get("/api/books/:id/availability", copyHandler.GetBookAvailability) // GET /api/books/:id/availability - Contadores por estado
+
+	// Crear un grupo de rutas para ejemplares con prefijo /api/copies
+	copies := app.Group("/api/copies")
+
+	copies.Get("/:id", copyHandler.GetCopyByID)             // GET /api/copies/:id - Obtener ejemplar por ID
+	copies.Put("/:id", copyHandler.UpdateCopy)              // PUT /api/copies/:id - Actualizar ejemplar
+	copies.Put("/:id/status", copyHandler.ChangeCopyStatus) // PUT /api/copies/:id/status - Cambiar estado
+	copies.Delete("/:id", copyHandler.DeleteCopy)           // DELETE /api/copies/:id - Dar de baja
+}
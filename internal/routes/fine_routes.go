@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupFineRoutes configura todas las rutas de multas
+func SetupFineRoutes(app *fiber.App, fineHandler *http.FineHandler) {
+	// Crear un grupo de rutas para multas con prefijo /api/fines
+	fines := app.Group("/api/fines")
+
+	fines.Get("/", fineHandler.GetAllFines)         // GET /api/fines - Listar multas
+	fines.Get("/:id", fineHandler.GetFineByID)      // GET /api/fines/:id - Obtener multa por ID
+	fines.Post("/:id/pay", fineHandler.PayFine)     // POST /api/fines/:id/pay - Registrar un pago
+	fines.Post("/:id/waive", fineHandler.WaiveFine) // POST /api/fines/:id/waive - Perdonar
+
+	// Multas y deuda de un usuario (subrecursos de /api/users)
+	app.Get("/api/users/:id/fines", fineHandler.GetUserFines)     // GET /api/users/:id/fines - Multas del usuario
+	app.Get("/api/users/:id/balance", fineHandler.GetUserBalance) // GET /api/users/:id/balance - Deuda y bloqueo
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/graphql"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupGraphQLRoutes configura el endpoint GraphQL
+//
+// 🔐 auth son los middlewares que identifican al cliente (API key y JWT opcional):
+// GraphQL tiene un único endpoint, así que cada resolver (su caso de uso) decide
+// si la operación exige usuario
+func SetupGraphQLRoutes(app *fiber.App, graphqlHandler *graphql.Handler, auth ...fiber.Handler) {
+	handlers := append(auth, graphqlHandler.Query)
+
+	app.Post("/graphql", handlers...)              // POST /graphql - Ejecutar consultas y mutaciones
+	app.Get("/graphql", graphqlHandler.Playground) // GET /graphql - GraphiQL (solo en desarrollo)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupHoldRoutes configura todas las rutas de la cola de reservas
+func SetupHoldRoutes(app *fiber.App, holdHandler *http.HoldHandler) {
+	// Reservas de un libro y de un usuario (subrecursos)
+	app.Post("/api/books/:id/holds", holdHandler.PlaceHold)   // POST /api/books/:id/holds - Anotarse en la cola
+	app.Get("/api/books/:id/holds", holdHandler.GetBookHolds) // GET /api/books/:id/holds - Cola del libro
+	app.Get("/api/users/:id/holds", holdHandler.GetUserHolds) // GET /api/users/:id/holds - Reservas del usuario
+
+	// Crear un grupo de rutas para reservas con prefijo /api/holds
+	holds := app.Group("/api/holds")
+
+	holds.Get("/:id", holdHandler.GetHoldByID)        // GET /api/holds/:id - Obtener reserva por ID
+	holds.Post("/:id/cancel", holdHandler.CancelHold) // POST /api/holds/:id/cancel - Cancelar
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupLoanRoutes configura todas las rutas relacionadas con préstamos
+func SetupLoanRoutes(app *fiber.App, loanHandler *http.LoanHandler) {
+	// Crear un grupo de rutas para préstamos con prefijo /api/loans
+	loans := app.Group("/api/loans")
+
+	loans.Post("/", loanHandler.Checkout)             // POST /api/loans - Prestar un libro
+	loans.Get("/", loanHandler.GetAllLoans)           // GET /api/loans - Listar préstamos
+	loans.Get("/:id", loanHandler.GetLoanByID)        // GET /api/loans/:id - Obtener préstamo por ID
+	loans.Post("/:id/return", loanHandler.ReturnLoan) // POST /api/loans/:id/return - Devolver
+	loans.Post("/:id/renew", loanHandler.RenewLoan)   // POST /api/loans/:id/renew - Renovar
+
+	// Préstamos de un usuario (subrecurso de /api/users)
+	app.Get("/api/users/:id/loans", loanHandler.GetUserLoans) // GET /api/users/:id/loans - Historial del usuario
+}
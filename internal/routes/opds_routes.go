@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"go-book-clean-architecture-api/internal/delivery/opds"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupOPDSRoutes configura el catálogo OPDS para apps de lectura
+//
+// 🔐 auth son los middlewares que identifican al cliente (API key, también por Basic con
+// APIKeyHandler.AuthenticateBasic, y JWT): a diferencia de GraphQL, todo el catálogo exige
+// credenciales. ⚠️ Basic se acepta SOLO aquí: /api y /graphql usan Authenticate.
+// opds.Challenge va primero para que un 401 le pida al lector usuario y contraseña
+func SetupOPDSRoutes(app *fiber.App, opdsHandler *opds.Handler, auth ...fiber.Handler) {
+	catalog := app.Group("/opds", append([]fiber.Handler{opds.Challenge}, auth...)...)
+
+	catalog.Get("/", opdsHandler.Root)                     // GET /opds - Menú principal (navegación)
+	catalog.Get("/books", opdsHandler.Books)               // GET /opds/books - Libros con facetas (adquisición)
+	catalog.Get("/search", opdsHandler.Search)             // GET /opds/search?q= - Resultados de búsqueda
+	catalog.Get("/authors", opdsHandler.Authors)           // GET /opds/authors - Menú de autores
+	catalog.Get("/languages", opdsHandler.Languages)       // GET /opds/languages - Menú de idiomas
+	catalog.Get("/opensearch.xml", opdsHandler.OpenSearch) // GET /opds/opensearch.xml - Descripción OpenSearch
+}
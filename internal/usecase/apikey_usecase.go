@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Formato de las API keys: "bk_<prefijo>_<secreto>"
+const (
+	apiKeyTag          = "bk"        // Marca fija: permite reconocer una clave filtrada (ej: en un repositorio)
+	apiKeyPrefixBytes  = 6           // Bytes aleatorios del prefijo (12 caracteres hex)
+	apiKeySecretBytes  = 32          // Bytes aleatorios del secreto (256 bits)
+	maxAPIKeyNameRunes = 100         // Largo máximo del nombre
+	apiKeyTouchEvery   = time.Minute // Cada cuánto se vuelve a guardar el último uso
+)
+
+// APIKeyInput son los datos para crear una API key
+type APIKeyInput struct {
+	Name      string              // Para qué es (obligatorio)
+	UserID    string              // En nombre de quién actúa ("" = el admin que la crea)
+	Scopes    []domain.Permission // Permisos que habilita (al menos uno)
+	ExpiresAt *time.Time          // Vencimiento (nil = no vence)
+}
+
+// APIKeyUseCase contiene la lógica de las API keys
+//
+// 🔐 ¿Por qué SHA-256 y no bcrypt como las contraseñas?
+// bcrypt es lento a propósito para que adivinar una contraseña humana sea caro.
+// El secreto de una API key son 256 bits aleatorios: no hay nada que adivinar,
+// y la clave se verifica en CADA petición, así que un hash rápido es lo correcto.
+//
+// 📋 Reglas:
+// - Solo un admin (users:admin) crea, lista y revoca claves
+// - Los scopes de una clave no pueden superar lo que el rol de su usuario permite
+// - Una clave revocada o vencida deja de valer en el acto
+type APIKeyUseCase struct {
+	keyRepo  repository.APIKeyRepository // Dependencia inyectada del repositorio
+	userRepo repository.UserRepository   // Dueños de las claves
+	clock    Clock                       // Hora actual (inyectable en tests)
+}
+
+// NewAPIKeyUseCase constructor para APIKeyUseCase
+// 💡 clock puede ser nil: se usa el reloj del sistema
+func NewAPIKeyUseCase(keyRepo repository.APIKeyRepository, userRepo repository.UserRepository, clock Clock) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		keyRepo:  keyRepo,
+		userRepo: userRepo,
+		clock:    orSystemClock(clock),
+	}
+}
+
+// CreateAPIKey crea una API key y la retorna junto con la clave en texto plano
+//
+// ⚠️ La clave completa solo viaja en esta respuesta: después solo se ve el prefijo
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, input APIKeyInput) (*domain.IssuedAPIKey, error) {
+	admin, err := authorize(ctx, domain.PermUsersAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if input.UserID == "" {
+		input.UserID = admin.ID
+	}
+
+	owner, err := uc.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	input.Name = strings.TrimSpace(input.Name)
+	var v domain.Validator
+	v.Required("name", input.Name, "el nombre de la API key es obligatorio")
+	v.Check(len([]rune(input.Name)) <= maxAPIKeyNameRunes, "name", domain.CodeTooLong, "el nombre de la API key es demasiado largo")
+	scopes := validateScopes(&v, input.Scopes, owner.Role)
+	v.Check(input.ExpiresAt == nil || input.ExpiresAt.After(now), "expires_at", domain.CodeOutOfRange, "el vencimiento tiene que ser futuro")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix, secret, err := newAPIKeyParts()
+	if err != nil {
+		return nil, domain.NewInternalError("no se pudo generar la API key", err)
+	}
+	raw := apiKeyTag + "_" + prefix + "_" + secret
+
+	key, err := uc.keyRepo.Create(ctx, &domain.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    owner.ID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIKey(raw),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedAPIKey{APIKey: key, Key: raw}, nil
+}
+
+// GetAPIKeyByID obtiene una API key por su ID (sin la clave)
+func (uc *APIKeyUseCase) GetAPIKeyByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	if _, err := authorize(ctx, domain.PermUsersAdmin); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID de la API key es obligatorio")
+	}
+	return uc.keyRepo.GetByID(ctx, id)
+}
+
+// ListAPIKeys obtiene una página de API keys (GET /api/api-keys)
+//
+// 📄 Ordenadas por created_at; filtro: user_id
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context, q domain.APIKeyQuery) (*domain.Page[*domain.APIKey], error) {
+	if _, err := authorize(ctx, domain.PermUsersAdmin); err != nil {
+		return nil, err
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+
+	keys, total, err := uc.keyRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(keys, total, q.PageRequest, q.Filter), nil
+}
+
+// RevokeAPIKey revoca una API key
+// 💡 Es idempotente: revocar una clave ya revocada no es un error
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	if _, err := authorize(ctx, domain.PermUsersAdmin); err != nil {
+		return err
+	}
+	if id == "" {
+		return requiredIDError("ID de la API key es obligatorio")
+	}
+
+	_, err := uc.keyRepo.Revoke(ctx, id, uc.clock.Now())
+	if errors.Is(err, domain.ErrAPIKeyRevoked) {
+		return nil
+	}
+	return err
+}
+
+// Authenticate verifica una API key y retorna su usuario y la clave
+//
+// 🔄 Flujo:
+// 1. Separa el prefijo y busca la clave por él
+// 2. Compara el hash en tiempo constante
+// 3. Verifica que no esté revocada ni vencida y que el usuario exista
+// 4. Anota el último uso
+//
+// 💡 El último uso se guarda como mucho una vez por minuto: un script que hace
+// cientos de peticiones por segundo no genera cientos de escrituras
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*domain.User, *domain.APIKey, error) {
+	prefix, ok := parseAPIKey(rawKey)
+	if !ok {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := uc.keyRepo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	now := uc.clock.Now()
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKey(rawKey))) != 1 || !key.Active(now) {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, key.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchEvery {
+		if err := uc.keyRepo.Touch(ctx, key.ID, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return user, key, nil
+}
+
+// validateScopes valida los scopes pedidos y los retorna sin repetidos
+func validateScopes(v *domain.Validator, requested []domain.Permission, role domain.Role) []domain.Permission {
+	v.Check(len(requested) > 0, "scopes", domain.CodeRequired, "la API key necesita al menos un scope")
+
+	scopes := make([]domain.Permission, 0, len(requested))
+	seen := make(map[domain.Permission]bool, len(requested))
+	for _, scope := range requested {
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		switch {
+		case !domain.ValidAPIKeyScope(scope):
+			v.Add("scopes", domain.CodeInvalidFormat, "scope desconocido: "+string(scope)+" (books:read, books:write o users:admin)")
+		case !role.Can(scope):
+			v.Add("scopes", domain.CodeOutOfRange, "el rol del usuario no tiene el permiso "+string(scope))
+		default:
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// newAPIKeyParts genera el prefijo (hex) y el secreto (base64url) de una clave nueva
+func newAPIKeyParts() (prefix, secret string, err error) {
+	buf := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:apiKeyPrefixBytes]), base64.RawURLEncoding.EncodeToString(buf[apiKeyPrefixBytes:]), nil
+}
+
+// parseAPIKey extrae el prefijo de una clave "bk_<prefijo>_<secreto>"
+// 💡 El secreto en base64url puede tener "_": solo se corta en los dos primeros
+func parseAPIKey(raw string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(raw), "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag || len(parts[1]) != 2*apiKeyPrefixBytes || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// hashAPIKey retorna el SHA-256 (hex) de la clave completa
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(raw)))
+	return hex.EncodeToString(sum[:])
+}
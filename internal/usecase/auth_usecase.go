@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHasher calcula y verifica hashes de contraseñas
+//
+// 🔌 Es un "puerto": el caso de uso define QUÉ necesita y la infraestructura
+// (infrastructure/security) decide CÓMO (bcrypt, argon2, ...)
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) bool
+}
+
+// TokenManager firma y verifica tokens
+//
+// 🔍 Parse retorna domain.ErrInvalidToken si el token no es válido o está vencido
+type TokenManager interface {
+	Issue(claims domain.TokenClaims) (string, error)
+	Parse(token string) (*domain.TokenClaims, error)
+}
+
+// AuthUseCase contiene la lógica de autenticación: login, refresh, logout
+// y la verificación del access token de cada petición
+//
+// 🔐 Flujo:
+// 1. Login con email y contraseña → par de tokens (access + refresh)
+// 2. Cada petición manda el access token → Authenticate → usuario
+// 3. Vence el access token → Refresh con el refresh token → par nuevo (el viejo deja de valer)
+// 4. Logout → Revoke del refresh token
+type AuthUseCase struct {
+	userRepo    repository.UserRepository    // Dueños de las credenciales
+	sessionRepo repository.SessionRepository // Refresh tokens emitidos
+	hasher      PasswordHasher               // Verificación de contraseñas
+	tokens      TokenManager                 // Firma y verificación de tokens
+	policy      domain.TokenPolicy           // Vida de los tokens
+	clock       Clock                        // Hora actual (inyectable en tests)
+
+	dummyOnce sync.Once // Calcula dummyHash la primera vez que hace falta
+	dummyHash string    // Hash para comparar cuando el email no existe
+}
+
+// NewAuthUseCase constructor para AuthUseCase
+func NewAuthUseCase(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, hasher PasswordHasher, tokens TokenManager, policy domain.TokenPolicy, clock Clock) *AuthUseCase {
+	return &AuthUseCase{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		hasher:      hasher,
+		tokens:      tokens,
+		policy:      policy,
+		clock:       orSystemClock(clock),
+	}
+}
+
+// Login verifica email y contraseña y abre una sesión
+//
+// 🕵️ Email inexistente y contraseña incorrecta devuelven el MISMO error, y en los dos
+// casos se calcula un bcrypt: ni el mensaje ni el tiempo de respuesta revelan qué emails
+// están registrados
+func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+	var v domain.Validator
+	v.Required("email", email, "el email es obligatorio")
+	v.Check(password != "", "password", domain.CodeRequired, "la contraseña es obligatoria")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		uc.hasher.Verify(uc.dummy(), password)
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.PasswordHash == "" || !uc.hasher.Verify(user.PasswordHash, password) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return uc.openSession(ctx, user.ID)
+}
+
+// Refresh canjea un refresh token por un par nuevo (rotación)
+//
+// 🔁 El refresh token usado se cierra y queda apuntando a la sesión nueva.
+// 🚨 Si llega un refresh token ya cerrado, alguien lo copió: se cierran TODAS las
+// sesiones del usuario y tanto el atacante como el usuario legítimo tienen que volver a hacer login
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	claims, err := uc.parse(refreshToken, domain.TokenRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, claims.ID)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := uc.clock.Now()
+	if session.RevokedAt != nil {
+		return nil, uc.reused(ctx, session.UserID, now)
+	}
+	if !session.Active(now) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// El usuario pudo haberse borrado mientras tanto
+	if _, err := uc.userRepo.GetByID(ctx, session.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// 🔒 Compare-and-swap: de dos refresh simultáneos con el mismo token, uno pierde
+	// y cuenta como reutilización
+	nextID := uuid.New().String()
+	if _, err := uc.sessionRepo.Revoke(ctx, session.ID, nextID, now); err != nil {
+		if errors.Is(err, domain.ErrSessionRevoked) {
+			return nil, uc.reused(ctx, session.UserID, now)
+		}
+		return nil, err
+	}
+
+	return uc.issue(ctx, session.UserID, nextID, now)
+}
+
+// Revoke cierra la sesión del refresh token (logout)
+// 💡 Es idempotente: cerrar una sesión ya cerrada no es un error
+func (uc *AuthUseCase) Revoke(ctx context.Context, refreshToken string) error {
+	claims, err := uc.parse(refreshToken, domain.TokenRefresh)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.sessionRepo.Revoke(ctx, claims.ID, "", uc.clock.Now())
+	switch {
+	case errors.Is(err, domain.ErrSessionRevoked):
+		return nil
+	case errors.Is(err, domain.ErrSessionNotFound):
+		return domain.ErrInvalidToken
+	default:
+		return err
+	}
+}
+
+// Authenticate verifica un access token y retorna su usuario
+//
+// 💡 El access token no se guarda: alcanza con la firma y el vencimiento.
+// Igual se busca el usuario, así un usuario borrado deja de tener acceso en el acto
+func (uc *AuthUseCase) Authenticate(ctx context.Context, accessToken string) (*domain.User, error) {
+	claims, err := uc.parse(accessToken, domain.TokenAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, claims.Subject)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	return user, err
+}
+
+// openSession abre una sesión nueva para el usuario
+func (uc *AuthUseCase) openSession(ctx context.Context, userID string) (*domain.TokenPair, error) {
+	return uc.issue(ctx, userID, uuid.New().String(), uc.clock.Now())
+}
+
+// issue guarda la sesión sessionID y firma su par de tokens
+func (uc *AuthUseCase) issue(ctx context.Context, userID, sessionID string, now time.Time) (*domain.TokenPair, error) {
+	session, err := uc.sessionRepo.Create(ctx, &domain.Session{
+		ID:        sessionID,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.policy.RefreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := uc.tokens.Issue(domain.TokenClaims{
+		ID:        uuid.New().String(),
+		Subject:   userID,
+		Kind:      domain.TokenAccess,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(uc.policy.AccessTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := uc.tokens.Issue(domain.TokenClaims{
+		ID:        session.ID,
+		Subject:   userID,
+		Kind:      domain.TokenRefresh,
+		IssuedAt:  now,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(uc.policy.AccessTTL / time.Second),
+	}, nil
+}
+
+// parse verifica el token y que sea del tipo esperado
+func (uc *AuthUseCase) parse(token string, kind domain.TokenKind) (*domain.TokenClaims, error) {
+	if token == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	claims, err := uc.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Kind != kind {
+		return nil, domain.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// reused cierra todas las sesiones del usuario ante la reutilización de un refresh token
+func (uc *AuthUseCase) reused(ctx context.Context, userID string, now time.Time) error {
+	if _, err := uc.sessionRepo.RevokeAll(ctx, userID, now); err != nil {
+		return err
+	}
+	return domain.ErrInvalidToken
+}
+
+// dummy retorna un hash válido que no corresponde a ninguna contraseña real
+func (uc *AuthUseCase) dummy() string {
+	uc.dummyOnce.Do(func() {
+		uc.dummyHash, _ = uc.hasher.Hash(uuid.New().String())
+	})
+	return uc.dummyHash
+}
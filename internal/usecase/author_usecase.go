@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthorUseCase contiene la lógica de negocio de los autores
+//
+// 👥 Necesita también el repositorio de libros:
+// - Para listar los libros de un autor (GET /api/authors/:id/books)
+// - Para impedir que se borre un autor que todavía tiene libros vinculados
+type AuthorUseCase struct {
+	authorRepo repository.AuthorRepository // Dependencia inyectada del repositorio
+	bookRepo   repository.BookRepository   // Para consultar los libros vinculados
+}
+
+// NewAuthorUseCase constructor para AuthorUseCase
+func NewAuthorUseCase(authorRepo repository.AuthorRepository, bookRepo repository.BookRepository) *AuthorUseCase {
+	return &AuthorUseCase{
+		authorRepo: authorRepo,
+		bookRepo:   bookRepo,
+	}
+}
+
+// AuthorInput son los datos de un autor que envía el cliente al crearlo o actualizarlo
+type AuthorInput struct {
+	Name string
+	Bio  string
+}
+
+// Límites de los datos de un autor
+const maxAuthorBioRunes = 5000
+
+// CreateAuthor valida los datos y da de alta un nuevo autor
+func (uc *AuthorUseCase) CreateAuthor(ctx context.Context, in AuthorInput) (*domain.Author, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+	author, err := newAuthor(uuid.New().String(), in)
+	if err != nil {
+		return nil, err
+	}
+	author.CreatedAt = time.Now().UTC()
+
+	return uc.authorRepo.Create(ctx, author)
+}
+
+// GetAuthorByID obtiene un autor por su ID
+func (uc *AuthorUseCase) GetAuthorByID(ctx context.Context, id string) (*domain.Author, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del autor es obligatorio")
+	}
+	return uc.authorRepo.GetByID(ctx, id)
+}
+
+// ListAuthors obtiene una página de autores filtrada y ordenada
+// Se puede ordenar por name o created_at
+func (uc *AuthorUseCase) ListAuthors(ctx context.Context, q domain.AuthorQuery) (*domain.Page[*domain.Author], error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByName, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+
+	authors, total, err := uc.authorRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(authors, total, q.PageRequest, q.Filter), nil
+}
+
+// ListAuthorBooks obtiene una página de los libros vinculados a un autor (en cualquier rol)
+//
+// 📄 Misma paginación y orden que ListBooks; el filtro por autor lo fija la ruta,
+// así que un cursor de otro autor no sirve para espiar sus libros
+func (uc *AuthorUseCase) ListAuthorBooks(ctx context.Context, authorID string, q domain.BookQuery) (*domain.Page[*domain.Book], error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	// 404 si el autor no existe (en lugar de una lista vacía engañosa)
+	if _, err := uc.GetAuthorByID(ctx, authorID); err != nil {
+		return nil, err
+	}
+
+	q.Filter.AuthorID = authorID
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByTitle, domain.SortByAuthor, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+	q.Filter.AuthorID = authorID // El cursor no puede cambiar de autor
+
+	books, total, err := uc.bookRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if books, err = attachAuthorNames(ctx, uc.authorRepo, books); err != nil {
+		return nil, err
+	}
+
+	return newPage(books, total, q.PageRequest, q.Filter), nil
+}
+
+// UpdateAuthor actualiza un autor existente
+//
+// ✍️ Los libros vinculados muestran el nombre nuevo en la próxima lectura
+// (el nombre no se copia en los vínculos, ver domain.BookAuthor)
+func (uc *AuthorUseCase) UpdateAuthor(ctx context.Context, id string, in AuthorInput) (*domain.Author, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del autor es obligatorio")
+	}
+
+	author, err := newAuthor(id, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.authorRepo.Update(ctx, author)
+}
+
+// DeleteAuthor elimina un autor que no tenga libros vinculados
+//
+// 🛡️ Regla de negocio: borrar un autor con libros dejaría créditos huérfanos,
+// así que se responde 409 Conflict (domain.ErrAuthorHasBooks)
+func (uc *AuthorUseCase) DeleteAuthor(ctx context.Context, id string) error {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return err
+	}
+	if _, err := uc.GetAuthorByID(ctx, id); err != nil {
+		return err
+	}
+
+	_, linked, err := uc.bookRepo.List(ctx, domain.BookQuery{
+		PageRequest: domain.PageRequest{Limit: 1, Sort: domain.SortByCreatedAt},
+		Filter:      domain.BookFilter{AuthorID: id},
+	})
+	if err != nil {
+		return err
+	}
+	if linked > 0 {
+		return domain.ErrAuthorHasBooks
+	}
+
+	return uc.authorRepo.Delete(ctx, id)
+}
+
+// newAuthor valida los datos de entrada y arma la entidad
+func newAuthor(id string, in AuthorInput) (*domain.Author, error) {
+	author := &domain.Author{
+		ID:   id,
+		Name: strings.TrimSpace(in.Name),
+		Bio:  strings.TrimSpace(in.Bio),
+	}
+
+	var v domain.Validator
+	v.Required("name", author.Name, "el nombre del autor es obligatorio")
+	v.MaxLength("name", author.Name, maxBookTextLength, "el nombre no puede superar los 255 caracteres")
+	v.MaxLength("bio", author.Bio, maxAuthorBioRunes, "la biografía no puede superar los 5000 caracteres")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return author, nil
+}
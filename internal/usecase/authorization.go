@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// 🛡️ Autorización en los casos de uso
+//
+// ¿Por qué acá y no solo en las rutas?
+// - La regla "solo un bibliotecario da de alta libros" es de NEGOCIO, no de HTTP
+// - Una CLI o un servidor gRPC que llamen a estos casos de uso heredan las mismas reglas
+//   con solo poner al usuario en el contexto (ContextWithUser)
+//
+// 🔄 Flujo:
+// 1. La capa de delivery autentica (JWT, API key, ...) y pone al usuario en el ctx
+// 2. El caso de uso pide un permiso: authorize(ctx, domain.PermBooksWrite)
+// 3. Sin usuario → domain.ErrAuthRequired (401); sin permiso → domain.ErrPermissionDenied (403)
+//
+// 🔑 Con una API key (ContextWithAPIKey) el permiso tiene que darlo el rol del usuario
+// Y los scopes de la clave: una clave books:read de un admin solo lee el catálogo
+//
+// 💡 Las tareas internas del servidor (vencer reservas, recalcular multas) no pasan por aquí
+
+// userContextKey es la clave del usuario autenticado dentro de un context.Context
+// 💡 Un tipo propio no exportado: ningún otro paquete puede pisar la clave
+type userContextKey struct{}
+
+// ContextWithUser retorna un contexto que lleva al usuario autenticado
+//
+// 🔌 Lo usa la capa de delivery (el middleware de autenticación) para que los casos
+// de uso sepan quién hace la petición sin agregar un parámetro a cada método
+func ContextWithUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext retorna el usuario autenticado del contexto (false si no hay)
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*domain.User)
+	return user, ok && user != nil
+}
+
+// apiKeyContextKey es la clave de la API key con la que se autenticó la petición
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey retorna un contexto que lleva al usuario autenticado con una API key
+// y la clave misma, para que sus scopes acoten los permisos del usuario
+func ContextWithAPIKey(ctx context.Context, user *domain.User, key *domain.APIKey) context.Context {
+	return context.WithValue(ContextWithUser(ctx, user), apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext retorna la API key de la petición (false si se autenticó de otra forma)
+func APIKeyFromContext(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*domain.APIKey)
+	return key, ok && key != nil
+}
+
+// actor retorna el usuario que hace la petición, o domain.ErrAuthRequired si no hay
+func actor(ctx context.Context) (*domain.User, error) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrAuthRequired
+	}
+	return user, nil
+}
+
+// authorize exige que el usuario de la petición tenga el permiso
+func authorize(ctx context.Context, perm domain.Permission) (*domain.User, error) {
+	user, err := actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !permitted(ctx, user, perm) {
+		return nil, domain.ErrPermissionDenied
+	}
+	return user, nil
+}
+
+// authorizeSelf permite la operación si el usuario de la petición ES ownerID,
+// o si tiene el permiso para hacerlo sobre cualquier usuario
+//
+// 📋 Ejemplo: authorizeSelf(ctx, loan.UserID, domain.PermCirculation)
+// → el socio ve SUS préstamos; el bibliotecario ve los de todos
+//
+// 🔑 Una API key no tiene el atajo de "es lo mío": solo cuenta lo que habilitan sus scopes
+func authorizeSelf(ctx context.Context, ownerID string, perm domain.Permission) (*domain.User, error) {
+	user, err := actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, viaKey := APIKeyFromContext(ctx)
+	if (viaKey || user.ID != ownerID) && !permitted(ctx, user, perm) {
+		return nil, domain.ErrPermissionDenied
+	}
+	return user, nil
+}
+
+// permitted indica si el rol del usuario (y la API key, si la hay) habilitan el permiso
+func permitted(ctx context.Context, user *domain.User, perm domain.Permission) bool {
+	if key, ok := APIKeyFromContext(ctx); ok && !key.Allows(perm) {
+		return false
+	}
+	return user.Role.Can(perm)
+}
@@ -12,7 +12,11 @@
 // ✅ Validan datos antes de procesarlos
 // ✅ Coordinan operaciones entre diferentes entidades
 // ✅ Usan las interfaces de repositorio (no implementaciones concretas)
-// ✅ Retornan errores de negocio significativos
+// ✅ Retornan errores de negocio significativos (tipados, ver domain/errors.go)
+//
+// ⏱️ Todos los métodos reciben context.Context como primer parámetro:
+// - El caso de uso NO sabe si viene de HTTP, CLI o gRPC, solo lo propaga
+// - Así la cancelación/deadline de la petición llega hasta el repositorio
 //
 // 🚫 Los casos de uso NO deben:
 // - Conocer detalles de HTTP (request/response)
@@ -22,9 +26,16 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"go-book-clean-architecture-api/internal/domain"
 	"go-book-clean-architecture-api/internal/repository"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -42,7 +53,10 @@ import (
 // - NO crea las dependencias internamente
 // - Esto facilita el testing y la flexibilidad
 type BookUseCase struct {
-	bookRepo repository.BookRepository // Dependencia inyectada del repositorio
+	bookRepo   repository.BookRepository   // Dependencia inyectada del repositorio
+	authorRepo repository.AuthorRepository // Para validar los autores vinculados y mostrar sus nombres
+	copyRepo   repository.CopyRepository   // Para informar cuántos ejemplares están disponibles
+	circ       circulation                 // Para no borrar libros con préstamos, reservas o multas
 }
 
 // NewBookUseCase es el CONSTRUCTOR que implementa Dependency Injection
@@ -54,12 +68,37 @@ type BookUseCase struct {
 // - Siguen el principio de inversión de dependencias
 //
 // 💡 Nota: En Go, los constructores son por convención funciones New*
-func NewBookUseCase(bookRepo repository.BookRepository) *BookUseCase {
+func NewBookUseCase(bookRepo repository.BookRepository, authorRepo repository.AuthorRepository, copyRepo repository.CopyRepository, loanRepo repository.LoanRepository, holdRepo repository.HoldRepository, fineRepo repository.FineRepository) *BookUseCase {
 	return &BookUseCase{
-		bookRepo: bookRepo,
+		bookRepo:   bookRepo,
+		authorRepo: authorRepo,
+		copyRepo:   copyRepo,
+		circ:       circulation{loans: loanRepo, holds: holdRepo, fines: fineRepo},
 	}
 }
 
+// BookInput son los datos de un libro que envía el cliente al crearlo o actualizarlo
+//
+// 📝 ¿Por qué un struct y no parámetros sueltos?
+// - Con 10 campos, CreateBook(ctx, title, author, isbn, ...) sería ilegible y frágil
+// - Agregar un campo nuevo no rompe a quienes llaman al caso de uso
+//
+// 💡 Solo Title y Author son obligatorios; el resto es opcional ("" o 0 = desconocido)
+// Author puede omitirse si se envían Authors: se arma con los nombres de los autores vinculados
+type BookInput struct {
+	Title           string
+	Author          string
+	ISBN            string // ISBN-10 o ISBN-13, con o sin guiones
+	Publisher       string
+	PublicationYear int
+	Language        string // Código ISO 639 ("es", "en", "pt-BR")
+	PageCount       int
+	Description     string
+	Subjects        []string
+	Edition         string
+	Authors         []domain.BookAuthor // Autores vinculados, en orden de créditos (Name se ignora)
+}
+
 // CreateBook maneja toda la lógica para crear un nuevo libro
 //
 // 🔄 Flujo típico de un caso de uso:
@@ -69,56 +108,237 @@ func NewBookUseCase(bookRepo repository.BookRepository) *BookUseCase {
 // 4. Retornar resultado o error
 //
 // 🎯 Responsabilidades:
-// ✅ Validar que el título no esté vacío (regla de negocio)
-// ✅ Validar que el autor no esté vacío (regla de negocio)
+// ✅ Validar título y autor obligatorios, ISBN, año, idioma, etc. (reglas de negocio)
+// ✅ Normalizar los datos (ISBN a ISBN-13, espacios, materias repetidas)
+// ✅ Verificar que los autores vinculados existan
 // ✅ Generar ID único para el libro
 // ✅ Crear la entidad Book
-// ✅ Delegar la persistencia al repositorio
-func (uc *BookUseCase) CreateBook(title, author string) (*domain.Book, error) {
-	// PASO 1: Validaciones de reglas de negocio
-	// Estas son reglas específicas de nuestro dominio
-	if title == "" {
-		return nil, errors.New("el título del libro es obligatorio")
+// ✅ Delegar la persistencia al repositorio (que verifica que el ISBN no se repita)
+func (uc *BookUseCase) CreateBook(ctx context.Context, in BookInput) (*domain.Book, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
 	}
-	if author == "" {
-		return nil, errors.New("el autor del libro es obligatorio")
+	// PASO 1 y 2: Validar las reglas de negocio y crear la entidad del dominio
+	// El Validator acumula TODOS los campos inválidos para reportarlos juntos
+	book, err := newBook(uuid.New().String(), in) // Generar ID único
+	if err != nil {
+		return nil, err
 	}
-
-	// PASO 2: Crear la entidad del dominio
-	book := &domain.Book{
-		ID:     uuid.New().String(), // Generar ID único
-		Title:  title,
-		Author: author,
+	if err := uc.resolveAuthors(ctx, book); err != nil {
+		return nil, err
 	}
+	book.CreatedAt = time.Now().UTC()
 
 	// PASO 3: Delegar la persistencia al repositorio
 	// El caso de uso NO sabe si esto se guarda en memoria, PostgreSQL, etc.
-	return uc.bookRepo.Create(book)
+	return uc.bookRepo.Create(ctx, book)
 }
 
 // GetBookByID obtiene un libro por su ID
 //
 // 🔍 Caso de uso simple: validar entrada y delegar al repositorio
 // Podríamos agregar lógica adicional como logging, métricas, cache, etc.
-func (uc *BookUseCase) GetBookByID(id string) (*domain.Book, error) {
+//
+// 📦 Además informa available_copies (ejemplares en el estante ahora mismo)
+func (uc *BookUseCase) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
 	// Validación de entrada
 	if id == "" {
-		return nil, errors.New("ID del libro es obligatorio")
+		return nil, requiredIDError("ID del libro es obligatorio")
 	}
 
 	// Delegar al repositorio
-	return uc.bookRepo.GetByID(id)
+	book, err := uc.bookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.withDetails(ctx, book)
+}
+
+// withDetails completa lo que un libro muestra y no guarda: el nombre de sus autores
+// y los ejemplares disponibles
+//
+// 🔢 GetBookByID y UpdateBook responden así la MISMA representación, con el mismo
+// ETag: un cliente que guarda el ETag del PUT recibe 304 en el GET siguiente
+func (uc *BookUseCase) withDetails(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	books, err := attachAuthorNames(ctx, uc.authorRepo, []*domain.Book{book})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.copyRepo.Counts(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Se completa una COPIA: el puntero puede ser el que guarda el repositorio en memoria
+	result := *books[0]
+	available := counts[book.ID].Available
+	result.AvailableCopies = &available
+	return &result, nil
+}
+
+// GetBooksByIDs obtiene varios libros en una sola consulta al repositorio
+//
+// 📦 Los IDs inexistentes se omiten y el orden no está garantizado: quien llama
+// arma su propio índice por ID (ej: el dataloader de GraphQL)
+// 💡 Sin available_copies: contarlos de a un libro volvería a ser N+1
+func (uc *BookUseCase) GetBooksByIDs(ctx context.Context, ids []string) ([]*domain.Book, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	books, err := uc.bookRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return attachAuthorNames(ctx, uc.authorRepo, books)
 }
 
 // GetAllBooks obtiene todos los libros disponibles
 //
-// 📚 En aplicaciones reales, aquí podrías implementar:
-// - Paginación: GetBooks(page, limit int)
-// - Filtros: GetBooksByAuthor(author string)
-// - Ordenamiento: GetBooksSortedByTitle()
-// - Cache: verificar cache antes de llamar al repositorio
-func (uc *BookUseCase) GetAllBooks() ([]*domain.Book, error) {
-	return uc.bookRepo.GetAll()
+// ⚠️ Sin límite: para listados de cara al cliente usa ListBooks
+func (uc *BookUseCase) GetAllBooks(ctx context.Context) ([]*domain.Book, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	books, err := uc.bookRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return attachAuthorNames(ctx, uc.authorRepo, books)
+}
+
+// ListBooks obtiene una página de libros filtrada y ordenada
+//
+// 📄 Reglas de negocio de la paginación (ver pagination.go):
+// - Límite por defecto 20, máximo 100
+// - Solo se puede ordenar por title, author o created_at
+// - Un cursor inválido es un error de validación
+func (uc *BookUseCase) ListBooks(ctx context.Context, q domain.BookQuery) (*domain.Page[*domain.Book], error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByTitle, domain.SortByAuthor, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+
+	books, total, err := uc.bookRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if books, err = attachAuthorNames(ctx, uc.authorRepo, books); err != nil {
+		return nil, err
+	}
+
+	return newPage(books, total, q.PageRequest, q.Filter), nil
+}
+
+// BookFacets cuenta los libros que cumplen el filtro por autor y por idioma
+//
+// 🧭 Son las facetas de un catálogo navegable (ver el feed OPDS): "Cortázar (12)", "es (120)"
+// 📋 Los autores llevan su nombre en Label; un vínculo a un autor que ya no existe no se muestra
+func (uc *BookUseCase) BookFacets(ctx context.Context, filter domain.BookFilter) (*domain.BookFacets, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+
+	facets, err := uc.bookRepo.Facets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(facets.Authors) == 0 {
+		return facets, nil
+	}
+
+	links := make([]domain.BookAuthor, len(facets.Authors))
+	for i, f := range facets.Authors {
+		links[i] = domain.BookAuthor{AuthorID: f.Value}
+	}
+	names, err := authorNames(ctx, uc.authorRepo, links)
+	if err != nil {
+		return nil, err
+	}
+	authors := make([]domain.FacetCount, 0, len(facets.Authors))
+	for _, f := range facets.Authors {
+		if name, ok := names[f.Value]; ok {
+			f.Label = name
+			authors = append(authors, f)
+		}
+	}
+	return &domain.BookFacets{Authors: authors, Languages: facets.Languages}, nil
+}
+
+// SearchBooks busca libros por texto libre, del más al menos relevante
+//
+// 🔎 Reglas de negocio:
+// - La búsqueda necesita al menos una palabra (letras o dígitos)
+// - Misma paginación que ListBooks; el único orden posible es por relevancia
+// - El cursor guarda el texto buscado: la página siguiente no necesita repetir ?q=
+func (uc *BookUseCase) SearchBooks(ctx context.Context, q domain.BookSearchQuery) (*domain.Page[domain.BookHit], error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if q.Sort == "" {
+		q.Sort = domain.SortByRelevance
+	}
+	if err := resolvePage(&q.PageRequest, &q.Text, domain.SortByRelevance); err != nil {
+		return nil, err
+	}
+	if len(domain.SearchTerms(q.Text)) == 0 {
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "q",
+			Code:    domain.CodeRequired,
+			Message: "la búsqueda necesita al menos una palabra",
+		})
+	}
+
+	hits, total, err := uc.bookRepo.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]*domain.Book, len(hits))
+	for i, hit := range hits {
+		books[i] = hit.Book
+	}
+	if books, err = attachAuthorNames(ctx, uc.authorRepo, books); err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		hits[i].Book = books[i]
+	}
+
+	return newPage(hits, total, q.PageRequest, q.Text), nil
+}
+
+// Límites de las sugerencias de autocompletado
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 25
+)
+
+// SuggestBooks retorna sugerencias de autocompletado (títulos y autores)
+//
+// ⌨️ Pensado para typeahead: se llama en cada tecla, así que devuelve pocos
+// resultados (10 por defecto, máximo 25) y tolera errores de tipeo
+func (uc *BookUseCase) SuggestBooks(ctx context.Context, q domain.SuggestQuery) ([]domain.Suggestion, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultSuggestLimit
+	}
+
+	var v domain.Validator
+	v.Check(len(domain.SearchTerms(q.Prefix)) > 0, "prefix", domain.CodeRequired, "escribe al menos una letra o dígito para recibir sugerencias")
+	v.Check(q.Limit > 0 && q.Limit <= MaxSuggestLimit, "limit", domain.CodeOutOfRange, "el límite debe estar entre 1 y 25")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return uc.bookRepo.Suggest(ctx, q)
 }
 
 // UpdateBook actualiza un libro existente
@@ -129,44 +349,77 @@ func (uc *BookUseCase) GetAllBooks() ([]*domain.Book, error) {
 // 3. Delegar la actualización al repositorio
 //
 // 💡 Nota: El repositorio se encarga de verificar si el libro existe
-func (uc *BookUseCase) UpdateBook(id, title, author string) (*domain.Book, error) {
+// ⚠️ Es un reemplazo completo (PUT): los campos opcionales que no se envían quedan vacíos
+// 🔢 version es la que leyó el cliente (If-Match): si otro guardó antes, retorna
+// domain.ErrBookVersionMismatch en vez de pisar sus cambios (0 = sin verificar)
+// 📦 Retorna el libro como GetBookByID (con autores y ejemplares disponibles)
+func (uc *BookUseCase) UpdateBook(ctx context.Context, id string, in BookInput, version int) (*domain.Book, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
 	// Validaciones de negocio
 	if id == "" {
-		return nil, errors.New("ID del libro es obligatorio")
-	}
-	if title == "" {
-		return nil, errors.New("el título del libro es obligatorio")
-	}
-	if author == "" {
-		return nil, errors.New("el autor del libro es obligatorio")
+		return nil, requiredIDError("ID del libro es obligatorio")
 	}
 
 	// Crear entidad con los datos actualizados
-	book := &domain.Book{
-		ID:     id,
-		Title:  title,
-		Author: author,
+	book, err := newBook(id, in)
+	if err != nil {
+		return nil, err
+	}
+	book.Version = version
+	if err := uc.resolveAuthors(ctx, book); err != nil {
+		return nil, err
 	}
 
 	// Delegar la actualización al repositorio
-	return uc.bookRepo.Update(book)
+	updated, err := uc.bookRepo.Update(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	return uc.withDetails(ctx, updated)
 }
 
 // DeleteBook elimina un libro por su ID
 //
-// 🗑️ Operación de eliminación simple
-// En aplicaciones reales, podrías implementar:
-// - Soft delete (marcar como eliminado, no borrar físicamente)
-// - Verificaciones adicionales (¿el libro está prestado?)
-// - Logging de auditoría
-func (uc *BookUseCase) DeleteBook(id string) error {
+// 🗑️ Solo se elimina un libro que nunca circuló:
+// - Con préstamos activos → domain.ErrBookHasActiveLoans
+// - Con multas impagas → domain.ErrBookHasUnpaidFines (se perdería lo que se debe)
+// - Con préstamos devueltos o reservas → domain.ErrBookHasCirculation (se perdería el historial)
+// - Con ejemplares → domain.ErrBookHasCopies (hay que darlos de baja primero)
+//
+// 🔢 version funciona como en UpdateBook (0 = sin verificar)
+func (uc *BookUseCase) DeleteBook(ctx context.Context, id string, version int) error {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return err
+	}
 	// Validación de entrada
 	if id == "" {
-		return errors.New("ID del libro es obligatorio")
+		return requiredIDError("ID del libro es obligatorio")
+	}
+
+	// Verificar que el libro no tenga circulación ni inventario
+	err := uc.circ.check(ctx, circulationRefs{
+		loans:   domain.LoanFilter{BookID: id},
+		holds:   domain.HoldFilter{BookID: id},
+		fines:   domain.FineFilter{BookID: id},
+		active:  domain.ErrBookHasActiveLoans,
+		unpaid:  domain.ErrBookHasUnpaidFines,
+		history: domain.ErrBookHasCirculation,
+	})
+	if err != nil {
+		return err
+	}
+	counts, err := uc.copyRepo.Counts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if counts[id].Total > 0 {
+		return domain.ErrBookHasCopies
 	}
 
 	// Delegar la eliminación al repositorio
-	return uc.bookRepo.Delete(id)
+	return uc.bookRepo.Delete(ctx, id, version)
 }
 
 // UserUseCase contiene toda la lógica de negocio relacionada con los usuarios
@@ -175,12 +428,16 @@ func (uc *BookUseCase) DeleteBook(id string) error {
 // Esto demuestra el patrón consistente en Clean Architecture
 type UserUseCase struct {
 	userRepo repository.UserRepository // Dependencia inyectada del repositorio
+	circ     circulation               // Para no borrar usuarios con préstamos, reservas o multas
+	hasher   PasswordHasher            // Hash de contraseñas (ver auth_usecase.go)
 }
 
 // NewUserUseCase constructor para UserUseCase
-func NewUserUseCase(userRepo repository.UserRepository) *UserUseCase {
+func NewUserUseCase(userRepo repository.UserRepository, loanRepo repository.LoanRepository, holdRepo repository.HoldRepository, fineRepo repository.FineRepository, hasher PasswordHasher) *UserUseCase {
 	return &UserUseCase{
 		userRepo: userRepo,
+		circ:     circulation{loans: loanRepo, holds: holdRepo, fines: fineRepo},
+		hasher:   hasher,
 	}
 }
 
@@ -188,77 +445,477 @@ func NewUserUseCase(userRepo repository.UserRepository) *UserUseCase {
 //
 // 👤 Lógica específica para usuarios:
 // - Validar que el nombre no esté vacío
-// - Validar que el email no esté vacío
-// - En aplicaciones reales: validar formato de email, unicidad, etc.
-func (uc *UserUseCase) CreateUser(name, email string) (*domain.User, error) {
+// - Validar que el email no esté vacío y tenga formato válido
+// - Validar el largo de la contraseña y guardar SOLO su hash
+// - La unicidad del email la verifica el repositorio
+func (uc *UserUseCase) CreateUser(ctx context.Context, name, email, password string) (*domain.User, error) {
 	// Validaciones de reglas de negocio
-	if name == "" {
-		return nil, errors.New("el nombre del usuario es obligatorio")
+	var v domain.Validator
+	validateUser(&v, name, email)
+	validatePassword(&v, password)
+	if err := v.Err(); err != nil {
+		return nil, err
 	}
-	if email == "" {
-		return nil, errors.New("el email del usuario es obligatorio")
+
+	hash, err := uc.hasher.Hash(password)
+	if err != nil {
+		return nil, domain.NewInternalError("no se pudo procesar la contraseña", err)
 	}
 
-	// TODO: En aplicaciones reales, aquí validarías:
-	// - Formato de email válido
-	// - Email único en el sistema
+	// 💡 La unicidad del email la garantiza el repositorio (domain.ErrEmailAlreadyInUse)
+	// TODO: En aplicaciones reales, aquí también validarías:
 	// - Longitud mínima del nombre
 	// - Caracteres permitidos, etc.
 
 	// Crear la entidad del dominio
 	user := &domain.User{
-		ID:    uuid.New().String(), // Generar ID único
-		Name:  name,
-		Email: email,
+		ID:           uuid.New().String(), // Generar ID único
+		Name:         name,
+		Email:        email,
+		PasswordHash: hash,
+		Role:         domain.RoleMember, // 🔐 El registro es público: el rol lo cambia un admin
+		CreatedAt:    time.Now().UTC(),
 	}
 
 	// Delegar la persistencia al repositorio
-	return uc.userRepo.Create(user)
+	return uc.userRepo.Create(ctx, user)
 }
 
 // GetUserByID obtiene un usuario por su ID
-func (uc *UserUseCase) GetUserByID(id string) (*domain.User, error) {
+func (uc *UserUseCase) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	if _, err := authorizeSelf(ctx, id, domain.PermUsersRead); err != nil {
+		return nil, err
+	}
 	if id == "" {
-		return nil, errors.New("ID del usuario es obligatorio")
+		return nil, requiredIDError("ID del usuario es obligatorio")
 	}
-	return uc.userRepo.GetByID(id)
+	return uc.userRepo.GetByID(ctx, id)
+}
+
+// GetUsersByIDs obtiene varios usuarios en una sola consulta al repositorio
+//
+// 🔐 Mismo permiso que GetUserByID para CADA ID: un socio puede pedirse a sí mismo,
+// pero si la lista incluye a otro usuario hace falta users:read
+func (uc *UserUseCase) GetUsersByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	for _, id := range ids {
+		if _, err := authorizeSelf(ctx, id, domain.PermUsersRead); err != nil {
+			return nil, err
+		}
+	}
+	return uc.userRepo.GetByIDs(ctx, ids)
 }
 
 // GetAllUsers obtiene todos los usuarios disponibles
-func (uc *UserUseCase) GetAllUsers() ([]*domain.User, error) {
-	return uc.userRepo.GetAll()
+func (uc *UserUseCase) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
+	if _, err := authorize(ctx, domain.PermUsersRead); err != nil {
+		return nil, err
+	}
+	return uc.userRepo.GetAll(ctx)
+}
+
+// ListUsers obtiene una página de usuarios filtrada y ordenada
+// Se puede ordenar por name, email o created_at
+func (uc *UserUseCase) ListUsers(ctx context.Context, q domain.UserQuery) (*domain.Page[*domain.User], error) {
+	if _, err := authorize(ctx, domain.PermUsersRead); err != nil {
+		return nil, err
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByName, domain.SortByEmail, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+
+	users, total, err := uc.userRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(users, total, q.PageRequest, q.Filter), nil
 }
 
 // UpdateUser actualiza un usuario existente
-func (uc *UserUseCase) UpdateUser(id, name, email string) (*domain.User, error) {
+// 🔢 version funciona como en BookUseCase.UpdateBook (domain.ErrUserVersionMismatch)
+func (uc *UserUseCase) UpdateUser(ctx context.Context, id, name, email string, version int) (*domain.User, error) {
+	if _, err := authorizeSelf(ctx, id, domain.PermUsersAdmin); err != nil {
+		return nil, err
+	}
 	// Validaciones de negocio
 	if id == "" {
-		return nil, errors.New("ID del usuario es obligatorio")
-	}
-	if name == "" {
-		return nil, errors.New("el nombre del usuario es obligatorio")
+		return nil, requiredIDError("ID del usuario es obligatorio")
 	}
-	if email == "" {
-		return nil, errors.New("el email del usuario es obligatorio")
+	var v domain.Validator
+	validateUser(&v, name, email)
+	if err := v.Err(); err != nil {
+		return nil, err
 	}
 
 	// Crear entidad con los datos actualizados
 	user := &domain.User{
-		ID:    id,
-		Name:  name,
-		Email: email,
+		ID:      id,
+		Name:    name,
+		Email:   email,
+		Version: version,
 	}
 
 	// Delegar la actualización al repositorio
-	return uc.userRepo.Update(user)
+	return uc.userRepo.Update(ctx, user)
+}
+
+// ChangeUserRole cambia el rol de un usuario (solo admin)
+//
+// 🔒 Nadie puede cambiar su propio rol: así un admin no se quita el acceso por error
+// y siempre queda al menos el que hace el cambio
+// 🔢 No pide versión: el rol nuevo no depende del anterior (pero la versión sube igual)
+func (uc *UserUseCase) ChangeUserRole(ctx context.Context, id string, role domain.Role) (*domain.User, error) {
+	admin, err := authorize(ctx, domain.PermUsersAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del usuario es obligatorio")
+	}
+	if !role.Valid() {
+		return nil, domain.NewFieldsError(domain.FieldError{Field: "role", Code: domain.CodeInvalidFormat,
+			Message: "el rol debe ser admin, librarian o member"})
+	}
+	if admin.ID == id {
+		return nil, domain.ErrOwnRoleChange
+	}
+
+	return uc.userRepo.UpdateRole(ctx, id, role)
 }
 
-// DeleteUser elimina un usuario por su ID
-func (uc *UserUseCase) DeleteUser(id string) error {
+// BootstrapAdmin garantiza que exista un admin con ese email (lo usa main.go al arrancar)
+//
+// 🐣 ¿El huevo o la gallina? Solo un admin puede nombrar admins, así que el primero
+// viene de la configuración (ADMIN_EMAIL / ADMIN_PASSWORD):
+// - Si el usuario no existe, se crea como admin con esa contraseña
+// - Si existe, se lo promueve a admin (su contraseña no cambia)
+func (uc *UserUseCase) BootstrapAdmin(ctx context.Context, email, password string) (*domain.User, error) {
+	existing, err := uc.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		if existing.Role == domain.RoleAdmin {
+			return existing, nil
+		}
+		return uc.userRepo.UpdateRole(ctx, existing.ID, domain.RoleAdmin)
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	user, err := uc.CreateUser(ctx, "Administrador", email, password)
+	if err != nil {
+		return nil, err
+	}
+	return uc.userRepo.UpdateRole(ctx, user.ID, domain.RoleAdmin)
+}
+
+// DeleteUser elimina un usuario por su ID (version 0 = sin verificar)
+//
+// 🗑️ Igual que DeleteBook: un usuario con préstamos activos (domain.ErrUserHasActiveLoans),
+// multas impagas (domain.ErrUserHasUnpaidFines) o con historial de préstamos o reservas
+// (domain.ErrUserHasCirculation) no se elimina
+func (uc *UserUseCase) DeleteUser(ctx context.Context, id string, version int) error {
+	if _, err := authorize(ctx, domain.PermUsersAdmin); err != nil {
+		return err
+	}
 	if id == "" {
-		return errors.New("ID del usuario es obligatorio")
+		return requiredIDError("ID del usuario es obligatorio")
+	}
+	err := uc.circ.check(ctx, circulationRefs{
+		loans:   domain.LoanFilter{UserID: id},
+		holds:   domain.HoldFilter{UserID: id},
+		fines:   domain.FineFilter{UserID: id},
+		active:  domain.ErrUserHasActiveLoans,
+		unpaid:  domain.ErrUserHasUnpaidFines,
+		history: domain.ErrUserHasCirculation,
+	})
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.Delete(ctx, id, version)
+}
+
+// Límites de los datos de un libro
+const (
+	maxBookTextLength   = 255   // Título, autor y editorial (VARCHAR(255) en PostgreSQL)
+	maxEditionLength    = 64    // Edición
+	maxDescriptionRunes = 10000 // Descripción
+	maxSubjects         = 25    // Cantidad de materias
+	maxSubjectLength    = 100   // Largo de cada materia
+	minPublicationYear  = 1450  // La imprenta de Gutenberg
+	maxPageCount        = 100000
+)
+
+// languagePattern acepta códigos ISO 639 con subetiquetas opcionales: "es", "spa", "pt-BR"
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// newBook valida y normaliza los datos de entrada y arma la entidad
+//
+// 📝 Los nombres de campo ("title", "isbn", ...) son los del JSON que envía el cliente,
+// así el frontend puede resaltar exactamente el campo rechazado
+//
+// 🧹 Normalización:
+// - Se recortan los espacios de todos los textos
+// - El ISBN se guarda como ISBN-13 sin guiones (ver domain.NormalizeISBN)
+// - El idioma se pasa a minúsculas en su parte principal ("ES" → "es", "PT-br" → "pt-BR")
+// - Las materias vacías o repetidas (sin distinguir mayúsculas) se descartan
+// - Los vínculos sin rol son de rol "author"; los repetidos (mismo autor y rol) se descartan
+func newBook(id string, in BookInput) (*domain.Book, error) {
+	book := &domain.Book{
+		ID:              id,
+		Title:           strings.TrimSpace(in.Title),
+		Author:          strings.TrimSpace(in.Author),
+		Publisher:       strings.TrimSpace(in.Publisher),
+		PublicationYear: in.PublicationYear,
+		Language:        normalizeLanguage(in.Language),
+		PageCount:       in.PageCount,
+		Description:     strings.TrimSpace(in.Description),
+		Subjects:        normalizeSubjects(in.Subjects),
+		Edition:         strings.TrimSpace(in.Edition),
+	}
+
+	var v domain.Validator
+	v.Required("title", book.Title, "el título del libro es obligatorio")
+	v.MaxLength("title", book.Title, maxBookTextLength, "el título no puede superar los 255 caracteres")
+	if len(in.Authors) == 0 {
+		v.Required("author", book.Author, "el autor del libro es obligatorio")
+	}
+	v.MaxLength("author", book.Author, maxBookTextLength, "el autor no puede superar los 255 caracteres")
+	book.Authors = normalizeBookAuthors(&v, in.Authors)
+
+	if strings.TrimSpace(in.ISBN) != "" {
+		isbn, err := domain.NormalizeISBN(in.ISBN)
+		v.Check(err == nil, "isbn", domain.CodeInvalidFormat, "el ISBN no es un ISBN-10 o ISBN-13 válido")
+		book.ISBN = isbn
+	}
+
+	v.MaxLength("publisher", book.Publisher, maxBookTextLength, "la editorial no puede superar los 255 caracteres")
+	if book.PublicationYear != 0 {
+		maxYear := time.Now().Year() + 1 // Se admiten preventas del año próximo
+		v.Check(book.PublicationYear >= minPublicationYear && book.PublicationYear <= maxYear,
+			"publication_year", domain.CodeOutOfRange,
+			fmt.Sprintf("el año de publicación debe estar entre %d y %d", minPublicationYear, maxYear))
+	}
+	v.Check(book.Language == "" || languagePattern.MatchString(book.Language),
+		"language", domain.CodeInvalidFormat, "el idioma debe ser un código ISO 639 (ej: es, en, pt-BR)")
+	v.Check(book.PageCount >= 0 && book.PageCount <= maxPageCount,
+		"page_count", domain.CodeOutOfRange, "la cantidad de páginas debe estar entre 0 y 100000")
+	v.MaxLength("description", book.Description, maxDescriptionRunes, "la descripción no puede superar los 10000 caracteres")
+	v.Check(len(book.Subjects) <= maxSubjects, "subjects", domain.CodeOutOfRange, "un libro puede tener como máximo 25 materias")
+	for _, subject := range book.Subjects {
+		v.MaxLength("subjects", subject, maxSubjectLength, "cada materia puede tener como máximo 100 caracteres")
+	}
+	v.MaxLength("edition", book.Edition, maxEditionLength, "la edición no puede superar los 64 caracteres")
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// normalizeLanguage pasa a minúsculas la parte principal del código de idioma
+// y a mayúsculas la región de dos letras ("PT-br" → "pt-BR")
+func normalizeLanguage(lang string) string {
+	primary, rest, found := strings.Cut(strings.TrimSpace(lang), "-")
+	primary = strings.ToLower(primary)
+	if !found {
+		return primary
+	}
+	if len(rest) == 2 {
+		rest = strings.ToUpper(rest)
+	}
+	return primary + "-" + rest
+}
+
+// normalizeSubjects recorta espacios y descarta materias vacías o repetidas
+func normalizeSubjects(subjects []string) []string {
+	seen := make(map[string]bool, len(subjects))
+	result := make([]string, 0, len(subjects))
+	for _, s := range subjects {
+		s = strings.TrimSpace(s)
+		key := strings.ToLower(s)
+		if s == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, s)
 	}
-	return uc.userRepo.Delete(id)
+	return result
+}
+
+// Límite de autores vinculados a un libro
+const maxBookAuthors = 50
+
+// normalizeBookAuthors valida los vínculos con autores y descarta los repetidos
+//
+// 💡 Solo se revisa el formato; que los autores existan lo verifica resolveAuthors
+func normalizeBookAuthors(v *domain.Validator, links []domain.BookAuthor) []domain.BookAuthor {
+	v.Check(len(links) <= maxBookAuthors, "authors", domain.CodeOutOfRange, "un libro puede tener como máximo 50 autores vinculados")
+
+	type key struct {
+		id   string
+		role domain.AuthorRole
+	}
+	seen := make(map[key]bool, len(links))
+	result := make([]domain.BookAuthor, 0, len(links))
+	for _, link := range links {
+		link.AuthorID = strings.TrimSpace(link.AuthorID)
+		link.Name = "" // El nombre sale siempre del autor, no de la petición
+		if link.Role == "" {
+			link.Role = domain.RoleAuthor
+		}
+
+		v.Required("authors", link.AuthorID, "cada autor vinculado necesita su author_id")
+		v.Check(link.Role.Valid(), "authors", domain.CodeInvalidFormat, "el rol debe ser author, editor o translator")
+
+		k := key{link.AuthorID, link.Role}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, link)
+	}
+	return result
+}
+
+// resolveAuthors verifica que los autores vinculados existan y completa sus nombres
+//
+// ✍️ Si el libro no trae el texto de Author, se arma con los nombres de los
+// vínculos de rol "author" (o de todos, si no hay ninguno): "Kent Beck, Martin Fowler"
+func (uc *BookUseCase) resolveAuthors(ctx context.Context, book *domain.Book) error {
+	if len(book.Authors) == 0 {
+		return nil
+	}
+
+	names, err := authorNames(ctx, uc.authorRepo, book.Authors)
+	if err != nil {
+		return err
+	}
+
+	var v domain.Validator
+	var credits, all []string
+	for i := range book.Authors {
+		link := &book.Authors[i]
+		name, ok := names[link.AuthorID]
+		if !ok {
+			v.Add("authors", domain.CodeUnknownRef, fmt.Sprintf("el autor %s no existe", link.AuthorID))
+			continue
+		}
+		link.Name = name
+		all = append(all, name)
+		if link.Role == domain.RoleAuthor {
+			credits = append(credits, name)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	if book.Author == "" {
+		if len(credits) == 0 {
+			credits = all
+		}
+		book.Author = strings.Join(credits, ", ")
+		if utf8.RuneCountInString(book.Author) > maxBookTextLength {
+			return domain.NewFieldsError(domain.FieldError{
+				Field:   "author",
+				Code:    domain.CodeTooLong,
+				Message: "los nombres de los autores superan los 255 caracteres: envía el campo author",
+			})
+		}
+	}
+	return nil
+}
+
+// authorNames busca en UNA consulta los nombres de los autores vinculados (ID → nombre)
+func authorNames(ctx context.Context, authorRepo repository.AuthorRepository, links []domain.BookAuthor) (map[string]string, error) {
+	ids := make([]string, 0, len(links))
+	seen := make(map[string]bool, len(links))
+	for _, link := range links {
+		if !seen[link.AuthorID] {
+			seen[link.AuthorID] = true
+			ids = append(ids, link.AuthorID)
+		}
+	}
+
+	authors, err := authorRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(authors))
+	for _, author := range authors {
+		names[author.ID] = author.Name
+	}
+	return names, nil
+}
+
+// attachAuthorNames completa el nombre de los autores vinculados de varios libros
+//
+// 📦 Una sola consulta para toda la página (nada de N+1)
+// ⚠️ Retorna COPIAS de los libros: el repositorio en memoria entrega sus propios punteros
+// y modificarlos desde aquí sería una carrera de datos
+func attachAuthorNames(ctx context.Context, authorRepo repository.AuthorRepository, books []*domain.Book) ([]*domain.Book, error) {
+	var links []domain.BookAuthor
+	for _, book := range books {
+		links = append(links, book.Authors...)
+	}
+	if len(links) == 0 {
+		return books, nil
+	}
+
+	names, err := authorNames(ctx, authorRepo, links)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Book, len(books))
+	for i, book := range books {
+		if len(book.Authors) == 0 {
+			result[i] = book
+			continue
+		}
+		cp := *book
+		cp.Authors = make([]domain.BookAuthor, len(book.Authors))
+		for j, link := range book.Authors {
+			link.Name = names[link.AuthorID]
+			cp.Authors[j] = link
+		}
+		result[i] = &cp
+	}
+	return result, nil
+}
+
+// validateUser aplica las reglas de negocio comunes a crear y actualizar usuarios
+func validateUser(v *domain.Validator, name, email string) {
+	v.Required("name", name, "el nombre del usuario es obligatorio")
+	v.Required("email", email, "el email del usuario es obligatorio")
+	if strings.TrimSpace(email) != "" {
+		_, err := mail.ParseAddress(email)
+		v.Check(err == nil, "email", domain.CodeInvalidFormat, "el email del usuario no tiene un formato válido")
+	}
+}
+
+// Límites de la contraseña
+const (
+	minPasswordLength = 8  // Caracteres
+	maxPasswordBytes  = 72 // bcrypt ignora lo que pase de 72 bytes: mejor rechazarlo que truncarlo en silencio
+)
+
+// validatePassword aplica las reglas de una contraseña nueva
+func validatePassword(v *domain.Validator, password string) {
+	if password == "" {
+		v.Add("password", domain.CodeRequired, "la contraseña es obligatoria")
+		return
+	}
+	v.Check(utf8.RuneCountInString(password) >= minPasswordLength, "password", domain.CodeOutOfRange,
+		fmt.Sprintf("la contraseña debe tener al menos %d caracteres", minPasswordLength))
+	v.Check(len(password) <= maxPasswordBytes, "password", domain.CodeTooLong,
+		fmt.Sprintf("la contraseña no puede superar los %d bytes", maxPasswordBytes))
+}
+
+// requiredIDError crea el error de validación para un ID vacío
+func requiredIDError(message string) error {
+	return domain.NewFieldsError(domain.FieldError{Field: "id", Code: domain.CodeRequired, Message: message})
 }
 
 // 💡 CONSEJOS PARA PRINCIPIANTES:
@@ -270,10 +927,8 @@ func (uc *UserUseCase) DeleteUser(id string) error {
 // 5. ✅ Retorna errores descriptivos que aporten valor al usuario
 //
 // 🌟 EJEMPLOS DE CASOS DE USO ADICIONALES QUE PODRÍAS AGREGAR:
-// - SearchBooksByAuthor(author string) ([]*domain.Book, error)
-// - GetBookStatistics() (*domain.BookStats, error)
-// - LendBookToUser(bookID, userID string) error
-// - GetUserBorrowedBooks(userID string) ([]*domain.Book, error)
+// - GetBookStatistics(ctx context.Context) (*domain.BookStats, error)
+// - (Prestar libros ya existe: ver LoanUseCase en loan_usecase.go)
 //
 // 🚫 EJEMPLOS DE LO QUE NO DEBES PONER AQUÍ:
 // - Detalles de HTTP (parsing JSON, status codes)
@@ -0,0 +1,36 @@
+package usecase
+
+import "time"
+
+// Clock da la hora actual a los casos de uso
+//
+// ⏰ ¿Por qué no llamar a time.Now() directamente?
+// Vencimientos y multas dependen de "qué día es hoy". Con un reloj inyectado,
+// un test puede adelantar 20 días sin dormir 20 días.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock es el reloj real (siempre en UTC)
+type SystemClock struct{}
+
+// Now retorna la hora actual en UTC
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// ClockFunc adapta una función al tipo Clock (útil en tests)
+type ClockFunc func() time.Time
+
+// Now llama a la función
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// orSystemClock retorna el reloj recibido o el del sistema si es nil
+func orSystemClock(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock{}
+	}
+	return clock
+}
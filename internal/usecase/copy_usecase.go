@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CopyUseCase contiene la lógica de negocio del inventario de ejemplares
+//
+// 📚 Un Book es la obra; cada Copy es un ejemplar físico de esa obra.
+// Este caso de uso da de alta ejemplares, cambia su estado y resume la disponibilidad.
+//
+// 🔒 Los préstamos (LoanUseCase) son los únicos que ponen y quitan el estado on_loan,
+// y la cola de reservas la única que pone y quita on_hold
+//
+// 📌 Un ejemplar que queda disponible (alta nueva, vuelve de reparación) se ofrece primero
+// a las reservas del libro
+type CopyUseCase struct {
+	copyRepo repository.CopyRepository // Dependencia inyectada del repositorio
+	bookRepo repository.BookRepository // Para verificar que el libro exista
+	queue    holdQueue                 // Para ofrecer a la cola los ejemplares que quedan disponibles
+	circ     circulation               // Para no borrar ejemplares que se prestaron
+}
+
+// NewCopyUseCase constructor para CopyUseCase
+// 💡 clock puede ser nil: se usa el reloj del sistema (lo usa la cola de reservas)
+func NewCopyUseCase(copyRepo repository.CopyRepository, bookRepo repository.BookRepository, loanRepo repository.LoanRepository, holdRepo repository.HoldRepository, policy domain.LoanPolicy, clock Clock) *CopyUseCase {
+	return &CopyUseCase{
+		copyRepo: copyRepo,
+		bookRepo: bookRepo,
+		queue:    newHoldQueue(holdRepo, copyRepo, policy, orSystemClock(clock)),
+		circ:     circulation{loans: loanRepo, holds: holdRepo},
+	}
+}
+
+// CopyInput son los datos de un ejemplar que envía el cliente al crearlo o actualizarlo
+//
+// 💡 Condition es opcional: si no viene se asume "good"
+type CopyInput struct {
+	Barcode   string
+	Condition domain.CopyCondition
+	Location  string
+}
+
+// Límites de los datos de un ejemplar
+const maxBarcodeLength = 64
+
+// AddCopy da de alta un ejemplar disponible de un libro
+//
+// 🔍 404 si el libro no existe; 409 si el código de barras ya está en uso
+func (uc *CopyUseCase) AddCopy(ctx context.Context, bookID string, in CopyInput) (*domain.Copy, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+	if bookID == "" {
+		return nil, requiredIDError("ID del libro es obligatorio")
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	cp, err := newCopy(uuid.New().String(), in)
+	if err != nil {
+		return nil, err
+	}
+	cp.BookID = bookID
+	cp.Status = domain.CopyAvailable
+	cp.CreatedAt = time.Now().UTC()
+
+	if cp, err = uc.copyRepo.Create(ctx, cp); err != nil {
+		return nil, err
+	}
+	return uc.offerToHolds(ctx, cp)
+}
+
+// GetCopyByID obtiene un ejemplar por su ID
+func (uc *CopyUseCase) GetCopyByID(ctx context.Context, id string) (*domain.Copy, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del ejemplar es obligatorio")
+	}
+	return uc.copyRepo.GetByID(ctx, id)
+}
+
+// ListBookCopies obtiene una página de los ejemplares de un libro (GET /api/books/:id/copies)
+//
+// 📄 Se puede ordenar por barcode (por defecto) o created_at y filtrar por status;
+// el filtro por libro lo fija la ruta (un cursor de otro libro no sirve)
+func (uc *CopyUseCase) ListBookCopies(ctx context.Context, bookID string, q domain.CopyQuery) (*domain.Page[*domain.Copy], error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return nil, err
+	}
+	if bookID == "" {
+		return nil, requiredIDError("ID del libro es obligatorio")
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	if q.Sort == "" {
+		q.Sort = domain.SortByBarcode
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByBarcode, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+	q.Filter.BookID = bookID
+
+	if q.Filter.Status != "" && !q.Filter.Status.Valid() {
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "status",
+			Code:    domain.CodeInvalidFormat,
+			Message: "el estado debe ser available, on_loan, lost o in_repair",
+		})
+	}
+
+	copies, total, err := uc.copyRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(copies, total, q.PageRequest, q.Filter), nil
+}
+
+// UpdateCopy modifica código de barras, estado físico y ubicación de un ejemplar
+// ⚠️ El estado NO se cambia aquí: para eso está ChangeCopyStatus
+func (uc *CopyUseCase) UpdateCopy(ctx context.Context, id string, in CopyInput) (*domain.Copy, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del ejemplar es obligatorio")
+	}
+
+	cp, err := newCopy(id, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.copyRepo.Update(ctx, cp)
+}
+
+// ChangeCopyStatus cambia el estado de un ejemplar a mano (ej: se perdió, fue a reparar, volvió)
+//
+// 📋 Reglas:
+// - Solo se puede pasar a available, lost o in_repair (on_loan y on_hold los ponen préstamos y reservas)
+// - Un ejemplar prestado no se cambia: primero hay que registrar la devolución
+// - Un ejemplar apartado tampoco: primero hay que cancelar la reserva
+// - Si el estado cambió entre la lectura y la escritura, 409 (domain.ErrCopyStatusChanged)
+func (uc *CopyUseCase) ChangeCopyStatus(ctx context.Context, id string, status domain.CopyStatus) (*domain.Copy, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+	if status != domain.CopyAvailable && status != domain.CopyLost && status != domain.CopyInRepair {
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "status",
+			Code:    domain.CodeInvalidFormat,
+			Message: "el estado debe ser available, lost o in_repair",
+		})
+	}
+
+	cp, err := uc.GetCopyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCopyReleased(cp); err != nil {
+		return nil, err
+	}
+	if cp.Status == status {
+		return cp, nil // Nada que cambiar
+	}
+
+	if cp, err = uc.copyRepo.SetStatus(ctx, id, cp.Status, status); err != nil {
+		return nil, err
+	}
+	return uc.offerToHolds(ctx, cp)
+}
+
+// DeleteCopy da de baja un ejemplar que no esté prestado ni apartado
+// 📚 Un ejemplar que alguna vez se prestó no se borra (domain.ErrCopyHasLoans): se
+// marca como perdido o en reparación para conservar el historial de sus préstamos
+func (uc *CopyUseCase) DeleteCopy(ctx context.Context, id string) error {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return err
+	}
+	cp, err := uc.GetCopyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkCopyReleased(cp); err != nil {
+		return err
+	}
+	err = uc.circ.check(ctx, circulationRefs{
+		loans:   domain.LoanFilter{CopyID: id},
+		active:  domain.ErrCopyOnLoan,
+		history: domain.ErrCopyHasLoans,
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.copyRepo.Delete(ctx, id)
+}
+
+// offerToHolds ofrece a la cola de reservas un ejemplar que quedó disponible
+// y retorna su estado actualizado (on_hold si alguien lo estaba esperando)
+func (uc *CopyUseCase) offerToHolds(ctx context.Context, cp *domain.Copy) (*domain.Copy, error) {
+	if cp.Status != domain.CopyAvailable {
+		return cp, nil
+	}
+	if err := uc.queue.offer(ctx, cp.BookID, cp.ID, domain.CopyAvailable); err != nil {
+		return nil, err
+	}
+	return uc.copyRepo.GetByID(ctx, cp.ID)
+}
+
+// checkCopyReleased verifica que el ejemplar no esté en manos de un préstamo o una reserva
+func checkCopyReleased(cp *domain.Copy) error {
+	switch cp.Status {
+	case domain.CopyOnLoan:
+		return domain.ErrCopyOnLoan
+	case domain.CopyOnHold:
+		return domain.ErrCopyOnHold
+	}
+	return nil
+}
+
+// BookAvailability cuenta los ejemplares de un libro por estado (GET /api/books/:id/availability)
+func (uc *CopyUseCase) BookAvailability(ctx context.Context, bookID string) (domain.CopyCounts, error) {
+	if _, err := authorize(ctx, domain.PermBooksRead); err != nil {
+		return domain.CopyCounts{}, err
+	}
+	if bookID == "" {
+		return domain.CopyCounts{}, requiredIDError("ID del libro es obligatorio")
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, bookID); err != nil {
+		return domain.CopyCounts{}, err
+	}
+
+	counts, err := uc.copyRepo.Counts(ctx, bookID)
+	if err != nil {
+		return domain.CopyCounts{}, err
+	}
+	return counts[bookID], nil
+}
+
+// newCopy valida los datos de entrada y arma la entidad
+//
+// 🏷️ El código de barras se guarda en mayúsculas y sin espacios alrededor:
+// "ab-001 " y "AB-001" son el mismo ejemplar para el escáner
+func newCopy(id string, in CopyInput) (*domain.Copy, error) {
+	cp := &domain.Copy{
+		ID:        id,
+		Barcode:   strings.ToUpper(strings.TrimSpace(in.Barcode)),
+		Condition: domain.CopyCondition(strings.ToLower(strings.TrimSpace(string(in.Condition)))),
+		Location:  strings.TrimSpace(in.Location),
+	}
+	if cp.Condition == "" {
+		cp.Condition = domain.ConditionGood
+	}
+
+	var v domain.Validator
+	v.Required("barcode", cp.Barcode, "el código de barras es obligatorio")
+	v.MaxLength("barcode", cp.Barcode, maxBarcodeLength, "el código de barras no puede superar los 64 caracteres")
+	v.Check(cp.Barcode == "" || validBarcode(cp.Barcode), "barcode", domain.CodeInvalidFormat,
+		"el código de barras solo admite letras, números y guiones")
+	v.Check(cp.Condition.Valid(), "condition", domain.CodeInvalidFormat,
+		"el estado físico debe ser new, good, fair, poor o damaged")
+	v.MaxLength("location", cp.Location, maxBookTextLength, "la ubicación no puede superar los 255 caracteres")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// validBarcode indica si el código de barras solo tiene letras, números y guiones
+func validBarcode(barcode string) bool {
+	for _, r := range barcode {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
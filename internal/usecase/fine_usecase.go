@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FineUseCase contiene la lógica de negocio de las multas
+//
+// 📋 Reglas (ver domain.FinePolicy):
+// - Cada préstamo vencido genera a lo sumo una multa, que crece mientras no se devuelve
+// - Al devolver el libro el importe queda fijo (Final)
+// - Se paga en uno o varios pagos, nunca más de lo que se debe
+// - La biblioteca puede perdonarla (waive) indicando el motivo
+type FineUseCase struct {
+	fineRepo repository.FineRepository // Dependencia inyectada del repositorio
+	loanRepo repository.LoanRepository // Para recalcular la multa desde el préstamo
+	userRepo repository.UserRepository // Para verificar que el usuario exista
+	ledger   fineLedger                // Calcula y guarda multas
+	policy   domain.FinePolicy         // Tarifa, gracia, tope y límite de deuda
+	clock    Clock                     // Hora actual (inyectable para tests)
+}
+
+// NewFineUseCase constructor para FineUseCase
+// 💡 clock puede ser nil: se usa el reloj del sistema
+func NewFineUseCase(fineRepo repository.FineRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository, policy domain.LoanPolicy, clock Clock) *FineUseCase {
+	clock = orSystemClock(clock)
+	return &FineUseCase{
+		fineRepo: fineRepo,
+		loanRepo: loanRepo,
+		userRepo: userRepo,
+		ledger:   newFineLedger(fineRepo, loanRepo, policy, clock),
+		policy:   policy.Fines,
+		clock:    clock,
+	}
+}
+
+// GetFineByID obtiene una multa por su ID
+// ⏰ Si el libro todavía no volvió, primero se recalcula: el importe refleja el atraso de hoy
+func (uc *FineUseCase) GetFineByID(ctx context.Context, id string) (*domain.Fine, error) {
+	if id == "" {
+		return nil, requiredIDError("ID de la multa es obligatorio")
+	}
+	fine, err := uc.fineRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeSelf(ctx, fine.UserID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if fine.Status != domain.FineOpen || fine.Final {
+		return fine, nil
+	}
+
+	loan, err := uc.loanRepo.GetByID(ctx, fine.LoanID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.ledger.assess(ctx, loan); err != nil {
+		return nil, err
+	}
+	return uc.fineRepo.GetByID(ctx, id)
+}
+
+// ListFines obtiene una página de multas (GET /api/fines)
+//
+// 📄 Se puede ordenar por created_at (por defecto, lo más nuevo primero) o amount;
+// filtros: user_id, loan_id, status (open | paid | waived)
+func (uc *FineUseCase) ListFines(ctx context.Context, q domain.FineQuery) (*domain.Page[*domain.Fine], error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	return uc.listFines(ctx, q, func(*domain.FineFilter) {})
+}
+
+// ListUserFines obtiene una página de las multas de un usuario (GET /api/users/:id/fines)
+//
+// 👤 404 si el usuario no existe; antes de listar se recalculan sus préstamos vencidos
+func (uc *FineUseCase) ListUserFines(ctx context.Context, userID string, q domain.FineQuery) (*domain.Page[*domain.Fine], error) {
+	if _, err := authorizeSelf(ctx, userID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, requiredIDError("ID del usuario es obligatorio")
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.ledger.assessUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.listFines(ctx, q, func(f *domain.FineFilter) { f.UserID = userID })
+}
+
+// listFines implementa los listados; pin fija el filtro de la ruta DESPUÉS de leer el cursor
+func (uc *FineUseCase) listFines(ctx context.Context, q domain.FineQuery, pin func(*domain.FineFilter)) (*domain.Page[*domain.Fine], error) {
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByCreatedAt, domain.SortByAmount); err != nil {
+		return nil, err
+	}
+	pin(&q.Filter)
+
+	if q.Filter.Status != "" && !q.Filter.Status.Valid() {
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "status",
+			Code:    domain.CodeInvalidFormat,
+			Message: "el estado debe ser open, paid o waived",
+		})
+	}
+
+	fines, total, err := uc.fineRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(fines, total, q.PageRequest, q.Filter), nil
+}
+
+// UserBalance resume la deuda de un usuario (GET /api/users/:id/balance)
+// 🚫 Blocked indica si hoy se le negaría un préstamo por deuda
+func (uc *FineUseCase) UserBalance(ctx context.Context, userID string) (*domain.UserBalance, error) {
+	if _, err := authorizeSelf(ctx, userID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, requiredIDError("ID del usuario es obligatorio")
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	debt, err := uc.ledger.debt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserBalance{
+		UserID:        userID,
+		Balance:       debt,
+		DebtThreshold: uc.policy.DebtThreshold,
+		Blocked:       uc.policy.Blocks(debt),
+	}, nil
+}
+
+// PayFine registra un pago (en centavos) de una multa abierta
+//
+// 📋 Reglas:
+// - El importe tiene que ser positivo (400) y no superar lo que falta pagar (409)
+// - Una multa pagada o perdonada no admite pagos (409)
+// - Si la multa es final y queda en cero, pasa a paid
+//
+// 💡 Se puede pagar una multa que todavía crece: el pago se descuenta y el resto
+// se sigue acumulando hasta la devolución
+func (uc *FineUseCase) PayFine(ctx context.Context, id string, amount int64) (*domain.Fine, error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	var v domain.Validator
+	v.Check(amount > 0, "amount_cents", domain.CodeOutOfRange, "el pago debe ser mayor a cero")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	// Recalcular primero: se paga contra el importe de hoy
+	if _, err := uc.GetFineByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	fine, err := uc.fineRepo.Pay(ctx, id, amount, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return uc.ledger.settle(ctx, fine)
+}
+
+// WaiveFine perdona una multa abierta; el motivo es obligatorio (queda registrado)
+// 🔁 409 si ya estaba pagada o perdonada. Lo que se había pagado no se devuelve.
+func (uc *FineUseCase) WaiveFine(ctx context.Context, id, reason string) (*domain.Fine, error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+
+	var v domain.Validator
+	v.Required("reason", reason, "el motivo es obligatorio")
+	v.MaxLength("reason", reason, 500, "el motivo no puede superar los 500 caracteres")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.GetFineByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.fineRepo.Close(ctx, id, domain.FineWaived, reason, uc.clock.Now())
+}
+
+// AssessOverdue recalcula las multas de todos los préstamos vencidos y retorna cuántos revisó
+//
+// ⏰ Lo ejecuta periódicamente el servidor (ver FINE_SWEEP_INTERVAL en main.go): así los
+// listados generales muestran el atraso al día aunque nadie consulte al usuario
+func (uc *FineUseCase) AssessOverdue(ctx context.Context) (int, error) {
+	return uc.ledger.assessOverdue(ctx, domain.LoanFilter{})
+}
+
+// fineLedger calcula y guarda las multas; lo comparten FineUseCase y LoanUseCase
+//
+// 🔗 Igual que holdQueue: devolver un préstamo, pedir uno nuevo (límite de deuda) y consultar
+// multas necesitan la misma cuenta, sin que LoanUseCase dependa de FineUseCase
+type fineLedger struct {
+	fines  repository.FineRepository
+	loans  repository.LoanRepository
+	policy domain.FinePolicy
+	clock  Clock
+}
+
+// newFineLedger arma el libro de multas con las reglas de la política
+func newFineLedger(fines repository.FineRepository, loans repository.LoanRepository, policy domain.LoanPolicy, clock Clock) fineLedger {
+	return fineLedger{fines: fines, loans: loans, policy: policy.Fines, clock: clock}
+}
+
+// assess calcula la multa de un préstamo y la crea o actualiza; nil si no corresponde multa
+//
+// 🔄 Flujo:
+// 1. Atraso hasta la devolución (o hasta hoy si sigue prestado) según FinePolicy.Assess
+// 2. Sin multa previa: crearla solo si hay importe
+// 3. Con multa abierta: guardar el nuevo cálculo si cambió (una pagada o perdonada no se toca)
+// 4. Si quedó final y sin saldo, cerrarla como paid
+func (l fineLedger) assess(ctx context.Context, loan *domain.Loan) (*domain.Fine, error) {
+	until, final := l.clock.Now(), loan.ReturnedAt != nil
+	if final {
+		until = *loan.ReturnedAt
+	}
+	days, amount := l.policy.Assess(loan.DueAt, until)
+
+	fine, err := l.fines.GetByLoanID(ctx, loan.ID)
+	if errors.Is(err, domain.ErrFineNotFound) {
+		if amount == 0 {
+			return nil, nil
+		}
+		now := l.clock.Now()
+		fine, err = l.fines.Create(ctx, &domain.Fine{
+			ID:          uuid.New().String(),
+			LoanID:      loan.ID,
+			UserID:      loan.UserID,
+			BookID:      loan.BookID,
+			Status:      domain.FineOpen,
+			OverdueDays: days,
+			Amount:      amount,
+			Final:       final,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if errors.Is(err, domain.ErrFineAlreadyExists) {
+			fine, err = l.fines.GetByLoanID(ctx, loan.ID) // Otra petición la creó: seguir con esa
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fine.Status != domain.FineOpen {
+		return fine, nil
+	}
+	if amount < fine.Paid {
+		amount = fine.Paid // Si cambió la política, nunca por debajo de lo ya pagado
+	}
+	if fine.OverdueDays != days || fine.Amount != amount || fine.Final != final {
+		updated := *fine
+		updated.OverdueDays, updated.Amount, updated.Final = days, amount, final
+		updated.UpdatedAt = l.clock.Now()
+		reassessed, err := l.fines.Reassess(ctx, &updated)
+		if errors.Is(err, domain.ErrFineNotOpen) {
+			return l.fines.GetByLoanID(ctx, loan.ID) // La pagaron o perdonaron mientras tanto
+		}
+		if err != nil {
+			return nil, err
+		}
+		fine = reassessed
+	}
+	return l.settle(ctx, fine)
+}
+
+// settle cierra como paid una multa final que ya no debe nada
+func (l fineLedger) settle(ctx context.Context, fine *domain.Fine) (*domain.Fine, error) {
+	if fine.Status != domain.FineOpen || !fine.Final || fine.Balance() > 0 {
+		return fine, nil
+	}
+	closed, err := l.fines.Close(ctx, fine.ID, domain.FinePaid, "", l.clock.Now())
+	if errors.Is(err, domain.ErrFineNotOpen) {
+		return l.fines.GetByID(ctx, fine.ID)
+	}
+	return closed, err
+}
+
+// assessOverdue recalcula las multas de los préstamos activos vencidos que cumplen filter
+func (l fineLedger) assessOverdue(ctx context.Context, filter domain.LoanFilter) (int, error) {
+	filter.Status, filter.DueBefore = domain.LoanActive, l.clock.Now()
+
+	assessed := 0
+	for offset := 0; ; offset += MaxPageLimit {
+		loans, _, err := l.loans.List(ctx, domain.LoanQuery{
+			PageRequest: domain.PageRequest{Sort: domain.SortByDueAt, Limit: MaxPageLimit, Offset: offset},
+			Filter:      filter,
+		})
+		if err != nil || len(loans) == 0 {
+			return assessed, err
+		}
+		for _, loan := range loans {
+			if _, err := l.assess(ctx, loan); err != nil {
+				return assessed, err
+			}
+			assessed++
+		}
+	}
+}
+
+// assessUser recalcula las multas de los préstamos vencidos de un usuario
+func (l fineLedger) assessUser(ctx context.Context, userID string) error {
+	_, err := l.assessOverdue(ctx, domain.LoanFilter{UserID: userID})
+	return err
+}
+
+// debt retorna la deuda de un usuario al día de hoy (incluye el atraso de lo que no devolvió)
+func (l fineLedger) debt(ctx context.Context, userID string) (int64, error) {
+	if err := l.assessUser(ctx, userID); err != nil {
+		return 0, err
+	}
+	return l.fines.Debt(ctx, userID)
+}
@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HoldUseCase contiene la lógica de negocio de las reservas
+//
+// 📋 Reglas:
+// - Solo se reserva un libro sin ejemplares disponibles (si hay, se presta directamente)
+// - Un usuario tiene como máximo una reserva activa por libro
+// - La cola es FIFO: al volver un ejemplar se aparta para la reserva más antigua (ready)
+// - Si no se retira antes de ExpiresAt, vence y el ejemplar pasa a la siguiente de la cola
+type HoldUseCase struct {
+	holdRepo repository.HoldRepository // Dependencia inyectada del repositorio
+	bookRepo repository.BookRepository // Para verificar que el libro exista
+	userRepo repository.UserRepository // Para verificar que el usuario exista
+	copyRepo repository.CopyRepository // Para saber si hay ejemplares disponibles
+	queue    holdQueue                 // Asigna ejemplares a la cola
+	clock    Clock                     // Hora actual (inyectable para tests)
+}
+
+// NewHoldUseCase constructor para HoldUseCase
+// 💡 clock puede ser nil: se usa el reloj del sistema
+func NewHoldUseCase(holdRepo repository.HoldRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, copyRepo repository.CopyRepository, policy domain.LoanPolicy, clock Clock) *HoldUseCase {
+	clock = orSystemClock(clock)
+	return &HoldUseCase{
+		holdRepo: holdRepo,
+		bookRepo: bookRepo,
+		userRepo: userRepo,
+		copyRepo: copyRepo,
+		queue:    newHoldQueue(holdRepo, copyRepo, policy, clock),
+		clock:    clock,
+	}
+}
+
+// PlaceHold anota a un usuario en la cola de un libro (POST /api/books/:id/holds)
+//
+// 🔄 Flujo:
+// 1. 404 si el libro no existe; 400 unknown_ref si el usuario no existe
+// 2. 409 si hay ejemplares disponibles (domain.ErrHoldNotNeeded)
+// 3. Anotar la reserva (el repositorio rechaza una segunda reserva activa del mismo libro)
+// 4. Si entre el paso 2 y el 3 se devolvió un ejemplar, ofrecerlo a la cola
+func (uc *HoldUseCase) PlaceHold(ctx context.Context, bookID, userID string) (*domain.Hold, error) {
+	userID = strings.TrimSpace(userID)
+	me, err := actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		userID = me.ID // Sin user_id, reserva para sí mismo
+	}
+	if _, err := authorizeSelf(ctx, userID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if bookID == "" {
+		return nil, requiredIDError("ID del libro es obligatorio")
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	var v domain.Validator
+	v.Required("user_id", userID, "el ID del usuario es obligatorio")
+	if v.Err() == nil {
+		if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return nil, err
+			}
+			v.Add("user_id", domain.CodeUnknownRef, "el usuario no existe")
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.copyRepo.Counts(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if counts[bookID].Available > 0 {
+		return nil, domain.ErrHoldNotNeeded
+	}
+
+	hold, err := uc.holdRepo.Create(ctx, &domain.Hold{
+		ID:        uuid.New().String(),
+		BookID:    bookID,
+		UserID:    userID,
+		Status:    domain.HoldWaiting,
+		CreatedAt: uc.clock.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.queue.offerAvailable(ctx, bookID); err != nil {
+		return nil, err
+	}
+	return uc.GetHoldByID(ctx, hold.ID)
+}
+
+// GetHoldByID obtiene una reserva por su ID, con su lugar en la cola si está esperando
+func (uc *HoldUseCase) GetHoldByID(ctx context.Context, id string) (*domain.Hold, error) {
+	if id == "" {
+		return nil, requiredIDError("ID de la reserva es obligatorio")
+	}
+	hold, err := uc.holdRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeSelf(ctx, hold.UserID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+
+	holds, err := uc.withPositions(ctx, []*domain.Hold{hold})
+	if err != nil {
+		return nil, err
+	}
+	return holds[0], nil
+}
+
+// ListBookHolds obtiene una página de las reservas de un libro (GET /api/books/:id/holds)
+//
+// 📄 Por defecto en orden de la cola (created_at ascendente); ?status=waiting para ver solo la cola
+func (uc *HoldUseCase) ListBookHolds(ctx context.Context, bookID string, q domain.HoldQuery) (*domain.Page[*domain.Hold], error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if bookID == "" {
+		return nil, requiredIDError("ID del libro es obligatorio")
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	if q.Sort == "" {
+		q.Sort = domain.SortByCreatedAt
+	}
+	return uc.listHolds(ctx, q, func(f *domain.HoldFilter) { f.BookID = bookID })
+}
+
+// ListUserHolds obtiene una página de las reservas de un usuario (GET /api/users/:id/holds)
+//
+// 👤 404 si el usuario no existe; por defecto lo más nuevo primero
+func (uc *HoldUseCase) ListUserHolds(ctx context.Context, userID string, q domain.HoldQuery) (*domain.Page[*domain.Hold], error) {
+	if _, err := authorizeSelf(ctx, userID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, requiredIDError("ID del usuario es obligatorio")
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.listHolds(ctx, q, func(f *domain.HoldFilter) { f.UserID = userID })
+}
+
+// listHolds implementa los listados; pin fija el filtro de la ruta DESPUÉS de leer el cursor
+func (uc *HoldUseCase) listHolds(ctx context.Context, q domain.HoldQuery, pin func(*domain.HoldFilter)) (*domain.Page[*domain.Hold], error) {
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByCreatedAt); err != nil {
+		return nil, err
+	}
+	pin(&q.Filter)
+
+	if q.Filter.Status != "" && !q.Filter.Status.Valid() {
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "status",
+			Code:    domain.CodeInvalidFormat,
+			Message: "el estado debe ser waiting, ready, fulfilled, cancelled o expired",
+		})
+	}
+
+	holds, total, err := uc.holdRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if holds, err = uc.withPositions(ctx, holds); err != nil {
+		return nil, err
+	}
+
+	return newPage(holds, total, q.PageRequest, q.Filter), nil
+}
+
+// CancelHold cancela una reserva activa (POST /api/holds/:id/cancel)
+//
+// 🔁 Si ya tenía un ejemplar apartado, ese ejemplar pasa a la siguiente reserva de la cola
+func (uc *HoldUseCase) CancelHold(ctx context.Context, id string) (*domain.Hold, error) {
+	hold, err := uc.GetHoldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.Status.Active() {
+		return nil, domain.ErrHoldNotActive
+	}
+
+	cancelled, err := uc.queue.close(ctx, hold, domain.HoldCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status == domain.HoldReady {
+		if err := uc.queue.offer(ctx, hold.BookID, hold.CopyID, domain.CopyOnHold); err != nil {
+			return nil, err
+		}
+	}
+	return cancelled, nil
+}
+
+// ExpireHolds vence las reservas listas cuyo plazo de retiro ya pasó y retorna cuántas venció
+//
+// ⏰ Lo ejecuta periódicamente el servidor (ver HOLD_SWEEP_INTERVAL en main.go).
+// Cada ejemplar liberado se ofrece a la siguiente reserva de la cola.
+func (uc *HoldUseCase) ExpireHolds(ctx context.Context) (int, error) {
+	now := uc.clock.Now()
+	expired := 0
+	for {
+		// Las que se procesan dejan de cumplir el filtro: siempre se pide la primera página
+		holds, _, err := uc.holdRepo.List(ctx, domain.HoldQuery{
+			PageRequest: domain.PageRequest{Sort: domain.SortByCreatedAt, Limit: MaxPageLimit},
+			Filter:      domain.HoldFilter{Status: domain.HoldReady, ExpiresBefore: now},
+		})
+		if err != nil || len(holds) == 0 {
+			return expired, err
+		}
+
+		for _, hold := range holds {
+			_, err := uc.queue.close(ctx, hold, domain.HoldExpired)
+			if errors.Is(err, domain.ErrHoldStatusChanged) {
+				continue // La retiraron o cancelaron mientras tanto
+			}
+			if err != nil {
+				return expired, err
+			}
+			expired++
+			if err := uc.queue.offer(ctx, hold.BookID, hold.CopyID, domain.CopyOnHold); err != nil {
+				return expired, err
+			}
+		}
+	}
+}
+
+// withPositions completa Position en COPIAS de las reservas en espera (una sola consulta)
+func (uc *HoldUseCase) withPositions(ctx context.Context, holds []*domain.Hold) ([]*domain.Hold, error) {
+	var ids []string
+	for _, hold := range holds {
+		if hold.Status == domain.HoldWaiting {
+			ids = append(ids, hold.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return holds, nil
+	}
+
+	positions, err := uc.holdRepo.Positions(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Hold, len(holds))
+	for i, hold := range holds {
+		cp := *hold
+		cp.Position = positions[hold.ID]
+		result[i] = &cp
+	}
+	return result, nil
+}
+
+// holdQueue reúne las transiciones que comparten reservas, préstamos e inventario
+//
+// 🔗 ¿Por qué un tipo aparte y no métodos de HoldUseCase?
+// Devolver un préstamo, dar de alta un ejemplar o cancelar una reserva terminan todos
+// igual: "si alguien espera este libro, apartarle el ejemplar". LoanUseCase y CopyUseCase
+// usan holdQueue sin depender de HoldUseCase.
+type holdQueue struct {
+	holds  repository.HoldRepository
+	copies repository.CopyRepository
+	pickup time.Duration // Cuánto se guarda un ejemplar apartado
+	clock  Clock
+}
+
+// newHoldQueue arma la cola con el plazo de retiro de la política
+func newHoldQueue(holds repository.HoldRepository, copies repository.CopyRepository, policy domain.LoanPolicy, clock Clock) holdQueue {
+	return holdQueue{holds: holds, copies: copies, pickup: policy.HoldPickupPeriod, clock: clock}
+}
+
+// offer aparta el ejemplar (que está en from) para la reserva más antigua del libro
+//
+// 🔄 Flujo:
+// 1. Buscar la primera reserva en espera; si no hay, un ejemplar apartado vuelve a available
+// 2. Pasar el ejemplar de from a on_hold (si otro lo tomó antes, no hay nada que ofrecer)
+// 3. Pasar la reserva de waiting a ready; si justo la cancelaron, probar con la siguiente
+func (q holdQueue) offer(ctx context.Context, bookID, copyID string, from domain.CopyStatus) error {
+	if copyID == "" {
+		return nil
+	}
+
+	for {
+		next, _, err := q.holds.List(ctx, domain.HoldQuery{
+			PageRequest: domain.PageRequest{Sort: domain.SortByCreatedAt, Limit: 1},
+			Filter:      domain.HoldFilter{BookID: bookID, Status: domain.HoldWaiting},
+		})
+		if err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			if from == domain.CopyOnHold {
+				return ignoreCopyGone(q.copies.SetStatus(ctx, copyID, domain.CopyOnHold, domain.CopyAvailable))
+			}
+			return nil
+		}
+
+		if from != domain.CopyOnHold {
+			if _, err := q.copies.SetStatus(ctx, copyID, from, domain.CopyOnHold); err != nil {
+				return ignoreCopyGone(nil, err)
+			}
+			from = domain.CopyOnHold
+		}
+
+		now := q.clock.Now()
+		expires := now.Add(q.pickup)
+		ready := *next[0]
+		ready.Status = domain.HoldReady
+		ready.CopyID = copyID
+		ready.ReadyAt, ready.ExpiresAt = &now, &expires
+		_, err = q.holds.Transition(ctx, &ready, domain.HoldWaiting)
+		if errors.Is(err, domain.ErrHoldStatusChanged) {
+			continue // La cancelaron o la atendió otro ejemplar: probar con la siguiente
+		}
+		return err
+	}
+}
+
+// offerAvailable ofrece a la cola los ejemplares disponibles de un libro
+// 💡 Cierra la carrera "se devolvió un ejemplar justo antes de que se anotara la reserva"
+func (q holdQueue) offerAvailable(ctx context.Context, bookID string) error {
+	available, _, err := q.copies.List(ctx, domain.CopyQuery{
+		PageRequest: domain.PageRequest{Sort: domain.SortByBarcode, Limit: MaxPageLimit},
+		Filter:      domain.CopyFilter{BookID: bookID, Status: domain.CopyAvailable},
+	})
+	if err != nil {
+		return err
+	}
+	for _, cp := range available {
+		if err := q.offer(ctx, bookID, cp.ID, domain.CopyAvailable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close termina una reserva activa con el estado indicado (fulfilled, cancelled o expired)
+func (q holdQueue) close(ctx context.Context, hold *domain.Hold, status domain.HoldStatus) (*domain.Hold, error) {
+	now := q.clock.Now()
+	closed := *hold
+	closed.Status = status
+	closed.ClosedAt = &now
+	closed.Position = 0
+	return q.holds.Transition(ctx, &closed, hold.Status)
+}
+
+// ignoreCopyGone descarta los errores de un ejemplar que se borró o cambió de estado:
+// en ese caso simplemente no hay ejemplar para ofrecer
+func ignoreCopyGone(_ *domain.Copy, err error) error {
+	if errors.Is(err, domain.ErrCopyNotFound) || errors.Is(err, domain.ErrCopyStatusChanged) {
+		return nil
+	}
+	return err
+}
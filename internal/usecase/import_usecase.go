@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// MaxImportRejections es cuántas filas rechazadas se detallan en el reporte
+//
+// 💾 Un archivo con el separador equivocado rechaza TODAS sus filas: sin este
+// tope, un millón de rechazos viviría en memoria. Los demás solo se cuentan
+const MaxImportRejections = 10000
+
+// BookImportRow es una fila del archivo a importar, ya traducida a BookInput
+type BookImportRow struct {
+	Line   int       // Línea del archivo, para el reporte
+	Record string    // La fila tal como venía en el archivo
+	Input  BookInput // Los datos del libro (las celdas vacías quedan en "" o 0)
+	Err    error     // La fila no se pudo interpretar (ej: un año que no es número)
+}
+
+// BookImportSource entrega las filas de un archivo de a una
+//
+// 🌊 Una fila por vez: ni quien lee el archivo ni el caso de uso lo cargan
+// entero en memoria (ver delivery/tabular)
+//
+// 💡 Next retorna io.EOF al terminar. Un error en UNA fila va en BookImportRow.Err;
+// un error de Next es del archivo entero (ej: la conexión se cortó) y aborta la importación
+type BookImportSource interface {
+	Next() (*BookImportRow, error)
+}
+
+// BookImportOptions configura una importación
+type BookImportOptions struct {
+	DryRun bool // Validar todas las filas sin guardar nada
+	Upsert bool // Un ISBN que ya existe actualiza ese libro en lugar de rechazar la fila
+}
+
+// ImportBooks importa libros fila por fila (catálogos de miles de filas)
+//
+// 🔄 Por cada fila:
+// 1. Si no se pudo interpretar → rechazada
+// 2. Un ISBN repetido dentro del mismo archivo → rechazada (¿cuál de las dos vale?)
+// 3. Si el ISBN ya existe: con Upsert se actualiza ese libro; sin Upsert → rechazada
+// 4. Si no, se crea con CreateBook: mismas reglas y mismos mensajes que POST /api/books
+//
+// 🧪 DryRun valida igual (incluidos los ISBN que ya existen) pero no guarda nada:
+// sirve para revisar un archivo antes de cargarlo
+//
+// ✏️ Al actualizar, las celdas vacías conservan el valor actual del libro:
+// un archivo con solo isbn y publisher no borra el título ni la descripción
+//
+// ⚠️ No es una transacción: las filas válidas se guardan aunque otras se rechacen.
+// Un error técnico (base de datos caída, petición cancelada) corta la importación
+// y se retorna junto con el reporte de lo hecho hasta ese momento
+func (uc *BookUseCase) ImportBooks(ctx context.Context, rows BookImportSource, opts BookImportOptions) (*domain.ImportReport, error) {
+	if _, err := authorize(ctx, domain.PermBooksWrite); err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{DryRun: opts.DryRun, Upsert: opts.Upsert, Rejections: []domain.ImportRejection{}}
+	seen := make(map[string]int) // ISBN → línea que ya lo importó
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Total++
+
+		created, err := uc.importRow(ctx, row, opts, seen)
+		switch {
+		case err == nil && created:
+			report.Created++
+		case err == nil:
+			report.Updated++
+		case rejectable(err):
+			report.Rejected++
+			if len(report.Rejections) < MaxImportRejections {
+				report.Rejections = append(report.Rejections, newRejection(row, err))
+			} else {
+				report.RejectionsTruncated = true
+			}
+		default:
+			return report, err
+		}
+	}
+}
+
+// importRow importa una fila y dice si el libro es nuevo (true) o se actualizó (false)
+func (uc *BookUseCase) importRow(ctx context.Context, row *BookImportRow, opts BookImportOptions, seen map[string]int) (bool, error) {
+	if row.Err != nil {
+		return false, row.Err
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// 🔑 El ISBN normalizado es la clave: "0132350882" y "978-0-13-235088-4" son el mismo libro
+	// Si es inválido no se busca: lo informa la validación completa
+	isbn, _ := domain.NormalizeISBN(row.Input.ISBN)
+	var existing *domain.Book
+	if isbn != "" {
+		if line, dup := seen[isbn]; dup {
+			return false, domain.NewConflictError(fmt.Sprintf("el ISBN %s ya aparece en la línea %d del archivo", isbn, line))
+		}
+
+		book, err := uc.bookRepo.GetByISBN(ctx, isbn)
+		switch {
+		case err == nil:
+			existing = book
+		case !errors.Is(err, domain.ErrNotFound):
+			return false, err
+		}
+	}
+	if existing != nil && !opts.Upsert {
+		return false, domain.ErrISBNAlreadyInUse
+	}
+
+	in := row.Input
+	if existing != nil {
+		in = mergeBookInput(existing, in)
+	}
+
+	var err error
+	switch {
+	case opts.DryRun:
+		err = uc.validateBook(ctx, in)
+	case existing != nil:
+		// La versión leída evita pisar una edición hecha mientras se importaba
+		_, err = uc.UpdateBook(ctx, existing.ID, in, existing.Version)
+	default:
+		_, err = uc.CreateBook(ctx, in)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if isbn != "" {
+		seen[isbn] = row.Line
+	}
+	return existing == nil, nil
+}
+
+// validateBook hace las validaciones de CreateBook sin guardar (para el dry run)
+func (uc *BookUseCase) validateBook(ctx context.Context, in BookInput) error {
+	book, err := newBook("", in)
+	if err != nil {
+		return err
+	}
+	return uc.resolveAuthors(ctx, book)
+}
+
+// mergeBookInput completa las celdas vacías de la fila con los datos actuales del libro
+// 💡 Los autores vinculados se conservan: el archivo no los trae
+func mergeBookInput(current *domain.Book, in BookInput) BookInput {
+	merged := BookInput{
+		Title:           orDefault(in.Title, current.Title),
+		Author:          orDefault(in.Author, current.Author),
+		ISBN:            orDefault(in.ISBN, current.ISBN),
+		Publisher:       orDefault(in.Publisher, current.Publisher),
+		PublicationYear: in.PublicationYear,
+		Language:        orDefault(in.Language, current.Language),
+		PageCount:       in.PageCount,
+		Description:     orDefault(in.Description, current.Description),
+		Subjects:        in.Subjects,
+		Edition:         orDefault(in.Edition, current.Edition),
+		Authors:         in.Authors,
+	}
+	if merged.PublicationYear == 0 {
+		merged.PublicationYear = current.PublicationYear
+	}
+	if merged.PageCount == 0 {
+		merged.PageCount = current.PageCount
+	}
+	if len(merged.Subjects) == 0 {
+		merged.Subjects = current.Subjects
+	}
+	if len(merged.Authors) == 0 {
+		merged.Authors = current.Authors
+	}
+	return merged
+}
+
+// orDefault retorna value, o fallback si value está vacío
+func orDefault(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
+// rejectable indica si el error es de la fila (se rechaza y se sigue) y no del sistema (se aborta)
+func rejectable(err error) bool {
+	return errors.Is(err, domain.ErrValidation) || errors.Is(err, domain.ErrConflict) ||
+		errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrPreconditionFailed)
+}
+
+// newRejection arma la entrada del reporte para una fila rechazada
+func newRejection(row *BookImportRow, err error) domain.ImportRejection {
+	rejection := domain.ImportRejection{Line: row.Line, Reason: err.Error(), Record: row.Record}
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		rejection.Errors = domainErr.Fields
+	}
+	return rejection
+}
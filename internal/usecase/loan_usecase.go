@@ -0,0 +1,416 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// LoanUseCase contiene la lógica de negocio de los préstamos
+//
+// 🔗 Es el primer caso de uso que coordina VARIAS entidades:
+// verifica que el usuario y el libro existan antes de registrar el préstamo
+//
+// 📚 Se presta un EJEMPLAR: el apartado por una reserva del usuario, el indicado por el cliente
+// o el primer disponible del libro
+//
+// 📋 Reglas (ver domain.LoanPolicy):
+// - Un ejemplar no se puede prestar si no está disponible
+// - Un usuario no puede superar MaxActiveLoans préstamos activos
+// - Un préstamo se renueva como máximo MaxRenewals veces y nunca si está vencido
+// - Un usuario con multas impagas por encima de Fines.DebtThreshold no puede pedir préstamos
+//
+// 💰 Al devolver un préstamo vencido queda calculada su multa (ver FineUseCase)
+type LoanUseCase struct {
+	loanRepo repository.LoanRepository // Dependencia inyectada del repositorio
+	bookRepo repository.BookRepository // Para verificar que el libro exista
+	userRepo repository.UserRepository // Para verificar que el usuario exista
+	copyRepo repository.CopyRepository // Para elegir el ejemplar a prestar
+	holdRepo repository.HoldRepository // Para atender las reservas del usuario
+	queue    holdQueue                 // Para ofrecer a la cola los ejemplares devueltos
+	ledger   fineLedger                // Para calcular multas y la deuda del usuario
+	policy   domain.LoanPolicy         // Reglas de préstamo (duración, límites)
+	clock    Clock                     // Hora actual (inyectable para tests)
+}
+
+// NewLoanUseCase constructor para LoanUseCase
+// 💡 clock puede ser nil: se usa el reloj del sistema
+func NewLoanUseCase(loanRepo repository.LoanRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, copyRepo repository.CopyRepository, holdRepo repository.HoldRepository, fineRepo repository.FineRepository, policy domain.LoanPolicy, clock Clock) *LoanUseCase {
+	clock = orSystemClock(clock)
+	return &LoanUseCase{
+		loanRepo: loanRepo,
+		bookRepo: bookRepo,
+		userRepo: userRepo,
+		copyRepo: copyRepo,
+		holdRepo: holdRepo,
+		queue:    newHoldQueue(holdRepo, copyRepo, policy, clock),
+		ledger:   newFineLedger(fineRepo, loanRepo, policy, clock),
+		policy:   policy,
+		clock:    clock,
+	}
+}
+
+// CheckoutInput son los datos para prestar un libro
+//
+// 💡 CopyID es opcional: sin él se presta cualquier ejemplar disponible del libro
+type CheckoutInput struct {
+	BookID string
+	CopyID string
+	UserID string
+}
+
+// Checkout presta un ejemplar de un libro a un usuario
+//
+// 🔄 Flujo:
+// 1. Validar que vengan ambos IDs
+// 2. Verificar que el usuario, el libro y el ejemplar (si vino) existan (400 unknown_ref si no)
+// 3. 409 si la deuda del usuario supera el límite (domain.ErrFineDebtExceeded)
+// 4. Calcular el vencimiento según la política
+// 5. Delegar al repositorio, que verifica ATÓMICAMENTE ejemplar libre y límite del usuario
+//
+// 🔁 Sin CopyID se prueban los ejemplares disponibles en orden de código de barras:
+// si otra petición se llevó uno entre el listado y el préstamo, se pasa al siguiente
+//
+// 📌 Si el usuario tiene una reserva lista, se le presta el ejemplar apartado para él;
+// al prestar, su reserva activa de ese libro queda como retirada (fulfilled)
+func (uc *LoanUseCase) Checkout(ctx context.Context, in CheckoutInput) (*domain.Loan, error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	in.BookID, in.UserID = strings.TrimSpace(in.BookID), strings.TrimSpace(in.UserID)
+	in.CopyID = strings.TrimSpace(in.CopyID)
+
+	var v domain.Validator
+	v.Required("book_id", in.BookID, "el ID del libro es obligatorio")
+	v.Required("user_id", in.UserID, "el ID del usuario es obligatorio")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, in.UserID); err != nil {
+		v.Add("user_id", domain.CodeUnknownRef, "el usuario no existe")
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if _, err := uc.bookRepo.GetByID(ctx, in.BookID); err != nil {
+		v.Add("book_id", domain.CodeUnknownRef, "el libro no existe")
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if in.CopyID != "" {
+		cp, err := uc.copyRepo.GetByID(ctx, in.CopyID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if err != nil || cp.BookID != in.BookID {
+			v.Add("copy_id", domain.CodeUnknownRef, "el ejemplar no existe o no pertenece al libro")
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	debt, err := uc.ledger.debt(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if uc.policy.Fines.Blocks(debt) {
+		return nil, domain.ErrFineDebtExceeded
+	}
+
+	now := uc.clock.Now()
+	newLoan := func(copyID string) *domain.Loan {
+		return &domain.Loan{
+			ID:           uuid.New().String(),
+			BookID:       in.BookID,
+			CopyID:       copyID,
+			UserID:       in.UserID,
+			Status:       domain.LoanActive,
+			CheckedOutAt: now,
+			DueAt:        now.Add(uc.policy.Period),
+		}
+	}
+
+	hold, err := uc.activeHold(ctx, in.UserID, in.BookID)
+	if err != nil {
+		return nil, err
+	}
+	loan, err := uc.checkoutCopy(ctx, in, hold, newLoan)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold != nil {
+		// Si justo venció o se canceló, el préstamo igual es válido: no hay nada que cerrar
+		_, err := uc.queue.close(ctx, hold, domain.HoldFulfilled)
+		if errors.Is(err, domain.ErrHoldStatusChanged) {
+			return loan, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Se llevó otro ejemplar: el que tenía apartado pasa al siguiente de la cola
+		if hold.Status == domain.HoldReady && hold.CopyID != loan.CopyID {
+			if err := uc.queue.offer(ctx, hold.BookID, hold.CopyID, domain.CopyOnHold); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return loan, nil
+}
+
+// checkoutCopy elige el ejemplar y registra el préstamo
+func (uc *LoanUseCase) checkoutCopy(ctx context.Context, in CheckoutInput, hold *domain.Hold, newLoan func(copyID string) *domain.Loan) (*domain.Loan, error) {
+	maxActive := uc.policy.MaxActiveLoans
+
+	// El ejemplar apartado por la reserva (on_hold → on_loan)
+	if hold != nil && hold.Status == domain.HoldReady && (in.CopyID == "" || in.CopyID == hold.CopyID) {
+		return uc.loanRepo.Checkout(ctx, newLoan(hold.CopyID), domain.CopyOnHold, maxActive)
+	}
+
+	if in.CopyID != "" {
+		return uc.loanRepo.Checkout(ctx, newLoan(in.CopyID), domain.CopyAvailable, maxActive)
+	}
+
+	candidates, _, err := uc.copyRepo.List(ctx, domain.CopyQuery{
+		PageRequest: domain.PageRequest{Sort: domain.SortByBarcode, Limit: MaxPageLimit},
+		Filter:      domain.CopyFilter{BookID: in.BookID, Status: domain.CopyAvailable},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range candidates {
+		loan, err := uc.loanRepo.Checkout(ctx, newLoan(cp.ID), domain.CopyAvailable, maxActive)
+		if errors.Is(err, domain.ErrCopyNotAvailable) {
+			continue // Otra petición se lo llevó: probar con el siguiente
+		}
+		return loan, err
+	}
+	return nil, domain.ErrNoCopiesAvailable
+}
+
+// activeHold retorna la reserva activa (waiting o ready) del usuario para el libro, o nil
+func (uc *LoanUseCase) activeHold(ctx context.Context, userID, bookID string) (*domain.Hold, error) {
+	holds, _, err := uc.holdRepo.List(ctx, domain.HoldQuery{
+		PageRequest: domain.PageRequest{Sort: domain.SortByCreatedAt, Desc: true, Limit: MaxPageLimit},
+		Filter:      domain.HoldFilter{UserID: userID, BookID: bookID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if hold.Status.Active() {
+			return hold, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetLoanByID obtiene un préstamo por su ID
+func (uc *LoanUseCase) GetLoanByID(ctx context.Context, id string) (*domain.Loan, error) {
+	if id == "" {
+		return nil, requiredIDError("ID del préstamo es obligatorio")
+	}
+	loan, err := uc.loanRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeSelf(ctx, loan.UserID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// ListLoans obtiene una página de préstamos filtrada y ordenada
+//
+// 📄 Se puede ordenar por checked_out_at (por defecto, lo más nuevo primero) o due_at
+// ⏰ El filtro status=overdue se traduce a "activos que vencen antes de ahora"
+func (uc *LoanUseCase) ListLoans(ctx context.Context, q domain.LoanQuery) (*domain.Page[*domain.Loan], error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	return uc.listLoans(ctx, q, "")
+}
+
+// listLoans implementa ListLoans; si userID no está vacío, fija el filtro por usuario
+// DESPUÉS de leer el cursor (un cursor de otro usuario no sirve para ver sus préstamos)
+func (uc *LoanUseCase) listLoans(ctx context.Context, q domain.LoanQuery, userID string) (*domain.Page[*domain.Loan], error) {
+	if q.Sort == "" {
+		q.Sort, q.Desc = domain.SortByCheckedOutAt, true
+	}
+	if err := resolvePage(&q.PageRequest, &q.Filter, domain.SortByCheckedOutAt, domain.SortByDueAt); err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		q.Filter.UserID = userID
+	}
+
+	switch q.Filter.Status {
+	case "", domain.LoanActive, domain.LoanReturned:
+	case domain.LoanOverdue:
+		q.Filter.Status, q.Filter.DueBefore = domain.LoanActive, uc.clock.Now()
+	default:
+		return nil, domain.NewFieldsError(domain.FieldError{
+			Field:   "status",
+			Code:    domain.CodeInvalidFormat,
+			Message: "el estado debe ser active, returned u overdue",
+		})
+	}
+
+	loans, total, err := uc.loanRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(loans, total, q.PageRequest, q.Filter), nil
+}
+
+// ListUserLoans obtiene una página de los préstamos de un usuario (GET /api/users/:id/loans)
+//
+// 👤 404 si el usuario no existe; el filtro por usuario lo fija la ruta
+// ⚡ La existencia del usuario solo se verifica si no hay préstamos: si los hay, existe.
+// Así listar los préstamos de muchos usuarios (GraphQL) no suma un GetByID por cada uno
+func (uc *LoanUseCase) ListUserLoans(ctx context.Context, userID string, q domain.LoanQuery) (*domain.Page[*domain.Loan], error) {
+	if _, err := authorizeSelf(ctx, userID, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, requiredIDError("ID del usuario es obligatorio")
+	}
+
+	page, err := uc.listLoans(ctx, q, userID)
+	if err != nil || page.Total > 0 {
+		return page, err
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ReturnLoan registra la devolución de un préstamo
+//
+// 🔁 Devolver dos veces el mismo préstamo es un conflicto (domain.ErrLoanAlreadyReturned)
+// 📌 Si hay reservas del libro, el ejemplar devuelto queda apartado para la primera de la cola
+// 💰 Si se devolvió tarde, la multa queda calculada con su importe final
+func (uc *LoanUseCase) ReturnLoan(ctx context.Context, id string) (*domain.Loan, error) {
+	if _, err := authorize(ctx, domain.PermCirculation); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, requiredIDError("ID del préstamo es obligatorio")
+	}
+
+	loan, err := uc.loanRepo.Return(ctx, id, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.ledger.assess(ctx, loan); err != nil {
+		return nil, err
+	}
+	if err := uc.queue.offer(ctx, loan.BookID, loan.CopyID, domain.CopyAvailable); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// RenewLoan extiende un préstamo activo por un período más
+//
+// 📅 El nuevo vencimiento se cuenta desde el vencimiento actual (no desde hoy):
+// renovar antes de tiempo no hace perder días
+//
+// 🚫 No se renueva un préstamo devuelto, vencido o que ya agotó sus renovaciones
+func (uc *LoanUseCase) RenewLoan(ctx context.Context, id string) (*domain.Loan, error) {
+	loan, err := uc.GetLoanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status != domain.LoanActive {
+		return nil, domain.ErrLoanAlreadyReturned
+	}
+	if loan.IsOverdue(uc.clock.Now()) {
+		return nil, domain.ErrLoanOverdue
+	}
+
+	return uc.loanRepo.Renew(ctx, id, loan.DueAt.Add(uc.policy.Period), uc.policy.MaxRenewals)
+}
+
+// circulation verifica que borrar un libro, un usuario o un ejemplar no se lleve
+// puesto su historial de préstamos y reservas ni lo que se debe en multas
+//
+// 🔒 En PostgreSQL las FK de loans, holds y fines son ON DELETE RESTRICT: el borrado
+// fallaría igual. Verificarlo antes da un error claro y hace que la memoria se comporte igual
+type circulation struct {
+	loans repository.LoanRepository
+	holds repository.HoldRepository
+	fines repository.FineRepository
+}
+
+// circulationRefs son los filtros que encuentran lo que referencia al registro a borrar
+// y el error de cada caso
+// 💡 Un filtro de reservas o de multas vacío no se consulta (un ejemplar no tiene reservas propias)
+type circulationRefs struct {
+	loans   domain.LoanFilter
+	holds   domain.HoldFilter
+	fines   domain.FineFilter
+	active  error // Hay préstamos activos
+	unpaid  error // Hay multas abiertas
+	history error // Hay préstamos devueltos o reservas (de cualquier estado)
+}
+
+// check retorna el error del primer caso que encuentra, en el orden de circulationRefs
+func (c circulation) check(ctx context.Context, refs circulationRefs) error {
+	refs.loans.Status = domain.LoanActive
+	n, err := c.countLoans(ctx, refs.loans)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return refs.active
+	}
+
+	if refs.fines != (domain.FineFilter{}) {
+		refs.fines.Status = domain.FineOpen
+		_, n, err = c.fines.List(ctx, domain.FineQuery{PageRequest: domain.PageRequest{Limit: 1}, Filter: refs.fines})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return refs.unpaid
+		}
+	}
+
+	refs.loans.Status = ""
+	if n, err = c.countLoans(ctx, refs.loans); err != nil {
+		return err
+	}
+	if n > 0 {
+		return refs.history
+	}
+
+	if refs.holds != (domain.HoldFilter{}) {
+		_, n, err = c.holds.List(ctx, domain.HoldQuery{PageRequest: domain.PageRequest{Limit: 1}, Filter: refs.holds})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return refs.history
+		}
+	}
+	return nil
+}
+
+// countLoans cuenta los préstamos que cumplen el filtro
+func (c circulation) countLoans(ctx context.Context, f domain.LoanFilter) (int, error) {
+	_, n, err := c.loans.List(ctx, domain.LoanQuery{
+		PageRequest: domain.PageRequest{Limit: 1, Sort: domain.SortByCheckedOutAt},
+		Filter:      f,
+	})
+	return n, err
+}
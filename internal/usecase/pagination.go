@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-book-clean-architecture-api/internal/domain"
+)
+
+// Límites de paginación
+//
+// 🛡️ MaxPageLimit evita que un cliente pida "todo" y tumbe el servidor
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// cursorState es lo que viaja DENTRO del cursor opaco
+//
+// 🔐 El cliente solo ve un string base64; guardar orden y filtros en él
+// garantiza que la página siguiente use exactamente la misma consulta
+type cursorState[F any] struct {
+	Offset int    `json:"o"`
+	Limit  int    `json:"l"`
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Filter F      `json:"f"`
+}
+
+// resolvePage normaliza y valida una petición de página
+//
+// 🔄 Pasos:
+// 1. Si viene un cursor, reemplaza offset, orden y filtros por los del cursor
+// 2. Aplica el límite por defecto y el máximo
+// 3. Valida offset y campo de ordenamiento (contra la lista permitida)
+//
+// 💡 Sin orden explícito se usa created_at descendente (lo más nuevo primero)
+func resolvePage[F any](p *domain.PageRequest, filter *F, sortable ...string) error {
+	var v domain.Validator
+
+	if p.Cursor != "" {
+		state, err := decodeCursor[F](p.Cursor)
+		if err != nil {
+			v.Add("cursor", domain.CodeInvalidFormat, "el cursor de paginación no es válido")
+			return v.Err()
+		}
+		p.Offset, p.Sort, p.Desc, *filter = state.Offset, state.Sort, state.Desc, state.Filter
+		if p.Limit == 0 {
+			p.Limit = state.Limit
+		}
+	}
+
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	v.Check(p.Limit > 0 && p.Limit <= MaxPageLimit, "limit", domain.CodeOutOfRange, "el límite debe estar entre 1 y 100")
+	v.Check(p.Offset >= 0, "offset", domain.CodeOutOfRange, "el offset no puede ser negativo")
+
+	if p.Sort == "" {
+		p.Sort, p.Desc = domain.SortByCreatedAt, true
+	}
+	v.Check(contains(sortable, p.Sort), "sort", domain.CodeInvalidFormat, "no se puede ordenar por el campo "+p.Sort)
+
+	return v.Err()
+}
+
+// newPage arma el resultado con los cursores a la página siguiente y anterior
+func newPage[T, F any](items []T, total int, p domain.PageRequest, filter F) *domain.Page[T] {
+	page := &domain.Page[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  p.Limit,
+		Offset: p.Offset,
+	}
+
+	state := cursorState[F]{Limit: p.Limit, Sort: p.Sort, Desc: p.Desc, Filter: filter}
+	if next := p.Offset + p.Limit; next < total {
+		state.Offset = next
+		page.NextCursor = encodeCursor(state)
+	}
+	if p.Offset > 0 {
+		state.Offset = max(p.Offset-p.Limit, 0)
+		page.PrevCursor = encodeCursor(state)
+	}
+
+	return page
+}
+
+// encodeCursor serializa el estado como JSON en base64 apto para URLs
+func encodeCursor[F any](state cursorState[F]) string {
+	raw, _ := json.Marshal(state) // No falla: solo contiene strings, ints y bools
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor es la operación inversa de encodeCursor
+func decodeCursor[F any](cursor string) (cursorState[F], error) {
+	var state cursorState[F]
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(raw, &state)
+	return state, err
+}
+
+// contains indica si s está en values
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newAPIKey crea una API key de prueba para el usuario y retorna la clave completa
+func (f loanFixture) newAPIKey(t *testing.T, userID string, scopes ...domain.Permission) *domain.IssuedAPIKey {
+	t.Helper()
+	key, err := f.apiKeys.CreateAPIKey(staffCtx, usecase.APIKeyInput{Name: "integración", UserID: userID, Scopes: scopes})
+	if err != nil {
+		t.Fatalf("No se pudo crear la API key: %v", err)
+	}
+	return key
+}
+
+// TestCreateAPIKey_Validation prueba las reglas de creación de API keys
+func TestCreateAPIKey_Validation(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ana := f.newUser(t, "ana@example.com") // Socia: solo books:read
+	past := f.clock.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		input usecase.APIKeyInput
+		field string
+	}{
+		{"sin nombre", usecase.APIKeyInput{UserID: ana, Scopes: []domain.Permission{domain.PermBooksRead}}, "name"},
+		{"sin scopes", usecase.APIKeyInput{Name: "x", UserID: ana}, "scopes"},
+		{"scope desconocido", usecase.APIKeyInput{Name: "x", UserID: ana, Scopes: []domain.Permission{domain.PermCirculation}}, "scopes"},
+		{"scope que el rol no tiene", usecase.APIKeyInput{Name: "x", UserID: ana, Scopes: []domain.Permission{domain.PermBooksWrite}}, "scopes"},
+		{"vencimiento pasado", usecase.APIKeyInput{Name: "x", UserID: ana, Scopes: []domain.Permission{domain.PermBooksRead}, ExpiresAt: &past}, "expires_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := f.apiKeys.CreateAPIKey(staffCtx, tt.input)
+
+			// Assert
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != tt.field {
+				t.Errorf("Se esperaba un error en el campo %s, pero se obtuvo: %v", tt.field, err)
+			}
+		})
+	}
+
+	// Solo un admin crea claves
+	ctx := f.actorCtx(t, ana)
+	_, err := f.apiKeys.CreateAPIKey(ctx, usecase.APIKeyInput{Name: "x", Scopes: []domain.Permission{domain.PermBooksRead}})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden, pero se obtuvo: %v", err)
+	}
+}
+
+// TestAPIKey_AuthenticateAndScopes prueba la verificación de la clave y que sus scopes acoten al usuario
+func TestAPIKey_AuthenticateAndScopes(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	admin, _ := f.users.BootstrapAdmin(context.Background(), "admin@example.com", testPassword)
+	issued := f.newAPIKey(t, admin.ID, domain.PermBooksRead)
+
+	// Assert: la clave lleva su prefijo público
+	if !strings.HasPrefix(issued.Key, "bk_"+issued.Prefix+"_") {
+		t.Fatalf("Se esperaba una clave bk_<prefijo>_<secreto>, pero se obtuvo: %q", issued.Key)
+	}
+
+	// Act: autenticar con la clave
+	user, key, err := f.apiKeys.Authenticate(context.Background(), issued.Key)
+	if err != nil || user.ID != admin.ID {
+		t.Fatalf("Se esperaba autenticar al admin, pero se obtuvo: %+v (err: %v)", user, err)
+	}
+	if stored, _ := f.apiKeys.GetAPIKeyByID(staffCtx, key.ID); stored == nil || stored.LastUsedAt == nil {
+		t.Errorf("Se esperaba registrado el último uso, pero se obtuvo: %+v", stored)
+	}
+
+	// Aunque el usuario es admin, la clave solo lee el catálogo
+	ctx := usecase.ContextWithAPIKey(context.Background(), user, key)
+	book := f.newBook(t, "Rayuela")
+	if _, err := f.books.GetBookByID(ctx, book); err != nil {
+		t.Errorf("Se esperaba poder leer con books:read, pero se obtuvo: %v", err)
+	}
+	if _, err := f.books.CreateBook(ctx, usecase.BookInput{Title: "Ficciones", Author: "Borges"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al escribir con books:read, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.GetUserByID(ctx, admin.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al leer usuarios con books:read, pero se obtuvo: %v", err)
+	}
+
+	// Claves mal formadas o con el secreto alterado
+	for _, raw := range []string{"", "bk_nada", issued.Key + "x", strings.Replace(issued.Key, "bk_", "xx_", 1)} {
+		if _, _, err := f.apiKeys.Authenticate(context.Background(), raw); !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Errorf("Se esperaba ErrInvalidAPIKey para %q, pero se obtuvo: %v", raw, err)
+		}
+	}
+}
+
+// TestAPIKey_RevokeAndExpiry verifica que una clave revocada o vencida deja de valer
+func TestAPIKey_RevokeAndExpiry(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ana := f.newUser(t, "ana@example.com")
+	revoked := f.newAPIKey(t, ana, domain.PermBooksRead)
+	expiresAt := f.clock.Now().Add(24 * time.Hour)
+	expiring, err := f.apiKeys.CreateAPIKey(staffCtx, usecase.APIKeyInput{
+		Name: "temporal", UserID: ana, Scopes: []domain.Permission{domain.PermBooksRead}, ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+
+	// Act + Assert: revocar es idempotente y la clave deja de valer
+	if err := f.apiKeys.RevokeAPIKey(staffCtx, revoked.ID); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if err := f.apiKeys.RevokeAPIKey(staffCtx, revoked.ID); err != nil {
+		t.Errorf("Se esperaba que revocar dos veces no fallara, pero se obtuvo: %v", err)
+	}
+	if _, _, err := f.apiKeys.Authenticate(context.Background(), revoked.Key); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Errorf("Se esperaba ErrInvalidAPIKey con la clave revocada, pero se obtuvo: %v", err)
+	}
+
+	// La clave temporal vale hasta su vencimiento
+	if _, _, err := f.apiKeys.Authenticate(context.Background(), expiring.Key); err != nil {
+		t.Errorf("Se esperaba que la clave temporal valiera, pero se obtuvo: %v", err)
+	}
+	f.clock.Advance(25 * time.Hour)
+	if _, _, err := f.apiKeys.Authenticate(context.Background(), expiring.Key); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Errorf("Se esperaba ErrInvalidAPIKey con la clave vencida, pero se obtuvo: %v", err)
+	}
+
+	// El listado incluye las revocadas y las vencidas
+	page, err := f.apiKeys.ListAPIKeys(staffCtx, domain.APIKeyQuery{Filter: domain.APIKeyFilter{UserID: ana}})
+	if err != nil || page.Total != 2 {
+		t.Errorf("Se esperaban 2 claves de Ana, pero se obtuvo: %+v (err: %v)", page, err)
+	}
+}
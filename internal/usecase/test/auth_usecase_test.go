@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/usecase"
+	"testing"
+	"time"
+)
+
+// TestCreateUser_Password verifica que la contraseña se valida y se guarda solo como hash
+func TestCreateUser_Password(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := context.Background()
+
+	// Act + Assert: contraseña corta
+	_, err := f.users.CreateUser(ctx, "Ana", "ana@example.com", "corta")
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != "password" {
+		t.Fatalf("Se esperaba un error en el campo password, pero se obtuvo: %v", err)
+	}
+
+	// Contraseña válida: el hash no es la contraseña
+	user, err := f.users.CreateUser(ctx, "Ana", "ana@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if user.PasswordHash == "" || user.PasswordHash == testPassword {
+		t.Errorf("Se esperaba un hash de la contraseña, pero se obtuvo: %q", user.PasswordHash)
+	}
+}
+
+// TestLogin_Credentials prueba el login y la verificación del access token
+func TestLogin_Credentials(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := context.Background()
+	ana := f.newUser(t, "ana@example.com")
+
+	// Act + Assert: email inexistente y contraseña incorrecta dan el mismo error
+	for _, tc := range []struct{ email, password string }{
+		{"nadie@example.com", testPassword},
+		{"ana@example.com", "otra-contraseña"},
+	} {
+		if _, err := f.auth.Login(ctx, tc.email, tc.password); !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Errorf("Se esperaba ErrInvalidCredentials para %s, pero se obtuvo: %v", tc.email, err)
+		}
+	}
+
+	pair, err := f.auth.Login(ctx, "ana@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if pair.TokenType != "Bearer" || pair.ExpiresIn != int64((15*time.Minute)/time.Second) {
+		t.Errorf("Se esperaba un par Bearer de 15 minutos, pero se obtuvo: %+v", pair)
+	}
+
+	// El access token identifica a Ana; el refresh token no sirve como access token
+	if user, err := f.auth.Authenticate(ctx, pair.AccessToken); err != nil || user.ID != ana {
+		t.Errorf("Se esperaba autenticar a Ana, pero se obtuvo: %+v (err: %v)", user, err)
+	}
+	if _, err := f.auth.Authenticate(ctx, pair.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Se esperaba ErrInvalidToken con un refresh token, pero se obtuvo: %v", err)
+	}
+
+	// Vencido el access token, deja de valer
+	f.clock.Advance(16 * time.Minute)
+	if _, err := f.auth.Authenticate(ctx, pair.AccessToken); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("Se esperaba un error Unauthorized con el token vencido, pero se obtuvo: %v", err)
+	}
+}
+
+// TestRefresh_RotationAndReuse prueba la rotación, la detección de reutilización y el logout
+func TestRefresh_RotationAndReuse(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := context.Background()
+	f.newUser(t, "ana@example.com")
+	first, _ := f.auth.Login(ctx, "ana@example.com", testPassword)
+	other, _ := f.auth.Login(ctx, "ana@example.com", testPassword) // Otra sesión (otro dispositivo)
+
+	// Act + Assert: la rotación entrega un par nuevo
+	second, err := f.auth.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("Se esperaba un refresh token nuevo")
+	}
+
+	// Reutilizar el token rotado cierra TODAS las sesiones de Ana
+	if _, err := f.auth.Refresh(ctx, first.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Se esperaba ErrInvalidToken al reutilizar el token, pero se obtuvo: %v", err)
+	}
+	for name, token := range map[string]string{"rotado": second.RefreshToken, "otra sesión": other.RefreshToken} {
+		if _, err := f.auth.Refresh(ctx, token); !errors.Is(err, domain.ErrInvalidToken) {
+			t.Errorf("Se esperaba la sesión %s cerrada, pero se obtuvo: %v", name, err)
+		}
+	}
+
+	// Logout: el refresh token deja de servir y revocar dos veces no es un error
+	third, _ := f.auth.Login(ctx, "ana@example.com", testPassword)
+	if err := f.auth.Revoke(ctx, third.RefreshToken); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if err := f.auth.Revoke(ctx, third.RefreshToken); err != nil {
+		t.Errorf("Se esperaba que revocar dos veces no fallara, pero se obtuvo: %v", err)
+	}
+	if _, err := f.auth.Refresh(ctx, third.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Se esperaba ErrInvalidToken tras el logout, pero se obtuvo: %v", err)
+	}
+}
+
+// TestJWTManager_KeyRotation verifica que los tokens firmados con la clave anterior
+// siguen valiendo mientras esa clave siga configurada
+func TestJWTManager_KeyRotation(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	clock := func() time.Time { return now }
+	newKey := config.SigningKey{ID: "nueva", Secret: []byte("otra-clave-de-prueba-de-32-bytes!!")}
+	oldManager, _ := security.NewJWTManager(config.AuthConfig{Keys: []config.SigningKey{testSigningKey}, ActiveKey: testSigningKey.ID}, clock)
+	rotated, _ := security.NewJWTManager(config.AuthConfig{Keys: []config.SigningKey{newKey, testSigningKey}, ActiveKey: newKey.ID}, clock)
+	retired, _ := security.NewJWTManager(config.AuthConfig{Keys: []config.SigningKey{newKey}, ActiveKey: newKey.ID}, clock)
+	claims := domain.TokenClaims{ID: "t1", Subject: "u1", Kind: domain.TokenAccess, IssuedAt: now, ExpiresAt: now.Add(time.Minute)}
+
+	// Act
+	token, err := oldManager.Issue(claims)
+	if err != nil {
+		t.Fatalf("Issue falló: %v", err)
+	}
+
+	// Assert
+	if parsed, err := rotated.Parse(token); err != nil || parsed.Subject != "u1" {
+		t.Errorf("Se esperaba verificar con la clave anterior, pero se obtuvo: %+v (err: %v)", parsed, err)
+	}
+	if _, err := retired.Parse(token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Se esperaba ErrInvalidToken con la clave retirada, pero se obtuvo: %v", err)
+	}
+}
+
+// TestUserFromContext verifica el transporte del usuario autenticado en el contexto
+func TestUserFromContext(t *testing.T) {
+	if _, ok := usecase.UserFromContext(context.Background()); ok {
+		t.Error("Se esperaba un contexto sin usuario")
+	}
+	ctx := usecase.ContextWithUser(context.Background(), &domain.User{ID: "u1"})
+	if user, ok := usecase.UserFromContext(ctx); !ok || user.ID != "u1" {
+		t.Errorf("Se esperaba el usuario u1, pero se obtuvo: %+v", user)
+	}
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/repository"
+	"go-book-clean-architecture-api/internal/usecase"
+	"testing"
+)
+
+// newAuthorFixture arma los casos de uso de libros y autores sobre los MISMOS repositorios en memoria
+func newAuthorFixture() (*usecase.BookUseCase, *usecase.AuthorUseCase) {
+	var bookRepo repository.BookRepository = memory.NewInMemoryBookRepository()
+	authorRepo := memory.NewInMemoryAuthorRepository()
+	copyRepo := memory.NewInMemoryCopyRepository()
+	return usecase.NewBookUseCase(bookRepo, authorRepo, copyRepo, memory.NewInMemoryLoanRepository(copyRepo), memory.NewInMemoryHoldRepository(), memory.NewInMemoryFineRepository()), usecase.NewAuthorUseCase(authorRepo, bookRepo)
+}
+
+// TestCreateBook_LinkedAuthorsWithRoles prueba los vínculos con roles y el texto de autor derivado
+func TestCreateBook_LinkedAuthorsWithRoles(t *testing.T) {
+	// Arrange
+	bookUseCase, authorUseCase := newAuthorFixture()
+	ctx := staffCtx
+	marquez, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Gabriel García Márquez"})
+	rabassa, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Gregory Rabassa"})
+
+	// Act: sin campo Author, con un vínculo repetido y uno sin rol
+	book, err := bookUseCase.CreateBook(ctx, usecase.BookInput{
+		Title: "One Hundred Years of Solitude",
+		Authors: []domain.BookAuthor{
+			{AuthorID: marquez.ID},
+			{AuthorID: rabassa.ID, Role: domain.RoleTranslator},
+			{AuthorID: marquez.ID, Role: domain.RoleAuthor},
+		},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if book.Author != "Gabriel García Márquez" {
+		t.Errorf("Se esperaba el autor derivado de los vínculos, pero se obtuvo: %q", book.Author)
+	}
+	if len(book.Authors) != 2 || book.Authors[0].Role != domain.RoleAuthor || book.Authors[1].Role != domain.RoleTranslator {
+		t.Fatalf("Se esperaban 2 vínculos (author, translator), pero se obtuvo: %+v", book.Authors)
+	}
+	if book.Authors[1].Name != "Gregory Rabassa" {
+		t.Errorf("Se esperaba el nombre del traductor, pero se obtuvo: %+v", book.Authors[1])
+	}
+}
+
+// TestCreateBook_UnknownOrInvalidAuthors prueba que los vínculos inválidos son errores de validación
+func TestCreateBook_UnknownOrInvalidAuthors(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []domain.BookAuthor
+		code  string
+	}{
+		{"autor inexistente", []domain.BookAuthor{{AuthorID: "no-existe"}}, domain.CodeUnknownRef},
+		{"rol desconocido", []domain.BookAuthor{{AuthorID: "x", Role: "ilustrador"}}, domain.CodeInvalidFormat},
+		{"sin author_id", []domain.BookAuthor{{Role: domain.RoleEditor}}, domain.CodeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookUseCase, _ := newAuthorFixture()
+
+			_, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Título", Authors: tt.links})
+
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("Se esperaba un error de validación, pero se obtuvo: %v", err)
+			}
+			if domainErr.Fields[0].Field != "authors" || domainErr.Fields[0].Code != tt.code {
+				t.Errorf("Se esperaba el código %q en authors, pero se obtuvo: %v", tt.code, domainErr.Fields)
+			}
+		})
+	}
+}
+
+// TestListAuthorBooks_ReflectsRename prueba GET /api/authors/:id/books y que el nombre sale del autor
+func TestListAuthorBooks_ReflectsRename(t *testing.T) {
+	// Arrange
+	bookUseCase, authorUseCase := newAuthorFixture()
+	ctx := staffCtx
+	martin, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Robert Martin"})
+	other, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Kent Beck"})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Code", Authors: []domain.BookAuthor{{AuthorID: martin.ID}}})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Architecture", Authors: []domain.BookAuthor{{AuthorID: martin.ID}}})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "TDD", Authors: []domain.BookAuthor{{AuthorID: other.ID}}})
+
+	// Act
+	if _, err := authorUseCase.UpdateAuthor(ctx, martin.ID, usecase.AuthorInput{Name: "Robert C. Martin"}); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error al renombrar, pero se obtuvo: %v", err)
+	}
+	page, err := authorUseCase.ListAuthorBooks(ctx, martin.ID, domain.BookQuery{
+		PageRequest: domain.PageRequest{Sort: domain.SortByTitle},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if page.Total != 2 || page.Items[0].Title != "Clean Architecture" {
+		t.Fatalf("Se esperaban los 2 libros del autor ordenados por título, pero se obtuvo: %+v", page.Items)
+	}
+	if page.Items[0].Authors[0].Name != "Robert C. Martin" {
+		t.Errorf("Se esperaba el nombre nuevo del autor, pero se obtuvo: %+v", page.Items[0].Authors)
+	}
+
+	if _, err := authorUseCase.ListAuthorBooks(ctx, "no-existe", domain.BookQuery{}); !errors.Is(err, domain.ErrAuthorNotFound) {
+		t.Errorf("Se esperaba ErrAuthorNotFound, pero se obtuvo: %v", err)
+	}
+}
+
+// TestDeleteAuthor_WithBooks prueba que no se puede borrar un autor con libros vinculados
+func TestDeleteAuthor_WithBooks(t *testing.T) {
+	// Arrange
+	bookUseCase, authorUseCase := newAuthorFixture()
+	ctx := staffCtx
+	author, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Martin Fowler"})
+	book, _ := bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Refactoring", Authors: []domain.BookAuthor{{AuthorID: author.ID}}})
+
+	// Act & Assert: con libros → 409
+	if err := authorUseCase.DeleteAuthor(ctx, author.ID); !errors.Is(err, domain.ErrAuthorHasBooks) || !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Se esperaba ErrAuthorHasBooks, pero se obtuvo: %v", err)
+	}
+
+	// Sin libros → se elimina
+	bookUseCase.DeleteBook(ctx, book.ID, 0)
+	if err := authorUseCase.DeleteAuthor(ctx, author.ID); err != nil {
+		t.Errorf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+}
+
+// Para ejecutar estos tests, usa:
+// go test ./internal/usecase/test -run Author -v
+
+// TestBookFacets_CountsAndLabels prueba las facetas por autor e idioma y el filtro por idioma base
+func TestBookFacets_CountsAndLabels(t *testing.T) {
+	// Arrange: "pt" y "pt-BR" cuentan como el mismo idioma; un libro sin idioma no cuenta
+	bookUseCase, authorUseCase := newAuthorFixture()
+	ctx := staffCtx
+	amado, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "Jorge Amado"})
+	saramago, _ := authorUseCase.CreateAuthor(ctx, usecase.AuthorInput{Name: "José Saramago"})
+	for _, in := range []usecase.BookInput{
+		{Title: "Capitães da Areia", Language: "pt-BR", Authors: []domain.BookAuthor{{AuthorID: amado.ID}}},
+		{Title: "Gabriela", Language: "pt-BR", Authors: []domain.BookAuthor{{AuthorID: amado.ID}}},
+		{Title: "Ensaio sobre a cegueira", Language: "pt", Authors: []domain.BookAuthor{{AuthorID: saramago.ID}}},
+		{Title: "Blindness", Language: "en", Authors: []domain.BookAuthor{{AuthorID: saramago.ID}, {AuthorID: saramago.ID, Role: domain.RoleEditor}}},
+		{Title: "Sin idioma", Author: "Anónimo"},
+	} {
+		if _, err := bookUseCase.CreateBook(ctx, in); err != nil {
+			t.Fatalf("No se pudo crear el libro: %v", err)
+		}
+	}
+
+	// Act
+	all, err := bookUseCase.BookFacets(ctx, domain.BookFilter{})
+	portuguese, _ := bookUseCase.BookFacets(ctx, domain.BookFilter{Language: "pt"})
+	brazilian, _ := bookUseCase.ListBooks(ctx, domain.BookQuery{Filter: domain.BookFilter{Language: "PT-br"}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if len(all.Languages) != 2 || all.Languages[0] != (domain.FacetCount{Value: "pt", Count: 3}) || all.Languages[1].Count != 1 {
+		t.Errorf("Se esperaban pt (3) y en (1), pero se obtuvo: %+v", all.Languages)
+	}
+	// A igual cantidad, por valor: el orden de los IDs decide entre Amado y Saramago
+	if len(all.Authors) != 2 || all.Authors[0].Count != 2 || all.Authors[1].Count != 2 || all.Authors[0].Label == "" {
+		t.Errorf("Se esperaban los dos autores con 2 libros y su nombre, pero se obtuvo: %+v", all.Authors)
+	}
+	if len(portuguese.Authors) != 2 || portuguese.Authors[0] != (domain.FacetCount{Value: amado.ID, Label: "Jorge Amado", Count: 2}) {
+		t.Errorf("Se esperaba Amado primero entre los libros en portugués, pero se obtuvo: %+v", portuguese.Authors)
+	}
+	if brazilian.Total != 2 {
+		t.Errorf("Se esperaban 2 libros en pt-BR, pero se obtuvo: %d", brazilian.Total)
+	}
+}
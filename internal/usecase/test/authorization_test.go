@@ -0,0 +1,175 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+	"testing"
+)
+
+// actorCtx retorna el contexto de un usuario existente, como lo dejaría RequireAuth
+func (f loanFixture) actorCtx(t *testing.T, userID string) context.Context {
+	t.Helper()
+	user, err := f.users.GetUserByID(staffCtx, userID)
+	if err != nil {
+		t.Fatalf("No se pudo obtener el usuario: %v", err)
+	}
+	return usecase.ContextWithUser(context.Background(), user)
+}
+
+// TestRole_Can verifica la tabla de permisos de cada rol
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		role domain.Role
+		perm domain.Permission
+		want bool
+	}{
+		{domain.RoleMember, domain.PermBooksRead, true},
+		{domain.RoleMember, domain.PermBooksWrite, false},
+		{domain.RoleMember, domain.PermCirculation, false},
+		{domain.RoleLibrarian, domain.PermBooksWrite, true},
+		{domain.RoleLibrarian, domain.PermCirculation, true},
+		{domain.RoleLibrarian, domain.PermUsersAdmin, false},
+		{domain.RoleAdmin, domain.PermUsersAdmin, true},
+		{domain.Role("root"), domain.PermBooksRead, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Can(tt.perm); got != tt.want {
+			t.Errorf("%s.Can(%s) = %v, se esperaba %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+}
+
+// TestAuthorization_Member prueba lo que puede y no puede hacer un socio
+func TestAuthorization_Member(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ana := f.newUser(t, "ana@example.com")
+	beto := f.newUser(t, "beto@example.com")
+	book := f.newBook(t, "Rayuela")
+	ctx := f.actorCtx(t, ana)
+
+	// Act + Assert: lee el catálogo pero no lo modifica
+	if _, err := f.books.GetBookByID(ctx, book); err != nil {
+		t.Errorf("Se esperaba que un socio pudiera leer libros, pero se obtuvo: %v", err)
+	}
+	if _, err := f.books.CreateBook(ctx, usecase.BookInput{Title: "Ficciones", Author: "Borges"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al crear un libro, pero se obtuvo: %v", err)
+	}
+
+	// Ve y edita su propio usuario, pero no el de otro
+	if _, err := f.users.UpdateUser(ctx, ana, "Ana María", "ana@example.com", 0); err != nil {
+		t.Errorf("Se esperaba que un socio pudiera editarse, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.GetUserByID(ctx, beto); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al ver otro usuario, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.ListUsers(ctx, domain.UserQuery{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al listar usuarios, pero se obtuvo: %v", err)
+	}
+
+	// Se anota en la cola a su nombre (con el único ejemplar prestado), pero no puede prestar
+	if _, err := f.loans.Checkout(staffCtx, usecase.CheckoutInput{BookID: book, UserID: beto}); err != nil {
+		t.Fatalf("No se pudo prestar el libro: %v", err)
+	}
+	if hold, err := f.holds.PlaceHold(ctx, book, ""); err != nil || hold.UserID != ana {
+		t.Errorf("Se esperaba una reserva de Ana, pero se obtuvo: %+v (err: %v)", hold, err)
+	}
+	if _, err := f.holds.PlaceHold(ctx, book, beto); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al reservar para otro, pero se obtuvo: %v", err)
+	}
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: ana}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al prestar, pero se obtuvo: %v", err)
+	}
+}
+
+// TestAuthorization_Librarian prueba que un bibliotecario gestiona el catálogo pero no los usuarios
+func TestAuthorization_Librarian(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	lucia := f.newUser(t, "lucia@example.com")
+	beto := f.newUser(t, "beto@example.com")
+	if _, err := f.users.ChangeUserRole(staffCtx, lucia, domain.RoleLibrarian); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	ctx := f.actorCtx(t, lucia)
+
+	// Act + Assert
+	if _, err := f.books.CreateBook(ctx, usecase.BookInput{Title: "Ficciones", Author: "Borges"}); err != nil {
+		t.Errorf("Se esperaba que un bibliotecario pudiera crear libros, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.GetUserByID(ctx, beto); err != nil {
+		t.Errorf("Se esperaba que un bibliotecario pudiera ver usuarios, pero se obtuvo: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, beto, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al borrar un usuario, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.ChangeUserRole(ctx, beto, domain.RoleAdmin); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Se esperaba ErrForbidden al cambiar un rol, pero se obtuvo: %v", err)
+	}
+}
+
+// TestAuthorization_NoActor verifica que sin usuario en el contexto no se hace nada
+func TestAuthorization_NoActor(t *testing.T) {
+	f := newLoanFixture()
+
+	_, err := f.books.CreateBook(context.Background(), usecase.BookInput{Title: "Ficciones", Author: "Borges"})
+
+	if !errors.Is(err, domain.ErrAuthRequired) {
+		t.Errorf("Se esperaba ErrAuthRequired, pero se obtuvo: %v", err)
+	}
+}
+
+// TestChangeUserRole prueba la validación del rol y que un admin no pueda cambiarse a sí mismo
+func TestChangeUserRole(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ana := f.newUser(t, "ana@example.com")
+	admin, err := f.users.BootstrapAdmin(staffCtx, "admin@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	ctx := f.actorCtx(t, admin.ID)
+
+	// Act + Assert
+	if _, err := f.users.ChangeUserRole(ctx, ana, domain.Role("root")); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Se esperaba un error de validación con un rol inexistente, pero se obtuvo: %v", err)
+	}
+	if _, err := f.users.ChangeUserRole(ctx, admin.ID, domain.RoleMember); !errors.Is(err, domain.ErrOwnRoleChange) {
+		t.Errorf("Se esperaba ErrOwnRoleChange, pero se obtuvo: %v", err)
+	}
+	user, err := f.users.ChangeUserRole(ctx, ana, domain.RoleLibrarian)
+	if err != nil || user.Role != domain.RoleLibrarian {
+		t.Errorf("Se esperaba a Ana como bibliotecaria, pero se obtuvo: %+v (err: %v)", user, err)
+	}
+}
+
+// TestBootstrapAdmin verifica que el admin inicial se crea o se promueve, y que es idempotente
+func TestBootstrapAdmin(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ana := f.newUser(t, "ana@example.com")
+
+	// Act: promueve a un usuario existente sin tocar su contraseña
+	promoted, err := f.users.BootstrapAdmin(context.Background(), "ana@example.com", "otra-contraseña")
+
+	// Assert
+	if err != nil || promoted.ID != ana || promoted.Role != domain.RoleAdmin {
+		t.Fatalf("Se esperaba promover a Ana, pero se obtuvo: %+v (err: %v)", promoted, err)
+	}
+	if _, err := f.auth.Login(context.Background(), "ana@example.com", testPassword); err != nil {
+		t.Errorf("Se esperaba que la contraseña de Ana no cambiara, pero se obtuvo: %v", err)
+	}
+	again, err := f.users.BootstrapAdmin(context.Background(), "ana@example.com", "otra-contraseña")
+	if err != nil || again.ID != ana {
+		t.Errorf("Se esperaba el mismo admin, pero se obtuvo: %+v (err: %v)", again, err)
+	}
+
+	// Con un email nuevo lo crea
+	created, err := f.users.BootstrapAdmin(context.Background(), "admin@example.com", testPassword)
+	if err != nil || created.Role != domain.RoleAdmin {
+		t.Errorf("Se esperaba un admin nuevo, pero se obtuvo: %+v (err: %v)", created, err)
+	}
+}
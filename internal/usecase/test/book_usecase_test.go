@@ -15,9 +15,13 @@
 package test
 
 import (
+	"context"
 	"errors"
 	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/repository"
 	"go-book-clean-architecture-api/internal/usecase"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +37,12 @@ type MockBookRepository struct {
 	shouldError bool
 }
 
+// newBookUseCase arma un BookUseCase sobre repo con el resto de los repositorios en memoria
+func newBookUseCase(repo repository.BookRepository) *usecase.BookUseCase {
+	copies := memory.NewInMemoryCopyRepository()
+	return usecase.NewBookUseCase(repo, memory.NewInMemoryAuthorRepository(), copies, memory.NewInMemoryLoanRepository(copies), memory.NewInMemoryHoldRepository(), memory.NewInMemoryFineRepository())
+}
+
 // NewMockBookRepository crea una nueva instancia del mock
 func NewMockBookRepository() *MockBookRepository {
 	return &MockBookRepository{
@@ -48,28 +58,53 @@ func (m *MockBookRepository) SetShouldError(shouldError bool) {
 
 // Implementación de la interfaz BookRepository
 
-func (m *MockBookRepository) Create(book *domain.Book) (*domain.Book, error) {
+func (m *MockBookRepository) Create(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	if m.shouldError {
-		return nil, errors.New("error simulado del repositorio")
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
 	}
 	m.books[book.ID] = book
 	return book, nil
 }
 
-func (m *MockBookRepository) GetByID(id string) (*domain.Book, error) {
+func (m *MockBookRepository) GetByID(ctx context.Context, id string) (*domain.Book, error) {
 	if m.shouldError {
-		return nil, errors.New("error simulado del repositorio")
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
 	}
 	book, exists := m.books[id]
 	if !exists {
-		return nil, errors.New("libro no encontrado")
+		return nil, domain.ErrBookNotFound
 	}
 	return book, nil
 }
 
-func (m *MockBookRepository) GetAll() ([]*domain.Book, error) {
+func (m *MockBookRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Book, error) {
+	if m.shouldError {
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
+	}
+	books := make([]*domain.Book, 0, len(ids))
+	for _, id := range ids {
+		if book, exists := m.books[id]; exists {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+func (m *MockBookRepository) GetByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
 	if m.shouldError {
-		return nil, errors.New("error simulado del repositorio")
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
+	}
+	for _, book := range m.books {
+		if isbn != "" && book.ISBN == isbn {
+			return book, nil
+		}
+	}
+	return nil, domain.ErrBookNotFound
+}
+
+func (m *MockBookRepository) GetAll(ctx context.Context) ([]*domain.Book, error) {
+	if m.shouldError {
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
 	}
 	books := make([]*domain.Book, 0, len(m.books))
 	for _, book := range m.books {
@@ -78,23 +113,44 @@ func (m *MockBookRepository) GetAll() ([]*domain.Book, error) {
 	return books, nil
 }
 
-func (m *MockBookRepository) Update(book *domain.Book) (*domain.Book, error) {
+// List ignora filtros y orden: los tests de paginación usan el repositorio en memoria
+func (m *MockBookRepository) List(ctx context.Context, q domain.BookQuery) ([]*domain.Book, int, error) {
+	books, err := m.GetAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, len(books), nil
+}
+
+func (m *MockBookRepository) Facets(ctx context.Context, filter domain.BookFilter) (*domain.BookFacets, error) {
+	return &domain.BookFacets{Authors: []domain.FacetCount{}, Languages: []domain.FacetCount{}}, nil
+}
+
+func (m *MockBookRepository) Search(ctx context.Context, q domain.BookSearchQuery) ([]domain.BookHit, int, error) {
+	return []domain.BookHit{}, 0, nil
+}
+
+func (m *MockBookRepository) Suggest(ctx context.Context, q domain.SuggestQuery) ([]domain.Suggestion, error) {
+	return []domain.Suggestion{}, nil
+}
+
+func (m *MockBookRepository) Update(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	if m.shouldError {
-		return nil, errors.New("error simulado del repositorio")
+		return nil, domain.NewInternalError("error simulado del repositorio", nil)
 	}
 	if _, exists := m.books[book.ID]; !exists {
-		return nil, errors.New("libro no encontrado")
+		return nil, domain.ErrBookNotFound
 	}
 	m.books[book.ID] = book
 	return book, nil
 }
 
-func (m *MockBookRepository) Delete(id string) error {
+func (m *MockBookRepository) Delete(ctx context.Context, id string, version int) error {
 	if m.shouldError {
-		return errors.New("error simulado del repositorio")
+		return domain.NewInternalError("error simulado del repositorio", nil)
 	}
 	if _, exists := m.books[id]; !exists {
-		return errors.New("libro no encontrado")
+		return domain.ErrBookNotFound
 	}
 	delete(m.books, id)
 	return nil
@@ -109,10 +165,10 @@ func (m *MockBookRepository) Delete(id string) error {
 func TestCreateBook_Success(t *testing.T) {
 	// Arrange: Preparar el entorno
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Act: Ejecutar la acción
-	book, err := bookUseCase.CreateBook("Clean Architecture", "Robert C. Martin")
+	book, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Clean Architecture", Author: "Robert C. Martin"})
 
 	// Assert: Verificar resultados
 	if err != nil {
@@ -136,10 +192,10 @@ func TestCreateBook_Success(t *testing.T) {
 func TestCreateBook_EmptyTitle(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Act
-	book, err := bookUseCase.CreateBook("", "Algún autor")
+	book, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "", Author: "Algún autor"})
 
 	// Assert
 	if err == nil {
@@ -152,16 +208,19 @@ func TestCreateBook_EmptyTitle(t *testing.T) {
 	if err.Error() != expectedError {
 		t.Errorf("Se esperaba error '%s', pero se obtuvo: %s", expectedError, err.Error())
 	}
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Se esperaba un error de validación, pero se obtuvo: %v", err)
+	}
 }
 
 // TestCreateBook_EmptyAuthor prueba el error cuando el autor está vacío
 func TestCreateBook_EmptyAuthor(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Act
-	book, err := bookUseCase.CreateBook("Algún título", "")
+	book, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Algún título", Author: ""})
 
 	// Assert
 	if err == nil {
@@ -176,20 +235,130 @@ func TestCreateBook_EmptyAuthor(t *testing.T) {
 	}
 }
 
+// TestCreateBook_AllFieldsInvalid prueba que se reportan TODOS los campos inválidos juntos
+func TestCreateBook_AllFieldsInvalid(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(NewMockBookRepository())
+
+	// Act
+	_, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "", Author: "   "})
+
+	// Assert
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		t.Fatalf("Se esperaba un *domain.Error, pero se obtuvo: %v", err)
+	}
+	if len(domainErr.Fields) != 2 {
+		t.Fatalf("Se esperaban 2 campos inválidos, pero se obtuvieron: %+v", domainErr.Fields)
+	}
+	if domainErr.Fields[0].Field != "title" || domainErr.Fields[1].Field != "author" {
+		t.Errorf("Se esperaban los campos title y author, pero se obtuvo: %+v", domainErr.Fields)
+	}
+	if domainErr.Fields[0].Code != domain.CodeRequired {
+		t.Errorf("Se esperaba el código '%s', pero se obtuvo: %s", domain.CodeRequired, domainErr.Fields[0].Code)
+	}
+}
+
+// TestCreateBook_BibliographicFields prueba la normalización de los datos bibliográficos
+func TestCreateBook_BibliographicFields(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+
+	// Act
+	book, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{
+		Title:           "  Clean Code ",
+		Author:          "Robert C. Martin",
+		ISBN:            "0-13-235088-2",
+		Publisher:       "Prentice Hall",
+		PublicationYear: 2008,
+		Language:        "EN-us",
+		PageCount:       464,
+		Subjects:        []string{"Programación", " programación ", "", "Calidad"},
+		Edition:         "1ra",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if book.Title != "Clean Code" || book.ISBN != "9780132350884" || book.Language != "en-US" {
+		t.Errorf("Se esperaban título, ISBN-13 e idioma normalizados, pero se obtuvo: %+v", book)
+	}
+	if len(book.Subjects) != 2 || book.Subjects[0] != "Programación" || book.Subjects[1] != "Calidad" {
+		t.Errorf("Se esperaban 2 materias sin repetir, pero se obtuvo: %v", book.Subjects)
+	}
+}
+
+// TestCreateBook_InvalidBibliographicFields prueba que se reportan todos los campos inválidos
+func TestCreateBook_InvalidBibliographicFields(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(NewMockBookRepository())
+
+	// Act
+	_, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{
+		Title:           "Título",
+		Author:          "Autor",
+		ISBN:            "978-0-13-235088-5", // Dígito de control incorrecto
+		PublicationYear: 3000,
+		Language:        "español",
+		PageCount:       -1,
+	})
+
+	// Assert
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("Se esperaba un error de validación, pero se obtuvo: %v", err)
+	}
+	fields := make(map[string]string)
+	for _, f := range domainErr.Fields {
+		fields[f.Field] = f.Code
+	}
+	expected := map[string]string{
+		"isbn":             domain.CodeInvalidFormat,
+		"publication_year": domain.CodeOutOfRange,
+		"language":         domain.CodeInvalidFormat,
+		"page_count":       domain.CodeOutOfRange,
+	}
+	for field, code := range expected {
+		if fields[field] != code {
+			t.Errorf("Se esperaba el código %q en el campo %q, pero se obtuvo: %v", code, field, domainErr.Fields)
+		}
+	}
+}
+
+// TestCreateBook_DuplicateISBN prueba que el mismo ISBN (escrito como ISBN-10 o 13) es un conflicto
+func TestCreateBook_DuplicateISBN(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+	ctx := staffCtx
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884"})
+
+	// Act
+	_, err := bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Otro", Author: "Otro", ISBN: "0132350882"})
+
+	// Assert
+	if !errors.Is(err, domain.ErrISBNAlreadyInUse) || !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Se esperaba ErrISBNAlreadyInUse, pero se obtuvo: %v", err)
+	}
+}
+
 // TestCreateBook_RepositoryError prueba el manejo de errores del repositorio
 func TestCreateBook_RepositoryError(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
 	mockRepo.SetShouldError(true) // Configurar el mock para que retorne error
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Act
-	book, err := bookUseCase.CreateBook("Título válido", "Autor válido")
+	book, err := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Título válido", Author: "Autor válido"})
 
 	// Assert
 	if err == nil {
 		t.Error("Se esperaba un error del repositorio, pero no se obtuvo ninguno")
 	}
+	if !errors.Is(err, domain.ErrInternal) {
+		t.Errorf("Se esperaba un error interno, pero se obtuvo: %v", err)
+	}
 	if book != nil {
 		t.Error("Se esperaba nil, pero se obtuvo un libro")
 	}
@@ -199,13 +368,13 @@ func TestCreateBook_RepositoryError(t *testing.T) {
 func TestGetBookByID_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Primero crear un libro
-	createdBook, _ := bookUseCase.CreateBook("Test Book", "Test Author")
+	createdBook, _ := bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Test Book", Author: "Test Author"})
 
 	// Act
-	foundBook, err := bookUseCase.GetBookByID(createdBook.ID)
+	foundBook, err := bookUseCase.GetBookByID(staffCtx, createdBook.ID)
 
 	// Assert
 	if err != nil {
@@ -223,10 +392,10 @@ func TestGetBookByID_Success(t *testing.T) {
 func TestGetBookByID_EmptyID(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Act
-	book, err := bookUseCase.GetBookByID("")
+	book, err := bookUseCase.GetBookByID(staffCtx, "")
 
 	// Assert
 	if err == nil {
@@ -241,18 +410,39 @@ func TestGetBookByID_EmptyID(t *testing.T) {
 	}
 }
 
+// TestGetBookByID_NotFound prueba que un ID inexistente devuelve un error de categoría NotFound
+func TestGetBookByID_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockBookRepository()
+	bookUseCase := newBookUseCase(mockRepo)
+
+	// Act
+	book, err := bookUseCase.GetBookByID(staffCtx, "id-que-no-existe")
+
+	// Assert
+	if book != nil {
+		t.Error("Se esperaba nil, pero se obtuvo un libro")
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Se esperaba un error NotFound, pero se obtuvo: %v", err)
+	}
+	if errors.Is(err, domain.ErrValidation) {
+		t.Error("Un libro inexistente NO debe clasificarse como error de validación")
+	}
+}
+
 // TestGetAllBooks_Success prueba obtener todos los libros
 func TestGetAllBooks_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockBookRepository()
-	bookUseCase := usecase.NewBookUseCase(mockRepo)
+	bookUseCase := newBookUseCase(mockRepo)
 
 	// Crear algunos libros de prueba
-	bookUseCase.CreateBook("Libro 1", "Autor 1")
-	bookUseCase.CreateBook("Libro 2", "Autor 2")
+	bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Libro 1", Author: "Autor 1"})
+	bookUseCase.CreateBook(staffCtx, usecase.BookInput{Title: "Libro 2", Author: "Autor 2"})
 
 	// Act
-	books, err := bookUseCase.GetAllBooks()
+	books, err := bookUseCase.GetAllBooks(staffCtx)
 
 	// Assert
 	if err != nil {
@@ -263,6 +453,230 @@ func TestGetAllBooks_Success(t *testing.T) {
 	}
 }
 
+// TestListBooks_CursorPagination recorre un listado ordenado página a página con el cursor
+func TestListBooks_CursorPagination(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+	ctx := staffCtx
+	for _, title := range []string{"C", "A", "E", "B", "D"} {
+		bookUseCase.CreateBook(ctx, usecase.BookInput{Title: title, Author: "Autor"})
+	}
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Otro", Author: "Cervantes"}) // No cumple el filtro
+
+	query := domain.BookQuery{
+		PageRequest: domain.PageRequest{Limit: 2, Sort: domain.SortByTitle},
+		Filter:      domain.BookFilter{Author: "autor"},
+	}
+
+	// Act: pedir páginas hasta que no haya cursor siguiente
+	var titles []string
+	for page := 0; page < 10; page++ {
+		result, err := bookUseCase.ListBooks(ctx, query)
+		if err != nil {
+			t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+		}
+		if result.Total != 5 {
+			t.Errorf("Se esperaba un total de 5, pero se obtuvo: %d", result.Total)
+		}
+		for _, book := range result.Items {
+			titles = append(titles, book.Title)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		// El cursor lleva orden y filtros: solo hace falta enviarlo a él
+		query = domain.BookQuery{PageRequest: domain.PageRequest{Cursor: result.NextCursor}}
+	}
+
+	// Assert
+	if got := strings.Join(titles, ","); got != "A,B,C,D,E" {
+		t.Errorf("Se esperaba A,B,C,D,E, pero se obtuvo: %s", got)
+	}
+}
+
+// TestListBooks_InvalidParams prueba que los parámetros fuera de las reglas son errores de validación
+//
+// 📋 Table-driven test: un caso por fila
+func TestListBooks_InvalidParams(t *testing.T) {
+	bookUseCase := newBookUseCase(NewMockBookRepository())
+
+	tests := []struct {
+		name  string
+		page  domain.PageRequest
+		field string
+	}{
+		{"límite excesivo", domain.PageRequest{Limit: usecase.MaxPageLimit + 1}, "limit"},
+		{"límite negativo", domain.PageRequest{Limit: -1}, "limit"},
+		{"offset negativo", domain.PageRequest{Offset: -5}, "offset"},
+		{"campo de orden desconocido", domain.PageRequest{Sort: "password"}, "sort"},
+		{"cursor corrupto", domain.PageRequest{Cursor: "no-es-un-cursor"}, "cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bookUseCase.ListBooks(staffCtx, domain.BookQuery{PageRequest: tt.page})
+
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("Se esperaba un error de validación, pero se obtuvo: %v", err)
+			}
+			if len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != tt.field {
+				t.Errorf("Se esperaba un error en el campo %q, pero se obtuvo: %+v", tt.field, domainErr.Fields)
+			}
+		})
+	}
+}
+
+// TestSearchBooks_AccentInsensitiveRanked prueba la búsqueda de texto con el índice en memoria
+func TestSearchBooks_AccentInsensitiveRanked(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+	ctx := staffCtx
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Arquitectura Limpia", Author: "Robert C. Martin"})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Architecture", Author: "Robert C. Martin"})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Martín Fierro", Author: "José Hernández"})
+
+	// Act + Assert: todas las palabras deben coincidir
+	page, err := bookUseCase.SearchBooks(ctx, domain.BookSearchQuery{Text: "martin arquitectura"})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].Title != "Arquitectura Limpia" {
+		t.Errorf("Se esperaba solo 'Arquitectura Limpia', pero se obtuvo: %+v", page.Items)
+	}
+
+	// "MARTIN" encuentra "Martín"; una coincidencia en el título pesa más que en el autor
+	page, err = bookUseCase.SearchBooks(ctx, domain.BookSearchQuery{Text: "MARTIN"})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if page.Total != 3 || page.Items[0].Title != "Martín Fierro" {
+		t.Errorf("Se esperaba 'Martín Fierro' primero de 3, pero se obtuvo: %+v", page.Items)
+	}
+
+	// Las palabras incompletas coinciden por prefijo
+	page, _ = bookUseCase.SearchBooks(ctx, domain.BookSearchQuery{Text: "arqui"})
+	if page == nil || page.Total != 1 {
+		t.Errorf("Se esperaba 1 resultado para el prefijo 'arqui', pero se obtuvo: %+v", page)
+	}
+}
+
+// TestSearchBooks_EmptyQuery prueba que una búsqueda sin palabras es un error de validación
+func TestSearchBooks_EmptyQuery(t *testing.T) {
+	bookUseCase := newBookUseCase(NewMockBookRepository())
+
+	_, err := bookUseCase.SearchBooks(staffCtx, domain.BookSearchQuery{Text: "  ¿? "})
+
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Se esperaba un error de validación, pero se obtuvo: %v", err)
+	}
+}
+
+// TestSuggestBooks_PrefixAndTypos prueba el autocompletado con el trie y los trigramas en memoria
+func TestSuggestBooks_PrefixAndTypos(t *testing.T) {
+	// Arrange
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+	ctx := staffCtx
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Architecture", Author: "Robert C. Martin"})
+	bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin"})
+	mercy, _ := bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Mercy", Author: "Autora Desconocida"})
+
+	// Act + Assert: el texto que empieza por lo escrito va primero
+	suggestions, err := bookUseCase.SuggestBooks(ctx, domain.SuggestQuery{Prefix: "clean a"})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Text != "Clean Architecture" {
+		t.Errorf("Se esperaba 'Clean Architecture' primero, pero se obtuvo: %+v", suggestions)
+	}
+
+	// Un autor compartido aparece una sola vez, con la cantidad de libros
+	suggestions, _ = bookUseCase.SuggestBooks(ctx, domain.SuggestQuery{Prefix: "rob mar"})
+	if len(suggestions) != 1 || suggestions[0].Text != "Robert C. Martin" || suggestions[0].Books != 2 {
+		t.Errorf("Se esperaba 'Robert C. Martin' con 2 libros, pero se obtuvo: %+v", suggestions)
+	}
+
+	// Error de tipeo: "martni" se parece a "martin"
+	suggestions, _ = bookUseCase.SuggestBooks(ctx, domain.SuggestQuery{Prefix: "Martni"})
+	if len(suggestions) == 0 || suggestions[0].Text != "Robert C. Martin" {
+		t.Errorf("Se esperaba 'Robert C. Martin' para 'Martni', pero se obtuvo: %+v", suggestions)
+	}
+
+	// El índice se actualiza al modificar y eliminar
+	bookUseCase.UpdateBook(ctx, mercy.ID, usecase.BookInput{Title: "Misericordia", Author: "Benito Pérez Galdós"}, 0)
+	suggestions, _ = bookUseCase.SuggestBooks(ctx, domain.SuggestQuery{Prefix: "galdos"})
+	if len(suggestions) != 1 || suggestions[0].Text != "Benito Pérez Galdós" {
+		t.Errorf("Se esperaba el autor actualizado, pero se obtuvo: %+v", suggestions)
+	}
+	bookUseCase.DeleteBook(ctx, mercy.ID, 0)
+	suggestions, _ = bookUseCase.SuggestBooks(ctx, domain.SuggestQuery{Prefix: "miseri"})
+	if len(suggestions) != 0 {
+		t.Errorf("No se esperaban sugerencias de un libro eliminado, pero se obtuvo: %+v", suggestions)
+	}
+}
+
+// TestUpdateBook_Version prueba la concurrencia optimista: dos bibliotecarios editan
+// el mismo libro y el segundo recibe un error en vez de pisar los cambios del primero
+func TestUpdateBook_Version(t *testing.T) {
+	// Arrange: los dos leyeron la versión 1
+	bookUseCase := newBookUseCase(memory.NewInMemoryBookRepository())
+	ctx := staffCtx
+	book, _ := bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin"})
+	if book.Version != 1 {
+		t.Fatalf("Se esperaba la versión 1 al crear, pero se obtuvo: %d", book.Version)
+	}
+
+	// Act
+	first, err := bookUseCase.UpdateBook(ctx, book.ID, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", PublicationYear: 2008}, 1)
+	_, staleErr := bookUseCase.UpdateBook(ctx, book.ID, usecase.BookInput{Title: "Código limpio", Author: "Robert C. Martin"}, 1)
+
+	// Assert: el primero sube la versión, el segundo falla y no cambia nada
+	if err != nil || first.Version != 2 {
+		t.Fatalf("Se esperaba la versión 2, pero se obtuvo: %+v (%v)", first, err)
+	}
+	if !errors.Is(staleErr, domain.ErrPreconditionFailed) {
+		t.Errorf("Se esperaba ErrPreconditionFailed con la versión vieja, pero se obtuvo: %v", staleErr)
+	}
+	saved, _ := bookUseCase.GetBookByID(ctx, book.ID)
+	if saved.Title != "Clean Code" || saved.PublicationYear != 2008 || saved.Version != 2 {
+		t.Errorf("Se esperaban los cambios del primero, pero se obtuvo: %+v", saved)
+	}
+
+	// Borrar con la versión vieja tampoco se permite; con la actual, sí
+	if err := bookUseCase.DeleteBook(ctx, book.ID, 1); !errors.Is(err, domain.ErrBookVersionMismatch) {
+		t.Errorf("Se esperaba ErrBookVersionMismatch al borrar, pero se obtuvo: %v", err)
+	}
+	if err := bookUseCase.DeleteBook(ctx, book.ID, 2); err != nil {
+		t.Errorf("Se esperaba borrar con la versión actual, pero se obtuvo: %v", err)
+	}
+}
+
+// TestCreateBook_CancelledContext prueba que una petición cancelada no persiste nada
+//
+// ⏱️ Usamos el repositorio en memoria real: también debe respetar la cancelación
+func TestCreateBook_CancelledContext(t *testing.T) {
+	// Arrange
+	repo := memory.NewInMemoryBookRepository()
+	bookUseCase := newBookUseCase(repo)
+	ctx, cancel := context.WithCancel(staffCtx)
+	cancel() // Simular que el cliente canceló la petición
+
+	// Act
+	book, err := bookUseCase.CreateBook(ctx, usecase.BookInput{Title: "Título válido", Author: "Autor válido"})
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Se esperaba context.Canceled, pero se obtuvo: %v", err)
+	}
+	if book != nil {
+		t.Error("Se esperaba nil, pero se obtuvo un libro")
+	}
+	books, _ := repo.GetAll(staffCtx)
+	if len(books) != 0 {
+		t.Errorf("No se esperaban libros guardados, pero hay: %d", len(books))
+	}
+}
+
 // Para ejecutar estos tests, usa:
 // go test ./internal/usecase/test -v
 //
@@ -273,14 +687,36 @@ func TestGetAllBooks_Success(t *testing.T) {
 // --- PASS: TestCreateBook_EmptyTitle (0.00s)
 // === RUN   TestCreateBook_EmptyAuthor
 // --- PASS: TestCreateBook_EmptyAuthor (0.00s)
+// === RUN   TestCreateBook_AllFieldsInvalid
+// --- PASS: TestCreateBook_AllFieldsInvalid (0.00s)
+// === RUN   TestCreateBook_BibliographicFields
+// --- PASS: TestCreateBook_BibliographicFields (0.00s)
+// === RUN   TestCreateBook_InvalidBibliographicFields
+// --- PASS: TestCreateBook_InvalidBibliographicFields (0.00s)
+// === RUN   TestCreateBook_DuplicateISBN
+// --- PASS: TestCreateBook_DuplicateISBN (0.00s)
 // === RUN   TestCreateBook_RepositoryError
 // --- PASS: TestCreateBook_RepositoryError (0.00s)
 // === RUN   TestGetBookByID_Success
 // --- PASS: TestGetBookByID_Success (0.00s)
 // === RUN   TestGetBookByID_EmptyID
 // --- PASS: TestGetBookByID_EmptyID (0.00s)
+// === RUN   TestGetBookByID_NotFound
+// --- PASS: TestGetBookByID_NotFound (0.00s)
 // === RUN   TestGetAllBooks_Success
 // --- PASS: TestGetAllBooks_Success (0.00s)
+// === RUN   TestListBooks_CursorPagination
+// --- PASS: TestListBooks_CursorPagination (0.00s)
+// === RUN   TestListBooks_InvalidParams
+// --- PASS: TestListBooks_InvalidParams (0.00s)
+// === RUN   TestSearchBooks_AccentInsensitiveRanked
+// --- PASS: TestSearchBooks_AccentInsensitiveRanked (0.00s)
+// === RUN   TestSearchBooks_EmptyQuery
+// --- PASS: TestSearchBooks_EmptyQuery (0.00s)
+// === RUN   TestSuggestBooks_PrefixAndTypos
+// --- PASS: TestSuggestBooks_PrefixAndTypos (0.00s)
+// === RUN   TestCreateBook_CancelledContext
+// --- PASS: TestCreateBook_CancelledContext (0.00s)
 // PASS
 
 // 💡 CONSEJOS PARA TESTING EN CLEAN ARCHITECTURE:
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+	"testing"
+)
+
+// TestAddCopy_Validation prueba la normalización y las reglas del alta de ejemplares
+func TestAddCopy_Validation(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	book := f.newBook(t, "Clean Architecture")
+
+	// Act: el código de barras se normaliza a mayúsculas y el estado físico por defecto es good
+	cp, err := f.copies.AddCopy(ctx, book, usecase.CopyInput{Barcode: " ca-002 ", Location: "Sala 2"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if cp.Barcode != "CA-002" || cp.Condition != domain.ConditionGood || cp.Status != domain.CopyAvailable {
+		t.Errorf("Se esperaba CA-002, good y available, pero se obtuvo: %+v", cp)
+	}
+	if _, err := f.copies.AddCopy(ctx, book, usecase.CopyInput{Barcode: "CA-002"}); !errors.Is(err, domain.ErrBarcodeAlreadyInUse) {
+		t.Errorf("Se esperaba ErrBarcodeAlreadyInUse, pero se obtuvo: %v", err)
+	}
+	if _, err := f.copies.AddCopy(ctx, "no-existe", usecase.CopyInput{Barcode: "X-1"}); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound, pero se obtuvo: %v", err)
+	}
+
+	_, err = f.copies.AddCopy(ctx, book, usecase.CopyInput{Barcode: "con espacios", Condition: "roto"})
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) != 2 {
+		t.Errorf("Se esperaban errores en barcode y condition, pero se obtuvo: %v", err)
+	}
+}
+
+// TestChangeCopyStatus_Rules prueba los cambios de estado manuales y la disponibilidad
+func TestChangeCopyStatus_Rules(t *testing.T) {
+	// Arrange: un libro con 3 ejemplares, uno prestado
+	f := newLoanFixture()
+	ctx := staffCtx
+	book := f.newBook(t, "Refactoring")
+	second, third := f.newCopy(t, book, "RF-2"), f.newCopy(t, book, "RF-3")
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: f.newUser(t, "ana@example.com")})
+	if err != nil {
+		t.Fatalf("No se pudo prestar: %v", err)
+	}
+
+	// Act + Assert: un ejemplar prestado no se cambia a mano ni se borra
+	if _, err := f.copies.ChangeCopyStatus(ctx, loan.CopyID, domain.CopyLost); !errors.Is(err, domain.ErrCopyOnLoan) {
+		t.Errorf("Se esperaba ErrCopyOnLoan, pero se obtuvo: %v", err)
+	}
+	if err := f.copies.DeleteCopy(ctx, loan.CopyID); !errors.Is(err, domain.ErrCopyOnLoan) {
+		t.Errorf("Se esperaba ErrCopyOnLoan al borrar, pero se obtuvo: %v", err)
+	}
+	// on_loan solo lo pone un préstamo
+	if _, err := f.copies.ChangeCopyStatus(ctx, second, domain.CopyOnLoan); err == nil {
+		t.Error("Se esperaba un error al marcar on_loan a mano")
+	}
+
+	f.copies.ChangeCopyStatus(ctx, second, domain.CopyInRepair)
+	f.copies.ChangeCopyStatus(ctx, third, domain.CopyLost)
+	counts, err := f.copies.BookAvailability(ctx, book)
+	want := domain.CopyCounts{Total: 3, OnLoan: 1, InRepair: 1, Lost: 1}
+	if err != nil || counts != want {
+		t.Errorf("Se esperaba %+v, pero se obtuvo: %+v (err: %v)", want, counts, err)
+	}
+
+	// Sin ejemplares en el estante no se puede prestar
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: f.newUser(t, "beto@example.com")}); !errors.Is(err, domain.ErrNoCopiesAvailable) {
+		t.Errorf("Se esperaba ErrNoCopiesAvailable, pero se obtuvo: %v", err)
+	}
+}
+
+// TestGetBookByID_AvailableCopies prueba que el detalle del libro informa los ejemplares disponibles
+func TestGetBookByID_AvailableCopies(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	book := f.newBook(t, "Domain-Driven Design")
+	f.newCopy(t, book, "DDD-2")
+	f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: f.newUser(t, "ana@example.com")})
+
+	// Act
+	got, err := f.books.GetBookByID(ctx, book)
+
+	// Assert
+	if err != nil || got.AvailableCopies == nil || *got.AvailableCopies != 1 {
+		t.Errorf("Se esperaba available_copies = 1, pero se obtuvo: %+v (err: %v)", got, err)
+	}
+	// Al devolver vuelve a estar en el estante
+	loans, _ := f.loans.ListLoans(ctx, domain.LoanQuery{})
+	f.loans.ReturnLoan(ctx, loans.Items[0].ID)
+	if got, _ := f.books.GetBookByID(ctx, book); *got.AvailableCopies != 2 {
+		t.Errorf("Se esperaba available_copies = 2 tras la devolución, pero se obtuvo: %d", *got.AvailableCopies)
+	}
+}
+
+// Para ejecutar estos tests, usa:
+// go test ./internal/usecase/test -run 'Copy|Copies' -v
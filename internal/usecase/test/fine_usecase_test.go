@@ -0,0 +1,194 @@
+package test
+
+import (
+	"errors"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+	"testing"
+	"time"
+)
+
+// day es un día de reloj
+const day = 24 * time.Hour
+
+// newFineFixture arma el fixture con multas de 1.00 por día, sin gracia, tope de 10.00
+// y bloqueo con más de 2.50 de deuda; el reloj arranca el lunes 2 de marzo de 2026
+func newFineFixture() loanFixture {
+	f := newLoanFixtureWithPolicy(domain.LoanPolicy{
+		Period:           14 * day,
+		MaxActiveLoans:   2,
+		MaxRenewals:      1,
+		HoldPickupPeriod: 3 * day,
+		Fines: domain.FinePolicy{
+			DailyRate:     100,
+			MaxPerItem:    1000,
+			DebtThreshold: 250,
+		},
+	})
+	f.clock.Set(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC))
+	return f
+}
+
+// overdueReturn presta un libro, deja pasar late días después del vencimiento y lo devuelve
+func (f loanFixture) overdueReturn(t *testing.T, userID string, late int) *domain.Fine {
+	t.Helper()
+	ctx := staffCtx
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Atrasado"), UserID: userID})
+	if err != nil {
+		t.Fatalf("No se pudo prestar: %v", err)
+	}
+	f.clock.Advance(14*day + time.Duration(late)*day)
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("No se pudo devolver: %v", err)
+	}
+
+	page, err := f.fines.ListFines(ctx, domain.FineQuery{Filter: domain.FineFilter{LoanID: loan.ID}})
+	if err != nil || page.Total != 1 {
+		t.Fatalf("Se esperaba una multa para el préstamo, pero se obtuvo: %v, %v", page, err)
+	}
+	return page.Items[0]
+}
+
+// TestFines_AccrueUntilReturn prueba que la multa crece con el reloj y se congela al devolver
+func TestFines_AccrueUntilReturn(t *testing.T) {
+	// Arrange
+	f := newFineFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Dune"), UserID: ana})
+	if err != nil {
+		t.Fatalf("No se pudo prestar: %v", err)
+	}
+
+	// Act + Assert: a tiempo no hay multa
+	f.clock.Advance(14 * day)
+	if page, _ := f.fines.ListUserFines(ctx, ana, domain.FineQuery{}); page.Total != 0 {
+		t.Errorf("Se esperaba ninguna multa el día del vencimiento, pero se obtuvieron %d", page.Total)
+	}
+
+	// Dos días tarde: 2.00 y sigue creciendo
+	f.clock.Advance(2 * day)
+	page, err := f.fines.ListUserFines(ctx, ana, domain.FineQuery{})
+	if err != nil || page.Total != 1 {
+		t.Fatalf("Se esperaba una multa, pero se obtuvo: %v, %v", page, err)
+	}
+	fine := page.Items[0]
+	if fine.Amount != 200 || fine.OverdueDays != 2 || fine.Final || fine.Status != domain.FineOpen {
+		t.Errorf("Se esperaba una multa abierta de 200 por 2 días, pero se obtuvo: %+v", fine)
+	}
+
+	// Un día más, se devuelve: 3.00 final
+	f.clock.Advance(day)
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("No se pudo devolver: %v", err)
+	}
+	f.clock.Advance(10 * day)
+	fine, err = f.fines.GetFineByID(ctx, fine.ID)
+	if err != nil || fine.Amount != 300 || !fine.Final {
+		t.Errorf("Se esperaba una multa final de 300, pero se obtuvo: %+v, %v", fine, err)
+	}
+}
+
+// TestFines_PayAndWaive prueba pagos parciales, sobrepagos y perdón
+func TestFines_PayAndWaive(t *testing.T) {
+	// Arrange
+	f := newFineFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	fine := f.overdueReturn(t, ana, 3)
+
+	// Act + Assert: importes inválidos
+	var domainErr *domain.Error
+	if _, err := f.fines.PayFine(ctx, fine.ID, 0); !errors.As(err, &domainErr) || domainErr.Fields[0].Field != "amount_cents" {
+		t.Errorf("Se esperaba un error en amount_cents, pero se obtuvo: %v", err)
+	}
+	if _, err := f.fines.PayFine(ctx, fine.ID, 301); !errors.Is(err, domain.ErrFineOverpayment) {
+		t.Errorf("Se esperaba ErrFineOverpayment, pero se obtuvo: %v", err)
+	}
+
+	// Pago parcial y luego el resto: queda paid
+	partial, err := f.fines.PayFine(ctx, fine.ID, 100)
+	if err != nil || partial.Status != domain.FineOpen || partial.Balance() != 200 {
+		t.Errorf("Se esperaba la multa abierta con 200 de saldo, pero se obtuvo: %+v, %v", partial, err)
+	}
+	paid, err := f.fines.PayFine(ctx, fine.ID, 200)
+	if err != nil || paid.Status != domain.FinePaid || paid.ClosedAt == nil {
+		t.Errorf("Se esperaba la multa paid, pero se obtuvo: %+v, %v", paid, err)
+	}
+	if _, err := f.fines.PayFine(ctx, fine.ID, 1); !errors.Is(err, domain.ErrFineNotOpen) {
+		t.Errorf("Se esperaba ErrFineNotOpen, pero se obtuvo: %v", err)
+	}
+
+	// Perdón: el motivo es obligatorio
+	other := f.overdueReturn(t, ana, 2)
+	if _, err := f.fines.WaiveFine(ctx, other.ID, "  "); !errors.As(err, &domainErr) {
+		t.Errorf("Se esperaba un error de validación sin motivo, pero se obtuvo: %v", err)
+	}
+	waived, err := f.fines.WaiveFine(ctx, other.ID, "Primera vez")
+	if err != nil || waived.Status != domain.FineWaived || waived.Balance() != 0 {
+		t.Errorf("Se esperaba la multa waived, pero se obtuvo: %+v, %v", waived, err)
+	}
+	if balance, _ := f.fines.UserBalance(ctx, ana); balance.Balance != 0 || balance.Blocked {
+		t.Errorf("Se esperaba deuda cero, pero se obtuvo: %+v", balance)
+	}
+}
+
+// TestCheckout_BlockedByDebt prueba que la deuda (incluido el atraso en curso) bloquea préstamos
+func TestCheckout_BlockedByDebt(t *testing.T) {
+	// Arrange: Ana tiene un libro 3 días vencido (3.00 > 2.50 de límite) y todavía no lo devolvió
+	f := newFineFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	next := f.newBook(t, "Siguiente")
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Vencido"), UserID: ana})
+	if err != nil {
+		t.Fatalf("No se pudo prestar: %v", err)
+	}
+	f.clock.Advance(17 * day)
+
+	// Act + Assert
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: next, UserID: ana}); !errors.Is(err, domain.ErrFineDebtExceeded) {
+		t.Errorf("Se esperaba ErrFineDebtExceeded, pero se obtuvo: %v", err)
+	}
+	if balance, _ := f.fines.UserBalance(ctx, ana); balance.Balance != 300 || !balance.Blocked {
+		t.Errorf("Se esperaba deuda de 300 y bloqueo, pero se obtuvo: %+v", balance)
+	}
+
+	// Devuelve y paga una parte: queda por debajo del límite
+	f.loans.ReturnLoan(ctx, loan.ID)
+	page, _ := f.fines.ListUserFines(ctx, ana, domain.FineQuery{})
+	if _, err := f.fines.PayFine(ctx, page.Items[0].ID, 100); err != nil {
+		t.Fatalf("No se pudo pagar: %v", err)
+	}
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: next, UserID: ana}); err != nil {
+		t.Errorf("Se esperaba poder pedir prestado con 2.00 de deuda, pero se obtuvo: %v", err)
+	}
+}
+
+// TestFines_BlockDelete prueba que no se borre un usuario o un libro con multas impagas
+func TestFines_BlockDelete(t *testing.T) {
+	// Arrange: Ana devolvió tarde y debe 2.00
+	f := newFineFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	fine := f.overdueReturn(t, ana, 2)
+
+	// Act + Assert
+	if err := f.users.DeleteUser(ctx, ana, 0); !errors.Is(err, domain.ErrUserHasUnpaidFines) || !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Se esperaba ErrUserHasUnpaidFines (409), pero se obtuvo: %v", err)
+	}
+	if err := f.books.DeleteBook(ctx, fine.BookID, 0); !errors.Is(err, domain.ErrBookHasUnpaidFines) {
+		t.Errorf("Se esperaba ErrBookHasUnpaidFines, pero se obtuvo: %v", err)
+	}
+
+	// Saldada la multa, lo que queda es historial de circulación
+	if _, err := f.fines.PayFine(ctx, fine.ID, fine.Amount); err != nil {
+		t.Fatalf("No se pudo pagar la multa: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, ana, 0); !errors.Is(err, domain.ErrUserHasCirculation) {
+		t.Errorf("Se esperaba ErrUserHasCirculation, pero se obtuvo: %v", err)
+	}
+	if err := f.books.DeleteBook(ctx, fine.BookID, 0); !errors.Is(err, domain.ErrBookHasCirculation) {
+		t.Errorf("Se esperaba ErrBookHasCirculation, pero se obtuvo: %v", err)
+	}
+}
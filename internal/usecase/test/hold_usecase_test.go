@@ -0,0 +1,203 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/usecase"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lentBook crea un libro con un único ejemplar ya prestado y retorna el libro y el préstamo
+func (f loanFixture) lentBook(t *testing.T, title string) (string, *domain.Loan) {
+	t.Helper()
+	book := f.newBook(t, title)
+	loan, err := f.loans.Checkout(staffCtx, usecase.CheckoutInput{BookID: book, UserID: f.newUser(t, "lector-"+book[:8]+"@example.com")})
+	if err != nil {
+		t.Fatalf("No se pudo prestar: %v", err)
+	}
+	return book, loan
+}
+
+// TestPlaceHold_Rules prueba cuándo se puede reservar
+func TestPlaceHold_Rules(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	available := f.newBook(t, "Disponible")
+	book, _ := f.lentBook(t, "Prestado")
+
+	// Act + Assert: con ejemplares disponibles no hace falta reservar
+	if _, err := f.holds.PlaceHold(ctx, available, ana); !errors.Is(err, domain.ErrHoldNotNeeded) {
+		t.Errorf("Se esperaba ErrHoldNotNeeded, pero se obtuvo: %v", err)
+	}
+
+	hold, err := f.holds.PlaceHold(ctx, book, ana)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if hold.Status != domain.HoldWaiting || hold.Position != 1 {
+		t.Errorf("Se esperaba waiting en la posición 1, pero se obtuvo: %+v", hold)
+	}
+
+	// Una sola reserva activa por libro
+	if _, err := f.holds.PlaceHold(ctx, book, ana); !errors.Is(err, domain.ErrHoldAlreadyExists) {
+		t.Errorf("Se esperaba ErrHoldAlreadyExists, pero se obtuvo: %v", err)
+	}
+	if _, err := f.holds.PlaceHold(ctx, "no-existe", ana); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("Se esperaba ErrBookNotFound, pero se obtuvo: %v", err)
+	}
+
+	// Cancelada, se puede volver a reservar (al final de la cola)
+	if _, err := f.holds.CancelHold(ctx, hold.ID); err != nil {
+		t.Fatalf("No se pudo cancelar: %v", err)
+	}
+	if _, err := f.holds.CancelHold(ctx, hold.ID); !errors.Is(err, domain.ErrHoldNotActive) {
+		t.Errorf("Se esperaba ErrHoldNotActive, pero se obtuvo: %v", err)
+	}
+	if _, err := f.holds.PlaceHold(ctx, book, ana); err != nil {
+		t.Errorf("Se esperaba poder reservar de nuevo, pero se obtuvo: %v", err)
+	}
+}
+
+// TestPlaceHold_ConcurrentPositions prueba que reservas simultáneas reciben posiciones 1..N sin repetir
+func TestPlaceHold_ConcurrentPositions(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	book, _ := f.lentBook(t, "Muy pedido")
+	const n = 20
+	users := make([]string, n)
+	for i := range users {
+		users[i] = f.newUser(t, fmt.Sprintf("user%d@example.com", i))
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	for _, user := range users {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			if _, err := f.holds.PlaceHold(ctx, book, user); err != nil {
+				t.Errorf("No se pudo reservar: %v", err)
+			}
+		}(user)
+	}
+	wg.Wait()
+
+	// Assert
+	page, err := f.holds.ListBookHolds(ctx, book, domain.HoldQuery{})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if page.Total != n {
+		t.Fatalf("Se esperaban %d reservas, pero se obtuvieron %d", n, page.Total)
+	}
+	for i, hold := range page.Items {
+		if hold.Position != i+1 {
+			t.Errorf("Se esperaba la posición %d en orden de cola, pero se obtuvo %d", i+1, hold.Position)
+		}
+	}
+}
+
+// TestHoldQueue_ReturnPromotesFIFO prueba que la devolución aparta el ejemplar para la reserva más antigua
+func TestHoldQueue_ReturnPromotesFIFO(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	book, loan := f.lentBook(t, "Dune")
+	ana, beto := f.newUser(t, "ana@example.com"), f.newUser(t, "beto@example.com")
+	// El reloj de los tests no avanza solo: cada reserva llega un minuto después
+	first, _ := f.holds.PlaceHold(ctx, book, ana)
+	f.clock.Advance(time.Minute)
+	second, _ := f.holds.PlaceHold(ctx, book, beto)
+
+	// Act
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("No se pudo devolver: %v", err)
+	}
+
+	// Assert: Ana (la primera) tiene el ejemplar apartado; Beto pasa al primer lugar
+	ready, _ := f.holds.GetHoldByID(ctx, first.ID)
+	if ready.Status != domain.HoldReady || ready.CopyID != loan.CopyID || ready.ExpiresAt == nil {
+		t.Errorf("Se esperaba la primera reserva ready con el ejemplar devuelto, pero se obtuvo: %+v", ready)
+	}
+	if waiting, _ := f.holds.GetHoldByID(ctx, second.ID); waiting.Position != 1 {
+		t.Errorf("Se esperaba la segunda reserva en la posición 1, pero se obtuvo: %+v", waiting)
+	}
+	if cp, _ := f.copies.GetCopyByID(ctx, loan.CopyID); cp.Status != domain.CopyOnHold {
+		t.Errorf("Se esperaba el ejemplar on_hold, pero se obtuvo: %s", cp.Status)
+	}
+
+	// Nadie más puede llevarse el ejemplar apartado
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: beto}); !errors.Is(err, domain.ErrNoCopiesAvailable) {
+		t.Errorf("Se esperaba ErrNoCopiesAvailable, pero se obtuvo: %v", err)
+	}
+	if _, err := f.copies.ChangeCopyStatus(ctx, loan.CopyID, domain.CopyInRepair); !errors.Is(err, domain.ErrCopyOnHold) {
+		t.Errorf("Se esperaba ErrCopyOnHold, pero se obtuvo: %v", err)
+	}
+
+	// Ana lo retira: la reserva se cumple
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: ana}); err != nil {
+		t.Fatalf("Se esperaba que Ana pudiera retirar su reserva, pero se obtuvo: %v", err)
+	}
+	if fulfilled, _ := f.holds.GetHoldByID(ctx, first.ID); fulfilled.Status != domain.HoldFulfilled {
+		t.Errorf("Se esperaba la reserva fulfilled, pero se obtuvo: %s", fulfilled.Status)
+	}
+}
+
+// TestHoldQueue_CancelAndExpirePassCopy prueba que el ejemplar apartado pasa a la siguiente reserva
+func TestHoldQueue_CancelAndExpirePassCopy(t *testing.T) {
+	// Arrange: plazo de retiro de un día (las reservas vencen adelantando el reloj)
+	f := newLoanFixtureWithPolicy(domain.LoanPolicy{
+		Period:           14 * 24 * time.Hour,
+		MaxActiveLoans:   2,
+		HoldPickupPeriod: 24 * time.Hour,
+	})
+	ctx := staffCtx
+	book, loan := f.lentBook(t, "Neuromante")
+	ana, beto, caro := f.newUser(t, "ana@example.com"), f.newUser(t, "beto@example.com"), f.newUser(t, "caro@example.com")
+	first, _ := f.holds.PlaceHold(ctx, book, ana)
+	f.clock.Advance(time.Minute)
+	second, _ := f.holds.PlaceHold(ctx, book, beto)
+	f.clock.Advance(time.Minute)
+	third, _ := f.holds.PlaceHold(ctx, book, caro)
+	f.loans.ReturnLoan(ctx, loan.ID)
+
+	// Act + Assert: Ana cancela su reserva lista → el ejemplar pasa a Beto
+	if _, err := f.holds.CancelHold(ctx, first.ID); err != nil {
+		t.Fatalf("No se pudo cancelar: %v", err)
+	}
+	if hold, _ := f.holds.GetHoldByID(ctx, second.ID); hold.Status != domain.HoldReady || hold.CopyID != loan.CopyID {
+		t.Errorf("Se esperaba la segunda reserva ready, pero se obtuvo: %+v", hold)
+	}
+
+	// Antes del plazo no vence nada
+	f.clock.Advance(23 * time.Hour)
+	if expired, err := f.holds.ExpireHolds(ctx); err != nil || expired != 0 {
+		t.Fatalf("Se esperaba 0 reservas vencidas antes del plazo, pero se obtuvo %d (%v)", expired, err)
+	}
+
+	// Beto no lo retira a tiempo → vence y el ejemplar pasa a Caro
+	f.clock.Advance(2 * time.Hour)
+	expired, err := f.holds.ExpireHolds(ctx)
+	if err != nil || expired != 1 {
+		t.Fatalf("Se esperaba 1 reserva vencida, pero se obtuvo %d (%v)", expired, err)
+	}
+	if hold, _ := f.holds.GetHoldByID(ctx, second.ID); hold.Status != domain.HoldExpired {
+		t.Errorf("Se esperaba la segunda reserva expired, pero se obtuvo: %s", hold.Status)
+	}
+	if hold, _ := f.holds.GetHoldByID(ctx, third.ID); hold.Status != domain.HoldReady {
+		t.Errorf("Se esperaba la tercera reserva ready, pero se obtuvo: %s", hold.Status)
+	}
+
+	// Caro tampoco lo retira y la cola está vacía → el ejemplar vuelve a estar disponible
+	f.clock.Advance(25 * time.Hour)
+	f.holds.ExpireHolds(ctx)
+	if cp, _ := f.copies.GetCopyByID(ctx, loan.CopyID); cp.Status != domain.CopyAvailable {
+		t.Errorf("Se esperaba el ejemplar available, pero se obtuvo: %s", cp.Status)
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/usecase"
+)
+
+// sliceSource entrega filas desde un slice (el lector de archivos real vive en delivery/tabular)
+type sliceSource struct{ rows []*usecase.BookImportRow }
+
+func (s *sliceSource) Next() (*usecase.BookImportRow, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// importRows arma una fuente con una fila por BookInput (línea 2 en adelante, como un CSV)
+func importRows(inputs ...usecase.BookInput) *sliceSource {
+	source := &sliceSource{}
+	for i, in := range inputs {
+		source.rows = append(source.rows, &usecase.BookImportRow{Line: i + 2, Record: in.Title, Input: in})
+	}
+	return source
+}
+
+// TestImportBooks_CreatesAndRejects verifica que cada fila se valide por separado
+func TestImportBooks_CreatesAndRejects(t *testing.T) {
+	// Arrange: una fila válida, una inválida, una ilegible y un ISBN repetido en el archivo
+	books := memory.NewInMemoryBookRepository()
+	uc := newBookUseCase(books)
+	source := importRows(
+		usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "0132350882"},
+		usecase.BookInput{Title: "", Author: "Sin título", ISBN: "123"},
+		usecase.BookInput{Title: "Ilegible"},
+		usecase.BookInput{Title: "Otra edición", Author: "Robert C. Martin", ISBN: "978-0-13-235088-4"},
+	)
+	source.rows[2].Err = domain.NewValidationError("la fila no es CSV válido")
+
+	// Act
+	report, err := uc.ImportBooks(staffCtx, source, usecase.BookImportOptions{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+	}
+	if report.Total != 4 || report.Created != 1 || report.Rejected != 3 {
+		t.Fatalf("Se esperaban 4 filas, 1 creada y 3 rechazadas, pero se obtuvo: %+v", report)
+	}
+	invalid := report.Rejections[0]
+	if invalid.Line != 3 || len(invalid.Errors) != 2 || invalid.Errors[0].Field != "title" || invalid.Errors[1].Field != "isbn" {
+		t.Errorf("Se esperaba el detalle de title e isbn en la línea 3, pero se obtuvo: %+v", invalid)
+	}
+	if dup := report.Rejections[2]; dup.Line != 5 || !strings.Contains(dup.Reason, "línea 2") {
+		t.Errorf("Se esperaba el ISBN repetido en la línea 5, pero se obtuvo: %+v", dup)
+	}
+	if all, _ := books.GetAll(context.Background()); len(all) != 1 {
+		t.Errorf("Se esperaba 1 libro guardado, pero hay %d", len(all))
+	}
+}
+
+// TestImportBooks_DryRunSavesNothing verifica que el dry run valide sin guardar
+func TestImportBooks_DryRunSavesNothing(t *testing.T) {
+	// Arrange
+	books := memory.NewInMemoryBookRepository()
+	uc := newBookUseCase(books)
+	source := importRows(
+		usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "0132350882"},
+		usecase.BookInput{Title: "Refactoring", Author: "Martin Fowler", PublicationYear: -1},
+	)
+
+	// Act
+	report, err := uc.ImportBooks(staffCtx, source, usecase.BookImportOptions{DryRun: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+	}
+	if !report.DryRun || report.Created != 1 || report.Rejected != 1 {
+		t.Errorf("Se esperaba 1 válida y 1 rechazada en dry run, pero se obtuvo: %+v", report)
+	}
+	if all, _ := books.GetAll(context.Background()); len(all) != 0 {
+		t.Errorf("El dry run no debe guardar nada, pero hay %d libros", len(all))
+	}
+}
+
+// TestImportBooks_ExistingISBN verifica el rechazo sin upsert y la actualización con upsert
+func TestImportBooks_ExistingISBN(t *testing.T) {
+	// Arrange: un libro con descripción; la fila trae solo el ISBN (con guiones) y la editorial
+	uc := newBookUseCase(memory.NewInMemoryBookRepository())
+	book, err := uc.CreateBook(staffCtx, usecase.BookInput{Title: "Clean Code", Author: "Robert C. Martin", Description: "Código limpio", ISBN: "9780132350884"})
+	if err != nil {
+		t.Fatalf("No se pudo crear el libro: %v", err)
+	}
+	row := usecase.BookInput{ISBN: "978-0-13-235088-4", Publisher: "Prentice Hall"}
+
+	// Act: sin upsert
+	report, err := uc.ImportBooks(staffCtx, importRows(row), usecase.BookImportOptions{})
+
+	// Assert
+	if err != nil || report.Rejected != 1 || report.Rejections[0].Reason != domain.ErrISBNAlreadyInUse.Error() {
+		t.Fatalf("Se esperaba el rechazo por ISBN en uso, pero se obtuvo: %+v (err: %v)", report, err)
+	}
+
+	// Act: con upsert
+	report, err = uc.ImportBooks(staffCtx, importRows(row), usecase.BookImportOptions{Upsert: true})
+
+	// Assert: se actualiza y las celdas vacías conservan los datos actuales
+	if err != nil || report.Updated != 1 {
+		t.Fatalf("Se esperaba 1 libro actualizado, pero se obtuvo: %+v (err: %v)", report, err)
+	}
+	updated, _ := uc.GetBookByID(staffCtx, book.ID)
+	if updated.Publisher != "Prentice Hall" || updated.Title != "Clean Code" || updated.Description != "Código limpio" {
+		t.Errorf("Se esperaba la editorial nueva y el resto intacto, pero se obtuvo: %+v", updated)
+	}
+}
+
+// TestImportBooks_RequiresPermission verifica que un lector no pueda importar
+func TestImportBooks_RequiresPermission(t *testing.T) {
+	uc := newBookUseCase(memory.NewInMemoryBookRepository())
+
+	_, err := uc.ImportBooks(context.Background(), importRows(usecase.BookInput{Title: "Clean Code"}), usecase.BookImportOptions{})
+
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("Se esperaba ErrUnauthorized, pero se obtuvo: %v", err)
+	}
+}
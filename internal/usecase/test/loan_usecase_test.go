@@ -0,0 +1,366 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-book-clean-architecture-api/internal/config"
+	"go-book-clean-architecture-api/internal/domain"
+	"go-book-clean-architecture-api/internal/infrastructure/memory"
+	"go-book-clean-architecture-api/internal/infrastructure/security"
+	"go-book-clean-architecture-api/internal/repository"
+	"go-book-clean-architecture-api/internal/usecase"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loanFixture agrupa los casos de uso de préstamos sobre repositorios en memoria
+type loanFixture struct {
+	loans   *usecase.LoanUseCase
+	books   *usecase.BookUseCase
+	users   *usecase.UserUseCase
+	copies  *usecase.CopyUseCase
+	holds   *usecase.HoldUseCase
+	fines   *usecase.FineUseCase
+	auth    *usecase.AuthUseCase
+	apiKeys *usecase.APIKeyUseCase
+	clock   *testClock
+}
+
+// testPassword es la contraseña de los usuarios que crea newUser
+const testPassword = "secreto-de-prueba"
+
+// testSigningKey es la clave con la que el fixture firma los tokens
+var testSigningKey = config.SigningKey{ID: "test", Secret: []byte("clave-de-prueba-de-32-bytes-o-mas")}
+
+// staffCtx es el contexto de un admin: los tests de cada caso de uso no prueban permisos
+// (de eso se ocupa authorization_test.go), así que actúan con todos
+var staffCtx = usecase.ContextWithUser(context.Background(), &domain.User{ID: "staff", Role: domain.RoleAdmin})
+
+// testClock es un reloj que solo avanza cuando el test lo pide
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now implementa usecase.Clock
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance adelanta el reloj d
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set pone el reloj en t
+func (c *testClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// newLoanFixture arma el fixture con una política de 2 préstamos, 1 renovación y 3 días para retirar
+func newLoanFixture() loanFixture {
+	return newLoanFixtureWithPolicy(domain.LoanPolicy{
+		Period:           14 * 24 * time.Hour,
+		MaxActiveLoans:   2,
+		MaxRenewals:      1,
+		HoldPickupPeriod: 3 * 24 * time.Hour,
+	})
+}
+
+// newLoanFixtureWithPolicy arma el fixture con la política indicada y un reloj que arranca ahora
+func newLoanFixtureWithPolicy(policy domain.LoanPolicy) loanFixture {
+	var bookRepo repository.BookRepository = memory.NewInMemoryBookRepository()
+	var userRepo repository.UserRepository = memory.NewInMemoryUserRepository()
+	copyRepo := memory.NewInMemoryCopyRepository()
+	holdRepo := memory.NewInMemoryHoldRepository()
+	loanRepo := memory.NewInMemoryLoanRepository(copyRepo)
+	fineRepo := memory.NewInMemoryFineRepository()
+	clock := &testClock{now: time.Now().UTC()}
+	hasher := security.NewBcryptHasher(config.MinBcryptCost) // Costo mínimo: los tests no necesitan un hash lento
+	tokens, err := security.NewJWTManager(config.AuthConfig{
+		Keys:      []config.SigningKey{testSigningKey},
+		ActiveKey: testSigningKey.ID,
+		Issuer:    "test",
+	}, clock.Now)
+	if err != nil {
+		panic(err)
+	}
+	return loanFixture{
+		loans:   usecase.NewLoanUseCase(loanRepo, bookRepo, userRepo, copyRepo, holdRepo, fineRepo, policy, clock),
+		books:   usecase.NewBookUseCase(bookRepo, memory.NewInMemoryAuthorRepository(), copyRepo, loanRepo, holdRepo, fineRepo),
+		users:   usecase.NewUserUseCase(userRepo, loanRepo, holdRepo, fineRepo, hasher),
+		copies:  usecase.NewCopyUseCase(copyRepo, bookRepo, loanRepo, holdRepo, policy, clock),
+		holds:   usecase.NewHoldUseCase(holdRepo, bookRepo, userRepo, copyRepo, policy, clock),
+		fines:   usecase.NewFineUseCase(fineRepo, loanRepo, userRepo, policy, clock),
+		auth:    usecase.NewAuthUseCase(userRepo, memory.NewInMemorySessionRepository(), hasher, tokens, domain.DefaultTokenPolicy(), clock),
+		apiKeys: usecase.NewAPIKeyUseCase(memory.NewInMemoryAPIKeyRepository(), userRepo, clock),
+		clock:   clock,
+	}
+}
+
+// newBook crea un libro de prueba con un único ejemplar y retorna su ID
+func (f loanFixture) newBook(t *testing.T, title string) string {
+	t.Helper()
+	book, err := f.books.CreateBook(staffCtx, usecase.BookInput{Title: title, Author: "Autor"})
+	if err != nil {
+		t.Fatalf("No se pudo crear el libro: %v", err)
+	}
+	f.newCopy(t, book.ID, "BC-"+book.ID[:8])
+	return book.ID
+}
+
+// newCopy da de alta un ejemplar de prueba y retorna su ID
+func (f loanFixture) newCopy(t *testing.T, bookID, barcode string) string {
+	t.Helper()
+	cp, err := f.copies.AddCopy(staffCtx, bookID, usecase.CopyInput{Barcode: barcode})
+	if err != nil {
+		t.Fatalf("No se pudo crear el ejemplar: %v", err)
+	}
+	return cp.ID
+}
+
+// newUser crea un usuario de prueba y retorna su ID
+func (f loanFixture) newUser(t *testing.T, email string) string {
+	t.Helper()
+	user, err := f.users.CreateUser(staffCtx, "Lector", email, testPassword)
+	if err != nil {
+		t.Fatalf("No se pudo crear el usuario: %v", err)
+	}
+	return user.ID
+}
+
+// TestCheckout_Rules prueba el vencimiento, "no se presta dos veces" y el límite por usuario
+func TestCheckout_Rules(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	ana, beto := f.newUser(t, "ana@example.com"), f.newUser(t, "beto@example.com")
+	b1, b2, b3 := f.newBook(t, "Uno"), f.newBook(t, "Dos"), f.newBook(t, "Tres")
+
+	// Act + Assert: préstamo normal con vencimiento a 14 días
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b1, UserID: ana})
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error, pero se obtuvo: %v", err)
+	}
+	if loan.Status != domain.LoanActive || loan.DueAt.Sub(loan.CheckedOutAt) != 14*24*time.Hour {
+		t.Errorf("Se esperaba un préstamo activo a 14 días, pero se obtuvo: %+v", loan)
+	}
+
+	// El único ejemplar ya está prestado
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b1, UserID: beto}); !errors.Is(err, domain.ErrNoCopiesAvailable) {
+		t.Errorf("Se esperaba ErrNoCopiesAvailable, pero se obtuvo: %v", err)
+	}
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b1, CopyID: loan.CopyID, UserID: beto}); !errors.Is(err, domain.ErrCopyNotAvailable) {
+		t.Errorf("Se esperaba ErrCopyNotAvailable, pero se obtuvo: %v", err)
+	}
+
+	// Ana llega a su límite de 2
+	f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b2, UserID: ana})
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b3, UserID: ana}); !errors.Is(err, domain.ErrLoanLimitReached) {
+		t.Errorf("Se esperaba ErrLoanLimitReached, pero se obtuvo: %v", err)
+	}
+
+	// Al devolver, el libro vuelve a estar disponible y Ana recupera un cupo
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("Se esperaba que no hubiera error al devolver, pero se obtuvo: %v", err)
+	}
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b1, UserID: beto}); err != nil {
+		t.Errorf("Se esperaba poder prestar el libro devuelto, pero se obtuvo: %v", err)
+	}
+	if _, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: b3, UserID: ana}); err != nil {
+		t.Errorf("Se esperaba que Ana recuperara un cupo, pero se obtuvo: %v", err)
+	}
+}
+
+// TestCheckout_UnknownReferences prueba que libro y usuario inexistentes se reportan por campo
+func TestCheckout_UnknownReferences(t *testing.T) {
+	f := newLoanFixture()
+
+	_, err := f.loans.Checkout(staffCtx, usecase.CheckoutInput{BookID: "no-existe", UserID: "tampoco"})
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) != 2 || domainErr.Fields[0].Code != domain.CodeUnknownRef {
+		t.Errorf("Se esperaban 2 errores unknown_ref, pero se obtuvo: %v", err)
+	}
+
+	// Un ejemplar de OTRO libro también es una referencia inválida
+	other := f.newCopy(t, f.newBook(t, "Otro"), "OTRO-1")
+	_, err = f.loans.Checkout(staffCtx, usecase.CheckoutInput{
+		BookID: f.newBook(t, "Libro"), CopyID: other, UserID: f.newUser(t, "ana@example.com"),
+	})
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != "copy_id" {
+		t.Errorf("Se esperaba un error en copy_id, pero se obtuvo: %v", err)
+	}
+}
+
+// TestCheckout_Concurrent prueba que de muchos préstamos simultáneos del mismo libro
+// ganan tantos como ejemplares haya (y ninguno se presta dos veces)
+func TestCheckout_Concurrent(t *testing.T) {
+	// Arrange: 3 ejemplares del mismo libro
+	f := newLoanFixture()
+	book := f.newBook(t, "Muy pedido")
+	f.newCopy(t, book, "EXTRA-1")
+	f.newCopy(t, book, "EXTRA-2")
+	users := make([]string, 20)
+	for i := range users {
+		users[i] = f.newUser(t, "lector"+string(rune('a'+i))+"@example.com")
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for _, user := range users {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			if _, err := f.loans.Checkout(staffCtx, usecase.CheckoutInput{BookID: book, UserID: user}); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(user)
+	}
+	wg.Wait()
+
+	// Assert
+	if succeeded != 3 {
+		t.Errorf("Se esperaban exactamente 3 préstamos exitosos, pero hubo: %d", succeeded)
+	}
+	counts, _ := f.copies.BookAvailability(staffCtx, book)
+	if counts.OnLoan != 3 || counts.Available != 0 {
+		t.Errorf("Se esperaban los 3 ejemplares prestados, pero se obtuvo: %+v", counts)
+	}
+}
+
+// TestReturnAndRenew_Conflicts prueba renovaciones y devoluciones repetidas
+func TestReturnAndRenew_Conflicts(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	loan, _ := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Libro"), UserID: f.newUser(t, "ana@example.com")})
+
+	// Act + Assert: la renovación corre el vencimiento un período desde el vencimiento actual
+	renewed, err := f.loans.RenewLoan(ctx, loan.ID)
+	if err != nil {
+		t.Fatalf("Se esperaba que no hubiera error al renovar, pero se obtuvo: %v", err)
+	}
+	if renewed.Renewals != 1 || !renewed.DueAt.Equal(loan.DueAt.Add(14*24*time.Hour)) {
+		t.Errorf("Se esperaba 1 renovación y 14 días más, pero se obtuvo: %+v", renewed)
+	}
+	if _, err := f.loans.RenewLoan(ctx, loan.ID); !errors.Is(err, domain.ErrRenewalLimitReached) {
+		t.Errorf("Se esperaba ErrRenewalLimitReached, pero se obtuvo: %v", err)
+	}
+
+	// Devolver dos veces es un conflicto
+	f.loans.ReturnLoan(ctx, loan.ID)
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); !errors.Is(err, domain.ErrLoanAlreadyReturned) {
+		t.Errorf("Se esperaba ErrLoanAlreadyReturned, pero se obtuvo: %v", err)
+	}
+	if _, err := f.loans.RenewLoan(ctx, loan.ID); !errors.Is(err, domain.ErrLoanAlreadyReturned) {
+		t.Errorf("Se esperaba ErrLoanAlreadyReturned al renovar, pero se obtuvo: %v", err)
+	}
+}
+
+// TestListUserLoans prueba el historial de un usuario y el filtro por estado
+func TestListUserLoans(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	first, _ := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Uno"), UserID: ana})
+	f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Dos"), UserID: ana})
+	f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: f.newBook(t, "Tres"), UserID: f.newUser(t, "beto@example.com")})
+	f.loans.ReturnLoan(ctx, first.ID)
+
+	// Act
+	all, err := f.loans.ListUserLoans(ctx, ana, domain.LoanQuery{})
+	active, _ := f.loans.ListUserLoans(ctx, ana, domain.LoanQuery{Filter: domain.LoanFilter{Status: domain.LoanActive}})
+	overdue, _ := f.loans.ListUserLoans(ctx, ana, domain.LoanQuery{Filter: domain.LoanFilter{Status: domain.LoanOverdue}})
+
+	// Assert
+	if err != nil || all.Total != 2 || active.Total != 1 || overdue.Total != 0 {
+		t.Errorf("Se esperaban 2 préstamos, 1 activo y 0 vencidos, pero se obtuvo: %d, %d, %d (err: %v)",
+			all.Total, active.Total, overdue.Total, err)
+	}
+	if _, err := f.loans.ListUserLoans(ctx, "no-existe", domain.LoanQuery{}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Se esperaba ErrUserNotFound, pero se obtuvo: %v", err)
+	}
+}
+
+// Para ejecutar estos tests, usa:
+// go test ./internal/usecase/test -run 'Checkout|Return|Loans' -v
+
+// TestDelete_KeepsCirculation prueba que no se borren libros, usuarios ni ejemplares con préstamos
+func TestDelete_KeepsCirculation(t *testing.T) {
+	// Arrange
+	f := newLoanFixture()
+	ctx := staffCtx
+	ana := f.newUser(t, "ana@example.com")
+	book := f.newBook(t, "Prestado")
+	loan, err := f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: book, UserID: ana})
+	if err != nil {
+		t.Fatalf("No se pudo prestar el libro: %v", err)
+	}
+
+	// Act + Assert: con el préstamo activo
+	if err := f.books.DeleteBook(ctx, book, 0); !errors.Is(err, domain.ErrBookHasActiveLoans) {
+		t.Errorf("Se esperaba ErrBookHasActiveLoans, pero se obtuvo: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, ana, 0); !errors.Is(err, domain.ErrUserHasActiveLoans) {
+		t.Errorf("Se esperaba ErrUserHasActiveLoans, pero se obtuvo: %v", err)
+	}
+
+	// Devuelto, el préstamo sigue siendo historial
+	if _, err := f.loans.ReturnLoan(ctx, loan.ID); err != nil {
+		t.Fatalf("No se pudo devolver el libro: %v", err)
+	}
+	if err := f.books.DeleteBook(ctx, book, 0); !errors.Is(err, domain.ErrBookHasCirculation) || !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Se esperaba ErrBookHasCirculation (409), pero se obtuvo: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, ana, 0); !errors.Is(err, domain.ErrUserHasCirculation) {
+		t.Errorf("Se esperaba ErrUserHasCirculation, pero se obtuvo: %v", err)
+	}
+	if err := f.copies.DeleteCopy(ctx, loan.CopyID); !errors.Is(err, domain.ErrCopyHasLoans) {
+		t.Errorf("Se esperaba ErrCopyHasLoans, pero se obtuvo: %v", err)
+	}
+
+	// Una reserva también es historial del usuario
+	beto := f.newUser(t, "beto@example.com")
+	waiting := f.newBook(t, "Sin ejemplares libres")
+	f.loans.Checkout(ctx, usecase.CheckoutInput{BookID: waiting, UserID: ana})
+	if _, err := f.holds.PlaceHold(ctx, waiting, beto); err != nil {
+		t.Fatalf("No se pudo reservar el libro: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, beto, 0); !errors.Is(err, domain.ErrUserHasCirculation) {
+		t.Errorf("Se esperaba ErrUserHasCirculation por la reserva, pero se obtuvo: %v", err)
+	}
+
+	// Un libro sin circulación pero con ejemplares pide darlos de baja primero
+	fresh := f.newBook(t, "Nuevo")
+	if err := f.books.DeleteBook(ctx, fresh, 0); !errors.Is(err, domain.ErrBookHasCopies) {
+		t.Errorf("Se esperaba ErrBookHasCopies, pero se obtuvo: %v", err)
+	}
+	copies, err := f.copies.ListBookCopies(ctx, fresh, domain.CopyQuery{})
+	if err != nil {
+		t.Fatalf("No se pudieron listar los ejemplares: %v", err)
+	}
+	for _, cp := range copies.Items {
+		if err := f.copies.DeleteCopy(ctx, cp.ID); err != nil {
+			t.Fatalf("No se pudo dar de baja el ejemplar: %v", err)
+		}
+	}
+	if err := f.books.DeleteBook(ctx, fresh, 0); err != nil {
+		t.Errorf("Se esperaba poder borrar el libro sin circulación, pero se obtuvo: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, f.newUser(t, "carla@example.com"), 0); err != nil {
+		t.Errorf("Se esperaba poder borrar el usuario sin circulación, pero se obtuvo: %v", err)
+	}
+}
@@ -1,12 +1,12 @@
 -- Script de inicialización para PostgreSQL
 -- Este archivo se ejecuta automáticamente cuando se crea el contenedor de PostgreSQL
--- Útil para cuando quieras implementar un repositorio real con PostgreSQL
+-- Crea las tablas y carga datos de ejemplo para el repositorio PostgreSQL
 
--- Crear la base de datos principal (si no existe)
-CREATE DATABASE IF NOT EXISTS cleanarch;
-
--- Conectar a la base de datos
-\c cleanarch;
+-- La base de datos "cleanarch" la crea la imagen oficial a partir de POSTGRES_DB
+-- (PostgreSQL no soporta CREATE DATABASE IF NOT EXISTS)
+--
+-- 💡 El esquema también lo aplica la aplicación al arrancar con STORAGE_DRIVER=postgres
+-- (ver internal/infrastructure/postgresql/migrations). Ambos usan IF NOT EXISTS.
 
 -- Tabla para almacenar libros
 CREATE TABLE IF NOT EXISTS books (